@@ -0,0 +1,393 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gin-gonic/gin"
+)
+
+type AnnouncementController struct {
+	announcementService service.AnnouncementServiceInterface
+}
+
+func NewAnnouncementController() *AnnouncementController {
+	return &AnnouncementController{
+		announcementService: service.NewAnnouncementService(),
+	}
+}
+
+func NewAnnouncementControllerWithService(announcementService service.AnnouncementServiceInterface) *AnnouncementController {
+	return &AnnouncementController{
+		announcementService: announcementService,
+	}
+}
+
+// CreateAnnouncement
+//
+//	@Summary		Post an announcement to a team
+//	@Description	Team admins only. The body is markdown; the attachment must be one of the team's files. Members are notified over the message WebSocket as "announcement".
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Team ID"
+//	@Param			request	body		dto.AnnouncementRequest	true	"Announcement"
+//	@Success		201		{object}	dto.AnnouncementResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/announcements [post]
+func (ac *AnnouncementController) CreateAnnouncement(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.AnnouncementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := ac.announcementService.CreateAnnouncement(userID, c.Param("id"), &request)
+	if err != nil {
+		handleAnnouncementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetAnnouncements
+//
+//	@Summary		List the announcements of a team
+//	@Description	Pinned announcements first, then newest first. Admins also see who acknowledged each announcement.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			page	query		int		false	"Page number (default 1)"
+//	@Param			limit	query		int		false	"Items per page (default 10, max 100)"
+//	@Success		200		{object}	dto.AnnouncementListResponse
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/announcements [get]
+func (ac *AnnouncementController) GetAnnouncements(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	page := 1
+	limit := 10
+	if p := c.Query("page"); p != "" {
+		if val, err := strconv.Atoi(p); err == nil {
+			page = val
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil {
+			limit = val
+		}
+	}
+
+	resp, err := ac.announcementService.GetAnnouncements(userID, c.Param("id"), page, limit)
+	if err != nil {
+		handleAnnouncementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetAnnouncement
+//
+//	@Summary	Get an announcement
+//	@Security	Bearer
+//	@Produce	json
+//	@Param		id				path		string	true	"Team ID"
+//	@Param		announcementId	path		string	true	"Announcement ID"
+//	@Success	200				{object}	dto.AnnouncementResponse
+//	@Failure	401				{object}	map[string]string
+//	@Failure	403				{object}	map[string]string
+//	@Failure	404				{object}	map[string]string
+//	@Failure	500				{object}	map[string]string
+//	@Router		/teams/{id}/announcements/{announcementId} [get]
+func (ac *AnnouncementController) GetAnnouncement(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := ac.announcementService.GetAnnouncement(userID, c.Param("id"), c.Param("announcementId"))
+	if err != nil {
+		handleAnnouncementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateAnnouncement
+//
+//	@Summary		Edit an announcement
+//	@Description	Team admins only. Replaces the title, body and attachment; the pin and the acknowledgements are kept.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string					true	"Team ID"
+//	@Param			announcementId	path		string					true	"Announcement ID"
+//	@Param			request			body		dto.AnnouncementRequest	true	"Announcement"
+//	@Success		200				{object}	dto.AnnouncementResponse
+//	@Failure		400				{object}	map[string]string
+//	@Failure		401				{object}	map[string]string
+//	@Failure		403				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/teams/{id}/announcements/{announcementId} [put]
+func (ac *AnnouncementController) UpdateAnnouncement(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.AnnouncementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := ac.announcementService.UpdateAnnouncement(userID, c.Param("id"), c.Param("announcementId"), &request)
+	if err != nil {
+		handleAnnouncementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteAnnouncement
+//
+//	@Summary		Delete an announcement
+//	@Description	Team admins only
+//	@Security		Bearer
+//	@Param			id				path	string	true	"Team ID"
+//	@Param			announcementId	path	string	true	"Announcement ID"
+//	@Success		204
+//	@Failure		401	{object}	map[string]string
+//	@Failure		403	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		409	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/teams/{id}/announcements/{announcementId} [delete]
+func (ac *AnnouncementController) DeleteAnnouncement(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	if err := ac.announcementService.DeleteAnnouncement(userID, c.Param("id"), c.Param("announcementId")); err != nil {
+		handleAnnouncementError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PinAnnouncement
+//
+//	@Summary		Pin an announcement
+//	@Description	Team admins only
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id				path		string	true	"Team ID"
+//	@Param			announcementId	path		string	true	"Announcement ID"
+//	@Success		200				{object}	dto.AnnouncementResponse
+//	@Failure		401				{object}	map[string]string
+//	@Failure		403				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/teams/{id}/announcements/{announcementId}/pin [post]
+func (ac *AnnouncementController) PinAnnouncement(c *gin.Context) {
+	ac.setAnnouncementPinned(c, true)
+}
+
+// UnpinAnnouncement
+//
+//	@Summary		Unpin an announcement
+//	@Description	Team admins only
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id				path		string	true	"Team ID"
+//	@Param			announcementId	path		string	true	"Announcement ID"
+//	@Success		200				{object}	dto.AnnouncementResponse
+//	@Failure		401				{object}	map[string]string
+//	@Failure		403				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/teams/{id}/announcements/{announcementId}/pin [delete]
+func (ac *AnnouncementController) UnpinAnnouncement(c *gin.Context) {
+	ac.setAnnouncementPinned(c, false)
+}
+
+func (ac *AnnouncementController) setAnnouncementPinned(c *gin.Context, pinned bool) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := ac.announcementService.SetAnnouncementPinned(userID, c.Param("id"), c.Param("announcementId"), pinned)
+	if err != nil {
+		handleAnnouncementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AcknowledgeAnnouncement
+//
+//	@Summary		Acknowledge an announcement
+//	@Description	Records that the caller has read the announcement
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id				path		string	true	"Team ID"
+//	@Param			announcementId	path		string	true	"Announcement ID"
+//	@Success		200				{object}	dto.AnnouncementResponse
+//	@Failure		401				{object}	map[string]string
+//	@Failure		403				{object}	map[string]string
+//	@Failure		404				{object}	map[string]string
+//	@Failure		409				{object}	map[string]string
+//	@Failure		500				{object}	map[string]string
+//	@Router			/teams/{id}/announcements/{announcementId}/ack [post]
+func (ac *AnnouncementController) AcknowledgeAnnouncement(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := ac.announcementService.Acknowledge(userID, c.Param("id"), c.Param("announcementId"))
+	if err != nil {
+		handleAnnouncementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// PinMessage
+//
+//	@Summary		Pin a message of the team chat
+//	@Description	Team admins only. Members are notified over the message WebSocket as "message_pin_updated".
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id			path		string	true	"Team ID"
+//	@Param			messageId	path		string	true	"Message ID"
+//	@Success		200			{object}	dto.MessageDTO
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/messages/{messageId}/pin [post]
+func (ac *AnnouncementController) PinMessage(c *gin.Context) {
+	ac.setMessagePinned(c, true)
+}
+
+// UnpinMessage
+//
+//	@Summary		Unpin a message of the team chat
+//	@Description	Team admins only
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id			path		string	true	"Team ID"
+//	@Param			messageId	path		string	true	"Message ID"
+//	@Success		200			{object}	dto.MessageDTO
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/messages/{messageId}/pin [delete]
+func (ac *AnnouncementController) UnpinMessage(c *gin.Context) {
+	ac.setMessagePinned(c, false)
+}
+
+func (ac *AnnouncementController) setMessagePinned(c *gin.Context, pinned bool) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	message, err := ac.announcementService.SetMessagePinned(userID, c.Param("id"), c.Param("messageId"), pinned)
+	if err != nil {
+		handleAnnouncementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, message)
+}
+
+// GetPins
+//
+//	@Summary		Get what is pinned in a team
+//	@Description	The pinned announcements and chat messages, most recently pinned first
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id	path		string	true	"Team ID"
+//	@Success		200	{object}	dto.TeamPinsResponse
+//	@Failure		401	{object}	map[string]string
+//	@Failure		403	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/teams/{id}/pins [get]
+func (ac *AnnouncementController) GetPins(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := ac.announcementService.GetPins(userID, c.Param("id"))
+	if err != nil {
+		handleAnnouncementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func handleAnnouncementError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
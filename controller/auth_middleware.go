@@ -20,39 +20,47 @@ import (
 //	@Router			/auth/middleware [post]
 func JWTAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// expected: "Bearer <token>" (HTTP) or "?token=<token>" (WebSocket)
-		var tokenString string
-
-		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-				tokenString = authHeader[7:]
-			} else {
-				tokenString = authHeader
-			}
-		} else {
-			// It might be a WebSocket request
-			if c.GetHeader("Upgrade") == "websocket" {
-				tokenString = c.Query("token")
-			}
-		}
-
-		if tokenString == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		if !authenticate(c) {
 			return
 		}
 
-		claims, err := config.ValidateJWT(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
-			c.Abort()
-			return
+		c.Next()
+	}
+}
+
+// authenticate stores the claims of the request's token in the context, or aborts the request with 401
+func authenticate(c *gin.Context) bool {
+	// expected: "Bearer <token>" (HTTP) or "?token=<token>" (WebSocket)
+	var tokenString string
+
+	authHeader := c.GetHeader("Authorization")
+	if authHeader != "" {
+		if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
+			tokenString = authHeader[7:]
+		} else {
+			tokenString = authHeader
 		}
+	} else {
+		// It might be a WebSocket request
+		if c.GetHeader("Upgrade") == "websocket" {
+			tokenString = c.Query("token")
+		}
+	}
 
-		c.Set("userClaims", claims)
+	if tokenString == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		return false
+	}
 
-		c.Next()
+	claims, err := config.ValidateJWT(tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+		c.Abort()
+		return false
 	}
+
+	c.Set("userClaims", claims)
+	return true
 }
 
 // RequireOwner ensures the authenticated subject matches the provided path parameter
@@ -103,3 +111,57 @@ func RequireOwner(paramName string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireAdmin ensures the authenticated user is a platform administrator
+//
+//	@Summary		Admin Authorization Middleware
+//	@Description	Middleware to ensure the authenticated user is a platform administrator
+//	@Security		Bearer
+//	@Success		200	{string}	string				"User is an administrator"
+//	@Failure		403	{object}	map[string]string	"Forbidden"
+//	@Router			/auth/admin [post]
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requireAdmin(c) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireAdminForQuery lets only authenticated administrators set the query parameter to true, for the options of
+// public endpoints that are too costly for everyone
+func RequireAdminForQuery(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Query(param) == "true" && (!authenticate(c) || !requireAdmin(c)) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// requireAdmin aborts the request with 403 unless the authenticated user is a platform administrator
+func requireAdmin(c *gin.Context) bool {
+	claimsI, ok := c.Get("userClaims")
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		c.Abort()
+		return false
+	}
+
+	claims, ok := claimsI.(jwt.MapClaims)
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		c.Abort()
+		return false
+	}
+
+	if isAdmin, ok := claims["admin"].(bool); !ok || !isAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		c.Abort()
+		return false
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const calendarFeedPath = "/calendar/ical/"
+
+type CalendarController struct {
+	calendarService service.CalendarServiceInterface
+}
+
+func NewCalendarController() *CalendarController {
+	return &CalendarController{
+		calendarService: service.NewCalendarService(),
+	}
+}
+
+func NewCalendarControllerWithService(calendarService service.CalendarServiceInterface) *CalendarController {
+	return &CalendarController{
+		calendarService: calendarService,
+	}
+}
+
+// CreateFeed
+//
+//	@Summary		Create a calendar feed URL
+//	@Description	Returns a secret iCalendar URL with the study sessions of all the caller's teams, or of one team when teamId is set. Calendar apps subscribe to it without a bearer token. The URL is only shown once; creating a feed again revokes the previous URL for the same teams.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.CalendarFeedRequest	false	"Team of the feed"
+//	@Success		201		{object}	dto.CalendarFeedResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/calendar/feeds [post]
+func (cc *CalendarController) CreateFeed(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.CalendarFeedRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	resp, err := cc.calendarService.CreateFeed(userID, &request)
+	if err != nil {
+		handleCalendarError(c, err)
+		return
+	}
+	resp.URL = feedBaseURL(c) + calendarFeedPath + resp.Token + ".ics"
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetFeeds
+//
+//	@Summary		List the caller's calendar feeds
+//	@Description	The secret URLs are not returned again
+//	@Security		Bearer
+//	@Produce		json
+//	@Success		200	{array}		dto.CalendarFeedResponse
+//	@Failure		401	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/calendar/feeds [get]
+func (cc *CalendarController) GetFeeds(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	feeds, err := cc.calendarService.GetFeeds(userID)
+	if err != nil {
+		handleCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feeds)
+}
+
+// RevokeFeed
+//
+//	@Summary	Revoke a calendar feed URL
+//	@Security	Bearer
+//	@Param		id	path	string	true	"Feed ID"
+//	@Success	204
+//	@Failure	401	{object}	map[string]string
+//	@Failure	404	{object}	map[string]string
+//	@Failure	500	{object}	map[string]string
+//	@Router		/calendar/feeds/{id} [delete]
+func (cc *CalendarController) RevokeFeed(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	if err := cc.calendarService.RevokeFeed(userID, c.Param("id")); err != nil {
+		handleCalendarError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetFeedCalendar
+//
+//	@Summary		Get a calendar feed
+//	@Description	RFC 5545 iCalendar with the study sessions of the feed, including recurrences, timezones and reminders. The token in the URL is the only credential.
+//	@Produce		text/calendar
+//	@Param			token	path		string	true	"Feed token, optionally followed by .ics"
+//	@Success		200		{string}	string
+//	@Failure		404		{string}	string
+//	@Router			/calendar/ical/{token} [get]
+func (cc *CalendarController) GetFeedCalendar(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	calendar, err := cc.calendarService.GetFeedCalendar(token)
+	if err != nil {
+		if errors.Is(err, service.ErrResourceNotFound) {
+			c.String(http.StatusNotFound, "calendar feed not found")
+			return
+		}
+		c.String(http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	// Calendar apps poll the feed, changed sessions have to show up on the next poll
+	c.Header("Cache-Control", "no-cache, private")
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar))
+}
+
+// feedBaseURL returns the scheme and host the client reached the API on
+func feedBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+func handleCalendarError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
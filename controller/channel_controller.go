@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gin-gonic/gin"
+)
+
+type ChannelController struct {
+	channelService service.ChannelServiceInterface
+	teamService    TeamServiceInterface
+	hub            *hub.Hub[hub.Message]
+}
+
+func NewChannelController() *ChannelController {
+	return &ChannelController{
+		channelService: service.NewChannelService(),
+		teamService:    service.NewTeamService(),
+		hub:            service.GetMessageHub(),
+	}
+}
+
+func NewChannelControllerWithService(channelService service.ChannelServiceInterface, teamService TeamServiceInterface) *ChannelController {
+	return &ChannelController{
+		channelService: channelService,
+		teamService:    teamService,
+		hub:            hub.NewHub[hub.Message](),
+	}
+}
+
+// GetChannels
+//
+//	@Summary		List the channels of a team
+//	@Description	The default channel comes first, the others are sorted by name. Archived channels are hidden unless requested.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id			path		string	true	"Team ID"
+//	@Param			archived	query		bool	false	"Include archived channels"
+//	@Success		200			{array}		entity.Channel
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/channels [get]
+func (cc *ChannelController) GetChannels(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	channels, err := cc.channelService.GetChannels(userID, c.Param("id"), c.Query("archived") == "true")
+	if err != nil {
+		handleChannelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, channels)
+}
+
+// CreateChannel
+//
+//	@Summary		Create a channel in a team
+//	@Description	Names are lowercased and a leading "#" is dropped, so "#Exam-Prep" becomes "exam-prep"
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"Team ID"
+//	@Param			request	body		dto.ChannelRequest	true	"Channel"
+//	@Success		201		{object}	entity.Channel
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/channels [post]
+func (cc *ChannelController) CreateChannel(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.ChannelRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channel, err := cc.channelService.CreateChannel(userID, c.Param("id"), &request)
+	if err != nil {
+		handleChannelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, channel)
+
+	cc.broadcast(channel.TeamID, hub.NewMessage(hub.ChannelCreated, channel))
+}
+
+// UpdateChannel
+//
+//	@Summary		Rename, archive or restore a channel
+//	@Description	Team admins only. Archived channels keep their history but accept no new messages. The default channel can not be archived.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string						true	"Team ID"
+//	@Param			channelId	path		string						true	"Channel ID"
+//	@Param			request		body		dto.UpdateChannelRequest	true	"Channel changes"
+//	@Success		200			{object}	entity.Channel
+//	@Failure		400			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/channels/{channelId} [put]
+func (cc *ChannelController) UpdateChannel(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.UpdateChannelRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channel, err := cc.channelService.UpdateChannel(userID, c.Param("id"), c.Param("channelId"), &request)
+	if err != nil {
+		handleChannelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, channel)
+
+	cc.broadcast(channel.TeamID, hub.NewMessage(hub.ChannelUpdated, channel))
+}
+
+// GetChannelMessages
+//
+//	@Summary	Get the message history of a channel
+//	@Security	Bearer
+//	@Produce	json
+//	@Param		id			path		string	true	"Team ID"
+//	@Param		channelId	path		string	true	"Channel ID"
+//	@Success	200			{array}		dto.MessageDTO
+//	@Failure	401			{object}	map[string]string
+//	@Failure	403			{object}	map[string]string
+//	@Failure	404			{object}	map[string]string
+//	@Failure	500			{object}	map[string]string
+//	@Router		/teams/{id}/channels/{channelId}/messages [get]
+func (cc *ChannelController) GetChannelMessages(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	messages, err := cc.channelService.GetChannelMessages(userID, c.Param("id"), c.Param("channelId"))
+	if err != nil {
+		handleChannelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, messages)
+}
+
+// NewChannelMessage
+//
+//	@Summary		Send a message to a channel
+//	@Description	The message is pushed to the team members over the message WebSocket as a "team_message"
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string						true	"Team ID"
+//	@Param			channelId	path		string						true	"Channel ID"
+//	@Param			request		body		dto.ChannelMessageRequest	true	"Message"
+//	@Success		201			{object}	dto.MessageDTO
+//	@Failure		400			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/channels/{channelId}/messages [post]
+func (cc *ChannelController) NewChannelMessage(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.ChannelMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	message, err := cc.channelService.CreateChannelMessage(userID, c.Param("id"), c.Param("channelId"), &request)
+	if err != nil {
+		handleChannelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+
+	cc.broadcast(message.TeamID, hub.NewMessage(hub.TeamBroadcast, message))
+}
+
+func (cc *ChannelController) broadcast(teamID string, msg *hub.Message) {
+	team, err := cc.teamService.GetTeamById(teamID)
+	if err != nil {
+		return
+	}
+	cc.hub.SendMany(team.UsersIds, *msg)
+}
+
+func handleChannelError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ConversationController struct {
+	conversationService service.ConversationServiceInterface
+}
+
+func NewConversationController() *ConversationController {
+	return &ConversationController{
+		conversationService: service.NewConversationService(),
+	}
+}
+
+func NewConversationControllerWithService(conversationService service.ConversationServiceInterface) *ConversationController {
+	return &ConversationController{
+		conversationService: conversationService,
+	}
+}
+
+// GetConversations
+//
+//	@Summary		Get the caller's inbox
+//	@Description	The caller's direct conversations and the channels of their teams, the most recently active first, with the last message, the other user or team and the unread count
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			limit	query		int		false	"Page size (default 20, at most 100)"
+//	@Param			cursor	query		string	false	"The nextCursor of the previous page"
+//	@Success		200		{object}	dto.ConversationsResponse
+//	@Failure		400		{object}	map[string]interface{}	"Invalid limit or cursor"
+//	@Failure		401		{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		404		{object}	map[string]interface{}	"User not found"
+//	@Failure		500		{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/conversations [get]
+func (cc *ConversationController) GetConversations(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err = strconv.Atoi(limitStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+			return
+		}
+	}
+
+	conversations, err := cc.conversationService.GetConversations(userID, c.Query("cursor"), limit)
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, conversations)
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -41,6 +42,7 @@ type FileServiceInterface interface {
 //	@Success	201		{object}	dto.FileUploadResponse
 //	@Failure	400		{object}	map[string]string
 //	@Failure	403		{object}	map[string]string
+//	@Failure	409		{object}	map[string]string
 //	@Failure	500		{object}	map[string]string
 //	@Router		/teams/{id}/files [post]
 func (fc *FileController) UploadFile(c *gin.Context) {
@@ -72,6 +74,10 @@ func (fc *FileController) UploadFile(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
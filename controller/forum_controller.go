@@ -0,0 +1,479 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gin-gonic/gin"
+)
+
+type ForumController struct {
+	forumService service.ForumServiceInterface
+}
+
+func NewForumController() *ForumController {
+	return &ForumController{
+		forumService: service.NewForumService(),
+	}
+}
+
+func NewForumControllerWithService(forumService service.ForumServiceInterface) *ForumController {
+	return &ForumController{
+		forumService: forumService,
+	}
+}
+
+// AskQuestion
+//
+//	@Summary		Ask a question on the team's Q&A board
+//	@Description	Members only. Up to 5 tags; tags are lowercased and spaces become dashes.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Team ID"
+//	@Param			request	body		dto.ForumQuestionRequest	true	"Question"
+//	@Success		201		{object}	dto.ForumQuestionResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/questions [post]
+func (fc *ForumController) AskQuestion(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.ForumQuestionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := fc.forumService.AskQuestion(userID, c.Param("id"), &request)
+	if err != nil {
+		handleForumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetQuestions
+//
+//	@Summary		List the questions of a team
+//	@Description	sort is "recent" (latest activity first, default), "votes" or "unanswered" (only questions without answers). q matches every word against the title, body and tags.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			sort	query		string	false	"recent, votes or unanswered"
+//	@Param			tag		query		string	false	"Only questions with this tag"
+//	@Param			q		query		string	false	"Search text"
+//	@Param			page	query		int		false	"Page number (default 1)"
+//	@Param			limit	query		int		false	"Items per page (default 10, max 100)"
+//	@Success		200		{object}	dto.ForumQuestionListResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/questions [get]
+func (fc *ForumController) GetQuestions(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	page := 1
+	limit := 10
+	if p := c.Query("page"); p != "" {
+		if val, err := strconv.Atoi(p); err == nil {
+			page = val
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil {
+			limit = val
+		}
+	}
+
+	resp, err := fc.forumService.GetQuestions(userID, c.Param("id"), c.Query("sort"), c.Query("tag"), c.Query("q"), page, limit)
+	if err != nil {
+		handleForumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetQuestion
+//
+//	@Summary		Get a question with its answers
+//	@Description	The accepted answer comes first, then the answers by score and age.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id			path		string	true	"Team ID"
+//	@Param			questionId	path		string	true	"Question ID"
+//	@Success		200			{object}	dto.ForumQuestionDetailsResponse
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/questions/{questionId} [get]
+func (fc *ForumController) GetQuestion(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := fc.forumService.GetQuestion(userID, c.Param("id"), c.Param("questionId"))
+	if err != nil {
+		handleForumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateQuestion
+//
+//	@Summary		Edit a question
+//	@Description	Only the asker can edit a question.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string						true	"Team ID"
+//	@Param			questionId	path		string						true	"Question ID"
+//	@Param			request		body		dto.ForumQuestionRequest	true	"Question"
+//	@Success		200			{object}	dto.ForumQuestionResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/questions/{questionId} [put]
+func (fc *ForumController) UpdateQuestion(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.ForumQuestionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := fc.forumService.UpdateQuestion(userID, c.Param("id"), c.Param("questionId"), &request)
+	if err != nil {
+		handleForumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteQuestion
+//
+//	@Summary		Delete a question and its answers
+//	@Description	The asker or a team admin.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id			path		string	true	"Team ID"
+//	@Param			questionId	path		string	true	"Question ID"
+//	@Success		200			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/questions/{questionId} [delete]
+func (fc *ForumController) DeleteQuestion(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	if err := fc.forumService.DeleteQuestion(userID, c.Param("id"), c.Param("questionId")); err != nil {
+		handleForumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Question deleted successfully"})
+}
+
+// VoteQuestion
+//
+//	@Summary		Vote on a question
+//	@Description	value is 1 (up), -1 (down) or 0 (remove the vote). Members can not vote on their own questions.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string			true	"Team ID"
+//	@Param			questionId	path		string			true	"Question ID"
+//	@Param			request		body		dto.VoteRequest	true	"Vote"
+//	@Success		200			{object}	dto.ForumQuestionResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/questions/{questionId}/vote [put]
+func (fc *ForumController) VoteQuestion(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.VoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := fc.forumService.VoteQuestion(userID, c.Param("id"), c.Param("questionId"), &request)
+	if err != nil {
+		handleForumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AddAnswer
+//
+//	@Summary	Answer a question
+//	@Security	Bearer
+//	@Accept		json
+//	@Produce	json
+//	@Param		id			path		string					true	"Team ID"
+//	@Param		questionId	path		string					true	"Question ID"
+//	@Param		request		body		dto.ForumAnswerRequest	true	"Answer"
+//	@Success	201			{object}	dto.ForumAnswerResponse
+//	@Failure	400			{object}	map[string]string
+//	@Failure	401			{object}	map[string]string
+//	@Failure	403			{object}	map[string]string
+//	@Failure	404			{object}	map[string]string
+//	@Failure	409			{object}	map[string]string
+//	@Failure	500			{object}	map[string]string
+//	@Router		/teams/{id}/questions/{questionId}/answers [post]
+func (fc *ForumController) AddAnswer(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.ForumAnswerRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := fc.forumService.AddAnswer(userID, c.Param("id"), c.Param("questionId"), &request)
+	if err != nil {
+		handleForumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// UpdateAnswer
+//
+//	@Summary		Edit an answer
+//	@Description	Only the author can edit an answer.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string					true	"Team ID"
+//	@Param			questionId	path		string					true	"Question ID"
+//	@Param			answerId	path		string					true	"Answer ID"
+//	@Param			request		body		dto.ForumAnswerRequest	true	"Answer"
+//	@Success		200			{object}	dto.ForumAnswerResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/questions/{questionId}/answers/{answerId} [put]
+func (fc *ForumController) UpdateAnswer(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.ForumAnswerRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := fc.forumService.UpdateAnswer(userID, c.Param("id"), c.Param("questionId"), c.Param("answerId"), &request)
+	if err != nil {
+		handleForumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteAnswer
+//
+//	@Summary		Delete an answer
+//	@Description	The author or a team admin. Deleting the accepted answer leaves the question without one.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id			path		string	true	"Team ID"
+//	@Param			questionId	path		string	true	"Question ID"
+//	@Param			answerId	path		string	true	"Answer ID"
+//	@Success		200			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/questions/{questionId}/answers/{answerId} [delete]
+func (fc *ForumController) DeleteAnswer(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	if err := fc.forumService.DeleteAnswer(userID, c.Param("id"), c.Param("questionId"), c.Param("answerId")); err != nil {
+		handleForumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Answer deleted successfully"})
+}
+
+// VoteAnswer
+//
+//	@Summary		Vote on an answer
+//	@Description	value is 1 (up), -1 (down) or 0 (remove the vote). Members can not vote on their own answers.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string			true	"Team ID"
+//	@Param			questionId	path		string			true	"Question ID"
+//	@Param			answerId	path		string			true	"Answer ID"
+//	@Param			request		body		dto.VoteRequest	true	"Vote"
+//	@Success		200			{object}	dto.ForumAnswerResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/questions/{questionId}/answers/{answerId}/vote [put]
+func (fc *ForumController) VoteAnswer(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.VoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := fc.forumService.VoteAnswer(userID, c.Param("id"), c.Param("questionId"), c.Param("answerId"), &request)
+	if err != nil {
+		handleForumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AcceptAnswer
+//
+//	@Summary		Accept an answer
+//	@Description	The asker or a team admin. Replaces the previously accepted answer.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id			path		string	true	"Team ID"
+//	@Param			questionId	path		string	true	"Question ID"
+//	@Param			answerId	path		string	true	"Answer ID"
+//	@Success		200			{object}	dto.ForumQuestionResponse
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/questions/{questionId}/answers/{answerId}/accept [post]
+func (fc *ForumController) AcceptAnswer(c *gin.Context) {
+	fc.setAcceptedAnswer(c, true)
+}
+
+// UnacceptAnswer
+//
+//	@Summary		Remove the accepted mark from an answer
+//	@Description	The asker or a team admin.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id			path		string	true	"Team ID"
+//	@Param			questionId	path		string	true	"Question ID"
+//	@Param			answerId	path		string	true	"Answer ID"
+//	@Success		200			{object}	dto.ForumQuestionResponse
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/questions/{questionId}/answers/{answerId}/accept [delete]
+func (fc *ForumController) UnacceptAnswer(c *gin.Context) {
+	fc.setAcceptedAnswer(c, false)
+}
+
+func (fc *ForumController) setAcceptedAnswer(c *gin.Context, accepted bool) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := fc.forumService.SetAcceptedAnswer(userID, c.Param("id"), c.Param("questionId"), c.Param("answerId"), accepted)
+	if err != nil {
+		handleForumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func handleForumError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
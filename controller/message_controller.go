@@ -1,13 +1,16 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,6 +18,7 @@ const (
 	BadMessageTypeError  = "message type must be direct or team"
 	MessageNotFoundError = "message not found"
 	MissingParameter     = "missing parameter(s)"
+	InvalidSinceError    = "since must be a sequence number"
 )
 
 type MessageRequestUnion struct {
@@ -25,6 +29,7 @@ type MessageRequestUnion struct {
 type MessageController struct {
 	messageService service.MessageServiceInterface
 	teamService    TeamServiceInterface
+	socketService  service.MessageSocketServiceInterface
 	hub            *hub.Hub[hub.Message]
 }
 
@@ -32,7 +37,8 @@ func NewMessageController() *MessageController {
 	return &MessageController{
 		messageService: service.NewMessageService(),
 		teamService:    service.NewTeamService(),
-		hub:            hub.NewHub[hub.Message](),
+		socketService:  service.NewMessageSocketService(),
+		hub:            service.GetMessageHub(),
 	}
 }
 
@@ -44,13 +50,15 @@ func NewMessageControllerWithService(messageService service.MessageServiceInterf
 
 // Connect
 //
-//	@Summary	Connect the user to the message WebSocket
-//	@Security	Bearer
-//	@Success	101	{string}	string					"Switching Protocols - WebSocket connection established"
-//	@Failure	400	{object}	map[string]interface{}	"Bad Request"
-//	@Failure	401	{object}	map[string]string		"Unauthorized"
-//	@Failure	500	{object}	map[string]interface{}	"Internal Server Error"
-//	@Router		/messages/connect [get]
+//	@Summary		Connect the user to the message WebSocket
+//	@Description	The connection gets the user's events and takes commands: send_message, edit, delete, typing and mark_read. Commands with a requestId are answered with an ack carrying the stored message, failed ones with an error, on that connection only. Each of the user's connections gets the events. Events carry the user's sequence number seq; with since, the events after it are replayed before a connected event and the live ones.
+//	@Security		Bearer
+//	@Param			since	query		int		false	"The seq of the last event received, to get the missed ones"
+//	@Success		101	{string}	string					"Switching Protocols - WebSocket connection established"
+//	@Failure		400	{object}	map[string]interface{}	"Bad Request"
+//	@Failure		401	{object}	map[string]string		"Unauthorized"
+//	@Failure		500	{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/messages/connect [get]
 func (mc *MessageController) Connect(c *gin.Context) {
 	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
@@ -59,20 +67,27 @@ func (mc *MessageController) Connect(c *gin.Context) {
 		return
 	}
 
+	var since int64
+	if s := c.Query("since"); s != "" {
+		if since, err = strconv.ParseInt(s, 10, 64); err != nil || since < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": InvalidSinceError})
+			return
+		}
+	}
+
 	conn, err := hub.AcceptConnection(c)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	client := hub.NewClient[hub.Message](userID, conn)
-	mc.hub.Register(client)
+	mc.socketService.Connect(userID, since, conn)
 }
 
 // NewMessage
 //
 //	@Summary		Create and send a message
-//	@Description	Create and send a message either to another user or to a team
+//	@Description	Create and send a message either to another user or to a team. The sender is always the logged in user; senderId in the body is ignored. With parentId the message is a reply in that message's thread and the conversation also gets a thread_updated event.
 //	@Security		Bearer
 //	@Accept			json
 //	@Produce		json
@@ -80,9 +95,18 @@ func (mc *MessageController) Connect(c *gin.Context) {
 //	@Param			request	body		MessageRequestUnion	true	"The message request (this is only for documentation purposes, the actual request should be either DirectMessageRequest or TeamMessageRequest)"
 //	@Success		201		{object}	dto.MessageDTO
 //	@Failure		400		{object}	map[string]interface{}	"Bad Request"
+//	@Failure		403		{object}	map[string]interface{}	"Not a member of the team or muted in it"
+//	@Failure		404		{object}	map[string]interface{}	"Channel or parent message not found"
+//	@Failure		409		{object}	map[string]interface{}	"Channel is archived or parent message deleted"
 //	@Failure		500		{object}	map[string]interface{}	"Internal Server Error"
 //	@Router			/messages [post]
 func (mc *MessageController) NewMessage(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		// This should never happen if the auth middleware is used
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 	message_type := c.Query("type")
 
 	switch message_type {
@@ -92,10 +116,12 @@ func (mc *MessageController) NewMessage(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		// The sender is whoever is logged in, whatever the body says
+		request.SenderID = userID
 
 		resp, err := mc.messageService.CreateDirectMessage(&request)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			handleMessageError(c, err)
 			return
 		}
 
@@ -111,10 +137,11 @@ func (mc *MessageController) NewMessage(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		request.SenderID = userID
 
 		resp, err := mc.messageService.CreateTeamMessage(&request)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			handleMessageError(c, err)
 			return
 		}
 
@@ -137,16 +164,28 @@ func (mc *MessageController) NewMessage(c *gin.Context) {
 //	@Produce	json
 //	@Param		id	path		string	true	"The message ID"
 //	@Success	200	{object}	dto.MessageDTO
+//	@Failure	401	{object}	map[string]interface{}
+//	@Failure	403	{object}	map[string]interface{}
 //	@Failure	404	{object}	map[string]interface{}
 //	@Failure	500	{object}	map[string]interface{}
 //	@Router		/messages/{id} [get]
 func (mc *MessageController) GetMessage(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
 	id := c.Param("id")
-	message, err := mc.messageService.GetMessageByID(id)
-	if err.Error() == entity.BadConversationKey {
+	message, err := mc.messageService.GetMessageByID(userID, id)
+	if err != nil && err.Error() == entity.BadConversationKey {
 		c.JSON(http.StatusInternalServerError, gin.H{"Error": err.Error()})
 		return
 	}
+	if errors.Is(err, service.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": MessageNotFoundError})
 		return
@@ -157,8 +196,8 @@ func (mc *MessageController) GetMessage(c *gin.Context) {
 
 // GetMessages
 //
-//	@Summary		Get all messages
-//	@Description	Get messages between 2 users or within a team
+//	@Summary		Get messages
+//	@Description	Get messages between 2 users or within a team. Without limit or a cursor the whole history is returned as an array, otherwise a dto.MessagePageResponse page of it, oldest first. before/after take a message ID or an RFC 3339 timestamp, around takes a message ID and returns it with its context; use prevCursor as before and nextCursor as after to keep paging.
 //	@Security		Bearer
 //	@Accept			json
 //	@Produce		json
@@ -166,11 +205,30 @@ func (mc *MessageController) GetMessage(c *gin.Context) {
 //	@Param			user1Id	query		string	false	"User1 ID (direct message)"
 //	@Param			user2Id	query		string	false	"User2 ID (direct message)"
 //	@Param			teamId	query		string	false	"Team ID (team message)"
+//	@Param			limit	query		int		false	"Page size, 50 by default, at most 100"
+//	@Param			before	query		string	false	"Messages before this message ID or timestamp"
+//	@Param			after	query		string	false	"Messages after this message ID or timestamp"
+//	@Param			around	query		string	false	"Messages around this message ID, including it"
 //	@Success		200		{array}		dto.MessageDTO
 //	@Failure		400		{object}	map[string]interface{}	"Bad Request"
+//	@Failure		401		{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403		{object}	map[string]interface{}	"Not in the conversation or team"
+//	@Failure		404		{object}	map[string]interface{}	"Cursor message not found"
 //	@Failure		500		{object}	map[string]interface{}	"Internal Server Error"
 //	@Router			/messages [get]
 func (mc *MessageController) GetMessages(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var query dto.MessagePageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	message_type := c.Query("type")
 
 	switch message_type {
@@ -183,9 +241,19 @@ func (mc *MessageController) GetMessages(c *gin.Context) {
 			return
 		}
 
-		resp, err := mc.messageService.GetDirectMessages(user1Id, user2Id)
+		if query.IsSet() {
+			resp, err := mc.messageService.GetDirectMessagePage(userID, user1Id, user2Id, &query)
+			if err != nil {
+				handleMessageError(c, err)
+				return
+			}
+			c.JSON(http.StatusOK, resp)
+			return
+		}
+
+		resp, err := mc.messageService.GetDirectMessages(userID, user1Id, user2Id)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			handleMessageError(c, err)
 			return
 		}
 
@@ -197,9 +265,19 @@ func (mc *MessageController) GetMessages(c *gin.Context) {
 			return
 		}
 
-		resp, err := mc.messageService.GetTeamMessages(teamId)
+		if query.IsSet() {
+			resp, err := mc.messageService.GetTeamMessagePage(userID, teamId, &query)
+			if err != nil {
+				handleMessageError(c, err)
+				return
+			}
+			c.JSON(http.StatusOK, resp)
+			return
+		}
+
+		resp, err := mc.messageService.GetTeamMessages(userID, teamId)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			handleMessageError(c, err)
 			return
 		}
 
@@ -209,10 +287,256 @@ func (mc *MessageController) GetMessages(c *gin.Context) {
 	}
 }
 
+// EditMessage
+//
+//	@Summary		Edit a message
+//	@Description	Sender only. The previous text is kept in the message's edit history and the conversation gets a message_edited event.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"The message ID"
+//	@Param			request	body		dto.EditMessageRequest	true	"The new text"
+//	@Success		200		{object}	dto.MessageDTO
+//	@Failure		400		{object}	map[string]interface{}	"Bad Request"
+//	@Failure		401		{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403		{object}	map[string]interface{}	"Not the sender, or muted in the team"
+//	@Failure		404		{object}	map[string]interface{}	"Message not found"
+//	@Failure		409		{object}	map[string]interface{}	"Message deleted or team archived"
+//	@Failure		500		{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/messages/{id} [patch]
 func (mc *MessageController) EditMessage(c *gin.Context) {
-	// TODO: implement
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.EditMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	message, err := mc.messageService.EditMessage(userID, c.Param("id"), &request)
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, message)
 }
 
+// DeleteMessage
+//
+//	@Summary		Delete a message
+//	@Description	Sender or team admins only. The message stays in the history as a tombstone without its text and the conversation gets a message_deleted event.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id	path		string	true	"The message ID"
+//	@Success		200	{object}	dto.MessageDTO
+//	@Failure		401	{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403	{object}	map[string]interface{}	"Not the sender or a team admin"
+//	@Failure		404	{object}	map[string]interface{}	"Message not found"
+//	@Failure		409	{object}	map[string]interface{}	"Team archived"
+//	@Failure		500	{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/messages/{id} [delete]
 func (mc *MessageController) DeleteMessage(c *gin.Context) {
-	// TODO: implement
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	message, err := mc.messageService.DeleteMessage(userID, c.Param("id"))
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, message)
+}
+
+// GetMessageHistory
+//
+//	@Summary		Get the edit history of a message
+//	@Description	Every version of the message's text, oldest first, the current one last. Participants of the conversation only.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id	path		string	true	"The message ID"
+//	@Success		200	{object}	dto.MessageHistoryResponse
+//	@Failure		401	{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403	{object}	map[string]interface{}	"Not a member of the team"
+//	@Failure		404	{object}	map[string]interface{}	"Message not found"
+//	@Failure		409	{object}	map[string]interface{}	"Message deleted"
+//	@Failure		500	{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/messages/{id}/history [get]
+func (mc *MessageController) GetMessageHistory(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	history, err := mc.messageService.GetMessageHistory(userID, c.Param("id"))
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetThread
+//
+//	@Summary		Get a message thread
+//	@Description	The thread's first message and its replies, oldest first. For a reply, the thread it belongs to. Participants of the conversation only.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id	path		string	true	"The message ID"
+//	@Success		200	{object}	dto.ThreadResponse
+//	@Failure		401	{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403	{object}	map[string]interface{}	"Not a member of the team"
+//	@Failure		404	{object}	map[string]interface{}	"Message not found"
+//	@Failure		500	{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/messages/{id}/thread [get]
+func (mc *MessageController) GetThread(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	thread, err := mc.messageService.GetThread(userID, c.Param("id"))
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
+
+// GetThreads
+//
+//	@Summary		List the threads of a conversation
+//	@Description	The messages of a direct conversation or team that have replies, the most recently active first
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			type		query		string	true	"Conversation type (direct/team)"
+//	@Param			userId		query		string	false	"The other user (direct)"
+//	@Param			teamId		query		string	false	"Team ID (team)"
+//	@Param			channelId	query		string	false	"Only the threads of this channel (team)"
+//	@Success		200			{array}		dto.MessageDTO
+//	@Failure		400			{object}	map[string]interface{}	"Bad Request"
+//	@Failure		401			{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403			{object}	map[string]interface{}	"Not a member of the team"
+//	@Failure		404			{object}	map[string]interface{}	"User, team or channel not found"
+//	@Failure		500			{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/messages/threads [get]
+func (mc *MessageController) GetThreads(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var threads []*dto.MessageDTO
+	switch c.Query("type") {
+	case "direct":
+		if c.Query("userId") == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": MissingParameter})
+			return
+		}
+		threads, err = mc.messageService.GetDirectThreads(userID, c.Query("userId"))
+	case "team":
+		if c.Query("teamId") == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": MissingParameter})
+			return
+		}
+		threads, err = mc.messageService.GetTeamThreads(userID, c.Query("teamId"), c.Query("channelId"))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": BadMessageTypeError})
+		return
+	}
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, threads)
+}
+
+// AddReaction
+//
+//	@Summary		React to a message
+//	@Description	Participants of the conversation only. A message has at most 20 different emoji; the conversation gets a message_reaction event.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"The message ID"
+//	@Param			emoji	path		string	true	"The emoji, URL encoded"
+//	@Success		200		{object}	dto.MessageDTO
+//	@Failure		400		{object}	map[string]interface{}	"Invalid emoji"
+//	@Failure		401		{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403		{object}	map[string]interface{}	"Not a member of the team"
+//	@Failure		404		{object}	map[string]interface{}	"Message not found"
+//	@Failure		409		{object}	map[string]interface{}	"Too many reactions, message deleted or team archived"
+//	@Failure		500		{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/messages/{id}/reactions/{emoji} [put]
+func (mc *MessageController) AddReaction(c *gin.Context) {
+	mc.setReaction(c, true)
+}
+
+// RemoveReaction
+//
+//	@Summary		Remove a reaction from a message
+//	@Description	Removes the caller's reaction with the emoji; the conversation gets a message_reaction event.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"The message ID"
+//	@Param			emoji	path		string	true	"The emoji, URL encoded"
+//	@Success		200		{object}	dto.MessageDTO
+//	@Failure		400		{object}	map[string]interface{}	"Invalid emoji"
+//	@Failure		401		{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403		{object}	map[string]interface{}	"Not a member of the team"
+//	@Failure		404		{object}	map[string]interface{}	"Message not found"
+//	@Failure		409		{object}	map[string]interface{}	"Message deleted or team archived"
+//	@Failure		500		{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/messages/{id}/reactions/{emoji} [delete]
+func (mc *MessageController) RemoveReaction(c *gin.Context) {
+	mc.setReaction(c, false)
+}
+
+func (mc *MessageController) setReaction(c *gin.Context, added bool) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var message *dto.MessageDTO
+	if added {
+		message, err = mc.messageService.AddReaction(userID, c.Param("id"), c.Param("emoji"))
+	} else {
+		message, err = mc.messageService.RemoveReaction(userID, c.Param("id"), c.Param("emoji"))
+	}
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, message)
+}
+
+func handleMessageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
@@ -0,0 +1,303 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationController struct {
+	moderationService service.ModerationServiceInterface
+}
+
+func NewModerationController() *ModerationController {
+	return &ModerationController{
+		moderationService: service.NewModerationService(),
+	}
+}
+
+func NewModerationControllerWithService(moderationService service.ModerationServiceInterface) *ModerationController {
+	return &ModerationController{
+		moderationService: moderationService,
+	}
+}
+
+// KickMember
+//
+//	@Summary		Kick a member out of a team
+//	@Description	Team admins only; only the owner can kick other admins. The member can join again right away.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Team ID"
+//	@Param			userId	path		string					true	"User ID"
+//	@Param			request	body		dto.ModerationRequest	false	"Reason"
+//	@Success		200		{object}	entity.ModerationLogEntry
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/moderation/kick/{userId} [post]
+func (mc *ModerationController) KickMember(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	request, ok := bindModerationRequest(c)
+	if !ok {
+		return
+	}
+
+	entry, err := mc.moderationService.Kick(userID, c.Param("id"), c.Param("userId"), request)
+	if err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// BanMember
+//
+//	@Summary		Ban a user from a team
+//	@Description	Team admins only; only the owner can ban other admins. Removes the user from the team and keeps them from joining it or its voice room. durationMinutes 0 bans permanently.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Team ID"
+//	@Param			userId	path		string					true	"User ID"
+//	@Param			request	body		dto.ModerationRequest	false	"Reason and duration"
+//	@Success		200		{object}	entity.TeamRestriction
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/moderation/ban/{userId} [post]
+func (mc *ModerationController) BanMember(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	request, ok := bindModerationRequest(c)
+	if !ok {
+		return
+	}
+
+	ban, err := mc.moderationService.Ban(userID, c.Param("id"), c.Param("userId"), request)
+	if err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ban)
+}
+
+// UnbanMember
+//
+//	@Summary		Lift a ban
+//	@Description	Team admins only
+//	@Security		Bearer
+//	@Param			id		path	string	true	"Team ID"
+//	@Param			userId	path	string	true	"User ID"
+//	@Success		204
+//	@Failure		401	{object}	map[string]string
+//	@Failure		403	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/teams/{id}/moderation/ban/{userId} [delete]
+func (mc *ModerationController) UnbanMember(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	if err := mc.moderationService.Unban(userID, c.Param("id"), c.Param("userId")); err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MuteMember
+//
+//	@Summary		Mute a member in the team chat
+//	@Description	Team admins only; only the owner can mute other admins. durationMinutes is required.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Team ID"
+//	@Param			userId	path		string					true	"User ID"
+//	@Param			request	body		dto.ModerationRequest	true	"Reason and duration"
+//	@Success		200		{object}	entity.TeamRestriction
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/moderation/mute/{userId} [post]
+func (mc *ModerationController) MuteMember(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	request, ok := bindModerationRequest(c)
+	if !ok {
+		return
+	}
+
+	mute, err := mc.moderationService.Mute(userID, c.Param("id"), c.Param("userId"), request)
+	if err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mute)
+}
+
+// UnmuteMember
+//
+//	@Summary		Lift a mute
+//	@Description	Team admins only
+//	@Security		Bearer
+//	@Param			id		path	string	true	"Team ID"
+//	@Param			userId	path	string	true	"User ID"
+//	@Success		204
+//	@Failure		401	{object}	map[string]string
+//	@Failure		403	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/teams/{id}/moderation/mute/{userId} [delete]
+func (mc *ModerationController) UnmuteMember(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	if err := mc.moderationService.Unmute(userID, c.Param("id"), c.Param("userId")); err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetRestrictions
+//
+//	@Summary		List the bans and mutes in effect in a team
+//	@Description	Team admins only
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id	path		string	true	"Team ID"
+//	@Success		200	{object}	dto.TeamRestrictionsResponse
+//	@Failure		401	{object}	map[string]string
+//	@Failure		403	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/teams/{id}/moderation [get]
+func (mc *ModerationController) GetRestrictions(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := mc.moderationService.GetRestrictions(userID, c.Param("id"))
+	if err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetModerationLog
+//
+//	@Summary		Get the moderation log of a team
+//	@Description	Team admins only. Newest first.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			page	query		int		false	"Page number (default 1)"
+//	@Param			limit	query		int		false	"Items per page (default 10, max 100)"
+//	@Success		200		{object}	dto.ModerationLogResponse
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/moderation/log [get]
+func (mc *ModerationController) GetModerationLog(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	page := 1
+	limit := 10
+	if p := c.Query("page"); p != "" {
+		if val, err := strconv.Atoi(p); err == nil {
+			page = val
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil {
+			limit = val
+		}
+	}
+
+	resp, err := mc.moderationService.GetModerationLog(userID, c.Param("id"), page, limit)
+	if err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// bindModerationRequest reads the optional body of a moderation action
+func bindModerationRequest(c *gin.Context) (*dto.ModerationRequest, bool) {
+	var request dto.ModerationRequest
+	if c.Request.ContentLength == 0 {
+		return &request, true
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return &request, true
+}
+
+func handleModerationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -0,0 +1,256 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gin-gonic/gin"
+)
+
+type NoteController struct {
+	noteService service.NoteServiceInterface
+}
+
+func NewNoteController() *NoteController {
+	return &NoteController{
+		noteService: service.NewNoteService(),
+	}
+}
+
+func NewNoteControllerWithService(noteService service.NoteServiceInterface) *NoteController {
+	return &NoteController{
+		noteService: noteService,
+	}
+}
+
+// CreateNote
+//
+//	@Summary		Create a collaborative note
+//	@Description	Members only. The note starts empty; its text is edited over the note WebSocket.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Team ID"
+//	@Param			request	body		dto.NoteRequest	true	"Note"
+//	@Success		201		{object}	dto.NoteResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/notes [post]
+func (nc *NoteController) CreateNote(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.NoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := nc.noteService.CreateNote(userID, c.Param("id"), &request)
+	if err != nil {
+		handleNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetNotes
+//
+//	@Summary		List the notes of a team
+//	@Description	The last changed first, without their text, with the members editing them now.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id	path		string	true	"Team ID"
+//	@Success		200	{array}		dto.NoteResponse
+//	@Failure		401	{object}	map[string]string
+//	@Failure		403	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/teams/{id}/notes [get]
+func (nc *NoteController) GetNotes(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := nc.noteService.GetNotes(userID, c.Param("id"))
+	if err != nil {
+		handleNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetNote
+//
+//	@Summary		Get a note with its current text
+//	@Description	Includes the changes not saved yet when the note is being edited.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			noteId	path		string	true	"Note ID"
+//	@Success		200		{object}	dto.NoteResponse
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/notes/{noteId} [get]
+func (nc *NoteController) GetNote(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := nc.noteService.GetNote(userID, c.Param("id"), c.Param("noteId"))
+	if err != nil {
+		handleNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RenameNote
+//
+//	@Summary	Rename a note
+//	@Security	Bearer
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string			true	"Team ID"
+//	@Param		noteId	path		string			true	"Note ID"
+//	@Param		request	body		dto.NoteRequest	true	"Note"
+//	@Success	200		{object}	dto.NoteResponse
+//	@Failure	400		{object}	map[string]string
+//	@Failure	401		{object}	map[string]string
+//	@Failure	403		{object}	map[string]string
+//	@Failure	404		{object}	map[string]string
+//	@Failure	409		{object}	map[string]string
+//	@Failure	500		{object}	map[string]string
+//	@Router		/teams/{id}/notes/{noteId} [put]
+func (nc *NoteController) RenameNote(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.NoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := nc.noteService.RenameNote(userID, c.Param("id"), c.Param("noteId"), &request)
+	if err != nil {
+		handleNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteNote
+//
+//	@Summary		Delete a note
+//	@Description	The creator of the note or a team admin. Members editing it get "note_deleted".
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			noteId	path		string	true	"Note ID"
+//	@Success		200		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/notes/{noteId} [delete]
+func (nc *NoteController) DeleteNote(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	if err := nc.noteService.DeleteNote(userID, c.Param("id"), c.Param("noteId")); err != nil {
+		handleNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Note deleted successfully"})
+}
+
+// Connect
+//
+//	@Summary		Edit a note over a WebSocket
+//	@Description	The first message is "note_state": the whole text, or, when version is still known, the operations made since (catchUp). Clients send {"type": "ops", "ops": [...]}, {"type": "cursor", "cursor": {...}} and {"type": "sync", "version": n}, and receive "note_ops", "note_presence", "note_error" and "note_deleted".
+//	@Security		Bearer
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			noteId	path		string	true	"Note ID"
+//	@Param			version	query		int		false	"The last version the client has"
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/notes/{noteId}/connect [get]
+func (nc *NoteController) Connect(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	teamID, noteID := c.Param("id"), c.Param("noteId")
+	if err := nc.noteService.CheckNoteAccess(userID, teamID, noteID); err != nil {
+		handleNoteError(c, err)
+		return
+	}
+
+	version := -1
+	if v := c.Query("version"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil {
+			version = val
+		}
+	}
+
+	conn, err := hub.AcceptConnection(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := nc.noteService.Join(userID, teamID, noteID, version, conn); err != nil {
+		_ = conn.Close()
+	}
+}
+
+func handleNoteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
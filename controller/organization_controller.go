@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationController struct {
+	organizationService service.OrganizationServiceInterface
+}
+
+func NewOrganizationController() *OrganizationController {
+	return &OrganizationController{
+		organizationService: service.NewOrganizationService(),
+	}
+}
+
+func NewOrganizationControllerWithService(organizationService service.OrganizationServiceInterface) *OrganizationController {
+	return &OrganizationController{
+		organizationService: organizationService,
+	}
+}
+
+// CreateOrganization
+//
+//	@Summary		Create an organization
+//	@Description	Admin only. Users signing up with an email on one of its domains join it; an email domain belongs to at most one organization.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.OrganizationRequest	true	"Name and email domains"
+//	@Success		201		{object}	entity.Organization
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/organizations [post]
+func (oc *OrganizationController) CreateOrganization(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.OrganizationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	organization, err := oc.organizationService.CreateOrganization(userID, &request)
+	if err != nil {
+		handleOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, organization)
+}
+
+// GetOrganizations
+//
+//	@Summary		List organizations
+//	@Description	Returns every organization by name, so users can pick theirs when signing up
+//	@Produce		json
+//	@Success		200	{array}		entity.Organization
+//	@Failure		500	{object}	map[string]string
+//	@Router			/organizations [get]
+func (oc *OrganizationController) GetOrganizations(c *gin.Context) {
+	organizations, err := oc.organizationService.GetOrganizations()
+	if err != nil {
+		handleOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, organizations)
+}
+
+// GetOrganization
+//
+//	@Summary		Get an organization
+//	@Description	Members of the organization and admins only
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	entity.Organization
+//	@Failure		401	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/organizations/{id} [get]
+func (oc *OrganizationController) GetOrganization(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	organization, err := oc.organizationService.GetOrganization(userID, c.Param("id"))
+	if err != nil {
+		handleOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+}
+
+// UpdateOrganization
+//
+//	@Summary		Update an organization
+//	@Description	Organization admins only. Replaces the name and the email domains; members keep their organization when their domain is removed.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Organization ID"
+//	@Param			request	body		dto.OrganizationRequest	true	"Name and email domains"
+//	@Success		200		{object}	entity.Organization
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/organizations/{id} [put]
+func (oc *OrganizationController) UpdateOrganization(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.OrganizationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	organization, err := oc.organizationService.UpdateOrganization(userID, c.Param("id"), &request)
+	if err != nil {
+		handleOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+}
+
+// AddOrganizationAdmin
+//
+//	@Summary		Make a member an organization admin
+//	@Description	Organization admins only
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Organization ID"
+//	@Param			userId	path		string	true	"User ID"
+//	@Success		200		{object}	entity.Organization
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/organizations/{id}/admins/{userId} [put]
+func (oc *OrganizationController) AddOrganizationAdmin(c *gin.Context) {
+	oc.setAdmin(c, true)
+}
+
+// RemoveOrganizationAdmin
+//
+//	@Summary		Remove an organization admin
+//	@Description	Organization admins only. The organization keeps at least one admin.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Organization ID"
+//	@Param			userId	path		string	true	"User ID"
+//	@Success		200		{object}	entity.Organization
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/organizations/{id}/admins/{userId} [delete]
+func (oc *OrganizationController) RemoveOrganizationAdmin(c *gin.Context) {
+	oc.setAdmin(c, false)
+}
+
+func (oc *OrganizationController) setAdmin(c *gin.Context, admin bool) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	organization, err := oc.organizationService.SetAdmin(userID, c.Param("id"), c.Param("userId"), admin)
+	if err != nil {
+		handleOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+}
+
+// AddOrganizationUser
+//
+//	@Summary		Move a user into an organization
+//	@Description	Admin only. Works whatever the user's email; users still in teams can not change organization.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Organization ID"
+//	@Param			userId	path		string	true	"User ID"
+//	@Success		200		{object}	entity.User
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/organizations/{id}/users/{userId} [put]
+func (oc *OrganizationController) AddOrganizationUser(c *gin.Context) {
+	user, err := oc.organizationService.AddUser(c.Param("id"), c.Param("userId"))
+	if err != nil {
+		handleOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func handleOrganizationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PresenceController struct {
+	presenceService service.PresenceServiceInterface
+}
+
+func NewPresenceController() *PresenceController {
+	return &PresenceController{
+		presenceService: service.NewPresenceService(),
+	}
+}
+
+func NewPresenceControllerWithService(presenceService service.PresenceServiceInterface) *PresenceController {
+	return &PresenceController{
+		presenceService: presenceService,
+	}
+}
+
+// GetPresence
+//
+//	@Summary		Get the presence of the caller's contacts
+//	@Description	Online, away or offline, with the last seen time, of the caller's friends and the members of the caller's teams. Changes are pushed to the message WebSocket as presence events.
+//	@Security		Bearer
+//	@Produce		json
+//	@Success		200	{array}		dto.PresenceDTO
+//	@Failure		401	{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		404	{object}	map[string]interface{}	"User not found"
+//	@Failure		500	{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/presence [get]
+func (pc *PresenceController) GetPresence(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	presences, err := pc.presenceService.GetContactsPresence(userID)
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, presences)
+}
@@ -39,6 +39,7 @@ func NewQuizControllerWithService(quizService service.QuizServiceInterface) *Qui
 //	@Success	201		{object}	dto.CreateQuizResponse
 //	@Failure	400		{object}	map[string]string
 //	@Failure	403		{object}	map[string]string
+//	@Failure	409		{object}	map[string]string
 //	@Failure	500		{object}	map[string]string
 //	@Router		/quizzes [post]
 func (qc *QuizController) CreateQuiz(c *gin.Context) {
@@ -63,6 +64,10 @@ func (qc *QuizController) CreateQuiz(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 		return
@@ -79,12 +84,19 @@ func (qc *QuizController) CreateQuiz(c *gin.Context) {
 //	@Produce	json
 //	@Param		id	path		string	true	"The id for quiz"
 //	@Success	200	{object}	entity.Quiz
+//	@Failure	401	{object}	map[string]string
 //	@Failure	404	{object}	map[string]string
 //	@Failure	500	{object}	map[string]string
 //	@Router		/quizzes/{id} [get]
 func (qc *QuizController) GetQuizWithAnswers(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
 	id := c.Param("id")
-	quiz, err := qc.quizService.GetQuizWithAnswersById(id)
+	quiz, err := qc.quizService.GetQuizWithAnswersById(userID, id)
 
 	if err != nil {
 		if errors.Is(err, validator.ErrValidation) {
@@ -110,12 +122,19 @@ func (qc *QuizController) GetQuizWithAnswers(c *gin.Context) {
 //	@Produce	json
 //	@Param		id	path		string	true	"The id for quiz"
 //	@Success	200	{object}	dto.ReadQuizResponse
+//	@Failure	401	{object}	map[string]string
 //	@Failure	404	{object}	map[string]string
 //	@Failure	500	{object}	map[string]string
 //	@Router		/quizzes/{id}/test [get]
 func (qc *QuizController) GetQuizWithoutAnswers(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
 	id := c.Param("id")
-	quiz, err := qc.quizService.GetQuizWithoutAnswersById(id)
+	quiz, err := qc.quizService.GetQuizWithoutAnswersById(userID, id)
 
 	if err != nil {
 		if errors.Is(err, validator.ErrValidation) {
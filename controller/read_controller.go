@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ReadController struct {
+	readService service.ReadServiceInterface
+}
+
+func NewReadController() *ReadController {
+	return &ReadController{
+		readService: service.NewReadService(),
+	}
+}
+
+func NewReadControllerWithService(readService service.ReadServiceInterface) *ReadController {
+	return &ReadController{
+		readService: readService,
+	}
+}
+
+// MarkRead
+//
+//	@Summary		Mark a conversation as read up to a message
+//	@Description	Moves the caller's read marker of the message's direct conversation or channel forward; it never moves back. The other user of a direct conversation gets a message_read event.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id	path		string	true	"The message ID"
+//	@Success		200	{object}	dto.ReadMarkerDTO
+//	@Failure		400	{object}	map[string]interface{}	"Thread reply"
+//	@Failure		401	{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403	{object}	map[string]interface{}	"Not a member of the team"
+//	@Failure		404	{object}	map[string]interface{}	"Message not found"
+//	@Failure		500	{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/messages/{id}/read [post]
+func (rc *ReadController) MarkRead(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	marker, err := rc.readService.MarkRead(userID, c.Param("id"))
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, marker)
+}
+
+// GetUnreadCounts
+//
+//	@Summary		Get the caller's unread counts
+//	@Description	Unread messages per direct conversation and per channel of the caller's teams, without thread replies and the caller's own messages
+//	@Security		Bearer
+//	@Produce		json
+//	@Success		200	{object}	dto.UnreadCountsResponse
+//	@Failure		401	{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		404	{object}	map[string]interface{}	"User not found"
+//	@Failure		500	{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/messages/unread [get]
+func (rc *ReadController) GetUnreadCounts(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	counts, err := rc.readService.GetUnreadCounts(userID)
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// GetReadReceipt
+//
+//	@Summary		Get how far the other user has read a direct conversation
+//	@Description	The other user's read marker in the direct conversation with the caller; messageId is empty when nothing was read yet
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			userId	query		string	true	"The other user"
+//	@Success		200		{object}	dto.ReadMarkerDTO
+//	@Failure		400		{object}	map[string]interface{}	"Bad Request"
+//	@Failure		401		{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		404		{object}	map[string]interface{}	"User not found"
+//	@Failure		500		{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/messages/receipts [get]
+func (rc *ReadController) GetReadReceipt(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	otherUserID := c.Query("userId")
+	if otherUserID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": MissingParameter})
+		return
+	}
+
+	marker, err := rc.readService.GetReadReceipt(userID, otherUserID)
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, marker)
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchController struct {
+	searchService service.SearchServiceInterface
+}
+
+func NewSearchController() *SearchController {
+	return &SearchController{
+		searchService: service.NewSearchService(),
+	}
+}
+
+func NewSearchControllerWithService(searchService service.SearchServiceInterface) *SearchController {
+	return &SearchController{
+		searchService: searchService,
+	}
+}
+
+// Search
+//
+//	@Summary		Full-text search
+//	@Description	Searches teams, users, quizzes and messages. Matching is case and diacritic insensitive, every word must match (the words may be incomplete) and only content visible to the caller is returned.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			q		query		string	true	"Search query"
+//	@Param			types	query		string	false	"Comma separated result types (team,user,quiz,message), all by default"
+//	@Param			page	query		int		false	"Page number (default 1)"
+//	@Param			limit	query		int		false	"Items per page (default 10, max 100)"
+//	@Success		200		{object}	dto.SearchResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/search [get]
+func (sc *SearchController) Search(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var types []string
+	if typesStr := c.Query("types"); typesStr != "" {
+		for _, t := range strings.Split(typesStr, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types = append(types, t)
+			}
+		}
+	}
+
+	page := 1
+	limit := 10
+	if p := c.Query("page"); p != "" {
+		if val, err := strconv.Atoi(p); err == nil {
+			page = val
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil {
+			limit = val
+		}
+	}
+
+	resp, err := sc.searchService.Search(userID, c.Query("q"), types, page, limit)
+	if err != nil {
+		if errors.Is(err, validator.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrResourceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,293 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultAgendaDays = 30
+
+type StudySessionController struct {
+	studySessionService service.StudySessionServiceInterface
+}
+
+func NewStudySessionController() *StudySessionController {
+	return &StudySessionController{
+		studySessionService: service.NewStudySessionService(),
+	}
+}
+
+func NewStudySessionControllerWithService(studySessionService service.StudySessionServiceInterface) *StudySessionController {
+	return &StudySessionController{
+		studySessionService: studySessionService,
+	}
+}
+
+// CreateSession
+//
+//	@Summary		Schedule a study session in a team
+//	@Description	The creator is marked as going. Recurring sessions keep their local time in the session's timezone. The response lists the creator's other sessions that overlap it in the next 90 days.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Team ID"
+//	@Param			request	body		dto.StudySessionRequest	true	"Session"
+//	@Success		201		{object}	dto.StudySessionResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/sessions [post]
+func (ssc *StudySessionController) CreateSession(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.StudySessionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := ssc.studySessionService.CreateSession(userID, c.Param("id"), &request)
+	if err != nil {
+		handleStudySessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetTeamSessions
+//
+//	@Summary		List the study sessions of a team
+//	@Description	Sorted by the start of their first occurrence
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id	path		string	true	"Team ID"
+//	@Success		200	{array}		entity.StudySession
+//	@Failure		401	{object}	map[string]string
+//	@Failure		403	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/teams/{id}/sessions [get]
+func (ssc *StudySessionController) GetTeamSessions(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	sessions, err := ssc.studySessionService.GetTeamSessions(userID, c.Param("id"))
+	if err != nil {
+		handleStudySessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// GetAgenda
+//
+//	@Summary		Get the caller's upcoming study sessions
+//	@Description	Every occurrence of the sessions of the caller's teams between from and to (RFC 3339, default the next 30 days), with the caller's RSVP
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			from	query		string	false	"Start of the range (RFC 3339)"
+//	@Param			to		query		string	false	"End of the range (RFC 3339)"
+//	@Success		200		{array}		dto.SessionOccurrence
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/sessions [get]
+func (ssc *StudySessionController) GetAgenda(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	from := time.Now()
+	if f := c.Query("from"); f != "" {
+		if from, err = time.Parse(time.RFC3339, f); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be an RFC 3339 date"})
+			return
+		}
+	}
+	to := from.AddDate(0, 0, defaultAgendaDays)
+	if t := c.Query("to"); t != "" {
+		if to, err = time.Parse(time.RFC3339, t); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be an RFC 3339 date"})
+			return
+		}
+	}
+
+	agenda, err := ssc.studySessionService.GetUserAgenda(userID, from, to)
+	if err != nil {
+		handleStudySessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, agenda)
+}
+
+// GetSession
+//
+//	@Summary		Get a study session
+//	@Description	Includes the caller's other sessions that overlap it in the next 90 days
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			sessionId	path		string	true	"Session ID"
+//	@Success		200			{object}	dto.StudySessionResponse
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/sessions/{sessionId} [get]
+func (ssc *StudySessionController) GetSession(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := ssc.studySessionService.GetSession(userID, c.Param("sessionId"))
+	if err != nil {
+		handleStudySessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateSession
+//
+//	@Summary		Reschedule a study session
+//	@Description	Only the creator or the team owner can change a session. RSVPs are kept.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			sessionId	path		string					true	"Session ID"
+//	@Param			request		body		dto.StudySessionRequest	true	"Session"
+//	@Success		200			{object}	dto.StudySessionResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/sessions/{sessionId} [put]
+func (ssc *StudySessionController) UpdateSession(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.StudySessionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := ssc.studySessionService.UpdateSession(userID, c.Param("sessionId"), &request)
+	if err != nil {
+		handleStudySessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteSession
+//
+//	@Summary		Cancel a study session
+//	@Description	Only the creator or the team owner can cancel a session
+//	@Security		Bearer
+//	@Param			sessionId	path	string	true	"Session ID"
+//	@Success		204
+//	@Failure		401	{object}	map[string]string
+//	@Failure		403	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		409	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/sessions/{sessionId} [delete]
+func (ssc *StudySessionController) DeleteSession(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	if err := ssc.studySessionService.DeleteSession(userID, c.Param("sessionId")); err != nil {
+		handleStudySessionError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RSVP
+//
+//	@Summary		Answer a study session invitation
+//	@Description	Members answer going, maybe or declined. Answering going returns the caller's other sessions that overlap it. Members who declined get no reminders.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			sessionId	path		string			true	"Session ID"
+//	@Param			request		body		dto.RSVPRequest	true	"RSVP"
+//	@Success		200			{object}	dto.StudySessionResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/sessions/{sessionId}/rsvp [put]
+func (ssc *StudySessionController) RSVP(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.RSVPRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := ssc.studySessionService.RSVP(userID, c.Param("sessionId"), &request)
+	if err != nil {
+		handleStudySessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func handleStudySessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -0,0 +1,393 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gin-gonic/gin"
+)
+
+type TaskController struct {
+	taskService service.TaskServiceInterface
+}
+
+func NewTaskController() *TaskController {
+	return &TaskController{
+		taskService: service.NewTaskService(),
+	}
+}
+
+func NewTaskControllerWithService(taskService service.TaskServiceInterface) *TaskController {
+	return &TaskController{
+		taskService: taskService,
+	}
+}
+
+// GetBoard
+//
+//	@Summary		Get the task board of a team
+//	@Description	The columns in order, each with its tasks in order. Teams start with the columns "To do", "In progress" and "Done".
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id	path		string	true	"Team ID"
+//	@Success		200	{object}	dto.TaskBoardResponse
+//	@Failure		401	{object}	map[string]string
+//	@Failure		403	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string
+//	@Failure		500	{object}	map[string]string
+//	@Router			/teams/{id}/board [get]
+func (tc *TaskController) GetBoard(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	board, err := tc.taskService.GetBoard(userID, c.Param("id"))
+	if err != nil {
+		handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+// CreateColumn
+//
+//	@Summary	Add a column to a team's task board
+//	@Security	Bearer
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string					true	"Team ID"
+//	@Param		request	body		dto.BoardColumnRequest	true	"Column"
+//	@Success	201		{object}	entity.TaskBoard
+//	@Failure	400		{object}	map[string]string
+//	@Failure	401		{object}	map[string]string
+//	@Failure	403		{object}	map[string]string
+//	@Failure	404		{object}	map[string]string
+//	@Failure	409		{object}	map[string]string
+//	@Failure	500		{object}	map[string]string
+//	@Router		/teams/{id}/board/columns [post]
+func (tc *TaskController) CreateColumn(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.BoardColumnRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	board, err := tc.taskService.CreateColumn(userID, c.Param("id"), &request)
+	if err != nil {
+		handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, board)
+}
+
+// UpdateColumn
+//
+//	@Summary	Rename or reorder a column of a team's task board
+//	@Security	Bearer
+//	@Accept		json
+//	@Produce	json
+//	@Param		id			path		string							true	"Team ID"
+//	@Param		columnId	path		string							true	"Column ID"
+//	@Param		request		body		dto.UpdateBoardColumnRequest	true	"Column changes"
+//	@Success	200			{object}	entity.TaskBoard
+//	@Failure	400			{object}	map[string]string
+//	@Failure	401			{object}	map[string]string
+//	@Failure	403			{object}	map[string]string
+//	@Failure	404			{object}	map[string]string
+//	@Failure	409			{object}	map[string]string
+//	@Failure	500			{object}	map[string]string
+//	@Router		/teams/{id}/board/columns/{columnId} [put]
+func (tc *TaskController) UpdateColumn(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.UpdateBoardColumnRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	board, err := tc.taskService.UpdateColumn(userID, c.Param("id"), c.Param("columnId"), &request)
+	if err != nil {
+		handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+// DeleteColumn
+//
+//	@Summary	Delete an empty column of a team's task board
+//	@Security	Bearer
+//	@Produce	json
+//	@Param		id			path		string	true	"Team ID"
+//	@Param		columnId	path		string	true	"Column ID"
+//	@Success	200			{object}	entity.TaskBoard
+//	@Failure	401			{object}	map[string]string
+//	@Failure	403			{object}	map[string]string
+//	@Failure	404			{object}	map[string]string
+//	@Failure	409			{object}	map[string]string
+//	@Failure	500			{object}	map[string]string
+//	@Router		/teams/{id}/board/columns/{columnId} [delete]
+func (tc *TaskController) DeleteColumn(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	board, err := tc.taskService.DeleteColumn(userID, c.Param("id"), c.Param("columnId"))
+	if err != nil {
+		handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+// CreateTask
+//
+//	@Summary		Create a task on a team's task board
+//	@Description	The task is added at the end of its column, the first column when none is given. Assignees must be members of the team.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Team ID"
+//	@Param			request	body		dto.TaskRequest	true	"Task"
+//	@Success		201		{object}	entity.Task
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/tasks [post]
+func (tc *TaskController) CreateTask(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.TaskRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := tc.taskService.CreateTask(userID, c.Param("id"), &request)
+	if err != nil {
+		handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, task)
+}
+
+// GetTask
+//
+//	@Summary	Get a task with its comments
+//	@Security	Bearer
+//	@Produce	json
+//	@Param		id		path		string	true	"Team ID"
+//	@Param		taskId	path		string	true	"Task ID"
+//	@Success	200		{object}	dto.TaskDetailsResponse
+//	@Failure	401		{object}	map[string]string
+//	@Failure	403		{object}	map[string]string
+//	@Failure	404		{object}	map[string]string
+//	@Failure	500		{object}	map[string]string
+//	@Router		/teams/{id}/tasks/{taskId} [get]
+func (tc *TaskController) GetTask(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	task, err := tc.taskService.GetTask(userID, c.Param("id"), c.Param("taskId"))
+	if err != nil {
+		handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// UpdateTask
+//
+//	@Summary		Replace the content of a task
+//	@Description	Title, description, assignees, due date, labels and checklist are replaced; use the move endpoint to change the column or position
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Team ID"
+//	@Param			taskId	path		string			true	"Task ID"
+//	@Param			request	body		dto.TaskRequest	true	"Task"
+//	@Success		200		{object}	entity.Task
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/tasks/{taskId} [put]
+func (tc *TaskController) UpdateTask(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.TaskRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := tc.taskService.UpdateTask(userID, c.Param("id"), c.Param("taskId"), &request)
+	if err != nil {
+		handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// MoveTask
+//
+//	@Summary	Move a task to a position in a column
+//	@Security	Bearer
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string				true	"Team ID"
+//	@Param		taskId	path		string				true	"Task ID"
+//	@Param		request	body		dto.MoveTaskRequest	true	"Target column and position"
+//	@Success	200		{object}	entity.Task
+//	@Failure	400		{object}	map[string]string
+//	@Failure	401		{object}	map[string]string
+//	@Failure	403		{object}	map[string]string
+//	@Failure	404		{object}	map[string]string
+//	@Failure	409		{object}	map[string]string
+//	@Failure	500		{object}	map[string]string
+//	@Router		/teams/{id}/tasks/{taskId}/move [put]
+func (tc *TaskController) MoveTask(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.MoveTaskRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := tc.taskService.MoveTask(userID, c.Param("id"), c.Param("taskId"), &request)
+	if err != nil {
+		handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// DeleteTask
+//
+//	@Summary	Delete a task and its comments
+//	@Security	Bearer
+//	@Param		id		path	string	true	"Team ID"
+//	@Param		taskId	path	string	true	"Task ID"
+//	@Success	204
+//	@Failure	401	{object}	map[string]string
+//	@Failure	403	{object}	map[string]string
+//	@Failure	404	{object}	map[string]string
+//	@Failure	409	{object}	map[string]string
+//	@Failure	500	{object}	map[string]string
+//	@Router		/teams/{id}/tasks/{taskId} [delete]
+func (tc *TaskController) DeleteTask(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	if err := tc.taskService.DeleteTask(userID, c.Param("id"), c.Param("taskId")); err != nil {
+		handleTaskError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddComment
+//
+//	@Summary	Comment on a task
+//	@Security	Bearer
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string					true	"Team ID"
+//	@Param		taskId	path		string					true	"Task ID"
+//	@Param		request	body		dto.TaskCommentRequest	true	"Comment"
+//	@Success	201		{object}	entity.TaskComment
+//	@Failure	400		{object}	map[string]string
+//	@Failure	401		{object}	map[string]string
+//	@Failure	403		{object}	map[string]string
+//	@Failure	404		{object}	map[string]string
+//	@Failure	409		{object}	map[string]string
+//	@Failure	500		{object}	map[string]string
+//	@Router		/teams/{id}/tasks/{taskId}/comments [post]
+func (tc *TaskController) AddComment(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.TaskCommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := tc.taskService.AddComment(userID, c.Param("id"), c.Param("taskId"), &request)
+	if err != nil {
+		handleTaskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+func handleTaskError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
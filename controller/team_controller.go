@@ -1,12 +1,15 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
 	"github.com/gin-gonic/gin"
 )
 
@@ -40,14 +43,18 @@ func NewTeamControllerWithService(teamService TeamServiceInterface) *TeamControl
 
 type TeamServiceInterface interface {
 	CreateTeam(request *dto.TeamRequest) (*entity.Team, error)
-	AddUserToTeam(idUser string, idTeam string) (*entity.User, *entity.Team, error)
-	DeleteUserFromTeam(idUser string, idTeam string) (*entity.User, *entity.Team, error)
+	AddUserToTeam(callerID, idUser, idTeam string) (*entity.User, *entity.Team, error)
+	DeleteUserFromTeam(callerID, idUser, idTeam string) (*entity.User, *entity.Team, error)
 	GetTeamById(id string) (*entity.Team, error)
-	GetXTeamsByPrefix(prefix string, x int) ([]*entity.Team, error)
-	GetTeamsByName(name string) ([]*entity.Team, error)
-	GetAll() ([]*entity.Team, error)
-	Update(team *entity.Team) error
-	Delete(id string) error
+	GetVisibleTeam(viewerID, id string) (*entity.Team, error)
+	GetTeamForMember(userID, id string) (*entity.Team, error)
+	GetXTeamsByPrefix(viewerID, prefix string, x int) ([]*entity.Team, error)
+	GetTeamsByName(viewerID, name string) ([]*entity.Team, error)
+	GetAll(viewerID string) ([]*entity.Team, error)
+	Update(userID string, team *entity.Team) error
+	SetArchived(userID, teamID string, archived bool) (*entity.Team, error)
+	SetAdmin(ownerID, teamID, userID string, admin bool) (*entity.Team, error)
+	Delete(userID, id string) error
 }
 
 // NewTeam
@@ -71,6 +78,10 @@ func (tc *TeamController) NewTeam(c *gin.Context) {
 
 	resp, err := tc.teamService.CreateTeam(&request)
 	if err != nil {
+		if errors.Is(err, validator.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -80,16 +91,23 @@ func (tc *TeamController) NewTeam(c *gin.Context) {
 // GetTeam
 //
 //	@Summary		Get a team by ID
-//	@Description	Get team details by ID
+//	@Description	Get team details by ID. Teams of other organizations are not found.
 //	@Security		Bearer
 //	@Produce		json
 //	@Param			id	path		string	true	"Team ID"
 //	@Success		200	{object}	entity.Team
+//	@Failure		401	{object}	map[string]interface{}	"Unauthorized"
 //	@Failure		404	{object}	map[string]interface{}	"Team not found"
 //	@Router			/teams/{id} [get]
 func (tc *TeamController) GetTeam(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
 	id := c.Param("id")
-	team, err := tc.teamService.GetTeamById(id)
+	team, err := tc.teamService.GetVisibleTeam(userID, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": TeamNotFoundError})
 		return
@@ -101,7 +119,7 @@ func (tc *TeamController) GetTeam(c *gin.Context) {
 // GetAllTeams
 //
 //	@Summary		Get teams with optional filtering
-//	@Description	Get teams - all teams, by name, or by prefix with limit
+//	@Description	Get the teams of the caller's organization - all teams, by name, or by prefix with limit
 //	@Security		Bearer
 //	@Produce		json
 //	@Param			name	query		string	false	"Filter by exact name"
@@ -109,16 +127,23 @@ func (tc *TeamController) GetTeam(c *gin.Context) {
 //	@Param			limit	query		int		false	"Limit results (required with prefix)"
 //	@Success		200		{array}		entity.Team
 //	@Failure		400		{object}	map[string]interface{}	"Bad Request"
+//	@Failure		401		{object}	map[string]interface{}	"Unauthorized"
 //	@Failure		500		{object}	map[string]interface{}	"Internal Server Error"
 //	@Router			/teams [get]
 func (tc *TeamController) GetAllTeams(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
 	name := c.Query("name")
 	prefix := c.Query("prefix")
 	limitStr := c.Query("limit")
 
 	// Filter by exact name
 	if name != "" {
-		teams, err := tc.teamService.GetTeamsByName(name)
+		teams, err := tc.teamService.GetTeamsByName(userID, name)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -138,7 +163,7 @@ func (tc *TeamController) GetAllTeams(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": LimitMustBeANumberError})
 			return
 		}
-		teams, err := tc.teamService.GetXTeamsByPrefix(prefix, limit)
+		teams, err := tc.teamService.GetXTeamsByPrefix(userID, prefix, limit)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -148,7 +173,7 @@ func (tc *TeamController) GetAllTeams(c *gin.Context) {
 	}
 
 	// Get all teams (no filters)
-	teams, err := tc.teamService.GetAll()
+	teams, err := tc.teamService.GetAll(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -160,7 +185,7 @@ func (tc *TeamController) GetAllTeams(c *gin.Context) {
 // AddUserToTeam
 //
 //	@Summary		Add a user to a team
-//	@Description	Adds a user to a team by providing user ID and team ID
+//	@Description	Adds a user to a team by providing user ID and team ID. Users can join public teams themselves; only team admins can add other users or add to private teams.
 //
 //	@Security		Bearer
 //
@@ -169,15 +194,37 @@ func (tc *TeamController) GetAllTeams(c *gin.Context) {
 //	@Param			request	body		dto.UserToTeamRequest	true	"User ID and Team ID"
 //	@Success		200		{object}	dto.AddUserToTeamResponse
 //	@Failure		400		{object}	map[string]string	"Invalid request body or error"
+//	@Failure		401		{object}	map[string]string	"Unauthorized"
+//	@Failure		403		{object}	map[string]string	"Not allowed to add the user, or the user is banned from the team"
+//	@Failure		404		{object}	map[string]string	"The team belongs to another organization"
+//	@Failure		409		{object}	map[string]string	"Team is archived"
 //	@Router			/teams/users [put]
 func (tc *TeamController) AddUserToTeam(c *gin.Context) {
+	callerID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
 	var req dto.UserToTeamRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": InvalidRequestBodyError})
 		return
 	}
-	user, team, err := tc.teamService.AddUserToTeam(req.UserID, req.TeamID)
+	user, team, err := tc.teamService.AddUserToTeam(callerID, req.UserID, req.TeamID)
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrResourceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -188,7 +235,7 @@ func (tc *TeamController) AddUserToTeam(c *gin.Context) {
 // DeleteUserFromTeam
 //
 //	@Summary		Delete a user from a team
-//	@Description	Deletes a user from a team by providing team ID
+//	@Description	Deletes a user from a team by providing team ID. Users can leave a team themselves; removing someone else is a kick and is limited to team admins.
 //
 //	@Security		Bearer
 //
@@ -197,8 +244,18 @@ func (tc *TeamController) AddUserToTeam(c *gin.Context) {
 //	@Param			request	body		dto.UserToTeamRequest		true	"User ID and Team ID"
 //	@Success		200		{object}	dto.AddUserToTeamResponse	"User removed from team"
 //	@Failure		400		{object}	map[string]string			"Invalid request body or error"
+//	@Failure		401		{object}	map[string]string			"Unauthorized"
+//	@Failure		403		{object}	map[string]string			"Not an admin of the team"
+//	@Failure		404		{object}	map[string]string			"Team or user not found"
+//	@Failure		409		{object}	map[string]string			"Cannot kick yourself or the team owner"
 //	@Router			/teams/users [delete]
 func (tc *TeamController) DeleteUserFromTeam(c *gin.Context) {
+	callerID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
 	var req dto.UserToTeamRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": InvalidRequestBodyError})
@@ -208,8 +265,20 @@ func (tc *TeamController) DeleteUserFromTeam(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": EmptyParametersError})
 		return
 	}
-	user, team, err := tc.teamService.DeleteUserFromTeam(req.UserID, req.TeamID)
+	user, team, err := tc.teamService.DeleteUserFromTeam(callerID, req.UserID, req.TeamID)
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrResourceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -220,7 +289,7 @@ func (tc *TeamController) DeleteUserFromTeam(c *gin.Context) {
 // UpdateTeam
 //
 //	@Summary		Update a team
-//	@Description	Update team details by providing team ID and updated details
+//	@Description	Update the team details; admins only. The members, owner, admins and archived state are kept.
 //	@Security		Bearer
 //	@Accept			json
 //	@Produce		json
@@ -228,16 +297,101 @@ func (tc *TeamController) DeleteUserFromTeam(c *gin.Context) {
 //	@Param			team	body		entity.Team	true	"Updated team details"
 //	@Success		200		{object}	entity.Team
 //	@Failure		400		{object}	map[string]interface{}	"Bad Request"
+//	@Failure		401		{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403		{object}	map[string]interface{}	"Not an admin of the team"
+//	@Failure		404		{object}	map[string]interface{}	"Team not found"
+//	@Failure		409		{object}	map[string]interface{}	"Team is archived"
 //	@Failure		500		{object}	map[string]interface{}	"Internal Server Error"
 //	@Router			/teams/{id} [put]
 func (tc *TeamController) UpdateTeam(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
 	var team entity.Team
 	if err := c.ShouldBindJSON(&team); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	team.Id = c.Param("id")
+
+	if err := tc.teamService.Update(userID, &team); err != nil {
+		if errors.Is(err, validator.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrResourceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+// ArchiveTeam
+//
+//	@Summary		Archive a team
+//	@Description	Owner only. An archived team keeps its history readable but accepts no new messages, channels, files, quizzes, voice rooms or members, and is hidden from team listings.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id	path		string	true	"Team ID"
+//	@Success		200	{object}	entity.Team
+//	@Failure		401	{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403	{object}	map[string]interface{}	"Not the team owner"
+//	@Failure		404	{object}	map[string]interface{}	"Team not found"
+//	@Failure		500	{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/teams/{id}/archive [post]
+func (tc *TeamController) ArchiveTeam(c *gin.Context) {
+	tc.setArchived(c, true)
+}
+
+// RestoreTeam
+//
+//	@Summary		Restore an archived team
+//	@Description	Owner only. Makes the team writable and listed again.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id	path		string	true	"Team ID"
+//	@Success		200	{object}	entity.Team
+//	@Failure		401	{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403	{object}	map[string]interface{}	"Not the team owner"
+//	@Failure		404	{object}	map[string]interface{}	"Team not found"
+//	@Failure		500	{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/teams/{id}/archive [delete]
+func (tc *TeamController) RestoreTeam(c *gin.Context) {
+	tc.setArchived(c, false)
+}
+
+func (tc *TeamController) setArchived(c *gin.Context, archived bool) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
 
-	if err := tc.teamService.Update(&team); err != nil {
+	team, err := tc.teamService.SetArchived(userID, c.Param("id"), archived)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrResourceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -245,25 +399,105 @@ func (tc *TeamController) UpdateTeam(c *gin.Context) {
 	c.JSON(http.StatusOK, team)
 }
 
+// AddTeamAdmin
+//
+//	@Summary		Make a member an admin of the team
+//	@Description	Owner only. Admins manage the team's announcements and pinned messages.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			userId	path		string	true	"User ID of the member"
+//	@Success		200		{object}	entity.Team
+//	@Failure		401		{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403		{object}	map[string]interface{}	"Not the team owner"
+//	@Failure		404		{object}	map[string]interface{}	"Team or member not found"
+//	@Failure		409		{object}	map[string]interface{}	"The owner is always an admin"
+//	@Failure		500		{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/teams/{id}/admins/{userId} [put]
+func (tc *TeamController) AddTeamAdmin(c *gin.Context) {
+	tc.setAdmin(c, true)
+}
+
+// RemoveTeamAdmin
+//
+//	@Summary		Remove a member from the admins of the team
+//	@Description	Owner only
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			userId	path		string	true	"User ID of the member"
+//	@Success		200		{object}	entity.Team
+//	@Failure		401		{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403		{object}	map[string]interface{}	"Not the team owner"
+//	@Failure		404		{object}	map[string]interface{}	"Team or member not found"
+//	@Failure		409		{object}	map[string]interface{}	"The owner is always an admin"
+//	@Failure		500		{object}	map[string]interface{}	"Internal Server Error"
+//	@Router			/teams/{id}/admins/{userId} [delete]
+func (tc *TeamController) RemoveTeamAdmin(c *gin.Context) {
+	tc.setAdmin(c, false)
+}
+
+func (tc *TeamController) setAdmin(c *gin.Context, admin bool) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	team, err := tc.teamService.SetAdmin(userID, c.Param("id"), c.Param("userId"), admin)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrResourceNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
 // DeleteTeam
 //
 //	@Summary		Delete a team
-//	@Description	Delete a team by providing team ID
+//	@Description	Delete a team by providing team ID. Only the team owner can delete it.
 //	@Security		Bearer
 //	@Produce		json
 //	@Param			id	path		string					true	"Team ID"
 //	@Success		200	{object}	map[string]interface{}	"Team deleted"
 //	@Failure		400	{object}	map[string]interface{}	"Bad Request: Missing team ID"
+//	@Failure		401	{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		403	{object}	map[string]interface{}	"Not the team owner"
+//	@Failure		404	{object}	map[string]interface{}	"Team not found"
 //	@Failure		500	{object}	map[string]interface{}	"Internal Server Error"
 //	@Router			/teams/{id} [delete]
 func (tc *TeamController) DeleteTeam(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
 	id := c.Param("id")
 	if id == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": MissingTeamIDError})
 		return
 	}
 
-	if err := tc.teamService.Delete(id); err != nil {
+	if err := tc.teamService.Delete(userID, id); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrResourceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
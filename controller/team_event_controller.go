@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TeamEventController struct {
+	teamEventService service.TeamEventServiceInterface
+}
+
+func NewTeamEventController() *TeamEventController {
+	return &TeamEventController{
+		teamEventService: service.NewTeamEventService(),
+	}
+}
+
+func NewTeamEventControllerWithService(teamEventService service.TeamEventServiceInterface) *TeamEventController {
+	return &TeamEventController{
+		teamEventService: teamEventService,
+	}
+}
+
+// GetTeamActivity
+//
+//	@Summary		Get the activity feed of a team
+//	@Description	Members joining and leaving, files, quizzes, voice sessions and announcements, newest first. New events are also pushed over the message WebSocket as "team_activity".
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			page	query		int		false	"Page number (default 1)"
+//	@Param			limit	query		int		false	"Items per page (default 10, max 100)"
+//	@Success		200		{object}	dto.TeamActivityResponse
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/activity [get]
+func (tec *TeamEventController) GetTeamActivity(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	page := 1
+	limit := 10
+	if p := c.Query("page"); p != "" {
+		if val, err := strconv.Atoi(p); err == nil {
+			page = val
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil {
+			limit = val
+		}
+	}
+
+	resp, err := tec.teamEventService.GetTeamActivity(userID, c.Param("id"), page, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrResourceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
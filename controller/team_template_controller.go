@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gin-gonic/gin"
+)
+
+type TeamTemplateController struct {
+	templateService service.TeamTemplateServiceInterface
+}
+
+func NewTeamTemplateController() *TeamTemplateController {
+	return &TeamTemplateController{
+		templateService: service.NewTeamTemplateService(),
+	}
+}
+
+func NewTeamTemplateControllerWithService(templateService service.TeamTemplateServiceInterface) *TeamTemplateController {
+	return &TeamTemplateController{
+		templateService: templateService,
+	}
+}
+
+// CreateTemplate
+//
+//	@Summary		Save a team as a template
+//	@Description	Team admins only. Keeps the description, topic, visibility and the selected content (channels, quizzes, taskBoard; all when include is empty). Members and messages are never saved.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Team ID"
+//	@Param			request	body		dto.TeamTemplateRequest	true	"Template name and content"
+//	@Success		201		{object}	entity.TeamTemplate
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/templates [post]
+func (ttc *TeamTemplateController) CreateTemplate(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.TeamTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := ttc.templateService.CreateTemplate(userID, c.Param("id"), &request)
+	if err != nil {
+		handleTeamTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// GetTemplates
+//
+//	@Summary	List the caller's team templates
+//	@Security	Bearer
+//	@Produce	json
+//	@Success	200	{array}		dto.TeamTemplateSummary
+//	@Failure	401	{object}	map[string]string
+//	@Failure	500	{object}	map[string]string
+//	@Router		/templates [get]
+func (ttc *TeamTemplateController) GetTemplates(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	templates, err := ttc.templateService.GetTemplates(userID)
+	if err != nil {
+		handleTeamTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate
+//
+//	@Summary	Get one of the caller's team templates with its content
+//	@Security	Bearer
+//	@Produce	json
+//	@Param		templateId	path		string	true	"Template ID"
+//	@Success	200			{object}	entity.TeamTemplate
+//	@Failure	401			{object}	map[string]string
+//	@Failure	404			{object}	map[string]string
+//	@Failure	500			{object}	map[string]string
+//	@Router		/templates/{templateId} [get]
+func (ttc *TeamTemplateController) GetTemplate(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	template, err := ttc.templateService.GetTemplate(userID, c.Param("templateId"))
+	if err != nil {
+		handleTeamTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate
+//
+//	@Summary	Delete one of the caller's team templates
+//	@Security	Bearer
+//	@Param		templateId	path	string	true	"Template ID"
+//	@Success	204
+//	@Failure	401	{object}	map[string]string
+//	@Failure	404	{object}	map[string]string
+//	@Failure	500	{object}	map[string]string
+//	@Router		/templates/{templateId} [delete]
+func (ttc *TeamTemplateController) DeleteTemplate(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	if err := ttc.templateService.DeleteTemplate(userID, c.Param("templateId")); err != nil {
+		handleTeamTemplateError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CreateTeamFromTemplate
+//
+//	@Summary		Create a team from a template
+//	@Description	The caller becomes the owner and only member. include selects the content to copy, all of the template's when empty.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			templateId	path		string							true	"Template ID"
+//	@Param			request		body		dto.NewTeamFromTemplateRequest	true	"New team"
+//	@Success		201			{object}	dto.NewTeamFromTemplateResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/templates/{templateId}/teams [post]
+func (ttc *TeamTemplateController) CreateTeamFromTemplate(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.NewTeamFromTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := ttc.templateService.CreateTeamFromTemplate(userID, c.Param("templateId"), &request)
+	if err != nil {
+		handleTeamTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// CloneTeam
+//
+//	@Summary		Clone a team
+//	@Description	Team admins only, archived teams can be cloned. The caller becomes the owner and only member of the new team; members and messages are not copied.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"Team ID"
+//	@Param			request	body		dto.NewTeamFromTemplateRequest	true	"New team"
+//	@Success		201		{object}	dto.NewTeamFromTemplateResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/clone [post]
+func (ttc *TeamTemplateController) CloneTeam(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.NewTeamFromTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := ttc.templateService.CloneTeam(userID, c.Param("id"), &request)
+	if err != nil {
+		handleTeamTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func handleTeamTemplateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
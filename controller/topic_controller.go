@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gin-gonic/gin"
+)
+
+const topicDeletedMessage = "Topic deleted"
+
+type TopicController struct {
+	topicService service.TopicServiceInterface
+}
+
+func NewTopicController() *TopicController {
+	return &TopicController{
+		topicService: service.NewTopicService(),
+	}
+}
+
+func NewTopicControllerWithService(topicService service.TopicServiceInterface) *TopicController {
+	return &TopicController{
+		topicService: topicService,
+	}
+}
+
+// GetTopics
+//
+//	@Summary		List topics of interest
+//	@Description	Returns every topic with its display name in the requested language and its child topics
+//	@Produce		json
+//	@Param			lang	query		string	false	"Display name language (default en)"
+//	@Param			usage	query		bool	false	"Include how many users and teams use each topic (admin only)"
+//	@Success		200		{array}		dto.TopicResponse
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/topics [get]
+func (tc *TopicController) GetTopics(c *gin.Context) {
+	topics, err := tc.topicService.GetTopics(topicLanguage(c), c.Query("usage") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, topics)
+}
+
+// GetTopic
+//
+//	@Summary	Get a topic of interest
+//	@Produce	json
+//	@Param		slug	path		string	true	"Topic slug"
+//	@Param		lang	query		string	false	"Display name language (default en)"
+//	@Param		usage	query		bool	false	"Include how many users and teams use the topic (admin only)"
+//	@Success	200		{object}	dto.TopicResponse
+//	@Failure	401		{object}	map[string]string
+//	@Failure	403		{object}	map[string]string
+//	@Failure	404		{object}	map[string]string
+//	@Failure	500		{object}	map[string]string
+//	@Router		/topics/{slug} [get]
+func (tc *TopicController) GetTopic(c *gin.Context) {
+	topic, err := tc.topicService.GetTopic(model.TopicOfInterest(c.Param("slug")), topicLanguage(c), c.Query("usage") == "true")
+	if err != nil {
+		handleTopicError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, topic)
+}
+
+// CreateTopic
+//
+//	@Summary		Create a topic of interest
+//	@Description	Admin only. The slug is permanent and a display name in English is required.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.TopicRequest	true	"Topic"
+//	@Success		201		{object}	dto.TopicResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/topics [post]
+func (tc *TopicController) CreateTopic(c *gin.Context) {
+	var request dto.TopicRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	topic, err := tc.topicService.CreateTopic(&request)
+	if err != nil {
+		handleTopicError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, topic)
+}
+
+// UpdateTopic
+//
+//	@Summary		Update a topic of interest
+//	@Description	Admin only. Replaces the display names and the parent topic.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			slug	path		string					true	"Topic slug"
+//	@Param			request	body		dto.UpdateTopicRequest	true	"Topic changes"
+//	@Success		200		{object}	dto.TopicResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/topics/{slug} [put]
+func (tc *TopicController) UpdateTopic(c *gin.Context) {
+	var request dto.UpdateTopicRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	topic, err := tc.topicService.UpdateTopic(model.TopicOfInterest(c.Param("slug")), &request)
+	if err != nil {
+		handleTopicError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, topic)
+}
+
+// DeleteTopic
+//
+//	@Summary		Delete a topic of interest
+//	@Description	Admin only. Topics with child topics or used by users or teams can not be deleted.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			slug	path		string	true	"Topic slug"
+//	@Success		200		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/topics/{slug} [delete]
+func (tc *TopicController) DeleteTopic(c *gin.Context) {
+	if err := tc.topicService.DeleteTopic(model.TopicOfInterest(c.Param("slug"))); err != nil {
+		handleTopicError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": topicDeletedMessage})
+}
+
+func topicLanguage(c *gin.Context) string {
+	if lang := c.Query("lang"); lang != "" {
+		return lang
+	}
+	return model.DefaultTopicLanguage
+}
+
+func handleTopicError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
 	"github.com/gin-gonic/gin"
 )
 
@@ -51,7 +53,8 @@ type UserServiceInterface interface {
 	UpdateUserProfile(userID string, req *dto.UserUpdateRequestDTO) (*dto.UserUpdateResponseDTO, error)
 	UpdateUserPassword(userID string, req *dto.UserPasswordRequestDTO) error
 	DeleteUser(id string) error
-	GetAllUsers() ([]*entity.User, error)
+	GetAllUsers(viewerID string) ([]*entity.User, error)
+	GetVisibleUser(viewerID, id string) (*entity.User, error)
 	GetUserStatistics(id string) (*dto.StatisticsResponse, error)
 	UpdateUserStatistics(id string, timeSpentOnApp int64, timeSpentOnTeam model.TimeSpentOnTeam) (*entity.User, error)
 }
@@ -77,6 +80,7 @@ func (uc *UserController) SignUp(c *gin.Context) {
 	response, err := uc.userService.SignUp(&request)
 	if err != nil {
 		if strings.Contains(err.Error(), "already exists") ||
+			errors.Is(err, validator.ErrValidation) ||
 			strings.Contains(err.Error(), "invalid") ||
 			strings.Contains(err.Error(), "required") ||
 			strings.Contains(err.Error(), "must") {
@@ -103,11 +107,18 @@ func (uc *UserController) SignUp(c *gin.Context) {
 //	@Produce	json
 //	@Param		id	path		string	true	"The user's ID"
 //	@Success	200	{object}	entity.User
+//	@Failure	401	{object}	map[string]string
 //	@Failure	404	{object}	map[string]string
 //	@Router		/users/{id}  [get]
 func (uc *UserController) GetUser(c *gin.Context) {
+	viewerID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
 	id := c.Param("id")
-	user, err := uc.userService.GetUserByID(id)
+	user, err := uc.userService.GetVisibleUser(viewerID, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": userNotFoundError})
 		return
@@ -118,15 +129,22 @@ func (uc *UserController) GetUser(c *gin.Context) {
 
 // GetAllUsers
 //
-//	@Summary	Get all users
+//	@Summary	Get the users of the caller's organization
 //	@Security	Bearer
 //	@Accept		json
 //	@Produce	json
 //	@Success	200	{object}	[]entity.User
+//	@Failure	401	{object}	map[string]string
 //	@Failure	500	{object}	map[string]string
 //	@Router		/users [get]
 func (uc *UserController) GetAllUsers(c *gin.Context) {
-	users, err := uc.userService.GetAllUsers()
+	viewerID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, err := uc.userService.GetAllUsers(viewerID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -159,6 +177,10 @@ func (uc *UserController) UpdateUser(c *gin.Context) {
 
 	resp, err := uc.userService.UpdateUserProfile(id, &req)
 	if err != nil {
+		if errors.Is(err, validator.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
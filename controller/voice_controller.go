@@ -3,6 +3,7 @@ package controller
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 
 	"log"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 )
@@ -34,6 +36,8 @@ const (
 	ErrorRoomNotFound    = "Voice room not found"
 	ErrorUnauthorized    = "You are not invited to this call"
 	ErrorPresenterActive = "A presenter is already active"
+	ErrorTeamArchived    = "Team is archived"
+	ErrorBannedFromTeam  = "You are banned from this team"
 )
 
 var upgrader = websocket.Upgrader{
@@ -47,6 +51,9 @@ type RoomResponse struct {
 
 type VoiceController struct {
 	userService  UserServiceInterface
+	teamService  TeamServiceInterface
+	recorder     service.EventRecorder
+	moderation   service.ModerationChecker
 	mu           sync.RWMutex
 	rooms        map[string]*entity.VoiceRoom
 	pendingDel   map[string]bool // tracks rooms scheduled for deletion
@@ -57,6 +64,9 @@ type VoiceController struct {
 func NewVoiceController() *VoiceController {
 	return &VoiceController{
 		userService:  service.NewUserService(),
+		teamService:  service.NewTeamService(),
+		recorder:     service.NewTeamEventService(),
+		moderation:   service.NewModerationService(),
 		rooms:        make(map[string]*entity.VoiceRoom),
 		pendingDel:   make(map[string]bool),
 		cleanupDelay: 5 * time.Second,
@@ -182,28 +192,42 @@ func (vc *VoiceController) GetJoinableRooms(c *gin.Context) {
 //	@Produce		json
 //	@Security		Bearer
 //	@Param			teamId	path		string	true	"Team ID"
-//	@Param			userId	query		string	true	"User ID of the creator"
 //	@Param			name	query		string	false	"Room name (optional)"
 //	@Success		201		{object}	entity.VoiceRoom
-//	@Failure		409		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string	"Unauthorized"
+//	@Failure		403		{object}	map[string]string	"Not a member of the team"
+//	@Failure		404		{object}	map[string]string	"Team not found"
+//	@Failure		409		{object}	map[string]string	"Room already exists or team is archived"
 //	@Router			/voice/rooms/{teamId} [post]
 func (vc *VoiceController) CreateVoiceRoom(c *gin.Context) {
+	userId, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
 	teamId := c.Param("teamId")
-	userId := c.Query("userId")
 	roomName := c.Query("name")
 
-	if roomName == "" {
-		roomName = DefaultRoomName
+	team, err := vc.teamService.GetTeamForMember(userId, teamId)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrResourceNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
 	}
-
-	vc.mu.RLock()
-	if _, exists := vc.rooms[teamId]; exists {
-		vc.mu.RUnlock()
-		log.Printf("[voice] CreateVoiceRoom: room already exists for teamId=%s", teamId)
-		c.JSON(http.StatusConflict, gin.H{"error": ErrorRoomExists})
+	if team.Archived {
+		c.JSON(http.StatusConflict, gin.H{"error": ErrorTeamArchived})
 		return
 	}
-	vc.mu.RUnlock()
+
+	if roomName == "" {
+		roomName = DefaultRoomName
+	}
 
 	newRoom := &entity.VoiceRoom{
 		Id:        teamId,
@@ -215,7 +239,14 @@ func (vc *VoiceController) CreateVoiceRoom(c *gin.Context) {
 		Clients:   make(map[*websocket.Conn]string),
 	}
 
+	// Checked under the write lock, so two requests can not both create the room
 	vc.mu.Lock()
+	if _, exists := vc.rooms[teamId]; exists {
+		vc.mu.Unlock()
+		log.Printf("[voice] CreateVoiceRoom: room already exists for teamId=%s", teamId)
+		c.JSON(http.StatusConflict, gin.H{"error": ErrorRoomExists})
+		return
+	}
 	vc.rooms[teamId] = newRoom
 
 	if vc.pendingDel[teamId] {
@@ -224,6 +255,7 @@ func (vc *VoiceController) CreateVoiceRoom(c *gin.Context) {
 	vc.mu.Unlock()
 
 	log.Printf("[voice] CreateVoiceRoom: created roomId=%s name=%q by userId=%s", teamId, roomName, userId)
+	vc.recorder.Record(entity.NewTeamEvent(teamId, entity.TeamEventVoiceSessionStarted, userId, newRoom.Id, roomName))
 	c.JSON(http.StatusCreated, newRoom)
 }
 
@@ -271,15 +303,19 @@ func (vc *VoiceController) GetActiveRooms(c *gin.Context) {
 //	@Description	Establishes a WebSocket connection for voice communication in a room
 //	@Security		Bearer
 //	@Param			roomId	path		string	true	"Room ID to join"
-//	@Param			userId	query		string	true	"User ID joining the room"
 //	@Success		101		{string}	string	"Switching Protocols"
 //	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
 //	@Failure		403		{object}	map[string]string
 //	@Failure		404		{object}	map[string]string
 //	@Router			/voice/join/{roomId} [get]
 func (vc *VoiceController) JoinVoiceRoom(c *gin.Context) {
 	roomId := c.Param("roomId")
-	userId := c.Query("userId")
+	userId, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
 
 	log.Printf("[voice] JoinVoiceRoom: request roomId=%s userId=%s", roomId, userId)
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
@@ -322,6 +358,13 @@ func (vc *VoiceController) JoinVoiceRoom(c *gin.Context) {
 		}
 	}
 
+	if room.Type == RoomTypeGroup && room.TeamId != "" {
+		if err := vc.moderation.CheckNotBanned(room.TeamId, userId); err != nil {
+			vc.sendErrorAndClose(conn, ErrorBannedFromTeam)
+			return
+		}
+	}
+
 	if !vc.canJoinRoom(room) {
 		vc.sendErrorAndClose(conn, ErrorRoomFull)
 		return
@@ -0,0 +1,367 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gin-gonic/gin"
+)
+
+const invalidRevision = "invalid revision number"
+
+type WikiController struct {
+	wikiService service.WikiServiceInterface
+}
+
+func NewWikiController() *WikiController {
+	return &WikiController{
+		wikiService: service.NewWikiService(),
+	}
+}
+
+func NewWikiControllerWithService(wikiService service.WikiServiceInterface) *WikiController {
+	return &WikiController{
+		wikiService: wikiService,
+	}
+}
+
+// GetPages
+//
+//	@Summary		List the pages of a team's wiki
+//	@Description	Pages are listed as a tree: every page is followed by its child pages. prefix only lists a page and the pages under it.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			prefix	query		string	false	"Only the pages under this path"
+//	@Success		200		{array}		dto.WikiPageSummary
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/wiki [get]
+func (wc *WikiController) GetPages(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := wc.wikiService.GetPages(userID, c.Param("id"), c.Query("prefix"))
+	if err != nil {
+		handleWikiError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetPage
+//
+//	@Summary		Get a wiki page by its path
+//	@Description	html is the content rendered server-side and sanitised; links written as file:<fileId>, quiz:<quizId> and wiki:<path> point to the team's files, quizzes and pages.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			path	path		string	true	"Page path, e.g. courses/algebra/week-1"
+//	@Success		200		{object}	dto.WikiPageResponse
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/wiki/pages/{path} [get]
+func (wc *WikiController) GetPage(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := wc.wikiService.GetPage(userID, c.Param("id"), c.Param("path"))
+	if err != nil {
+		handleWikiError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreatePage
+//
+//	@Summary		Create a wiki page
+//	@Description	Members only. The path is lowercased and spaces become dashes; parent pages do not need to exist.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"Team ID"
+//	@Param			request	body		dto.WikiPageRequest	true	"Page"
+//	@Success		201		{object}	dto.WikiPageResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/wiki [post]
+func (wc *WikiController) CreatePage(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.WikiPageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := wc.wikiService.CreatePage(userID, c.Param("id"), &request)
+	if err != nil {
+		handleWikiError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// UpdatePage
+//
+//	@Summary		Edit a wiki page
+//	@Description	Members only. Every change is saved as a new revision. With baseRevision set, the edit is rejected with 409 when someone else changed the page since.
+//	@Security		Bearer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Team ID"
+//	@Param			pageId	path		string						true	"Page ID"
+//	@Param			request	body		dto.UpdateWikiPageRequest	true	"Page"
+//	@Success		200		{object}	dto.WikiPageResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/wiki/{pageId} [put]
+func (wc *WikiController) UpdatePage(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var request dto.UpdateWikiPageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := wc.wikiService.UpdatePage(userID, c.Param("id"), c.Param("pageId"), &request)
+	if err != nil {
+		handleWikiError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeletePage
+//
+//	@Summary		Delete a wiki page and its history
+//	@Description	The creator of the page or a team admin. Child pages are kept.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			pageId	path		string	true	"Page ID"
+//	@Success		200		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		409		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/wiki/{pageId} [delete]
+func (wc *WikiController) DeletePage(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	if err := wc.wikiService.DeletePage(userID, c.Param("id"), c.Param("pageId")); err != nil {
+		handleWikiError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Page deleted successfully"})
+}
+
+// GetRevisions
+//
+//	@Summary	List the revisions of a wiki page, newest first
+//	@Security	Bearer
+//	@Produce	json
+//	@Param		id		path		string	true	"Team ID"
+//	@Param		pageId	path		string	true	"Page ID"
+//	@Success	200		{array}		dto.WikiRevisionSummary
+//	@Failure	401		{object}	map[string]string
+//	@Failure	403		{object}	map[string]string
+//	@Failure	404		{object}	map[string]string
+//	@Failure	500		{object}	map[string]string
+//	@Router		/teams/{id}/wiki/{pageId}/revisions [get]
+func (wc *WikiController) GetRevisions(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	resp, err := wc.wikiService.GetRevisions(userID, c.Param("id"), c.Param("pageId"))
+	if err != nil {
+		handleWikiError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRevision
+//
+//	@Summary	Get a revision of a wiki page with its rendered content
+//	@Security	Bearer
+//	@Produce	json
+//	@Param		id			path		string	true	"Team ID"
+//	@Param		pageId		path		string	true	"Page ID"
+//	@Param		revision	path		int		true	"Revision number"
+//	@Success	200			{object}	dto.WikiRevisionResponse
+//	@Failure	400			{object}	map[string]string
+//	@Failure	401			{object}	map[string]string
+//	@Failure	403			{object}	map[string]string
+//	@Failure	404			{object}	map[string]string
+//	@Failure	500			{object}	map[string]string
+//	@Router		/teams/{id}/wiki/{pageId}/revisions/{revision} [get]
+func (wc *WikiController) GetRevision(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidRevision})
+		return
+	}
+
+	resp, err := wc.wikiService.GetRevision(userID, c.Param("id"), c.Param("pageId"), number)
+	if err != nil {
+		handleWikiError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetDiff
+//
+//	@Summary		Compare two revisions of a wiki page
+//	@Description	Returns a unified diff of the content. to defaults to the current revision and from to the revision before to.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id		path		string	true	"Team ID"
+//	@Param			pageId	path		string	true	"Page ID"
+//	@Param			from	query		int		false	"Older revision"
+//	@Param			to		query		int		false	"Newer revision"
+//	@Success		200		{object}	dto.WikiDiffResponse
+//	@Failure		400		{object}	map[string]string
+//	@Failure		401		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string
+//	@Failure		404		{object}	map[string]string
+//	@Failure		500		{object}	map[string]string
+//	@Router			/teams/{id}/wiki/{pageId}/diff [get]
+func (wc *WikiController) GetDiff(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var from, to int
+	if f := c.Query("from"); f != "" {
+		if from, err = strconv.Atoi(f); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": invalidRevision})
+			return
+		}
+	}
+	if t := c.Query("to"); t != "" {
+		if to, err = strconv.Atoi(t); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": invalidRevision})
+			return
+		}
+	}
+
+	resp, err := wc.wikiService.GetDiff(userID, c.Param("id"), c.Param("pageId"), from, to)
+	if err != nil {
+		handleWikiError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevertPage
+//
+//	@Summary		Revert a wiki page to an older revision
+//	@Description	Members only. Saves the title and content of the revision as a new revision.
+//	@Security		Bearer
+//	@Produce		json
+//	@Param			id			path		string	true	"Team ID"
+//	@Param			pageId		path		string	true	"Page ID"
+//	@Param			revision	path		int		true	"Revision number"
+//	@Success		200			{object}	dto.WikiPageResponse
+//	@Failure		400			{object}	map[string]string
+//	@Failure		401			{object}	map[string]string
+//	@Failure		403			{object}	map[string]string
+//	@Failure		404			{object}	map[string]string
+//	@Failure		409			{object}	map[string]string
+//	@Failure		500			{object}	map[string]string
+//	@Router			/teams/{id}/wiki/{pageId}/revisions/{revision}/revert [post]
+func (wc *WikiController) RevertPage(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidRevision})
+		return
+	}
+
+	resp, err := wc.wikiService.RevertPage(userID, c.Param("id"), c.Param("pageId"), number)
+	if err != nil {
+		handleWikiError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func handleWikiError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrResourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
@@ -0,0 +1,201 @@
+// Package crdt implements a replicated text (an RGA sequence CRDT): every character gets a unique ID and is inserted
+// after the character it was typed after, so edits made concurrently or offline by several sites merge the same way
+// everywhere. Deleted characters are kept as tombstones so later operations can still refer to them.
+package crdt
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	OperationInsert = "insert"
+	OperationDelete = "delete"
+)
+
+var (
+	ErrUnknownOperation = errors.New("unknown operation type")
+	ErrUnknownElement   = errors.New("the operation refers to an unknown character")
+	ErrInvalidOperation = errors.New("invalid operation")
+)
+
+// ID is a Lamport timestamp: Counter is larger than every counter the site had seen when it made the character and
+// Site tells apart the characters made by different sites with the same counter. The zero ID is the start of the text.
+type ID struct {
+	Counter int    `json:"c"`
+	Site    string `json:"s"`
+}
+
+func (id ID) IsZero() bool {
+	return id.Counter == 0 && id.Site == ""
+}
+
+// Less orders the IDs by counter, then by site
+func (id ID) Less(other ID) bool {
+	if id.Counter != other.Counter {
+		return id.Counter < other.Counter
+	}
+	return id.Site < other.Site
+}
+
+// Element is a character of the text
+type Element struct {
+	ID      ID     `json:"id"`
+	Value   string `json:"v"`
+	Deleted bool   `json:"d,omitempty"`
+}
+
+// Operation inserts Value after the character After, or deletes the character ID. The characters of a multi-character
+// insert get consecutive counters starting at ID.Counter, each one inserted after the previous one.
+type Operation struct {
+	Type  string `json:"type"`
+	ID    ID     `json:"id"`
+	After ID     `json:"after,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// Text is a replicated text; it is not safe for concurrent use
+type Text struct {
+	elements []*Element
+	index    map[ID]*Element
+	clock    int
+}
+
+func NewText() *Text {
+	return &Text{index: make(map[ID]*Element)}
+}
+
+// NewTextFromElements restores a text from the elements of Elements, in order
+func NewTextFromElements(elements []Element) *Text {
+	t := &Text{
+		elements: make([]*Element, 0, len(elements)),
+		index:    make(map[ID]*Element, len(elements)),
+	}
+	for _, element := range elements {
+		e := element
+		t.elements = append(t.elements, &e)
+		t.index[e.ID] = &e
+		t.clock = max(t.clock, e.ID.Counter)
+	}
+	return t
+}
+
+// Apply applies the operation and reports whether it changed the text; applying an operation twice does nothing
+func (t *Text) Apply(op Operation) (bool, error) {
+	switch op.Type {
+	case OperationInsert:
+		return t.insert(op)
+	case OperationDelete:
+		element, ok := t.index[op.ID]
+		if !ok {
+			return false, ErrUnknownElement
+		}
+		if element.Deleted {
+			return false, nil
+		}
+		element.Deleted = true
+		return true, nil
+	default:
+		return false, ErrUnknownOperation
+	}
+}
+
+func (t *Text) insert(op Operation) (bool, error) {
+	if op.ID.Counter < 1 || op.ID.Site == "" || op.Value == "" {
+		return false, ErrInvalidOperation
+	}
+	existing := 0
+	id := op.ID
+	for range op.Value {
+		if _, ok := t.index[id]; ok {
+			existing++
+		}
+		id.Counter++
+	}
+	if existing > 0 {
+		if existing == len([]rune(op.Value)) {
+			return false, nil
+		}
+		// part of the run was already inserted by another operation
+		return false, ErrInvalidOperation
+	}
+
+	position := 0
+	if !op.After.IsZero() {
+		after, ok := t.index[op.After]
+		if !ok {
+			return false, ErrUnknownElement
+		}
+		position = t.position(after) + 1
+	}
+
+	id = op.ID
+	for _, r := range op.Value {
+		// characters inserted after the same one concurrently are ordered by ID, the newest first; the characters
+		// that follow with a larger ID were typed after them
+		for position < len(t.elements) && id.Less(t.elements[position].ID) {
+			position++
+		}
+		element := &Element{ID: id, Value: string(r)}
+		t.elements = append(t.elements, nil)
+		copy(t.elements[position+1:], t.elements[position:])
+		t.elements[position] = element
+		t.index[id] = element
+		t.clock = max(t.clock, id.Counter)
+
+		position++
+		id.Counter++
+	}
+	return true, nil
+}
+
+func (t *Text) position(element *Element) int {
+	for i, e := range t.elements {
+		if e == element {
+			return i
+		}
+	}
+	return -1
+}
+
+// String returns the visible text
+func (t *Text) String() string {
+	var b strings.Builder
+	for _, element := range t.elements {
+		if !element.Deleted {
+			b.WriteString(element.Value)
+		}
+	}
+	return b.String()
+}
+
+// Len returns the number of visible characters
+func (t *Text) Len() int {
+	n := 0
+	for _, element := range t.elements {
+		if !element.Deleted {
+			n++
+		}
+	}
+	return n
+}
+
+// Clock returns the largest counter seen; a site's next character must use a larger one
+func (t *Text) Clock() int {
+	return t.clock
+}
+
+// Contains reports whether the character exists, deleted or not
+func (t *Text) Contains(id ID) bool {
+	_, ok := t.index[id]
+	return ok
+}
+
+// Elements returns a copy of the characters in order, tombstones included
+func (t *Text) Elements() []Element {
+	elements := make([]Element, len(t.elements))
+	for i, element := range t.elements {
+		elements[i] = *element
+	}
+	return elements
+}
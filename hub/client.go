@@ -2,6 +2,8 @@ package hub
 
 import (
 	"net/http"
+	"strconv"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -13,19 +15,31 @@ const (
 	webSocketWriteBufferSize = 1024
 )
 
+// connectionCounter numbers the connections, so every client has its own ConnectionID
+var connectionCounter atomic.Uint64
+
+// Client is one connection; a ClientID, e.g. a user connected from several devices, can have many
 type Client[T any] struct {
-	ClientID string
-	Conn     *websocket.Conn
+	ClientID     string
+	ConnectionID string
+	Conn         *websocket.Conn
 
 	// Channel for sending messages to the client
 	outbound chan T
+	// Optional messages written before any other, see RegisterWithBacklog
+	backlog Backlog[T]
 }
 
+// Backlog returns the messages a connection gets before the ones sent to it, and which of the messages sent to it
+// meanwhile it already got with them; seen can be nil
+type Backlog[T any] func() (msgs []T, seen func(msg T) bool, err error)
+
 func NewClient[T any](clientID string, conn *websocket.Conn) *Client[T] {
 	return &Client[T]{
-		ClientID: clientID,
-		Conn:     conn,
-		outbound: make(chan T, clientOutboundBufferSize),
+		ClientID:     clientID,
+		ConnectionID: strconv.FormatUint(connectionCounter.Add(1), 10),
+		Conn:         conn,
+		outbound:     make(chan T, clientOutboundBufferSize),
 	}
 }
 
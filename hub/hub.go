@@ -13,57 +13,143 @@ const (
 
 	// MUST be LESS than readWait
 	pingFrequency = (readWait * 9) / 10 // pingFrequency = 90% * readWait
+
+	// maxMessageSize limits the messages clients send
+	maxMessageSize = 512 * 1024
 )
 
 type Hub[T any] struct {
-	// The clients connected to this hub
-	clients map[string]*Client[T]
+	// The connections of the clients connected to this hub, by ClientID and ConnectionID
+	clients map[string]map[string]*Client[T]
 	mu      sync.RWMutex
+
+	// Optional handlers for hubs whose clients also send messages
+	onMessage    func(client *Client[T], data []byte)
+	onConnect    func(client *Client[T])
+	onDisconnect func(client *Client[T])
+	onSend       func(clientID string, msg T) T
 }
 
 func NewHub[T any]() *Hub[T] {
 	return &Hub[T]{
-		clients: make(map[string]*Client[T]),
+		clients: make(map[string]map[string]*Client[T]),
 	}
 }
 
+// OnMessage sets the function called with every message a client sends; without it the messages are ignored.
+// It is called from the client's read loop, one message at a time.
+func (h *Hub[T]) OnMessage(handler func(client *Client[T], data []byte)) {
+	h.onMessage = handler
+}
+
+// OnConnect sets the function called once a connection is registered, before it gets any message
+func (h *Hub[T]) OnConnect(handler func(client *Client[T])) {
+	h.onConnect = handler
+}
+
+// OnSend sets the function every message goes through before it is sent, whether the client is connected or not
+func (h *Hub[T]) OnSend(handler func(clientID string, msg T) T) {
+	h.onSend = handler
+}
+
+// OnDisconnect sets the function called once a connection is unregistered
+func (h *Hub[T]) OnDisconnect(handler func(client *Client[T])) {
+	h.onDisconnect = handler
+}
+
+// Register adds the connection to the client's other connections, which all get the messages sent to the client
 func (h *Hub[T]) Register(client *Client[T]) {
 	h.mu.Lock()
-	h.clients[client.ClientID] = client
+	connections, ok := h.clients[client.ClientID]
+	if !ok {
+		connections = make(map[string]*Client[T])
+		h.clients[client.ClientID] = connections
+	}
+	connections[client.ConnectionID] = client
 	h.mu.Unlock()
 
+	if h.onConnect != nil {
+		h.onConnect(client)
+	}
+
 	// Start the read and write pump
 	go h.readPump(client)
 	go h.writePump(client)
 }
 
+// RegisterWithBacklog registers the connection, which gets the backlog's messages before the ones sent to it. The
+// backlog is read once the connection is registered, so no message sent meanwhile is missed; the connection is closed
+// when it fails.
+func (h *Hub[T]) RegisterWithBacklog(client *Client[T], backlog Backlog[T]) {
+	client.backlog = backlog
+	h.Register(client)
+}
+
+// Unregister removes the connection only; the client's other connections stay
 func (h *Hub[T]) Unregister(client *Client[T]) {
 	h.mu.Lock()
-	_, ok := h.clients[client.ClientID]
+	connections := h.clients[client.ClientID]
+	_, ok := connections[client.ConnectionID]
 	if ok {
-		delete(h.clients, client.ClientID)
+		delete(connections, client.ConnectionID)
+		if len(connections) == 0 {
+			delete(h.clients, client.ClientID)
+		}
+		// Closed under the lock so Send never writes to a closed channel
+		close(client.outbound)
 	}
 	h.mu.Unlock()
 
 	if ok {
-		close(client.outbound)
 		err := client.Conn.Close()
+		if h.onDisconnect != nil {
+			h.onDisconnect(client)
+		}
 		if err != nil {
 			return
 		}
 	}
 }
 
+// IsConnected reports whether the client has any connection registered
+func (h *Hub[T]) IsConnected(clientID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.clients[clientID]) > 0
+}
+
+// Send sends the message to every connection of the client
 func (h *Hub[T]) Send(clientID string, msg T) {
-	h.mu.Lock()
-	client, ok := h.clients[clientID]
-	h.mu.Unlock()
+	// Outside the lock, so the handler can wait for a client being registered
+	if h.onSend != nil {
+		msg = h.onSend(clientID, msg)
+	}
 
-	if !ok {
-		// Client is offline
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	// Nothing is sent when the client is offline
+	for _, client := range h.clients[clientID] {
+		enqueue(client, msg)
+	}
+}
+
+// SendToClient sends the message to this connection only, e.g. the answer to a message it sent. The message does not
+// go through the OnSend handler.
+func (h *Hub[T]) SendToClient(client *Client[T], msg T) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.clients[client.ClientID][client.ConnectionID] != client {
+		// Connection is closed
 		return
 	}
+	enqueue(client, msg)
+}
 
+// enqueue drops the message when the connection is too far behind; the caller holds the hub's lock
+func enqueue[T any](client *Client[T], msg T) {
 	select {
 	case client.outbound <- msg:
 		// Sent to outbound channel
@@ -86,6 +172,23 @@ func (h *Hub[T]) writePump(client *Client[T]) {
 		h.Unregister(client)
 	}()
 
+	var seen func(msg T) bool
+	if client.backlog != nil {
+		msgs, backlogSeen, err := client.backlog()
+		if err != nil {
+			return
+		}
+		for _, msg := range msgs {
+			if err := client.Conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+				return
+			}
+			if err := client.Conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+		seen = backlogSeen
+	}
+
 	for {
 		select {
 		case msg, ok := <-client.outbound:
@@ -96,6 +199,9 @@ func (h *Hub[T]) writePump(client *Client[T]) {
 					return
 				}
 			}
+			if seen != nil && seen(msg) {
+				continue
+			}
 
 			// Send the message as JSON
 			err := client.Conn.SetWriteDeadline(time.Now().Add(writeWait))
@@ -116,7 +222,7 @@ func (h *Hub[T]) writePump(client *Client[T]) {
 	}
 }
 
-// readPump continuously checks for disconnection
+// readPump continuously checks for disconnection and passes the client's messages to the message handler
 func (h *Hub[T]) readPump(client *Client[T]) {
 	defer func() {
 		h.Unregister(client)
@@ -132,11 +238,16 @@ func (h *Hub[T]) readPump(client *Client[T]) {
 		return client.Conn.SetReadDeadline(time.Now().Add(readWait))
 	})
 
+	client.Conn.SetReadLimit(maxMessageSize)
+
 	for {
-		_, _, err := client.Conn.ReadMessage()
+		_, data, err := client.Conn.ReadMessage()
 		if err != nil {
 			// WebSocket sent a disconnect message
 			return
 		}
+		if h.onMessage != nil {
+			h.onMessage(client, data)
+		}
 	}
 }
@@ -1,15 +1,82 @@
 package hub
 
+import "sync"
+
 type MessageType string
 
 const (
-	DirectMessage MessageType = "direct_message"
-	TeamBroadcast MessageType = "team_message"
+	DirectMessage       MessageType = "direct_message"
+	TeamBroadcast       MessageType = "team_message"
+	MessageEdited       MessageType = "message_edited"
+	MessageDeleted      MessageType = "message_deleted"
+	MessageReaction     MessageType = "message_reaction"
+	ThreadUpdated       MessageType = "thread_updated"
+	MessageRead         MessageType = "message_read"
+	Typing              MessageType = "typing"
+	Presence            MessageType = "presence"
+	Connected           MessageType = "connected"
+	CommandAck          MessageType = "ack"
+	CommandError        MessageType = "error"
+	ChannelCreated      MessageType = "channel_created"
+	ChannelUpdated      MessageType = "channel_updated"
+	TeamActivity        MessageType = "team_activity"
+	SessionReminder     MessageType = "session_reminder"
+	TaskBoardUpdated    MessageType = "task_board_updated"
+	TaskCreated         MessageType = "task_created"
+	TaskUpdated         MessageType = "task_updated"
+	TaskMoved           MessageType = "task_moved"
+	TaskDeleted         MessageType = "task_deleted"
+	TaskCommentAdded    MessageType = "task_comment_added"
+	AnnouncementPosted  MessageType = "announcement"
+	AnnouncementUpdated MessageType = "announcement_updated"
+	AnnouncementDeleted MessageType = "announcement_deleted"
+	MessagePinUpdated   MessageType = "message_pin_updated"
+	TeamModeration      MessageType = "team_moderation"
+	NoteState           MessageType = "note_state"
+	NoteOperations      MessageType = "note_ops"
+	NotePresence        MessageType = "note_presence"
+	NoteError           MessageType = "note_error"
+	NoteDeleted         MessageType = "note_deleted"
+)
+
+var (
+	messageHub     *Hub[Message]
+	messageHubOnce sync.Once
+	noteHub        *Hub[Message]
+	noteHubOnce    sync.Once
 )
 
+// GetMessageHub returns the hub shared by everything that pushes real-time messages to connected users. Use it through
+// service.GetMessageHub, which numbers its events before any is sent.
+func GetMessageHub() *Hub[Message] {
+	messageHubOnce.Do(func() {
+		messageHub = NewHub[Message]()
+	})
+	return messageHub
+}
+
+// GetNoteHub returns the hub of the collaborative note connections; its clients are connections, not users, since a
+// user can open several notes at once
+func GetNoteHub() *Hub[Message] {
+	noteHubOnce.Do(func() {
+		noteHub = NewHub[Message]()
+	})
+	return noteHub
+}
+
 type Message struct {
 	Type    MessageType `json:"type"`
 	Payload interface{} `json:"payload"`
+	Seq     int64       `json:"seq,omitempty"` // the recipient's sequence number of the message hub's events, see IsEphemeral
+}
+
+// IsEphemeral reports whether the events only make sense live: they are not numbered nor replayed after reconnecting
+func (t MessageType) IsEphemeral() bool {
+	switch t {
+	case Typing, Presence, CommandAck, CommandError, Connected:
+		return true
+	}
+	return false
 }
 
 func NewMessage(msgType MessageType, payload interface{}) *Message {
@@ -3,10 +3,12 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/docs"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/routes"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -34,6 +36,31 @@ func main() {
 
 	config.InitFirebase()
 
+	if err := service.NewTopicService().SeedDefaultTopics(); err != nil {
+		log.Printf("Error seeding the default topics: %v", err)
+	}
+
+	go func() {
+		if err := service.NewSearchService().ReindexIfEmpty(); err != nil {
+			log.Printf("Error building the search index: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := service.NewChannelService().MigrateTeamMessages(); err != nil {
+			log.Printf("Error moving team messages to channels: %v", err)
+		}
+		if err := service.NewMessageService().MigrateHistoryKeys(); err != nil {
+			log.Printf("Error adding history keys to messages: %v", err)
+		}
+		if err := service.NewConversationService().BuildIndexIfEmpty(); err != nil {
+			log.Printf("Error building the conversation index: %v", err)
+		}
+	}()
+
+	go service.NewStudySessionService().RunReminders(time.Minute)
+	go service.NewNoteService().RunSnapshots(30 * time.Second)
+
 	r := routes.SetupRoutes()
 
 	docs.SwaggerInfo.BasePath = "/"
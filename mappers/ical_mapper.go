@@ -0,0 +1,228 @@
+package mappers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+)
+
+const (
+	icalProdID          = "-//StudyWithMe//Study sessions//EN"
+	icalUIDDomain       = "studywithme"
+	icalRefreshInterval = "PT1H"
+	icalLineLimit       = 75
+	icalUTCFormat       = "20060102T150405Z"
+	icalLocalFormat     = "20060102T150405"
+	// icalTimezoneYears is how many years of timezone transitions are written after the first session
+	icalTimezoneYears = 5
+)
+
+// MapSessionsToICal renders the sessions as an RFC 5545 calendar. Sessions in a named timezone carry a VTIMEZONE
+// so recurrences keep their local time, sessions in UTC are written in UTC.
+func MapSessionsToICal(calendarName string, sessions []*entity.StudySession) string {
+	var b icalBuilder
+	b.line("BEGIN:VCALENDAR")
+	b.line("VERSION:2.0")
+	b.line("PRODID:" + icalProdID)
+	b.line("CALSCALE:GREGORIAN")
+	b.line("METHOD:PUBLISH")
+	b.line("X-WR-CALNAME:" + escapeICalText(calendarName))
+	b.line("REFRESH-INTERVAL;VALUE=DURATION:" + icalRefreshInterval)
+	b.line("X-PUBLISHED-TTL:" + icalRefreshInterval)
+
+	firstStart := make(map[string]time.Time)
+	for _, session := range sessions {
+		if session.Timezone == "" || session.Timezone == "UTC" {
+			continue
+		}
+		if start, ok := firstStart[session.Timezone]; !ok || session.StartAt.Before(start) {
+			firstStart[session.Timezone] = session.StartAt
+		}
+	}
+	timezones := make([]string, 0, len(firstStart))
+	for name := range firstStart {
+		timezones = append(timezones, name)
+	}
+	sort.Strings(timezones)
+	for _, name := range timezones {
+		if loc, err := utils.LoadLocation(name); err == nil {
+			writeTimezone(&b, name, loc, firstStart[name])
+		}
+	}
+
+	for _, session := range sessions {
+		writeSession(&b, session)
+	}
+
+	b.line("END:VCALENDAR")
+	return b.String()
+}
+
+func writeSession(b *icalBuilder, session *entity.StudySession) {
+	b.line("BEGIN:VEVENT")
+	b.line("UID:" + session.ID + "@" + icalUIDDomain)
+	b.line("DTSTAMP:" + session.UpdatedAt.UTC().Format(icalUTCFormat))
+	b.line("CREATED:" + session.CreatedAt.UTC().Format(icalUTCFormat))
+	b.line("LAST-MODIFIED:" + session.UpdatedAt.UTC().Format(icalUTCFormat))
+	b.line(fmt.Sprintf("SEQUENCE:%d", session.Sequence))
+
+	loc, err := utils.LoadLocation(session.Timezone)
+	if err != nil || loc == time.UTC {
+		b.line("DTSTART:" + session.StartAt.UTC().Format(icalUTCFormat))
+		b.line("DTEND:" + session.EndAt.UTC().Format(icalUTCFormat))
+	} else {
+		b.line("DTSTART;TZID=" + session.Timezone + ":" + session.StartAt.In(loc).Format(icalLocalFormat))
+		b.line("DTEND;TZID=" + session.Timezone + ":" + session.EndAt.In(loc).Format(icalLocalFormat))
+	}
+	if rule := formatRecurrence(session.Recurrence); rule != "" {
+		b.line("RRULE:" + rule)
+	}
+
+	b.line("SUMMARY:" + escapeICalText(session.Title))
+	if session.Description != "" {
+		b.line("DESCRIPTION:" + escapeICalText(session.Description))
+	}
+	if session.VoiceRoomID != "" {
+		b.line("LOCATION:" + escapeICalText("Voice room /voice/join/"+session.VoiceRoomID))
+	}
+	b.line("STATUS:CONFIRMED")
+
+	if session.ReminderMinutes > 0 {
+		b.line("BEGIN:VALARM")
+		b.line("ACTION:DISPLAY")
+		b.line("DESCRIPTION:" + escapeICalText(session.Title))
+		b.line(fmt.Sprintf("TRIGGER:-PT%dM", session.ReminderMinutes))
+		b.line("END:VALARM")
+	}
+	b.line("END:VEVENT")
+}
+
+func formatRecurrence(rule *entity.RecurrenceRule) string {
+	if rule == nil {
+		return ""
+	}
+
+	var frequency string
+	switch rule.Frequency {
+	case entity.RecurrenceDaily:
+		frequency = "DAILY"
+	case entity.RecurrenceWeekly:
+		frequency = "WEEKLY"
+	case entity.RecurrenceMonthly:
+		frequency = "MONTHLY"
+	default:
+		return ""
+	}
+
+	parts := []string{"FREQ=" + frequency}
+	if rule.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", rule.Interval))
+	}
+	if rule.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", rule.Count))
+	} else if rule.Until != nil {
+		// UNTIL has to be in UTC when DTSTART has a timezone
+		parts = append(parts, "UNTIL="+rule.Until.UTC().Format(icalUTCFormat))
+	}
+	return strings.Join(parts, ";")
+}
+
+// writeTimezone describes the location with one component per offset change, starting the year before from
+func writeTimezone(b *icalBuilder, name string, loc *time.Location, from time.Time) {
+	b.line("BEGIN:VTIMEZONE")
+	b.line("TZID:" + name)
+
+	start := time.Date(from.Year()-1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(time.Now().Year()+icalTimezoneYears, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if limit := start.AddDate(icalTimezoneYears*2, 0, 0); end.After(limit) {
+		end = limit
+	}
+
+	before := start.In(loc)
+	_, offset := before.Zone()
+	writeTimezoneComponent(b, before, offset)
+
+	for day := start.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+		_, dayOffset := day.In(loc).Zone()
+		if dayOffset == offset {
+			continue
+		}
+		transition := findTransition(loc, day.AddDate(0, 0, -1), day)
+		writeTimezoneComponent(b, transition.In(loc), offset)
+		offset = dayOffset
+	}
+
+	b.line("END:VTIMEZONE")
+}
+
+func writeTimezoneComponent(b *icalBuilder, onset time.Time, offsetFrom int) {
+	abbreviation, offsetTo := onset.Zone()
+	kind := "STANDARD"
+	if onset.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	b.line("BEGIN:" + kind)
+	// The onset is written in the local time that was in effect before it
+	b.line("DTSTART:" + onset.UTC().Add(time.Duration(offsetFrom)*time.Second).Format(icalLocalFormat))
+	b.line("TZOFFSETFROM:" + formatUTCOffset(offsetFrom))
+	b.line("TZOFFSETTO:" + formatUTCOffset(offsetTo))
+	b.line("TZNAME:" + abbreviation)
+	b.line("END:" + kind)
+}
+
+// findTransition returns the first second after low whose offset differs from low's
+func findTransition(loc *time.Location, low, high time.Time) time.Time {
+	_, lowOffset := low.In(loc).Zone()
+	for high.Sub(low) > time.Second {
+		mid := low.Add(high.Sub(low) / 2).Truncate(time.Second)
+		if _, offset := mid.In(loc).Zone(); offset == lowOffset {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return high
+}
+
+func formatUTCOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+func escapeICalText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// icalBuilder ends lines with CRLF and folds them at 75 octets without splitting UTF-8 characters
+type icalBuilder struct {
+	strings.Builder
+}
+
+func (b *icalBuilder) line(content string) {
+	limit := icalLineLimit
+	for len(content) > limit {
+		cut := limit
+		for !isRuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// The leading space of a continuation line counts toward its length
+		limit = icalLineLimit - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
@@ -0,0 +1,68 @@
+package mappers
+
+import (
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+)
+
+const (
+	searchWeightTitle    = 3
+	searchWeightSubtitle = 2
+	searchWeightBody     = 1
+	searchSnippetLength  = 140
+)
+
+func MapTeamToSearchDocument(team *entity.Team) *entity.SearchDocument {
+	doc := entity.NewSearchDocument(entity.SearchTypeTeam, team.Id, team.Name, truncate(team.Description))
+	doc.TeamID = team.Id
+	// Archived teams are left out of discovery, only their members still find them
+	doc.IsPublic = team.IsPublic && !team.Archived
+	doc.OrganizationID = team.OrganizationID
+	doc.AddTerms(utils.Tokenize(team.Name), searchWeightTitle)
+	doc.AddTerms(utils.Tokenize(team.Description), searchWeightBody)
+	return doc
+}
+
+func MapUserToSearchDocument(user *entity.User) *entity.SearchDocument {
+	fullName := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	doc := entity.NewSearchDocument(entity.SearchTypeUser, user.ID, user.Username, fullName)
+	doc.IsPublic = true
+	doc.OrganizationID = user.OrganizationID
+	doc.AddTerms(utils.Tokenize(user.Username), searchWeightTitle)
+	doc.AddTerms(utils.Tokenize(fullName), searchWeightSubtitle)
+	return doc
+}
+
+func MapQuizToSearchDocument(quiz entity.Quiz) *entity.SearchDocument {
+	questions := make([]string, 0, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		questions = append(questions, question.Question)
+	}
+	body := strings.Join(questions, " ")
+
+	doc := entity.NewSearchDocument(entity.SearchTypeQuiz, quiz.ID, quiz.QuizName, truncate(body))
+	doc.TeamID = quiz.TeamID
+	doc.AddTerms(utils.Tokenize(quiz.QuizName), searchWeightTitle)
+	doc.AddTerms(utils.Tokenize(body), searchWeightBody)
+	return doc
+}
+
+func MapMessageToSearchDocument(message *entity.Message) *entity.SearchDocument {
+	doc := entity.NewSearchDocument(entity.SearchTypeMessage, message.ID, "", truncate(message.TextContent))
+	doc.TeamID = message.TeamID
+	if message.ConversationKey != "" {
+		doc.MemberIDs = strings.Split(message.ConversationKey, "_")
+	}
+	doc.AddTerms(utils.Tokenize(message.TextContent), searchWeightBody)
+	return doc
+}
+
+func truncate(text string) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= searchSnippetLength {
+		return string(runes)
+	}
+	return string(runes[:searchSnippetLength]) + "…"
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+type AnnouncementRequest struct {
+	Title            string `json:"title"`
+	Body             string `json:"body"` // markdown
+	AttachmentFileID string `json:"attachmentFileId,omitempty"`
+	Pinned           bool   `json:"pinned,omitempty"` // only used when the announcement is created
+}
+
+type AnnouncementResponse struct {
+	ID                string           `json:"id"`
+	TeamID            string           `json:"teamId"`
+	AuthorID          string           `json:"authorId"`
+	Title             string           `json:"title"`
+	Body              string           `json:"body"`
+	AttachmentFileID  string           `json:"attachmentFileId,omitempty"`
+	PinnedAt          int64            `json:"pinnedAt,omitempty"`
+	PinnedBy          string           `json:"pinnedBy,omitempty"`
+	CreatedAt         time.Time        `json:"createdAt"`
+	UpdatedAt         time.Time        `json:"updatedAt"`
+	AcknowledgedCount int              `json:"acknowledgedCount"`
+	AcknowledgedByMe  bool             `json:"acknowledgedByMe"`
+	Acknowledgements  map[string]int64 `json:"acknowledgements,omitempty"` // only returned to team admins
+	PendingMemberIDs  []string         `json:"pendingMemberIds,omitempty"` // members who have not acknowledged yet, only returned to team admins
+}
+
+// NewAnnouncementResponse maps an announcement for a member, the acknowledgement details are only shown to admins
+func NewAnnouncementResponse(announcement *entity.Announcement, team *entity.Team, userId string) *AnnouncementResponse {
+	_, acknowledged := announcement.Acknowledgements[userId]
+	resp := &AnnouncementResponse{
+		ID:                announcement.ID,
+		TeamID:            announcement.TeamID,
+		AuthorID:          announcement.AuthorID,
+		Title:             announcement.Title,
+		Body:              announcement.Body,
+		AttachmentFileID:  announcement.AttachmentFileID,
+		PinnedAt:          announcement.PinnedAt,
+		PinnedBy:          announcement.PinnedBy,
+		CreatedAt:         announcement.CreatedAt,
+		UpdatedAt:         announcement.UpdatedAt,
+		AcknowledgedCount: len(announcement.Acknowledgements),
+		AcknowledgedByMe:  acknowledged,
+	}
+
+	if team.IsAdmin(userId) {
+		resp.Acknowledgements = announcement.Acknowledgements
+		resp.PendingMemberIDs = make([]string, 0)
+		for _, memberId := range team.UsersIds {
+			if _, ok := announcement.Acknowledgements[memberId]; !ok {
+				resp.PendingMemberIDs = append(resp.PendingMemberIDs, memberId)
+			}
+		}
+	}
+	return resp
+}
+
+type AnnouncementListResponse struct {
+	Announcements []*AnnouncementResponse `json:"announcements"`
+	Page          int                     `json:"page"`
+	Limit         int                     `json:"limit"`
+	TotalCount    int                     `json:"totalCount"`
+	TotalPages    int                     `json:"totalPages"`
+}
+
+// TeamPinsResponse lists what is pinned in a team, most recently pinned first
+type TeamPinsResponse struct {
+	Announcements []*AnnouncementResponse `json:"announcements"`
+	Messages      []*MessageDTO           `json:"messages"`
+}
+
+// AnnouncementDeleted is pushed to the team when an announcement is deleted
+type AnnouncementDeleted struct {
+	AnnouncementID string `json:"announcementId"`
+	TeamID         string `json:"teamId"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+type CalendarFeedRequest struct {
+	TeamID string `json:"teamId,omitempty"` // empty for the feed of all the caller's teams
+}
+
+type CalendarFeedResponse struct {
+	ID        string    `json:"id"`
+	TeamID    string    `json:"teamId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Token     string    `json:"token,omitempty"` // only returned when the feed is created
+	URL       string    `json:"url,omitempty"`   // only returned when the feed is created
+}
+
+func NewCalendarFeedResponse(feed *entity.CalendarFeed) CalendarFeedResponse {
+	return CalendarFeedResponse{
+		ID:        feed.ID,
+		TeamID:    feed.TeamID,
+		CreatedAt: feed.CreatedAt,
+	}
+}
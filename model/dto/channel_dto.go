@@ -0,0 +1,16 @@
+package dto
+
+type ChannelRequest struct {
+	Name string `json:"name"`
+}
+
+// UpdateChannelRequest renames and/or archives a channel; nil fields are left unchanged
+type UpdateChannelRequest struct {
+	Name     *string `json:"name,omitempty"`
+	Archived *bool   `json:"archived,omitempty"`
+}
+
+type ChannelMessageRequest struct {
+	TextContent string `json:"textContent"`
+	ParentID    string `json:"parentId,omitempty"` // replies to the message's thread
+}
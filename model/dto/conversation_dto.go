@@ -0,0 +1,34 @@
+package dto
+
+// ConversationDTO is an entry of the caller's inbox: a direct conversation or a channel of one of the caller's teams
+type ConversationDTO struct {
+	ConversationID string                  `json:"conversationId"`
+	Type           string                  `json:"type"`           // direct or channel
+	User           *SenderDTO              `json:"user,omitempty"` // the other user of a direct conversation
+	Team           *ConversationTeamDTO    `json:"team,omitempty"`
+	Channel        *ConversationChannelDTO `json:"channel,omitempty"`
+	LastMessage    *MessagePreviewDTO      `json:"lastMessage,omitempty"`
+	LastActivityAt int64                   `json:"lastActivityAt"` // unix milliseconds
+	UnreadCount    int                     `json:"unreadCount"`
+}
+
+type ConversationTeamDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ConversationChannelDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type MessagePreviewDTO struct {
+	ID          string `json:"id"`
+	SenderID    string `json:"senderId"`
+	TextContent string `json:"textContent"` // at most 100 characters, empty for deleted messages
+}
+
+type ConversationsResponse struct {
+	Conversations []ConversationDTO `json:"conversations"`
+	NextCursor    string            `json:"nextCursor,omitempty"` // passed as cursor for the next page, empty on the last one
+}
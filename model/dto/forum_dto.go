@@ -0,0 +1,101 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+type ForumQuestionRequest struct {
+	Title string   `json:"title"`
+	Body  string   `json:"body"` // markdown
+	Tags  []string `json:"tags,omitempty"`
+}
+
+type ForumAnswerRequest struct {
+	Body string `json:"body"` // markdown
+}
+
+// VoteRequest is 1 for an upvote, -1 for a downvote and 0 to take the vote back
+type VoteRequest struct {
+	Value int `json:"value"`
+}
+
+// ForumQuestionResponse is a question as a member sees it; who voted is not shown, only the member's own vote
+type ForumQuestionResponse struct {
+	ID               string    `json:"id"`
+	TeamID           string    `json:"teamId"`
+	AuthorID         string    `json:"authorId"`
+	Title            string    `json:"title"`
+	Body             string    `json:"body"`
+	Tags             []string  `json:"tags"`
+	Score            int       `json:"score"`
+	MyVote           int       `json:"myVote"`
+	AnswerCount      int       `json:"answerCount"`
+	AcceptedAnswerID string    `json:"acceptedAnswerId,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	LastActivityAt   time.Time `json:"lastActivityAt"`
+}
+
+func NewForumQuestionResponse(question *entity.ForumQuestion, userId string) *ForumQuestionResponse {
+	tags := question.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return &ForumQuestionResponse{
+		ID:               question.ID,
+		TeamID:           question.TeamID,
+		AuthorID:         question.AuthorID,
+		Title:            question.Title,
+		Body:             question.Body,
+		Tags:             tags,
+		Score:            question.Score,
+		MyVote:           question.Votes[userId],
+		AnswerCount:      question.AnswerCount,
+		AcceptedAnswerID: question.AcceptedAnswerID,
+		CreatedAt:        question.CreatedAt,
+		UpdatedAt:        question.UpdatedAt,
+		LastActivityAt:   question.LastActivityAt,
+	}
+}
+
+type ForumAnswerResponse struct {
+	ID         string    `json:"id"`
+	QuestionID string    `json:"questionId"`
+	AuthorID   string    `json:"authorId"`
+	Body       string    `json:"body"`
+	Score      int       `json:"score"`
+	MyVote     int       `json:"myVote"`
+	Accepted   bool      `json:"accepted"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+func NewForumAnswerResponse(answer *entity.ForumAnswer, acceptedAnswerId, userId string) *ForumAnswerResponse {
+	return &ForumAnswerResponse{
+		ID:         answer.ID,
+		QuestionID: answer.QuestionID,
+		AuthorID:   answer.AuthorID,
+		Body:       answer.Body,
+		Score:      answer.Score,
+		MyVote:     answer.Votes[userId],
+		Accepted:   answer.ID == acceptedAnswerId,
+		CreatedAt:  answer.CreatedAt,
+		UpdatedAt:  answer.UpdatedAt,
+	}
+}
+
+// ForumQuestionDetailsResponse is a question with its answers, the accepted one first, then by score
+type ForumQuestionDetailsResponse struct {
+	ForumQuestionResponse
+	Answers []*ForumAnswerResponse `json:"answers"`
+}
+
+type ForumQuestionListResponse struct {
+	Questions  []*ForumQuestionResponse `json:"questions"`
+	Page       int                      `json:"page"`
+	Limit      int                      `json:"limit"`
+	TotalCount int                      `json:"totalCount"`
+	TotalPages int                      `json:"totalPages"`
+}
@@ -1,17 +1,25 @@
 package dto
 
-import "time"
+import (
+	"sort"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
 
 type DirectMessageRequest struct {
 	SenderID    string `json:"senderId"`
 	ReceiverID  string `json:"receiverId"`
 	TextContent string `json:"textContent"`
+	ParentID    string `json:"parentId,omitempty"` // replies to the message's thread
 }
 
 type TeamMessageRequest struct {
 	SenderID    string `json:"senderId"`
 	TeamId      string `json:"teamId"`
+	ChannelID   string `json:"channelId,omitempty"` // the team's default channel when empty
 	TextContent string `json:"textContent"`
+	ParentID    string `json:"parentId,omitempty"` // replies to the message's thread
 }
 
 func NewDirectMessageRequest(senderId, receiverId, textContent string) *DirectMessageRequest {
@@ -31,21 +39,136 @@ func NewTeamMessageRequest(senderId, teamId, textContent string) *TeamMessageReq
 }
 
 type MessageDTO struct {
-	ID          string    `json:"id"`
-	Sender      SenderDTO `json:"sender"`
-	SentAt      string    `json:"sentAt"`
-	ReceiverID  string    `json:"receiverId,omitempty"`
-	TeamID      string    `json:"teamId,omitempty"`
-	TextContent string    `json:"textContent"`
+	ID          string        `json:"id"`
+	Sender      SenderDTO     `json:"sender"`
+	SentAt      string        `json:"sentAt"`
+	ReceiverID  string        `json:"receiverId,omitempty"`
+	TeamID      string        `json:"teamId,omitempty"`
+	ChannelID   string        `json:"channelId,omitempty"`
+	TextContent string        `json:"textContent"`
+	PinnedAt    int64         `json:"pinnedAt,omitempty"`
+	PinnedBy    string        `json:"pinnedBy,omitempty"`
+	Edited      bool          `json:"edited,omitempty"`
+	EditedAt    int64         `json:"editedAt,omitempty"`
+	Deleted     bool          `json:"deleted,omitempty"` // tombstone of a deleted message, without content
+	Reactions   []ReactionDTO `json:"reactions,omitempty"`
+	ParentID    string        `json:"parentId,omitempty"` // set on thread replies
+	ReplyCount  int           `json:"replyCount,omitempty"`
+	LastReplyAt int64         `json:"lastReplyAt,omitempty"`
+}
+
+// ReactionDTO aggregates the reactions to a message with one emoji
+type ReactionDTO struct {
+	Emoji       string `json:"emoji"`
+	Count       int    `json:"count"`
+	ReactedByMe bool   `json:"reactedByMe,omitempty"`
+}
+
+// MessageReactionEvent tells the participants of a conversation that a user added or removed a reaction
+type MessageReactionEvent struct {
+	MessageID  string        `json:"messageId"`
+	ReceiverID string        `json:"receiverId,omitempty"`
+	TeamID     string        `json:"teamId,omitempty"`
+	ChannelID  string        `json:"channelId,omitempty"`
+	UserID     string        `json:"userId"`
+	Emoji      string        `json:"emoji"`
+	Added      bool          `json:"added"`
+	Reactions  []ReactionDTO `json:"reactions"`
+}
+
+// ThreadResponse is a thread's first message with its replies, oldest first
+type ThreadResponse struct {
+	Parent  *MessageDTO   `json:"parent"`
+	Replies []*MessageDTO `json:"replies"`
+}
+
+// ThreadUpdatedEvent tells the participants of a conversation that a thread got a new reply, so they can update its badge
+type ThreadUpdatedEvent struct {
+	MessageID   string `json:"messageId"` // the thread's first message
+	ReceiverID  string `json:"receiverId,omitempty"`
+	TeamID      string `json:"teamId,omitempty"`
+	ChannelID   string `json:"channelId,omitempty"`
+	ReplyID     string `json:"replyId"`
+	ReplyCount  int    `json:"replyCount"`
+	LastReplyAt int64  `json:"lastReplyAt"`
+}
+
+// MessagePageQuery selects a page of a history: the latest messages, the ones before or after a cursor, or the ones
+// around a message. Cursors are message IDs or RFC 3339 timestamps; around takes a message ID.
+type MessagePageQuery struct {
+	Before string `form:"before"`
+	After  string `form:"after"`
+	Around string `form:"around"`
+	Limit  int    `form:"limit"`
+}
+
+// IsSet reports whether the query asks for a page rather than the whole history
+func (q *MessagePageQuery) IsSet() bool {
+	return q.Before != "" || q.After != "" || q.Around != "" || q.Limit != 0
 }
 
-func NewMessageDTO(id, receiverId, teamId, textContent string, sentAt time.Time, sender SenderDTO) *MessageDTO {
+type MessagePageResponse struct {
+	Messages   []*MessageDTO `json:"messages"`             // oldest first
+	PrevCursor string        `json:"prevCursor,omitempty"` // pass as before to get older messages, empty when there are none
+	NextCursor string        `json:"nextCursor,omitempty"` // pass as after to get newer messages, empty on the latest page
+}
+
+type EditMessageRequest struct {
+	TextContent string `json:"textContent"`
+}
+
+// MessageHistoryResponse lists the versions of a message, oldest first, the current one last
+type MessageHistoryResponse struct {
+	MessageID string               `json:"messageId"`
+	Versions  []entity.MessageEdit `json:"versions"`
+}
+
+func NewMessageDTO(id, receiverId, teamId, channelId, textContent string, sentAt time.Time, sender SenderDTO) *MessageDTO {
 	return &MessageDTO{
 		ID:          id,
 		Sender:      sender,
 		SentAt:      sentAt.Format(time.RFC3339),
 		ReceiverID:  receiverId,
 		TeamID:      teamId,
+		ChannelID:   channelId,
 		TextContent: textContent,
 	}
 }
+
+// NewMessageDTOFromEntity maps a stored message, including its state beyond the content. viewerId is the user the
+// message is shown to, for reactedByMe; it is empty for messages pushed to several users.
+func NewMessageDTOFromEntity(message *entity.Message, receiverId, viewerId string, sender SenderDTO) *MessageDTO {
+	dtoMessage := NewMessageDTO(message.ID, receiverId, message.TeamID, message.ChannelID, message.TextContent, message.SentAt, sender)
+	dtoMessage.PinnedAt = message.PinnedAt
+	dtoMessage.PinnedBy = message.PinnedBy
+	dtoMessage.Edited = message.EditedAt != 0
+	dtoMessage.EditedAt = message.EditedAt
+	dtoMessage.Deleted = message.IsDeleted()
+	dtoMessage.Reactions = NewReactionDTOs(message, viewerId)
+	dtoMessage.ParentID = message.ParentID
+	dtoMessage.ReplyCount = message.ReplyCount
+	dtoMessage.LastReplyAt = message.LastReplyAt
+	return dtoMessage
+}
+
+// NewReactionDTOs counts the message's reactions per emoji, the most used first
+func NewReactionDTOs(message *entity.Message, viewerId string) []ReactionDTO {
+	reactions := make([]ReactionDTO, 0, len(message.Reactions))
+	for emoji, userIds := range message.Reactions {
+		if len(userIds) == 0 {
+			continue
+		}
+		reactions = append(reactions, ReactionDTO{
+			Emoji:       emoji,
+			Count:       len(userIds),
+			ReactedByMe: message.HasReacted(viewerId, emoji),
+		})
+	}
+	sort.Slice(reactions, func(i, j int) bool {
+		if reactions[i].Count != reactions[j].Count {
+			return reactions[i].Count > reactions[j].Count
+		}
+		return reactions[i].Emoji < reactions[j].Emoji
+	})
+	return reactions
+}
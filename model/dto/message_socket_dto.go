@@ -0,0 +1,66 @@
+package dto
+
+// The commands clients send over the message WebSocket
+const (
+	MessageCommandSend     = "send_message"
+	MessageCommandEdit     = "edit"
+	MessageCommandDelete   = "delete"
+	MessageCommandTyping   = "typing"
+	MessageCommandMarkRead = "mark_read"
+	MessageCommandPresence = "presence"
+)
+
+// The codes of the command errors, matching the status codes of the REST endpoints
+const (
+	CommandErrorInvalid   = "invalid"   // 400
+	CommandErrorForbidden = "forbidden" // 403
+	CommandErrorNotFound  = "not_found" // 404
+	CommandErrorConflict  = "conflict"  // 409
+	CommandErrorInternal  = "internal"  // 500
+)
+
+// MessageCommand is what clients send over the message WebSocket, instead of calling the REST endpoints. The requestId
+// is chosen by the client and comes back in the command's ack or error.
+type MessageCommand struct {
+	Type        string `json:"type"`
+	RequestID   string `json:"requestId,omitempty"`
+	MessageID   string `json:"messageId,omitempty"`  // edit, delete and mark_read
+	ReceiverID  string `json:"receiverId,omitempty"` // send_message and typing in a direct conversation
+	TeamID      string `json:"teamId,omitempty"`     // send_message and typing in a team, instead of receiverId
+	ChannelID   string `json:"channelId,omitempty"`
+	ParentID    string `json:"parentId,omitempty"`
+	TextContent string `json:"textContent,omitempty"` // send_message and edit
+	Stopped     bool   `json:"stopped,omitempty"`     // typing: the user stopped typing
+	Status      string `json:"status,omitempty"`      // presence: online or away
+}
+
+// CommandAck confirms a command; the message is the one sent, edited or deleted, as stored
+type CommandAck struct {
+	RequestID  string         `json:"requestId"`
+	Message    *MessageDTO    `json:"message,omitempty"`
+	ReadMarker *ReadMarkerDTO `json:"readMarker,omitempty"` // mark_read
+}
+
+type CommandError struct {
+	RequestID string `json:"requestId,omitempty"`
+	Code      string `json:"code"`
+	Error     string `json:"error"`
+}
+
+// TypingEvent tells the other users of a conversation that the user started or stopped typing. Typing stops by itself
+// when the user sends no typing command for a few seconds, sends the message or disconnects.
+type TypingEvent struct {
+	UserID     string `json:"userId"`
+	ReceiverID string `json:"receiverId,omitempty"`
+	TeamID     string `json:"teamId,omitempty"`
+	ChannelID  string `json:"channelId,omitempty"`
+	Typing     bool   `json:"typing"`
+}
+
+// ConnectedEvent is the first event of a connection, sent once the events missed since the seq given on connect are
+// replayed
+type ConnectedEvent struct {
+	Seq       int64 `json:"seq"` // the user's latest sequence number
+	Replayed  int   `json:"replayed"`
+	Truncated bool  `json:"truncated,omitempty"` // some missed events are no longer kept, so the client should reload
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+
+// ModerationRequest is the body of kick, ban and mute requests. DurationMinutes is ignored for kicks,
+// optional for bans (0 bans permanently) and required for mutes.
+type ModerationRequest struct {
+	Reason          string `json:"reason,omitempty"`
+	DurationMinutes int    `json:"durationMinutes,omitempty"`
+}
+
+// TeamRestrictionsResponse lists the bans and mutes of a team that are still in effect
+type TeamRestrictionsResponse struct {
+	Bans  []*entity.TeamRestriction `json:"bans"`
+	Mutes []*entity.TeamRestriction `json:"mutes"`
+}
+
+type ModerationLogResponse struct {
+	Entries    []*entity.ModerationLogEntry `json:"entries"`
+	Page       int                          `json:"page"`
+	Limit      int                          `json:"limit"`
+	TotalCount int                          `json:"totalCount"`
+	TotalPages int                          `json:"totalPages"`
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/crdt"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	NoteClientOperations = "ops"
+	NoteClientCursor     = "cursor"
+	NoteClientSync       = "sync"
+)
+
+type NoteRequest struct {
+	Title string `json:"title"`
+}
+
+// NoteResponse is a note with its current text and the members editing it now
+type NoteResponse struct {
+	entity.Note
+	Text      string   `json:"text,omitempty"`
+	EditorIDs []string `json:"editorIds"`
+}
+
+// NoteClientMessage is what clients send over the note WebSocket: "ops" with the operations made locally, "cursor"
+// with the client's cursor, or "sync" to get the changes made since Version
+type NoteClientMessage struct {
+	Type    string           `json:"type"`
+	Ops     []crdt.Operation `json:"ops,omitempty"`
+	Cursor  *NoteCursor      `json:"cursor,omitempty"`
+	Version int              `json:"version,omitempty"`
+}
+
+// NoteCursor places a cursor after the character Anchor (the zero ID is the start of the text); Head is the other
+// end of the selection, equal to Anchor when nothing is selected
+type NoteCursor struct {
+	Anchor crdt.ID `json:"anchor"`
+	Head   crdt.ID `json:"head"`
+}
+
+type NotePresence struct {
+	ConnectionID string      `json:"connectionId"`
+	UserID       string      `json:"userId"`
+	Cursor       *NoteCursor `json:"cursor,omitempty"`
+	Left         bool        `json:"left,omitempty"`
+}
+
+// NoteStatePayload is sent when a client connects: the whole text, or with CatchUp the operations that bring the
+// client's text from BaseVersion to Version. Site is the site the client must use in the IDs of its characters and
+// Clock the largest counter used so far.
+type NoteStatePayload struct {
+	NoteID       string           `json:"noteId"`
+	ConnectionID string           `json:"connectionId"`
+	Site         string           `json:"site"`
+	Version      int              `json:"version"`
+	Clock        int              `json:"clock"`
+	CatchUp      bool             `json:"catchUp"`
+	Elements     []crdt.Element   `json:"elements,omitempty"`
+	BaseVersion  int              `json:"baseVersion,omitempty"`
+	Ops          []crdt.Operation `json:"ops,omitempty"`
+	Presence     []*NotePresence  `json:"presence"`
+}
+
+// NoteOperationsPayload brings the text from BaseVersion to Version. ConnectionID is the connection that made the
+// operations; it is empty when the operations are sent to catch up.
+type NoteOperationsPayload struct {
+	NoteID       string           `json:"noteId"`
+	BaseVersion  int              `json:"baseVersion"`
+	Version      int              `json:"version"`
+	Ops          []crdt.Operation `json:"ops"`
+	ConnectionID string           `json:"connectionId,omitempty"`
+	UserID       string           `json:"userId,omitempty"`
+}
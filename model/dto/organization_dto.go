@@ -0,0 +1,6 @@
+package dto
+
+type OrganizationRequest struct {
+	Name         string   `json:"name"`
+	EmailDomains []string `json:"emailDomains"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type PresenceDTO struct {
+	UserID     string `json:"userId"`
+	Status     string `json:"status"`               // online, away or offline
+	LastSeenAt int64  `json:"lastSeenAt,omitempty"` // unix seconds; now for connected users, empty for users never seen
+}
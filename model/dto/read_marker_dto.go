@@ -0,0 +1,36 @@
+package dto
+
+import "github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+
+type ReadMarkerDTO struct {
+	UserID         string `json:"userId"`
+	ConversationID string `json:"conversationId"`
+	MessageID      string `json:"messageId,omitempty"`
+	ReadAt         int64  `json:"readAt,omitempty"`
+}
+
+func NewReadMarkerDTO(marker *entity.ReadMarker) *ReadMarkerDTO {
+	return &ReadMarkerDTO{
+		UserID:         marker.UserID,
+		ConversationID: marker.ConversationID,
+		MessageID:      marker.MessageID,
+		ReadAt:         marker.ReadAt,
+	}
+}
+
+// UnreadCountDTO is the number of messages of a conversation the user has not read yet, thread replies and the user's
+// own messages left out
+type UnreadCountDTO struct {
+	ConversationID    string `json:"conversationId"`
+	Type              string `json:"type"`             // direct or channel
+	UserID            string `json:"userId,omitempty"` // the other user of a direct conversation
+	TeamID            string `json:"teamId,omitempty"`
+	ChannelID         string `json:"channelId,omitempty"`
+	UnreadCount       int    `json:"unreadCount"`
+	LastReadMessageID string `json:"lastReadMessageId,omitempty"`
+}
+
+type UnreadCountsResponse struct {
+	TotalUnread   int              `json:"totalUnread"`
+	Conversations []UnreadCountDTO `json:"conversations"`
+}
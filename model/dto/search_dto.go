@@ -0,0 +1,30 @@
+package dto
+
+type SearchResult struct {
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	Title   string `json:"title,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+	TeamID  string `json:"teamId,omitempty"`
+	Score   int    `json:"score"`
+}
+
+type SearchResponse struct {
+	Query      string         `json:"query"`
+	Results    []SearchResult `json:"results"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+	TotalCount int            `json:"totalCount"`
+	TotalPages int            `json:"totalPages"`
+}
+
+func NewSearchResult(docType, id, title, snippet, teamId string, score int) SearchResult {
+	return SearchResult{
+		Type:    docType,
+		ID:      id,
+		Title:   title,
+		Snippet: snippet,
+		TeamID:  teamId,
+		Score:   score,
+	}
+}
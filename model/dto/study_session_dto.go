@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+type StudySessionRequest struct {
+	Title           string                 `json:"title"`
+	Description     string                 `json:"description,omitempty"`
+	StartAt         time.Time              `json:"startAt"`
+	EndAt           time.Time              `json:"endAt"`
+	Timezone        string                 `json:"timezone,omitempty"` // UTC when empty
+	Recurrence      *entity.RecurrenceRule `json:"recurrence,omitempty"`
+	LinkVoiceRoom   bool                   `json:"linkVoiceRoom,omitempty"`
+	ReminderMinutes *int                   `json:"reminderMinutes,omitempty"` // 15 when empty
+}
+
+type RSVPRequest struct {
+	Status entity.RSVPStatus `json:"status"`
+}
+
+// SessionConflict is an occurrence of another session the member is going to that overlaps the session
+type SessionConflict struct {
+	SessionID string    `json:"sessionId"`
+	TeamID    string    `json:"teamId"`
+	Title     string    `json:"title"`
+	StartAt   time.Time `json:"startAt"`
+	EndAt     time.Time `json:"endAt"`
+}
+
+type StudySessionResponse struct {
+	Session   *entity.StudySession `json:"session"`
+	Conflicts []SessionConflict    `json:"conflicts,omitempty"`
+}
+
+type SessionOccurrence struct {
+	SessionID   string            `json:"sessionId"`
+	TeamID      string            `json:"teamId"`
+	Title       string            `json:"title"`
+	StartAt     time.Time         `json:"startAt"`
+	EndAt       time.Time         `json:"endAt"`
+	VoiceRoomID string            `json:"voiceRoomId,omitempty"`
+	RSVP        entity.RSVPStatus `json:"rsvp"`
+}
+
+func NewSessionOccurrence(session *entity.StudySession, startAt time.Time, userId string) SessionOccurrence {
+	return SessionOccurrence{
+		SessionID:   session.ID,
+		TeamID:      session.TeamID,
+		Title:       session.Title,
+		StartAt:     startAt,
+		EndAt:       startAt.Add(session.EndAt.Sub(session.StartAt)),
+		VoiceRoomID: session.VoiceRoomID,
+		RSVP:        session.GetRSVP(userId),
+	}
+}
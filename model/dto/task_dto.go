@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+type BoardColumnRequest struct {
+	Name string `json:"name"`
+}
+
+type UpdateBoardColumnRequest struct {
+	Name     *string `json:"name,omitempty"`
+	Position *int    `json:"position,omitempty"` // new index of the column on the board
+}
+
+// TaskRequest creates a task or replaces its content, the column and position only change through a move
+type TaskRequest struct {
+	ColumnID    string                 `json:"columnId,omitempty"` // the first column when empty
+	Title       string                 `json:"title"`
+	Description string                 `json:"description,omitempty"`
+	AssigneeIDs []string               `json:"assigneeIds,omitempty"`
+	DueAt       *time.Time             `json:"dueAt,omitempty"`
+	Labels      []string               `json:"labels,omitempty"`
+	Checklist   []entity.ChecklistItem `json:"checklist,omitempty"` // items without an id are new
+}
+
+type MoveTaskRequest struct {
+	ColumnID string `json:"columnId"`
+	Position int    `json:"position"` // index in the column, past the end means last
+}
+
+type TaskCommentRequest struct {
+	Text string `json:"text"`
+}
+
+type BoardColumnResponse struct {
+	entity.BoardColumn
+	Tasks []*entity.Task `json:"tasks"`
+}
+
+type TaskBoardResponse struct {
+	TeamID  string                `json:"teamId"`
+	Columns []BoardColumnResponse `json:"columns"`
+}
+
+type TaskDetailsResponse struct {
+	Task     *entity.Task          `json:"task"`
+	Comments []*entity.TaskComment `json:"comments"`
+}
+
+// TaskMoved is pushed to the team when a task changes column or position, the tasks after it shift by one
+type TaskMoved struct {
+	TaskID       string `json:"taskId"`
+	TeamID       string `json:"teamId"`
+	FromColumnID string `json:"fromColumnId"`
+	ColumnID     string `json:"columnId"`
+	Position     int    `json:"position"`
+}
+
+type TaskDeleted struct {
+	TaskID string `json:"taskId"`
+	TeamID string `json:"teamId"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+
+type TeamActivityResponse struct {
+	Events     []*entity.TeamEvent `json:"events"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	TotalCount int                 `json:"totalCount"`
+	TotalPages int                 `json:"totalPages"`
+}
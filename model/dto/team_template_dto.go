@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+// TeamTemplateRequest saves a team as a template. Include lists the content types to keep, all of them when empty.
+type TeamTemplateRequest struct {
+	Name    string                   `json:"name"`
+	Include []entity.TemplateContent `json:"include,omitempty"`
+}
+
+// NewTeamFromTemplateRequest creates a team from a template or clones a team. Description and IsPublic default to the
+// template's or the source team's; Include lists the content types to copy, all of them when empty.
+type NewTeamFromTemplateRequest struct {
+	Name        string                   `json:"name"`
+	Description *string                  `json:"description,omitempty"`
+	IsPublic    *bool                    `json:"ispublic,omitempty"`
+	Include     []entity.TemplateContent `json:"include,omitempty"`
+}
+
+// TeamTemplateSummary describes a template without its content
+type TeamTemplateSummary struct {
+	ID           string                   `json:"id"`
+	Name         string                   `json:"name"`
+	SourceTeamID string                   `json:"sourceTeamId,omitempty"`
+	Contents     []entity.TemplateContent `json:"contents"`
+	Channels     int                      `json:"channels"`
+	Quizzes      int                      `json:"quizzes"`
+	Tasks        int                      `json:"tasks"`
+	CreatedAt    time.Time                `json:"createdAt"`
+}
+
+func NewTeamTemplateSummary(template *entity.TeamTemplate) *TeamTemplateSummary {
+	return &TeamTemplateSummary{
+		ID:           template.ID,
+		Name:         template.Name,
+		SourceTeamID: template.SourceTeamID,
+		Contents:     template.Contents,
+		Channels:     len(template.Channels),
+		Quizzes:      len(template.Quizzes),
+		Tasks:        len(template.Tasks),
+		CreatedAt:    template.CreatedAt,
+	}
+}
+
+// NewTeamFromTemplateResponse is the created team and how much content was copied into it
+type NewTeamFromTemplateResponse struct {
+	Team     *entity.Team `json:"team"`
+	Channels int          `json:"channels"`
+	Quizzes  int          `json:"quizzes"`
+	Tasks    int          `json:"tasks"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+type TopicRequest struct {
+	Slug       model.TopicOfInterest `json:"slug"`
+	Names      map[string]string     `json:"names"`
+	ParentSlug model.TopicOfInterest `json:"parentSlug,omitempty"`
+}
+
+type UpdateTopicRequest struct {
+	Names      map[string]string     `json:"names"`
+	ParentSlug model.TopicOfInterest `json:"parentSlug,omitempty"`
+}
+
+type TopicResponse struct {
+	Slug        model.TopicOfInterest   `json:"slug"`
+	DisplayName string                  `json:"displayName"`
+	Names       map[string]string       `json:"names"`
+	ParentSlug  model.TopicOfInterest   `json:"parentSlug,omitempty"`
+	Children    []model.TopicOfInterest `json:"children,omitempty"`
+	UserCount   *int                    `json:"userCount,omitempty"`
+	TeamCount   *int                    `json:"teamCount,omitempty"`
+}
+
+func NewTopicResponse(topic *entity.Topic, lang string) *TopicResponse {
+	return &TopicResponse{
+		Slug:        topic.Slug,
+		DisplayName: topic.DisplayName(lang),
+		Names:       topic.Names,
+		ParentSlug:  topic.ParentSlug,
+	}
+}
@@ -12,6 +12,7 @@ type SignUpUserRequest struct {
 	Email            string                   `json:"email"`
 	Password         string                   `json:"password"`
 	TopicsOfInterest *[]model.TopicOfInterest `json:"topicsOfInterest,omitempty"`
+	OrganizationID   string                   `json:"organizationId,omitempty"` // optional, by default users join the organization owning their email domain
 }
 
 type SignUpUserResponse struct {
@@ -75,6 +76,7 @@ type UserResponse struct {
 	TopicsOfInterest *[]model.TopicOfInterest `json:"topicsOfInterest,omitempty"`
 	TeamsIds         *[]string                `json:"teams,omitempty"`
 	Statistics       *model.Statistics        `json:"statistics,omitempty"`
+	IsAdmin          bool                     `json:"isAdmin,omitempty"`
 }
 
 // NewUserResponse converts an entity.User to a safe UserResponse (omits password).
@@ -85,6 +87,7 @@ func NewUserResponse(u *entity.User) UserResponse {
 		LastName:  u.LastName,
 		Username:  u.Username,
 		Email:     u.Email,
+		IsAdmin:   u.IsAdmin,
 	}
 	if u.TopicsOfInterest != nil {
 		resp.TopicsOfInterest = u.TopicsOfInterest
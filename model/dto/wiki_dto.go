@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+type WikiPageRequest struct {
+	Path    string `json:"path"` // e.g. "courses/algebra/week-1"
+	Title   string `json:"title"`
+	Content string `json:"content"` // markdown
+	Summary string `json:"summary,omitempty"`
+}
+
+// UpdateWikiPageRequest edits a page. An empty path keeps the current one; when BaseRevision is set and the page has
+// been changed since that revision the edit is rejected instead of overwriting the other change.
+type UpdateWikiPageRequest struct {
+	Path         string `json:"path,omitempty"`
+	Title        string `json:"title"`
+	Content      string `json:"content"` // markdown
+	Summary      string `json:"summary,omitempty"`
+	BaseRevision int    `json:"baseRevision,omitempty"`
+}
+
+type WikiPageSummary struct {
+	ID         string    `json:"id"`
+	Path       string    `json:"path"`
+	ParentPath string    `json:"parentPath,omitempty"`
+	Depth      int       `json:"depth"` // 0 for top level pages
+	Title      string    `json:"title"`
+	Revision   int       `json:"revision"`
+	UpdatedBy  string    `json:"updatedBy"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+func NewWikiPageSummary(page *entity.WikiPage) *WikiPageSummary {
+	parentPath := ""
+	if i := strings.LastIndex(page.Path, "/"); i >= 0 {
+		parentPath = page.Path[:i]
+	}
+	return &WikiPageSummary{
+		ID:         page.ID,
+		Path:       page.Path,
+		ParentPath: parentPath,
+		Depth:      strings.Count(page.Path, "/"),
+		Title:      page.Title,
+		Revision:   page.Revision,
+		UpdatedBy:  page.UpdatedBy,
+		UpdatedAt:  page.UpdatedAt,
+	}
+}
+
+// WikiPageResponse is a page with its content rendered to sanitised HTML and its direct child pages
+type WikiPageResponse struct {
+	entity.WikiPage
+	HTML     string             `json:"html"`
+	Children []*WikiPageSummary `json:"children"`
+}
+
+type WikiRevisionSummary struct {
+	Number       int       `json:"number"`
+	Path         string    `json:"path"`
+	Title        string    `json:"title"`
+	Summary      string    `json:"summary,omitempty"`
+	RevertedFrom int       `json:"revertedFrom,omitempty"`
+	AuthorID     string    `json:"authorId"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func NewWikiRevisionSummary(revision *entity.WikiRevision) *WikiRevisionSummary {
+	return &WikiRevisionSummary{
+		Number:       revision.Number,
+		Path:         revision.Path,
+		Title:        revision.Title,
+		Summary:      revision.Summary,
+		RevertedFrom: revision.RevertedFrom,
+		AuthorID:     revision.AuthorID,
+		CreatedAt:    revision.CreatedAt,
+	}
+}
+
+type WikiRevisionResponse struct {
+	entity.WikiRevision
+	HTML string `json:"html"`
+}
+
+// WikiDiffResponse is a unified diff of the content of two revisions
+type WikiDiffResponse struct {
+	PageID    string `json:"pageId"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	FromTitle string `json:"fromTitle"`
+	ToTitle   string `json:"toTitle"`
+	Diff      string `json:"diff"`
+	Added     int    `json:"added"`
+	Removed   int    `json:"removed"`
+}
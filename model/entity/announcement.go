@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// Announcement is a notice posted by a team admin. Body is markdown, rendering is left to the clients.
+type Announcement struct {
+	ID               string           `json:"id"`
+	TeamID           string           `json:"teamId"`
+	AuthorID         string           `json:"authorId"`
+	Title            string           `json:"title"`
+	Body             string           `json:"body"`
+	AttachmentFileID string           `json:"attachmentFileId,omitempty"` // one of the team's files
+	PinnedAt         int64            `json:"pinnedAt,omitempty"`         // unix seconds, 0 when not pinned
+	PinnedBy         string           `json:"pinnedBy,omitempty"`
+	Acknowledgements map[string]int64 `json:"acknowledgements,omitempty"` // member ID to unix seconds
+	CreatedAt        time.Time        `json:"createdAt"`
+	UpdatedAt        time.Time        `json:"updatedAt"`
+}
+
+func NewAnnouncement(id, teamId, authorId, title, body, attachmentFileId string) *Announcement {
+	now := time.Now().UTC()
+	return &Announcement{
+		ID:               id,
+		TeamID:           teamId,
+		AuthorID:         authorId,
+		Title:            title,
+		Body:             body,
+		AttachmentFileID: attachmentFileId,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// CalendarFeed gives calendar apps access to a user's study sessions through a secret URL. The ID is the SHA-256 of
+// the secret token, the token itself is only shown once when the feed is created.
+type CalendarFeed struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"userId"`
+	TeamID    string    `json:"teamId,omitempty"` // empty for the feed of all the user's teams
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func NewCalendarFeed(id, userId, teamId string) *CalendarFeed {
+	return &CalendarFeed{
+		ID:        id,
+		UserID:    userId,
+		TeamID:    teamId,
+		CreatedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,34 @@
+package entity
+
+// DefaultChannelName is the channel every team has and the one team messages without a channel belong to
+const DefaultChannelName = "general"
+
+type Channel struct {
+	ID        string `json:"id"`
+	TeamID    string `json:"teamId"`
+	Name      string `json:"name"`
+	Archived  bool   `json:"archived"`
+	CreatedBy string `json:"createdBy,omitempty"`
+	CreatedAt int64  `json:"createdAt"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+func NewChannel(id, teamId, name, createdBy string, createdAt int64) *Channel {
+	return &Channel{
+		ID:        id,
+		TeamID:    teamId,
+		Name:      name,
+		CreatedBy: createdBy,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+// GetDefaultChannelID is deterministic so concurrent requests creating the default channel write the same record
+func GetDefaultChannelID(teamId string) string {
+	return teamId + "_" + DefaultChannelName
+}
+
+func (c *Channel) IsDefault() bool {
+	return c.ID == GetDefaultChannelID(c.TeamID)
+}
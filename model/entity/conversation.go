@@ -0,0 +1,55 @@
+package entity
+
+import "unicode/utf8"
+
+// ConversationPreviewLength is the number of characters of the last message kept in a conversation index entry
+const ConversationPreviewLength = 100
+
+// ConversationEntry is a direct conversation or team channel in a user's conversation index, kept up to date as
+// messages are sent, edited, deleted and read, so the inbox never scans the messages
+type ConversationEntry struct {
+	UserID             string `json:"userId"`
+	ConversationID     string `json:"conversationId"`
+	Type               string `json:"type"`                  // direct or channel
+	OtherUserID        string `json:"otherUserId,omitempty"` // direct conversations only
+	TeamID             string `json:"teamId,omitempty"`
+	ChannelID          string `json:"channelId,omitempty"`
+	LastMessageID      string `json:"lastMessageId,omitempty"`
+	LastSenderID       string `json:"lastSenderId,omitempty"`
+	LastMessagePreview string `json:"lastMessagePreview,omitempty"`
+	LastActivityAt     int64  `json:"lastActivityAt"` // unix milliseconds, the inbox is ordered by it
+	UnreadCount        int    `json:"unreadCount"`
+}
+
+// NewConversationEntry creates the user's entry for the conversation of the message
+func NewConversationEntry(userId string, message *Message) *ConversationEntry {
+	entry := &ConversationEntry{
+		UserID:         userId,
+		ConversationID: message.ConversationID(),
+		TeamID:         message.TeamID,
+		ChannelID:      message.ChannelID,
+	}
+	if message.ConversationKey != "" {
+		entry.Type = ConversationTypeDirect
+		entry.OtherUserID, _ = GetReceiverIdFromKey(userId, message.ConversationKey)
+	} else {
+		entry.Type = ConversationTypeChannel
+	}
+	return entry
+}
+
+// SetLastMessage makes the message the one the entry previews
+func (e *ConversationEntry) SetLastMessage(message *Message) {
+	e.LastMessageID = message.ID
+	e.LastSenderID = message.SenderID
+	e.LastMessagePreview = MessagePreview(message)
+	e.LastActivityAt = message.SentAt.UnixMilli()
+}
+
+// MessagePreview shortens the message's text for previews; deleted messages have none
+func MessagePreview(message *Message) string {
+	if message.IsDeleted() || utf8.RuneCountInString(message.TextContent) <= ConversationPreviewLength {
+		return message.TextContent
+	}
+	return string([]rune(message.TextContent)[:ConversationPreviewLength]) + "…"
+}
@@ -0,0 +1,78 @@
+package entity
+
+import "time"
+
+// ForumQuestion is a question of a team's Q&A board. Votes maps a member to +1 or -1, Score is their sum kept for sorting.
+type ForumQuestion struct {
+	ID               string         `json:"id"`
+	TeamID           string         `json:"teamId"`
+	AuthorID         string         `json:"authorId"`
+	Title            string         `json:"title"`
+	Body             string         `json:"body"`
+	Tags             []string       `json:"tags,omitempty"`
+	Votes            map[string]int `json:"votes,omitempty"`
+	Score            int            `json:"score"`
+	AnswerCount      int            `json:"answerCount"`
+	AcceptedAnswerID string         `json:"acceptedAnswerId,omitempty"`
+	CreatedAt        time.Time      `json:"createdAt"`
+	UpdatedAt        time.Time      `json:"updatedAt"`
+	LastActivityAt   time.Time      `json:"lastActivityAt"` // the question or its newest answer
+}
+
+func NewForumQuestion(id, teamId, authorId, title, body string, tags []string) *ForumQuestion {
+	now := time.Now().UTC()
+	return &ForumQuestion{
+		ID:             id,
+		TeamID:         teamId,
+		AuthorID:       authorId,
+		Title:          title,
+		Body:           body,
+		Tags:           tags,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		LastActivityAt: now,
+	}
+}
+
+type ForumAnswer struct {
+	ID         string         `json:"id"`
+	QuestionID string         `json:"questionId"`
+	TeamID     string         `json:"teamId"`
+	AuthorID   string         `json:"authorId"`
+	Body       string         `json:"body"`
+	Votes      map[string]int `json:"votes,omitempty"`
+	Score      int            `json:"score"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+}
+
+func NewForumAnswer(id, questionId, teamId, authorId, body string) *ForumAnswer {
+	now := time.Now().UTC()
+	return &ForumAnswer{
+		ID:         id,
+		QuestionID: questionId,
+		TeamID:     teamId,
+		AuthorID:   authorId,
+		Body:       body,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// ApplyVote sets the member's vote (1, -1, or 0 to remove it) and returns the new score
+func ApplyVote(votes map[string]int, userId string, value int) (map[string]int, int) {
+	if votes == nil {
+		votes = make(map[string]int)
+	}
+	if value == 0 {
+		delete(votes, userId)
+	} else {
+		votes[userId] = value
+	}
+
+	score := 0
+	for _, v := range votes {
+		score += v
+	}
+	return votes, score
+}
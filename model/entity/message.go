@@ -2,6 +2,7 @@ package entity
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -11,25 +12,97 @@ const (
 )
 
 type Message struct {
-	ID              string    `json:"id"`
-	SenderID        string    `json:"senderId"`
-	SentAt          time.Time `json:"timestamp"`
-	ConversationKey string    `json:"convKey,omitempty"`
-	TeamID          string    `json:"teamId,omitempty"`
-	TextContent     string    `json:"textContent"`
+	ID              string              `json:"id"`
+	SenderID        string              `json:"senderId"`
+	SentAt          time.Time           `json:"timestamp"`
+	ConversationKey string              `json:"convKey,omitempty"`
+	TeamID          string              `json:"teamId,omitempty"`
+	ChannelID       string              `json:"channelId,omitempty"`
+	TextContent     string              `json:"textContent"`
+	PinnedAt        int64               `json:"pinnedAt,omitempty"` // unix seconds, team messages only
+	PinnedBy        string              `json:"pinnedBy,omitempty"`
+	EditedAt        int64               `json:"editedAt,omitempty"` // unix seconds of the latest edit
+	Edits           []MessageEdit       `json:"edits,omitempty"`
+	DeletedAt       int64               `json:"deletedAt,omitempty"` // deleted messages stay in the history as tombstones without content
+	DeletedBy       string              `json:"deletedBy,omitempty"`
+	Reactions       map[string][]string `json:"reactions,omitempty"` // emoji to the IDs of the users who reacted with it, in reaction order
+	ParentID        string              `json:"parentId,omitempty"`  // the thread's first message, set on replies
+	ReplyCount      int                 `json:"replyCount,omitempty"`
+	LastReplyAt     int64               `json:"lastReplyAt,omitempty"` // unix seconds of the thread's latest reply
+	HistoryKey      string              `json:"historyKey,omitempty"`  // orders the history of a conversation or team, see NewHistoryKey
 }
 
-func NewMessage(id, senderId, convKey, teamId, textContent string) *Message {
+// MessageEdit is a previous version of an edited message's text
+type MessageEdit struct {
+	TextContent string `json:"textContent"`
+	EditedAt    int64  `json:"editedAt"` // unix seconds when this version was replaced
+}
+
+func NewMessage(id, senderId, convKey, teamId, channelId, textContent string) *Message {
 	return &Message{
 		ID:              id,
 		SenderID:        senderId,
 		SentAt:          time.Now().UTC(),
 		ConversationKey: convKey,
 		TeamID:          teamId,
+		ChannelID:       channelId,
 		TextContent:     textContent,
 	}
 }
 
+func (m *Message) IsDeleted() bool {
+	return m.DeletedAt != 0
+}
+
+func (m *Message) IsReply() bool {
+	return m.ParentID != ""
+}
+
+// HistoryScope is the history the message is paged in: its direct conversation or its team, across channels
+func (m *Message) HistoryScope() string {
+	if m.ConversationKey != "" {
+		return DirectConversationID(m.ConversationKey)
+	}
+	return TeamHistoryScope(m.TeamID)
+}
+
+func TeamHistoryScope(teamId string) string {
+	return "team:" + teamId
+}
+
+// SetHistoryKey puts the message in its history; thread replies are only listed in their thread, so they have none
+func (m *Message) SetHistoryKey() {
+	m.HistoryKey = ""
+	if !m.IsReply() {
+		m.HistoryKey = NewHistoryKey(m.HistoryScope(), m.SentAt, m.ID)
+	}
+}
+
+// NewHistoryKey orders messages by scope, then time, then ID in a single indexed field, which is how history pages are
+// queried. Without an ID the key sorts before every message sent at that time.
+func NewHistoryKey(scope string, sentAt time.Time, id string) string {
+	key := fmt.Sprintf("%s|%020d", scope, sentAt.UnixNano())
+	if id != "" {
+		key += "|" + id
+	}
+	return key
+}
+
+// InSameConversation reports whether both messages belong to the same direct conversation or team channel
+func (m *Message) InSameConversation(other *Message) bool {
+	return m.ConversationKey == other.ConversationKey && m.TeamID == other.TeamID && m.ChannelID == other.ChannelID
+}
+
+// HasReacted reports whether the user reacted to the message with the emoji
+func (m *Message) HasReacted(userId, emoji string) bool {
+	for _, id := range m.Reactions[emoji] {
+		if id == userId {
+			return true
+		}
+	}
+	return false
+}
+
 func GetConversationKey(user1Id, user2Id string) string {
 	if user1Id < user2Id {
 		return user1Id + "_" + user2Id
@@ -0,0 +1,49 @@
+package entity
+
+type ModerationAction string
+
+const (
+	ModerationKick   ModerationAction = "kick"
+	ModerationBan    ModerationAction = "ban"
+	ModerationUnban  ModerationAction = "unban"
+	ModerationMute   ModerationAction = "mute"
+	ModerationUnmute ModerationAction = "unmute"
+)
+
+// TeamRestriction is a ban or a mute of a member. Bans without an expiry are permanent, mutes always expire.
+type TeamRestriction struct {
+	UserID      string `json:"userId"`
+	TeamID      string `json:"teamId"`
+	Reason      string `json:"reason,omitempty"`
+	ModeratorID string `json:"moderatorId"`
+	CreatedAt   int64  `json:"createdAt"`           // unix seconds
+	ExpiresAt   int64  `json:"expiresAt,omitempty"` // unix seconds, 0 when it never expires
+}
+
+func NewTeamRestriction(teamId, userId, moderatorId, reason string, createdAt, expiresAt int64) *TeamRestriction {
+	return &TeamRestriction{
+		UserID:      userId,
+		TeamID:      teamId,
+		Reason:      reason,
+		ModeratorID: moderatorId,
+		CreatedAt:   createdAt,
+		ExpiresAt:   expiresAt,
+	}
+}
+
+// IsActive tells whether the restriction still applies at the given unix time
+func (r *TeamRestriction) IsActive(now int64) bool {
+	return r.ExpiresAt == 0 || now < r.ExpiresAt
+}
+
+// ModerationLogEntry records a moderation action taken in a team
+type ModerationLogEntry struct {
+	ID          string           `json:"id"`
+	TeamID      string           `json:"teamId"`
+	Action      ModerationAction `json:"action"`
+	ModeratorID string           `json:"moderatorId"`
+	TargetID    string           `json:"targetId"`
+	Reason      string           `json:"reason,omitempty"`
+	ExpiresAt   int64            `json:"expiresAt,omitempty"`
+	CreatedAt   int64            `json:"createdAt"` // unix milliseconds, like the activity feed
+}
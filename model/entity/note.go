@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/crdt"
+)
+
+// Note is a study note of a team edited by several members at once. Its text is a replicated text saved in a
+// NoteSnapshot; Version counts the changes made to it and is the version of the last snapshot.
+type Note struct {
+	ID        string    `json:"id"`
+	TeamID    string    `json:"teamId"`
+	Title     string    `json:"title"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Version   int       `json:"version"`
+}
+
+func NewNote(id, teamId, title, createdBy string) *Note {
+	now := time.Now().UTC()
+	return &Note{
+		ID:        id,
+		TeamID:    teamId,
+		Title:     title,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// NoteSnapshot is the state of a note's text at Version, deleted characters included
+type NoteSnapshot struct {
+	NoteID   string         `json:"noteId"`
+	Version  int            `json:"version"`
+	Elements []crdt.Element `json:"elements,omitempty"`
+	SavedAt  time.Time      `json:"savedAt"`
+}
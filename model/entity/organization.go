@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Organization is a tenant of the deployment, usually a university. Its users only see the users and teams of the same organization.
+// Users signing up with an email on one of its domains join it.
+type Organization struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	EmailDomains []string  `json:"emailDomains"`
+	AdminIds     []string  `json:"adminIds,omitempty"`
+	CreatedBy    string    `json:"createdBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func NewOrganization(id, name string, emailDomains []string, createdBy string) *Organization {
+	return &Organization{
+		ID:           id,
+		Name:         name,
+		EmailDomains: emailDomains,
+		AdminIds:     []string{},
+		CreatedBy:    createdBy,
+		CreatedAt:    time.Now(),
+	}
+}
+
+func (o *Organization) IsAdmin(userId string) bool {
+	return userId != "" && slices.Contains(o.AdminIds, userId)
+}
+
+// AllowsEmail reports whether the email is on one of the organization's domains
+func (o *Organization) AllowsEmail(email string) bool {
+	return slices.Contains(o.EmailDomains, GetEmailDomain(email))
+}
+
+// GetEmailDomain returns the lower-cased part of the email after the @
+func GetEmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
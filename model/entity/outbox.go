@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OutboxEntry is an event sent to a user over the message WebSocket, kept so the user can get it after reconnecting.
+// Each user's events are numbered from 1 in the order they were sent.
+type OutboxEntry struct {
+	UserID    string          `json:"userId"`
+	Seq       int64           `json:"seq"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	CreatedAt int64           `json:"createdAt"` // unix seconds
+}
+
+func NewOutboxEntry(userId string, seq int64, eventType string, payload json.RawMessage) *OutboxEntry {
+	return &OutboxEntry{
+		UserID:    userId,
+		Seq:       seq,
+		Type:      eventType,
+		Payload:   payload,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+// OutboxKey is the key of an entry in the user's outbox, padded so the keys sort like the sequence numbers
+func OutboxKey(seq int64) string {
+	return fmt.Sprintf("%020d", seq)
+}
@@ -0,0 +1,21 @@
+package entity
+
+// The statuses users show to their friends and teammates
+const (
+	PresenceOnline  = "online"
+	PresenceAway    = "away" // connected, but the client reported the user idle
+	PresenceOffline = "offline"
+)
+
+// Presence keeps when a user was last connected, which is all that is left of their presence once they are offline
+type Presence struct {
+	UserID     string `json:"userId"`
+	LastSeenAt int64  `json:"lastSeenAt"` // unix seconds
+}
+
+func NewPresence(userId string, lastSeenAt int64) *Presence {
+	return &Presence{
+		UserID:     userId,
+		LastSeenAt: lastSeenAt,
+	}
+}
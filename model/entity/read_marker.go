@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+const (
+	ConversationTypeDirect  = "direct"
+	ConversationTypeChannel = "channel"
+)
+
+// ReadMarker is how far a user has read a conversation: every message sent up to SentAt counts as read
+type ReadMarker struct {
+	UserID         string    `json:"userId"`
+	ConversationID string    `json:"conversationId"`
+	MessageID      string    `json:"messageId,omitempty"` // the last message read, empty when nothing was read yet
+	SentAt         time.Time `json:"sentAt"`
+	ReadAt         int64     `json:"readAt,omitempty"` // unix seconds
+}
+
+func NewReadMarker(userId, conversationId string) *ReadMarker {
+	return &ReadMarker{
+		UserID:         userId,
+		ConversationID: conversationId,
+	}
+}
+
+// HasRead reports whether the message is covered by the marker
+func (r *ReadMarker) HasRead(message *Message) bool {
+	return r.MessageID != "" && !message.SentAt.After(r.SentAt)
+}
+
+// DirectConversationID and ChannelConversationID identify conversations across both kinds, since a conversation key and
+// a channel ID can look alike
+func DirectConversationID(conversationKey string) string {
+	return ConversationTypeDirect + ":" + conversationKey
+}
+
+func ChannelConversationID(channelId string) string {
+	return ConversationTypeChannel + ":" + channelId
+}
+
+// ConversationID returns the conversation the message belongs to: its direct conversation or its team channel
+func (m *Message) ConversationID() string {
+	if m.ConversationKey != "" {
+		return DirectConversationID(m.ConversationKey)
+	}
+	return ChannelConversationID(m.ChannelID)
+}
@@ -0,0 +1,47 @@
+package entity
+
+const (
+	SearchTypeTeam    = "team"
+	SearchTypeUser    = "user"
+	SearchTypeQuiz    = "quiz"
+	SearchTypeMessage = "message"
+)
+
+// SearchDocument is the indexed view of a team, user, quiz or message.
+// Terms maps every token of the document to its weight, so the postings can be removed on re-index.
+type SearchDocument struct {
+	Key            string         `json:"key"`
+	Type           string         `json:"type"`
+	ID             string         `json:"id"`
+	Title          string         `json:"title"`
+	Snippet        string         `json:"snippet,omitempty"`
+	TeamID         string         `json:"teamId,omitempty"`
+	IsPublic       bool           `json:"isPublic,omitempty"`
+	MemberIDs      []string       `json:"memberIds,omitempty"` // participants of a direct conversation
+	OrganizationID string         `json:"organizationId,omitempty"`
+	Terms          map[string]int `json:"terms"`
+}
+
+func NewSearchDocument(docType, id, title, snippet string) *SearchDocument {
+	return &SearchDocument{
+		Key:     GetSearchDocumentKey(docType, id),
+		Type:    docType,
+		ID:      id,
+		Title:   title,
+		Snippet: snippet,
+		Terms:   map[string]int{},
+	}
+}
+
+func GetSearchDocumentKey(docType, id string) string {
+	return docType + "_" + id
+}
+
+// AddTerms adds the tokens with the given weight, keeping the highest weight when a token appears in several fields
+func (sd *SearchDocument) AddTerms(tokens []string, weight int) {
+	for _, token := range tokens {
+		if sd.Terms[token] < weight {
+			sd.Terms[token] = weight
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily   RecurrenceFrequency = "daily"
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceMonthly RecurrenceFrequency = "monthly"
+
+	// MaxSessionOccurrences bounds the expansion of sessions that repeat forever
+	MaxSessionOccurrences = 500
+)
+
+type RSVPStatus string
+
+const (
+	RSVPGoing    RSVPStatus = "going"
+	RSVPMaybe    RSVPStatus = "maybe"
+	RSVPDeclined RSVPStatus = "declined"
+)
+
+// RecurrenceRule repeats a session every Interval days, weeks or months, either Count times in total, until Until, or forever
+type RecurrenceRule struct {
+	Frequency RecurrenceFrequency `json:"frequency"`
+	Interval  int                 `json:"interval,omitempty"`
+	Count     int                 `json:"count,omitempty"`
+	Until     *time.Time          `json:"until,omitempty"`
+}
+
+type StudySession struct {
+	ID              string                `json:"id"`
+	TeamID          string                `json:"teamId"`
+	Title           string                `json:"title"`
+	Description     string                `json:"description,omitempty"`
+	StartAt         time.Time             `json:"startAt"`
+	EndAt           time.Time             `json:"endAt"`
+	Timezone        string                `json:"timezone"` // IANA name, recurrences keep the local wall clock time across DST changes
+	Recurrence      *RecurrenceRule       `json:"recurrence,omitempty"`
+	VoiceRoomID     string                `json:"voiceRoomId,omitempty"`
+	ReminderMinutes int                   `json:"reminderMinutes"` // 0 disables the reminder
+	RSVPs           map[string]RSVPStatus `json:"rsvps,omitempty"`
+	CreatedBy       string                `json:"createdBy"`
+	CreatedAt       time.Time             `json:"createdAt"`
+	UpdatedAt       time.Time             `json:"updatedAt"`
+	Sequence        int                   `json:"sequence"`                 // incremented on every change so calendar clients pick up updates
+	LastReminderAt  int64                 `json:"lastReminderAt,omitempty"` // unix start of the last occurrence a reminder was sent for
+}
+
+func NewStudySession(id, teamId, title, description string, startAt, endAt time.Time, timezone string, recurrence *RecurrenceRule, reminderMinutes int, createdBy string) *StudySession {
+	now := time.Now().UTC()
+	return &StudySession{
+		ID:              id,
+		TeamID:          teamId,
+		Title:           title,
+		Description:     description,
+		StartAt:         startAt,
+		EndAt:           endAt,
+		Timezone:        timezone,
+		Recurrence:      recurrence,
+		ReminderMinutes: reminderMinutes,
+		RSVPs:           map[string]RSVPStatus{createdBy: RSVPGoing},
+		CreatedBy:       createdBy,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// OccurrencesBetween returns the start times of the occurrences overlapping [from, to)
+func (s *StudySession) OccurrencesBetween(from, to time.Time) []time.Time {
+	loc, err := utils.LoadLocation(s.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	start := s.StartAt.In(loc)
+	duration := s.EndAt.Sub(s.StartAt)
+
+	var occurrences []time.Time
+	for i := 0; i < MaxSessionOccurrences; i++ {
+		occurrence, ok := s.nthOccurrence(start, i)
+		if !ok || !occurrence.Before(to) {
+			break
+		}
+		if occurrence.Add(duration).After(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+	return occurrences
+}
+
+func (s *StudySession) nthOccurrence(start time.Time, n int) (time.Time, bool) {
+	if n == 0 {
+		return start, true
+	}
+	rule := s.Recurrence
+	if rule == nil || (rule.Count > 0 && n >= rule.Count) {
+		return time.Time{}, false
+	}
+
+	interval := rule.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	var occurrence time.Time
+	switch rule.Frequency {
+	case RecurrenceDaily:
+		occurrence = start.AddDate(0, 0, n*interval)
+	case RecurrenceWeekly:
+		occurrence = start.AddDate(0, 0, 7*n*interval)
+	case RecurrenceMonthly:
+		occurrence = start.AddDate(0, n*interval, 0)
+	default:
+		return time.Time{}, false
+	}
+
+	if rule.Until != nil && occurrence.After(*rule.Until) {
+		return time.Time{}, false
+	}
+	return occurrence, true
+}
+
+// GetRSVP returns the member's answer, members who have not answered yet count as maybe
+func (s *StudySession) GetRSVP(userId string) RSVPStatus {
+	if status, ok := s.RSVPs[userId]; ok {
+		return status
+	}
+	return RSVPMaybe
+}
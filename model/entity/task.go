@@ -0,0 +1,98 @@
+package entity
+
+import "time"
+
+type BoardColumn struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// TaskBoard holds the columns of a team's board in display order, the tasks are stored separately
+type TaskBoard struct {
+	TeamID    string        `json:"teamId"`
+	Columns   []BoardColumn `json:"columns"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+func NewTaskBoard(teamId string, columns []BoardColumn) *TaskBoard {
+	return &TaskBoard{
+		TeamID:    teamId,
+		Columns:   columns,
+		UpdatedAt: time.Now().UTC(),
+	}
+}
+
+// NewDefaultTaskBoard is the board of a team until its members change the columns. The column IDs are fixed so the
+// board does not have to be stored before it is changed.
+func NewDefaultTaskBoard(teamId string) *TaskBoard {
+	return NewTaskBoard(teamId, []BoardColumn{
+		{ID: "todo", Name: "To do"},
+		{ID: "in-progress", Name: "In progress"},
+		{ID: "done", Name: "Done"},
+	})
+}
+
+// ColumnIndex returns the position of the column on the board or -1
+func (b *TaskBoard) ColumnIndex(columnId string) int {
+	for i, column := range b.Columns {
+		if column.ID == columnId {
+			return i
+		}
+	}
+	return -1
+}
+
+type ChecklistItem struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+type Task struct {
+	ID          string          `json:"id"`
+	TeamID      string          `json:"teamId"`
+	ColumnID    string          `json:"columnId"`
+	Position    int             `json:"position"` // order within the column, starting at 0
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	AssigneeIDs []string        `json:"assigneeIds,omitempty"`
+	DueAt       *time.Time      `json:"dueAt,omitempty"`
+	Labels      []string        `json:"labels,omitempty"`
+	Checklist   []ChecklistItem `json:"checklist,omitempty"`
+	CreatedBy   string          `json:"createdBy"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
+
+func NewTask(id, teamId, columnId string, position int, createdBy string) *Task {
+	now := time.Now().UTC()
+	return &Task{
+		ID:        id,
+		TeamID:    teamId,
+		ColumnID:  columnId,
+		Position:  position,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+type TaskComment struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"taskId"`
+	TeamID    string    `json:"teamId"`
+	AuthorID  string    `json:"authorId"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func NewTaskComment(id, taskId, teamId, authorId, text string) *TaskComment {
+	return &TaskComment{
+		ID:        id,
+		TaskID:    taskId,
+		TeamID:    teamId,
+		AuthorID:  authorId,
+		Text:      text,
+		CreatedAt: time.Now().UTC(),
+	}
+}
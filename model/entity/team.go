@@ -3,12 +3,17 @@ package entity
 import "github.com/SerbanEduard/ProiectColectivBackEnd/model"
 
 type Team struct {
-	Id          string                `json:"id"`
-	Name        string                `json:"name"`
-	Description string                `json:"description"`
-	IsPublic    bool                  `json:"ispublic"`
-	UsersIds    []string              `json:"users"`
-	TeamTopic   model.TopicOfInterest `json:"teamtopic"`
+	Id             string                `json:"id"`
+	Name           string                `json:"name"`
+	Description    string                `json:"description"`
+	IsPublic       bool                  `json:"ispublic"`
+	UsersIds       []string              `json:"users"`
+	TeamTopic      model.TopicOfInterest `json:"teamtopic"`
+	OwnerId        string                `json:"ownerId,omitempty"`
+	AdminIds       []string              `json:"adminIds,omitempty"` // members the owner made admins, the owner is always an admin
+	Archived       bool                  `json:"archived"`
+	ArchivedAt     int64                 `json:"archivedAt,omitempty"`
+	OrganizationID string                `json:"organizationId,omitempty"` // the creator's organization, only its users can see or join the team
 }
 
 func NewTeam(id, name, desc string, isPublic bool, Users []string, topic model.TopicOfInterest) *Team {
@@ -26,3 +31,31 @@ func NewTeam(id, name, desc string, isPublic bool, Users []string, topic model.T
 		TeamTopic: topic,
 	}
 }
+
+// GetOwnerId returns the owner of the team. Teams created before owners were stored belong to their first member.
+func (t *Team) GetOwnerId() string {
+	if t.OwnerId != "" {
+		return t.OwnerId
+	}
+	if len(t.UsersIds) > 0 {
+		return t.UsersIds[0]
+	}
+	return ""
+}
+
+func (t *Team) IsOwner(userId string) bool {
+	return userId != "" && t.GetOwnerId() == userId
+}
+
+// IsAdmin reports whether the user can manage the team's content: the owner and the members they made admins
+func (t *Team) IsAdmin(userId string) bool {
+	if t.IsOwner(userId) {
+		return true
+	}
+	for _, adminId := range t.AdminIds {
+		if adminId == userId {
+			return true
+		}
+	}
+	return false
+}
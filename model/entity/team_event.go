@@ -0,0 +1,37 @@
+package entity
+
+type TeamEventType string
+
+const (
+	TeamEventMemberJoined        TeamEventType = "member_joined"
+	TeamEventMemberLeft          TeamEventType = "member_left"
+	TeamEventFileUploaded        TeamEventType = "file_uploaded"
+	TeamEventFileDeleted         TeamEventType = "file_deleted"
+	TeamEventQuizCreated         TeamEventType = "quiz_created"
+	TeamEventQuizCompleted       TeamEventType = "quiz_completed"
+	TeamEventVoiceSessionStarted TeamEventType = "voice_session_started"
+	TeamEventAnnouncement        TeamEventType = "announcement"
+	TeamEventSessionScheduled    TeamEventType = "session_scheduled"
+)
+
+// TeamEvent is an entry of a team's activity feed. SubjectID and SubjectName identify what the event is about (a file, a quiz, a room...).
+type TeamEvent struct {
+	ID          string            `json:"id"`
+	TeamID      string            `json:"teamId"`
+	Type        TeamEventType     `json:"type"`
+	ActorID     string            `json:"actorId,omitempty"`
+	SubjectID   string            `json:"subjectId,omitempty"`
+	SubjectName string            `json:"subjectName,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	CreatedAt   int64             `json:"createdAt"` // unix milliseconds, events often happen within the same second
+}
+
+func NewTeamEvent(teamId string, eventType TeamEventType, actorId, subjectId, subjectName string) *TeamEvent {
+	return &TeamEvent{
+		TeamID:      teamId,
+		Type:        eventType,
+		ActorID:     actorId,
+		SubjectID:   subjectId,
+		SubjectName: subjectName,
+	}
+}
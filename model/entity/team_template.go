@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model"
+)
+
+// TemplateContent is a kind of team content a template can carry
+type TemplateContent string
+
+const (
+	TemplateContentChannels  TemplateContent = "channels"
+	TemplateContentQuizzes   TemplateContent = "quizzes"
+	TemplateContentTaskBoard TemplateContent = "taskBoard"
+)
+
+// TemplateContents lists every content type, in the order they are copied
+var TemplateContents = []TemplateContent{TemplateContentChannels, TemplateContentQuizzes, TemplateContentTaskBoard}
+
+// TemplateQuiz is a quiz of a template, without its ids and author
+type TemplateQuiz struct {
+	QuizName  string     `json:"quizName"`
+	Questions []Question `json:"questions"`
+}
+
+// TemplateTask is a task of a template. Assignees, due dates and comments belong to a semester and are not kept.
+type TemplateTask struct {
+	ColumnID    string   `json:"columnId"`
+	Position    int      `json:"position"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Checklist   []string `json:"checklist,omitempty"`
+}
+
+// TeamTemplate is a snapshot of a team's settings and content that new teams can start from. Members and messages are never part of it.
+type TeamTemplate struct {
+	ID           string                `json:"id"`
+	Name         string                `json:"name"`
+	OwnerID      string                `json:"ownerId"`
+	SourceTeamID string                `json:"sourceTeamId,omitempty"`
+	Description  string                `json:"description"`
+	IsPublic     bool                  `json:"ispublic"`
+	TeamTopic    model.TopicOfInterest `json:"teamtopic"`
+	Contents     []TemplateContent     `json:"contents"`
+	Channels     []string              `json:"channels,omitempty"`
+	Quizzes      []TemplateQuiz        `json:"quizzes,omitempty"`
+	BoardColumns []BoardColumn         `json:"boardColumns,omitempty"`
+	Tasks        []TemplateTask        `json:"tasks,omitempty"`
+	CreatedAt    time.Time             `json:"createdAt"`
+}
+
+// Has reports whether the template carries the content type
+func (t *TeamTemplate) Has(content TemplateContent) bool {
+	for _, c := range t.Contents {
+		if c == content {
+			return true
+		}
+	}
+	return false
+}
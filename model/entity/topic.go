@@ -0,0 +1,32 @@
+package entity
+
+import "github.com/SerbanEduard/ProiectColectivBackEnd/model"
+
+type Topic struct {
+	Slug       model.TopicOfInterest `json:"slug"`
+	Names      map[string]string     `json:"names"` // display name per language code
+	ParentSlug model.TopicOfInterest `json:"parentSlug,omitempty"`
+	CreatedAt  int64                 `json:"createdAt,omitempty"`
+	UpdatedAt  int64                 `json:"updatedAt,omitempty"`
+}
+
+func NewTopic(slug model.TopicOfInterest, names map[string]string, parentSlug model.TopicOfInterest, createdAt, updatedAt int64) *Topic {
+	return &Topic{
+		Slug:       slug,
+		Names:      names,
+		ParentSlug: parentSlug,
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+	}
+}
+
+// DisplayName returns the name in the given language, falling back to the default language and then to the slug
+func (t *Topic) DisplayName(lang string) string {
+	if name, ok := t.Names[lang]; ok && name != "" {
+		return name
+	}
+	if name, ok := t.Names[model.DefaultTopicLanguage]; ok && name != "" {
+		return name
+	}
+	return string(t.Slug)
+}
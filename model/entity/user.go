@@ -12,6 +12,8 @@ type User struct {
 	TopicsOfInterest *[]model.TopicOfInterest `json:"topicsOfInterest,omitempty"`
 	TeamsIds         *[]string                `json:"teams,omitempty"`
 	Statistics       *model.Statistics        `json:"statistics,omitempty"`
+	IsAdmin          bool                     `json:"isAdmin,omitempty"`
+	OrganizationID   string                   `json:"organizationId,omitempty"` // empty for users outside any organization
 }
 
 func NewUser(id, firstName, lastName, username, email, password string, topicsOfInterest *[]model.TopicOfInterest) *User {
@@ -0,0 +1,61 @@
+package entity
+
+import "time"
+
+// WikiPage is a markdown page of a team's wiki. Path places it in the wiki's hierarchy, e.g. "courses/algebra/week-1";
+// Revision is the number of its current revision.
+type WikiPage struct {
+	ID        string    `json:"id"`
+	TeamID    string    `json:"teamId"`
+	Path      string    `json:"path"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	Revision  int       `json:"revision"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedBy string    `json:"updatedBy"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func NewWikiPage(id, teamId, path, title, content, authorId string) *WikiPage {
+	now := time.Now().UTC()
+	return &WikiPage{
+		ID:        id,
+		TeamID:    teamId,
+		Path:      path,
+		Title:     title,
+		Content:   content,
+		Revision:  1,
+		CreatedBy: authorId,
+		CreatedAt: now,
+		UpdatedBy: authorId,
+		UpdatedAt: now,
+	}
+}
+
+// WikiRevision is a snapshot of a page saved by every change. RevertedFrom is the revision a revert restored.
+type WikiRevision struct {
+	Number       int       `json:"number"`
+	PageID       string    `json:"pageId"`
+	Path         string    `json:"path"`
+	Title        string    `json:"title"`
+	Content      string    `json:"content"`
+	Summary      string    `json:"summary,omitempty"`
+	RevertedFrom int       `json:"revertedFrom,omitempty"`
+	AuthorID     string    `json:"authorId"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// NewWikiRevision snapshots the page's current revision
+func NewWikiRevision(page *WikiPage, summary string) *WikiRevision {
+	return &WikiRevision{
+		Number:    page.Revision,
+		PageID:    page.ID,
+		Path:      page.Path,
+		Title:     page.Title,
+		Content:   page.Content,
+		Summary:   summary,
+		AuthorID:  page.UpdatedBy,
+		CreatedAt: page.UpdatedAt,
+	}
+}
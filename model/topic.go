@@ -1,8 +1,12 @@
 package model
 
+// TopicOfInterest is the slug of a topic. The topics themselves are managed data (see entity.Topic).
 type TopicOfInterest string
 
-// TODO add more such topics
+// DefaultTopicLanguage is the language every topic must have a display name in
+const DefaultTopicLanguage = "en"
+
+// Topics seeded into an empty taxonomy
 const (
 	Mathematics     TopicOfInterest = "Mathematics"
 	Science         TopicOfInterest = "Science"
@@ -13,3 +17,21 @@ const (
 	Photography     TopicOfInterest = "Photography"
 	Language        TopicOfInterest = "Language"
 )
+
+// DefaultTopic describes a seeded topic with its display names per language
+type DefaultTopic struct {
+	Slug   TopicOfInterest
+	Names  map[string]string
+	Parent TopicOfInterest
+}
+
+var DefaultTopics = []DefaultTopic{
+	{Slug: Mathematics, Names: map[string]string{"en": "Mathematics", "ro": "Matematică"}},
+	{Slug: Science, Names: map[string]string{"en": "Science", "ro": "Științe"}},
+	{Slug: ComputerScience, Names: map[string]string{"en": "Computer Science", "ro": "Informatică"}, Parent: Science},
+	{Slug: Programming, Names: map[string]string{"en": "Programming", "ro": "Programare"}, Parent: ComputerScience},
+	{Slug: Art, Names: map[string]string{"en": "Art", "ro": "Artă"}},
+	{Slug: Music, Names: map[string]string{"en": "Music", "ro": "Muzică"}, Parent: Art},
+	{Slug: Photography, Names: map[string]string{"en": "Photography", "ro": "Fotografie"}, Parent: Art},
+	{Slug: Language, Names: map[string]string{"en": "Language", "ro": "Limbi străine"}},
+}
@@ -0,0 +1,80 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	announcementsCollection = "announcements"
+	announcementNotFound    = "announcement not found"
+)
+
+type AnnouncementRepositoryInterface interface {
+	Create(announcement *entity.Announcement) error
+	GetByID(id string) (*entity.Announcement, error)
+	GetByTeamID(teamId string) ([]*entity.Announcement, error)
+	Update(announcement *entity.Announcement) error
+	Delete(id string) error
+}
+
+type AnnouncementRepository struct{}
+
+func NewAnnouncementRepository() *AnnouncementRepository {
+	return &AnnouncementRepository{}
+}
+
+func (ar *AnnouncementRepository) Create(announcement *entity.Announcement) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(announcementsCollection + "/" + announcement.ID)
+	return ref.Set(ctx, announcement)
+}
+
+func (ar *AnnouncementRepository) GetByID(id string) (*entity.Announcement, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(announcementsCollection + "/" + id)
+
+	var announcement entity.Announcement
+	if err := ref.Get(ctx, &announcement); err != nil {
+		return nil, err
+	}
+	if announcement.ID == "" {
+		return nil, errors.New(announcementNotFound)
+	}
+	return &announcement, nil
+}
+
+func (ar *AnnouncementRepository) GetByTeamID(teamId string) ([]*entity.Announcement, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(announcementsCollection)
+
+	results, err := ref.OrderByChild("teamId").EqualTo(teamId).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	announcements := make([]*entity.Announcement, 0, len(results))
+	for _, r := range results {
+		var announcement entity.Announcement
+		if err := r.Unmarshal(&announcement); err != nil {
+			return nil, err
+		}
+		announcements = append(announcements, &announcement)
+	}
+	return announcements, nil
+}
+
+func (ar *AnnouncementRepository) Update(announcement *entity.Announcement) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(announcementsCollection + "/" + announcement.ID)
+	return ref.Set(ctx, announcement)
+}
+
+func (ar *AnnouncementRepository) Delete(id string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(announcementsCollection + "/" + id)
+	return ref.Delete(ctx)
+}
@@ -0,0 +1,73 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	calendarFeedsCollection = "calendarFeeds"
+	calendarFeedNotFound    = "calendar feed not found"
+)
+
+type CalendarFeedRepositoryInterface interface {
+	Create(feed *entity.CalendarFeed) error
+	GetByID(id string) (*entity.CalendarFeed, error)
+	GetByUserID(userId string) ([]*entity.CalendarFeed, error)
+	Delete(id string) error
+}
+
+type CalendarFeedRepository struct{}
+
+func NewCalendarFeedRepository() *CalendarFeedRepository {
+	return &CalendarFeedRepository{}
+}
+
+func (cr *CalendarFeedRepository) Create(feed *entity.CalendarFeed) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(calendarFeedsCollection + "/" + feed.ID)
+	return ref.Set(ctx, feed)
+}
+
+func (cr *CalendarFeedRepository) GetByID(id string) (*entity.CalendarFeed, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(calendarFeedsCollection + "/" + id)
+
+	var feed entity.CalendarFeed
+	if err := ref.Get(ctx, &feed); err != nil {
+		return nil, err
+	}
+	if feed.ID == "" {
+		return nil, errors.New(calendarFeedNotFound)
+	}
+	return &feed, nil
+}
+
+func (cr *CalendarFeedRepository) GetByUserID(userId string) ([]*entity.CalendarFeed, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(calendarFeedsCollection)
+
+	results, err := ref.OrderByChild("userId").EqualTo(userId).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	feeds := make([]*entity.CalendarFeed, 0, len(results))
+	for _, r := range results {
+		var feed entity.CalendarFeed
+		if err := r.Unmarshal(&feed); err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, &feed)
+	}
+	return feeds, nil
+}
+
+func (cr *CalendarFeedRepository) Delete(id string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(calendarFeedsCollection + "/" + id)
+	return ref.Delete(ctx)
+}
@@ -0,0 +1,73 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	channelsCollection = "channels"
+	channelNotFound    = "channel not found"
+)
+
+type ChannelRepositoryInterface interface {
+	Create(channel *entity.Channel) error
+	GetByID(id string) (*entity.Channel, error)
+	GetByTeamID(teamId string) ([]*entity.Channel, error)
+	Update(channel *entity.Channel) error
+}
+
+type ChannelRepository struct{}
+
+func NewChannelRepository() *ChannelRepository {
+	return &ChannelRepository{}
+}
+
+func (cr *ChannelRepository) Create(channel *entity.Channel) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(channelsCollection + "/" + channel.ID)
+	return ref.Set(ctx, channel)
+}
+
+func (cr *ChannelRepository) GetByID(id string) (*entity.Channel, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(channelsCollection + "/" + id)
+
+	var channel entity.Channel
+	if err := ref.Get(ctx, &channel); err != nil {
+		return nil, err
+	}
+	if channel.ID == "" {
+		return nil, errors.New(channelNotFound)
+	}
+	return &channel, nil
+}
+
+func (cr *ChannelRepository) GetByTeamID(teamId string) ([]*entity.Channel, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(channelsCollection)
+
+	results, err := ref.OrderByChild("teamId").EqualTo(teamId).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make([]*entity.Channel, 0, len(results))
+	for _, r := range results {
+		var channel entity.Channel
+		if err := r.Unmarshal(&channel); err != nil {
+			return nil, err
+		}
+		channels = append(channels, &channel)
+	}
+	return channels, nil
+}
+
+func (cr *ChannelRepository) Update(channel *entity.Channel) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(channelsCollection + "/" + channel.ID)
+	return ref.Set(ctx, channel)
+}
@@ -0,0 +1,127 @@
+package persistence
+
+import (
+	"context"
+
+	"firebase.google.com/go/v4/db"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	conversationsCollection = "conversations"
+	lastActivityAtField     = "lastActivityAt"
+)
+
+type ConversationRepositoryInterface interface {
+	Get(userId, conversationId string) (*entity.ConversationEntry, error)
+	GetByUser(userId string) ([]*entity.ConversationEntry, error)
+	GetPage(userId string, before int64, limit int) ([]*entity.ConversationEntry, error)
+	Save(entry *entity.ConversationEntry) error
+	Update(userId, conversationId string, update func(entry *entity.ConversationEntry) *entity.ConversationEntry) error
+	IsEmpty() (bool, error)
+}
+
+// ConversationRepository stores each user's conversation index (conversations/<userId>/<conversationId>)
+type ConversationRepository struct{}
+
+func NewConversationRepository() *ConversationRepository {
+	return &ConversationRepository{}
+}
+
+// Get returns nil when the conversation is not in the user's index
+func (cr *ConversationRepository) Get(userId, conversationId string) (*entity.ConversationEntry, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(conversationsCollection + "/" + userId + "/" + conversationId)
+
+	var entry entity.ConversationEntry
+	if err := ref.Get(ctx, &entry); err != nil {
+		return nil, err
+	}
+	if entry.UserID == "" {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (cr *ConversationRepository) GetByUser(userId string) ([]*entity.ConversationEntry, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(conversationsCollection + "/" + userId)
+
+	var entriesMap map[string]*entity.ConversationEntry
+	if err := ref.Get(ctx, &entriesMap); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*entity.ConversationEntry, 0, len(entriesMap))
+	for _, entry := range entriesMap {
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// GetPage returns up to limit of the user's conversations, the most recently active first; with before, only the ones
+// last active at or before it
+func (cr *ConversationRepository) GetPage(userId string, before int64, limit int) ([]*entity.ConversationEntry, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(conversationsCollection + "/" + userId)
+
+	query := ref.OrderByChild(lastActivityAtField)
+	if before > 0 {
+		query = query.EndAt(before)
+	}
+	results, err := query.LimitToLast(limit).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*entity.ConversationEntry, 0, len(results))
+	for i := len(results) - 1; i >= 0; i-- {
+		var entry entity.ConversationEntry
+		if err := results[i].Unmarshal(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+func (cr *ConversationRepository) Save(entry *entity.ConversationEntry) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(conversationsCollection + "/" + entry.UserID + "/" + entry.ConversationID)
+	return ref.Set(ctx, entry)
+}
+
+// Update applies the update to the user's conversation entry in a transaction, so concurrent messages are all counted.
+// The update gets nil when the conversation is not in the user's index and returns the entry to store, or nil to keep
+// the stored one.
+func (cr *ConversationRepository) Update(userId, conversationId string, update func(entry *entity.ConversationEntry) *entity.ConversationEntry) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(conversationsCollection + "/" + userId + "/" + conversationId)
+
+	return ref.Transaction(ctx, func(node db.TransactionNode) (interface{}, error) {
+		var stored entity.ConversationEntry
+		if err := node.Unmarshal(&stored); err != nil {
+			return nil, err
+		}
+		current := &stored
+		if stored.UserID == "" {
+			current = nil
+		}
+		if updated := update(current); updated != nil {
+			return updated, nil
+		}
+		return current, nil
+	})
+}
+
+func (cr *ConversationRepository) IsEmpty() (bool, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(conversationsCollection)
+
+	results, err := ref.OrderByKey().LimitToFirst(1).GetOrdered(ctx)
+	if err != nil {
+		return false, err
+	}
+	return len(results) == 0, nil
+}
@@ -0,0 +1,137 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	forumQuestionsCollection = "forumQuestions"
+	forumAnswersCollection   = "forumAnswers"
+	forumQuestionNotFound    = "question not found"
+	forumAnswerNotFound      = "answer not found"
+)
+
+type ForumRepositoryInterface interface {
+	CreateQuestion(question *entity.ForumQuestion) error
+	GetQuestion(id string) (*entity.ForumQuestion, error)
+	GetQuestionsByTeamID(teamId string) ([]*entity.ForumQuestion, error)
+	UpdateQuestion(question *entity.ForumQuestion) error
+	DeleteQuestion(id string) error
+	SaveAnswer(answer *entity.ForumAnswer) error
+	GetAnswer(questionId, id string) (*entity.ForumAnswer, error)
+	GetAnswers(questionId string) ([]*entity.ForumAnswer, error)
+	DeleteAnswer(questionId, id string) error
+	DeleteAnswers(questionId string) error
+}
+
+// ForumRepository stores the questions by ID (forumQuestions/<id>) and their answers grouped by question (forumAnswers/<questionId>/<id>)
+type ForumRepository struct{}
+
+func NewForumRepository() *ForumRepository {
+	return &ForumRepository{}
+}
+
+func (fr *ForumRepository) CreateQuestion(question *entity.ForumQuestion) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(forumQuestionsCollection + "/" + question.ID)
+	return ref.Set(ctx, question)
+}
+
+func (fr *ForumRepository) GetQuestion(id string) (*entity.ForumQuestion, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(forumQuestionsCollection + "/" + id)
+
+	var question entity.ForumQuestion
+	if err := ref.Get(ctx, &question); err != nil {
+		return nil, err
+	}
+	if question.ID == "" {
+		return nil, errors.New(forumQuestionNotFound)
+	}
+	return &question, nil
+}
+
+func (fr *ForumRepository) GetQuestionsByTeamID(teamId string) ([]*entity.ForumQuestion, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(forumQuestionsCollection)
+
+	results, err := ref.OrderByChild("teamId").EqualTo(teamId).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	questions := make([]*entity.ForumQuestion, 0, len(results))
+	for _, r := range results {
+		var question entity.ForumQuestion
+		if err := r.Unmarshal(&question); err != nil {
+			return nil, err
+		}
+		questions = append(questions, &question)
+	}
+	return questions, nil
+}
+
+func (fr *ForumRepository) UpdateQuestion(question *entity.ForumQuestion) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(forumQuestionsCollection + "/" + question.ID)
+	return ref.Set(ctx, question)
+}
+
+func (fr *ForumRepository) DeleteQuestion(id string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(forumQuestionsCollection + "/" + id)
+	return ref.Delete(ctx)
+}
+
+// SaveAnswer creates or replaces an answer
+func (fr *ForumRepository) SaveAnswer(answer *entity.ForumAnswer) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(forumAnswersCollection + "/" + answer.QuestionID + "/" + answer.ID)
+	return ref.Set(ctx, answer)
+}
+
+func (fr *ForumRepository) GetAnswer(questionId, id string) (*entity.ForumAnswer, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(forumAnswersCollection + "/" + questionId + "/" + id)
+
+	var answer entity.ForumAnswer
+	if err := ref.Get(ctx, &answer); err != nil {
+		return nil, err
+	}
+	if answer.ID == "" {
+		return nil, errors.New(forumAnswerNotFound)
+	}
+	return &answer, nil
+}
+
+func (fr *ForumRepository) GetAnswers(questionId string) ([]*entity.ForumAnswer, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(forumAnswersCollection + "/" + questionId)
+
+	var answersMap map[string]*entity.ForumAnswer
+	if err := ref.Get(ctx, &answersMap); err != nil {
+		return nil, err
+	}
+
+	answers := make([]*entity.ForumAnswer, 0, len(answersMap))
+	for _, answer := range answersMap {
+		answers = append(answers, answer)
+	}
+	return answers, nil
+}
+
+func (fr *ForumRepository) DeleteAnswer(questionId, id string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(forumAnswersCollection + "/" + questionId + "/" + id)
+	return ref.Delete(ctx)
+}
+
+func (fr *ForumRepository) DeleteAnswers(questionId string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(forumAnswersCollection + "/" + questionId)
+	return ref.Delete(ctx)
+}
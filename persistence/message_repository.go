@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"firebase.google.com/go/v4/db"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
 )
@@ -19,7 +20,13 @@ type MessageRepositoryInterface interface {
 	GetByID(id string) (*entity.Message, error)
 	GetByConversation(user1Id, user2Id string) ([]*entity.Message, error)
 	GetByTeamID(teamId string) ([]*entity.Message, error)
+	GetByChannelID(channelId string) ([]*entity.Message, error)
+	GetByParentID(parentId string) ([]*entity.Message, error)
+	GetHistory(startAt, endAt string, limit int, newest bool) ([]*entity.Message, error)
+	GetAll() ([]*entity.Message, error)
 	Update(id string, updates map[string]interface{}) error
+	AddReply(id string, lastReplyAt int64) (*entity.Message, error)
+	UpdateReactions(id string, update func(reactions map[string][]string) error) (map[string][]string, error)
 	Delete(id string) error
 }
 
@@ -93,12 +100,152 @@ func (mr *MessageRepository) GetByTeamID(teamId string) ([]*entity.Message, erro
 	return messages, nil
 }
 
+func (mr *MessageRepository) GetByChannelID(channelId string) ([]*entity.Message, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(messagesCollection)
+
+	query := ref.OrderByChild("channelId").EqualTo(channelId)
+	results, err := query.GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*entity.Message, 0, len(results))
+	for _, r := range results {
+		var message entity.Message
+		if err := r.Unmarshal(&message); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &message)
+	}
+
+	return messages, nil
+}
+
+func (mr *MessageRepository) GetByParentID(parentId string) ([]*entity.Message, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(messagesCollection)
+
+	query := ref.OrderByChild("parentId").EqualTo(parentId)
+	results, err := query.GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*entity.Message, 0, len(results))
+	for _, r := range results {
+		var message entity.Message
+		if err := r.Unmarshal(&message); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &message)
+	}
+
+	return messages, nil
+}
+
+// GetHistory returns up to limit messages whose history key is between startAt and endAt, both included, oldest first.
+// It takes the first messages of the range, or the last ones with newest.
+func (mr *MessageRepository) GetHistory(startAt, endAt string, limit int, newest bool) ([]*entity.Message, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(messagesCollection)
+
+	query := ref.OrderByChild("historyKey").StartAt(startAt).EndAt(endAt)
+	if newest {
+		query = query.LimitToLast(limit)
+	} else {
+		query = query.LimitToFirst(limit)
+	}
+	results, err := query.GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*entity.Message, 0, len(results))
+	for _, r := range results {
+		var message entity.Message
+		if err := r.Unmarshal(&message); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &message)
+	}
+
+	return messages, nil
+}
+
+func (mr *MessageRepository) GetAll() ([]*entity.Message, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(messagesCollection)
+
+	var messagesMap map[string]*entity.Message
+	if err := ref.Get(ctx, &messagesMap); err != nil {
+		return nil, err
+	}
+
+	messages := make([]*entity.Message, 0, len(messagesMap))
+	for _, message := range messagesMap {
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
 func (mr *MessageRepository) Update(id string, updates map[string]interface{}) error {
 	ctx := context.Background()
 	ref := config.FirebaseDB.NewRef(messagesCollection + "/" + id)
 	return ref.Update(ctx, updates)
 }
 
+// AddReply counts a new reply of the thread in a transaction, so concurrent replies are all counted, and returns the
+// updated thread
+func (mr *MessageRepository) AddReply(id string, lastReplyAt int64) (*entity.Message, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(messagesCollection + "/" + id)
+
+	var message entity.Message
+	err := ref.Transaction(ctx, func(node db.TransactionNode) (interface{}, error) {
+		message = entity.Message{}
+		if err := node.Unmarshal(&message); err != nil {
+			return nil, err
+		}
+		if message.ID == "" {
+			return nil, errors.New(MessageNotFound)
+		}
+		message.ReplyCount++
+		message.LastReplyAt = max(message.LastReplyAt, lastReplyAt)
+		return &message, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// UpdateReactions applies the update to the message's reactions in a transaction, so concurrent reactions are all kept,
+// and returns the updated reactions; an error from the update cancels it
+func (mr *MessageRepository) UpdateReactions(id string, update func(reactions map[string][]string) error) (map[string][]string, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(messagesCollection + "/" + id + "/reactions")
+
+	var reactions map[string][]string
+	err := ref.Transaction(ctx, func(node db.TransactionNode) (interface{}, error) {
+		reactions = nil
+		if err := node.Unmarshal(&reactions); err != nil {
+			return nil, err
+		}
+		if reactions == nil {
+			reactions = make(map[string][]string)
+		}
+		if err := update(reactions); err != nil {
+			return nil, err
+		}
+		return reactions, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reactions, nil
+}
+
 func (mr *MessageRepository) Delete(id string) error {
 	ctx := context.Background()
 	ref := config.FirebaseDB.NewRef(messagesCollection + "/" + id)
@@ -0,0 +1,38 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+)
+
+const migrationsCollection = "migrations"
+
+type MigrationRepositoryInterface interface {
+	IsDone(name string) (bool, error)
+	MarkDone(name string) error
+}
+
+// MigrationRepository records the data migrations that completed (migrations/<name> = true)
+type MigrationRepository struct{}
+
+func NewMigrationRepository() *MigrationRepository {
+	return &MigrationRepository{}
+}
+
+func (mr *MigrationRepository) IsDone(name string) (bool, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(migrationsCollection + "/" + name)
+
+	var done bool
+	if err := ref.Get(ctx, &done); err != nil {
+		return false, err
+	}
+	return done, nil
+}
+
+func (mr *MigrationRepository) MarkDone(name string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(migrationsCollection + "/" + name)
+	return ref.Set(ctx, true)
+}
@@ -0,0 +1,129 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	teamBansCollection      = "teamBans"
+	teamMutesCollection     = "teamMutes"
+	moderationLogCollection = "moderationLog"
+)
+
+type ModerationRepositoryInterface interface {
+	GetBan(teamId, userId string) (*entity.TeamRestriction, error)
+	GetBans(teamId string) ([]*entity.TeamRestriction, error)
+	SaveBan(ban *entity.TeamRestriction) error
+	DeleteBan(teamId, userId string) error
+	GetMute(teamId, userId string) (*entity.TeamRestriction, error)
+	GetMutes(teamId string) ([]*entity.TeamRestriction, error)
+	SaveMute(mute *entity.TeamRestriction) error
+	DeleteMute(teamId, userId string) error
+	CreateLogEntry(entry *entity.ModerationLogEntry) error
+	GetLog(teamId string) ([]*entity.ModerationLogEntry, error)
+}
+
+// ModerationRepository stores bans and mutes by team and user (teamBans/<teamId>/<userId>, teamMutes/<teamId>/<userId>)
+// and the moderation log by team (moderationLog/<teamId>/<entryId>)
+type ModerationRepository struct{}
+
+func NewModerationRepository() *ModerationRepository {
+	return &ModerationRepository{}
+}
+
+// GetBan returns nil when the user is not banned from the team
+func (mr *ModerationRepository) GetBan(teamId, userId string) (*entity.TeamRestriction, error) {
+	return getRestriction(teamBansCollection, teamId, userId)
+}
+
+func (mr *ModerationRepository) GetBans(teamId string) ([]*entity.TeamRestriction, error) {
+	return getRestrictions(teamBansCollection, teamId)
+}
+
+func (mr *ModerationRepository) SaveBan(ban *entity.TeamRestriction) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(teamBansCollection + "/" + ban.TeamID + "/" + ban.UserID)
+	return ref.Set(ctx, ban)
+}
+
+func (mr *ModerationRepository) DeleteBan(teamId, userId string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(teamBansCollection + "/" + teamId + "/" + userId)
+	return ref.Delete(ctx)
+}
+
+// GetMute returns nil when the user is not muted in the team
+func (mr *ModerationRepository) GetMute(teamId, userId string) (*entity.TeamRestriction, error) {
+	return getRestriction(teamMutesCollection, teamId, userId)
+}
+
+func (mr *ModerationRepository) GetMutes(teamId string) ([]*entity.TeamRestriction, error) {
+	return getRestrictions(teamMutesCollection, teamId)
+}
+
+func (mr *ModerationRepository) SaveMute(mute *entity.TeamRestriction) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(teamMutesCollection + "/" + mute.TeamID + "/" + mute.UserID)
+	return ref.Set(ctx, mute)
+}
+
+func (mr *ModerationRepository) DeleteMute(teamId, userId string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(teamMutesCollection + "/" + teamId + "/" + userId)
+	return ref.Delete(ctx)
+}
+
+func (mr *ModerationRepository) CreateLogEntry(entry *entity.ModerationLogEntry) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(moderationLogCollection + "/" + entry.TeamID + "/" + entry.ID)
+	return ref.Set(ctx, entry)
+}
+
+func (mr *ModerationRepository) GetLog(teamId string) ([]*entity.ModerationLogEntry, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(moderationLogCollection + "/" + teamId)
+
+	var entriesMap map[string]*entity.ModerationLogEntry
+	if err := ref.Get(ctx, &entriesMap); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*entity.ModerationLogEntry, 0, len(entriesMap))
+	for _, entry := range entriesMap {
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func getRestriction(collection, teamId, userId string) (*entity.TeamRestriction, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(collection + "/" + teamId + "/" + userId)
+
+	var restriction entity.TeamRestriction
+	if err := ref.Get(ctx, &restriction); err != nil {
+		return nil, err
+	}
+	if restriction.UserID == "" {
+		return nil, nil
+	}
+	return &restriction, nil
+}
+
+func getRestrictions(collection, teamId string) ([]*entity.TeamRestriction, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(collection + "/" + teamId)
+
+	var restrictionsMap map[string]*entity.TeamRestriction
+	if err := ref.Get(ctx, &restrictionsMap); err != nil {
+		return nil, err
+	}
+
+	restrictions := make([]*entity.TeamRestriction, 0, len(restrictionsMap))
+	for _, restriction := range restrictionsMap {
+		restrictions = append(restrictions, restriction)
+	}
+	return restrictions, nil
+}
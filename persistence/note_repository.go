@@ -0,0 +1,113 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	notesCollection         = "notes"
+	noteSnapshotsCollection = "noteSnapshots"
+	noteNotFound            = "note not found"
+)
+
+type NoteRepositoryInterface interface {
+	Create(note *entity.Note) error
+	GetByID(id string) (*entity.Note, error)
+	GetByTeamID(teamId string) ([]*entity.Note, error)
+	Update(note *entity.Note) error
+	Delete(id string) error
+	SaveSnapshot(snapshot *entity.NoteSnapshot) error
+	GetSnapshot(noteId string) (*entity.NoteSnapshot, error)
+	DeleteSnapshot(noteId string) error
+}
+
+// NoteRepository stores the notes (notes/<id>) apart from their snapshots (noteSnapshots/<noteId>), so listing the
+// notes does not load their text
+type NoteRepository struct{}
+
+func NewNoteRepository() *NoteRepository {
+	return &NoteRepository{}
+}
+
+func (nr *NoteRepository) Create(note *entity.Note) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(notesCollection + "/" + note.ID)
+	return ref.Set(ctx, note)
+}
+
+func (nr *NoteRepository) GetByID(id string) (*entity.Note, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(notesCollection + "/" + id)
+
+	var note entity.Note
+	if err := ref.Get(ctx, &note); err != nil {
+		return nil, err
+	}
+	if note.ID == "" {
+		return nil, errors.New(noteNotFound)
+	}
+	return &note, nil
+}
+
+func (nr *NoteRepository) GetByTeamID(teamId string) ([]*entity.Note, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(notesCollection)
+
+	results, err := ref.OrderByChild("teamId").EqualTo(teamId).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]*entity.Note, 0, len(results))
+	for _, r := range results {
+		var note entity.Note
+		if err := r.Unmarshal(&note); err != nil {
+			return nil, err
+		}
+		notes = append(notes, &note)
+	}
+	return notes, nil
+}
+
+func (nr *NoteRepository) Update(note *entity.Note) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(notesCollection + "/" + note.ID)
+	return ref.Set(ctx, note)
+}
+
+func (nr *NoteRepository) Delete(id string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(notesCollection + "/" + id)
+	return ref.Delete(ctx)
+}
+
+func (nr *NoteRepository) SaveSnapshot(snapshot *entity.NoteSnapshot) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(noteSnapshotsCollection + "/" + snapshot.NoteID)
+	return ref.Set(ctx, snapshot)
+}
+
+// GetSnapshot returns nil, nil when the note was never saved
+func (nr *NoteRepository) GetSnapshot(noteId string) (*entity.NoteSnapshot, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(noteSnapshotsCollection + "/" + noteId)
+
+	var snapshot entity.NoteSnapshot
+	if err := ref.Get(ctx, &snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.NoteID == "" {
+		return nil, nil
+	}
+	return &snapshot, nil
+}
+
+func (nr *NoteRepository) DeleteSnapshot(noteId string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(noteSnapshotsCollection + "/" + noteId)
+	return ref.Delete(ctx)
+}
@@ -0,0 +1,69 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	organizationsCollection = "organizations"
+	organizationNotFound    = "organization not found"
+)
+
+type OrganizationRepositoryInterface interface {
+	Create(organization *entity.Organization) error
+	GetByID(id string) (*entity.Organization, error)
+	GetAll() ([]*entity.Organization, error)
+	Update(organization *entity.Organization) error
+}
+
+type OrganizationRepository struct{}
+
+func NewOrganizationRepository() *OrganizationRepository {
+	return &OrganizationRepository{}
+}
+
+func (or *OrganizationRepository) Create(organization *entity.Organization) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(organizationsCollection + "/" + organization.ID)
+	return ref.Set(ctx, organization)
+}
+
+func (or *OrganizationRepository) GetByID(id string) (*entity.Organization, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(organizationsCollection + "/" + id)
+
+	var organization entity.Organization
+	if err := ref.Get(ctx, &organization); err != nil {
+		return nil, err
+	}
+	if organization.ID == "" {
+		return nil, errors.New(organizationNotFound)
+	}
+	return &organization, nil
+}
+
+func (or *OrganizationRepository) GetAll() ([]*entity.Organization, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(organizationsCollection)
+
+	var organizationsMap map[string]*entity.Organization
+	if err := ref.Get(ctx, &organizationsMap); err != nil {
+		return nil, err
+	}
+
+	organizations := make([]*entity.Organization, 0, len(organizationsMap))
+	for _, organization := range organizationsMap {
+		organizations = append(organizations, organization)
+	}
+	return organizations, nil
+}
+
+func (or *OrganizationRepository) Update(organization *entity.Organization) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(organizationsCollection + "/" + organization.ID)
+	return ref.Set(ctx, organization)
+}
@@ -0,0 +1,89 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const outboxCollection = "outbox"
+
+type OutboxRepositoryInterface interface {
+	GetLast(userId string) (*entity.OutboxEntry, error)
+	GetAfter(userId string, seq int64) ([]*entity.OutboxEntry, error)
+	SaveAll(userId string, entries []*entity.OutboxEntry) error
+	DeleteUpTo(userId string, seq int64) error
+}
+
+// OutboxRepository stores the events sent to each user by sequence number (outbox/<userId>/<padded seq>)
+type OutboxRepository struct{}
+
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{}
+}
+
+// GetLast returns nil when the user's outbox is empty
+func (ob *OutboxRepository) GetLast(userId string) (*entity.OutboxEntry, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(outboxCollection + "/" + userId)
+
+	results, err := ref.OrderByKey().LimitToLast(1).GetOrdered(ctx)
+	if err != nil || len(results) == 0 {
+		return nil, err
+	}
+	var entry entity.OutboxEntry
+	if err := results[0].Unmarshal(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetAfter returns the user's entries with a sequence number above seq, in order
+func (ob *OutboxRepository) GetAfter(userId string, seq int64) ([]*entity.OutboxEntry, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(outboxCollection + "/" + userId)
+
+	results, err := ref.OrderByKey().StartAt(entity.OutboxKey(seq + 1)).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*entity.OutboxEntry, 0, len(results))
+	for _, result := range results {
+		var entry entity.OutboxEntry
+		if err := result.Unmarshal(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+// SaveAll writes the user's entries in one update
+func (ob *OutboxRepository) SaveAll(userId string, entries []*entity.OutboxEntry) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(outboxCollection + "/" + userId)
+
+	updates := make(map[string]interface{}, len(entries))
+	for _, entry := range entries {
+		updates[entity.OutboxKey(entry.Seq)] = entry
+	}
+	return ref.Update(ctx, updates)
+}
+
+// DeleteUpTo removes the user's entries with a sequence number up to seq
+func (ob *OutboxRepository) DeleteUpTo(userId string, seq int64) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(outboxCollection + "/" + userId)
+
+	results, err := ref.OrderByKey().EndAt(entity.OutboxKey(seq)).GetOrdered(ctx)
+	if err != nil || len(results) == 0 {
+		return err
+	}
+	updates := make(map[string]interface{}, len(results))
+	for _, result := range results {
+		updates[result.Key()] = nil
+	}
+	return ref.Update(ctx, updates)
+}
@@ -0,0 +1,43 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const presenceCollection = "presence"
+
+type PresenceRepositoryInterface interface {
+	Get(userId string) (*entity.Presence, error)
+	Save(presence *entity.Presence) error
+}
+
+// PresenceRepository stores the users' last seen times (presence/<userId>)
+type PresenceRepository struct{}
+
+func NewPresenceRepository() *PresenceRepository {
+	return &PresenceRepository{}
+}
+
+// Get returns nil when the user was never seen
+func (pr *PresenceRepository) Get(userId string) (*entity.Presence, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(presenceCollection + "/" + userId)
+
+	var presence entity.Presence
+	if err := ref.Get(ctx, &presence); err != nil {
+		return nil, err
+	}
+	if presence.UserID == "" {
+		return nil, nil
+	}
+	return &presence, nil
+}
+
+func (pr *PresenceRepository) Save(presence *entity.Presence) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(presenceCollection + "/" + presence.UserID)
+	return ref.Set(ctx, presence)
+}
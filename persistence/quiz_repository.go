@@ -25,6 +25,7 @@ type QuizRepositoryInterface interface {
 	GetByUser(id string, pageSize int, lastKey string) ([]entity.Quiz, string, error)
 	GetByUserAndTeam(userId string, teamId string, pageSize int, lastKey string) ([]entity.Quiz, string, error)
 	GetByTeam(id string, pageSize int, lastKey string) ([]entity.Quiz, string, error)
+	GetAll() ([]entity.Quiz, error)
 }
 
 type QuizRepository struct{}
@@ -61,6 +62,22 @@ func (qr *QuizRepository) GetById(id string) (entity.Quiz, error) {
 	return quiz, nil
 }
 
+func (qr *QuizRepository) GetAll() ([]entity.Quiz, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(quizCollection)
+
+	var quizzesMap map[string]entity.Quiz
+	if err := ref.Get(ctx, &quizzesMap); err != nil {
+		return nil, err
+	}
+
+	quizzes := make([]entity.Quiz, 0, len(quizzesMap))
+	for _, quiz := range quizzesMap {
+		quizzes = append(quizzes, quiz)
+	}
+	return quizzes, nil
+}
+
 func (qr *QuizRepository) GetByUser(id string, pageSize int, lastKey string) ([]entity.Quiz, string, error) {
 	ctx := context.Background()
 	ref := config.FirebaseDB.NewRef(quizCollection)
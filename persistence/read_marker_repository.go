@@ -0,0 +1,60 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const readMarkersCollection = "readMarkers"
+
+type ReadMarkerRepositoryInterface interface {
+	Get(userId, conversationId string) (*entity.ReadMarker, error)
+	GetByUser(userId string) ([]*entity.ReadMarker, error)
+	Save(marker *entity.ReadMarker) error
+}
+
+// ReadMarkerRepository stores the read markers by user and conversation (readMarkers/<userId>/<conversationId>)
+type ReadMarkerRepository struct{}
+
+func NewReadMarkerRepository() *ReadMarkerRepository {
+	return &ReadMarkerRepository{}
+}
+
+// Get returns nil when the user has no marker in the conversation
+func (rr *ReadMarkerRepository) Get(userId, conversationId string) (*entity.ReadMarker, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(readMarkersCollection + "/" + userId + "/" + conversationId)
+
+	var marker entity.ReadMarker
+	if err := ref.Get(ctx, &marker); err != nil {
+		return nil, err
+	}
+	if marker.UserID == "" {
+		return nil, nil
+	}
+	return &marker, nil
+}
+
+func (rr *ReadMarkerRepository) GetByUser(userId string) ([]*entity.ReadMarker, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(readMarkersCollection + "/" + userId)
+
+	var markersMap map[string]*entity.ReadMarker
+	if err := ref.Get(ctx, &markersMap); err != nil {
+		return nil, err
+	}
+
+	markers := make([]*entity.ReadMarker, 0, len(markersMap))
+	for _, marker := range markersMap {
+		markers = append(markers, marker)
+	}
+	return markers, nil
+}
+
+func (rr *ReadMarkerRepository) Save(marker *entity.ReadMarker) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(readMarkersCollection + "/" + marker.UserID + "/" + marker.ConversationID)
+	return ref.Set(ctx, marker)
+}
@@ -0,0 +1,172 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	searchIndexCollection = "searchIndex"
+	searchDocsCollection  = "searchDocs"
+	searchDocNotFound     = "search document not found"
+
+	// searchDocLoaders is how many documents GetByKeys reads at the same time
+	searchDocLoaders = 8
+)
+
+type SearchRepositoryInterface interface {
+	Save(doc *entity.SearchDocument) error
+	GetByKey(key string) (*entity.SearchDocument, error)
+	GetByKeys(keys []string) (map[string]*entity.SearchDocument, error)
+	Delete(key string) error
+	GetPostings(token string) (map[string]int, error)
+	GetPostingsByPrefix(prefix string, limit int) (map[string]map[string]int, error)
+	IsEmpty() (bool, error)
+}
+
+type SearchRepository struct{}
+
+func NewSearchRepository() *SearchRepository {
+	return &SearchRepository{}
+}
+
+// Save stores the document and its postings (searchIndex/<token>/<docKey> = weight) in a single multi-path update.
+// Postings of tokens that the previous version of the document had, but the new one does not, are removed.
+func (sr *SearchRepository) Save(doc *entity.SearchDocument) error {
+	ctx := context.Background()
+
+	updates := map[string]interface{}{
+		searchDocsCollection + "/" + doc.Key: doc,
+	}
+	if old, err := sr.GetByKey(doc.Key); err == nil {
+		for token := range old.Terms {
+			if _, ok := doc.Terms[token]; !ok {
+				updates[searchIndexCollection+"/"+token+"/"+doc.Key] = nil
+			}
+		}
+	}
+	for token, weight := range doc.Terms {
+		updates[searchIndexCollection+"/"+token+"/"+doc.Key] = weight
+	}
+
+	return config.FirebaseDB.NewRef("/").Update(ctx, updates)
+}
+
+func (sr *SearchRepository) GetByKey(key string) (*entity.SearchDocument, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(searchDocsCollection + "/" + key)
+
+	var doc entity.SearchDocument
+	if err := ref.Get(ctx, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Key == "" {
+		return nil, errors.New(searchDocNotFound)
+	}
+	return &doc, nil
+}
+
+// GetByKeys reads the documents concurrently; keys whose document is gone are left out of the result
+func (sr *SearchRepository) GetByKeys(keys []string) (map[string]*entity.SearchDocument, error) {
+	docs := make(map[string]*entity.SearchDocument, len(keys))
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	loaders := make(chan struct{}, searchDocLoaders)
+
+	for _, key := range keys {
+		wg.Add(1)
+		loaders <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-loaders }()
+
+			doc, err := sr.GetByKey(key)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				docs[key] = doc
+			case err.Error() != searchDocNotFound && firstErr == nil:
+				firstErr = err
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return docs, nil
+}
+
+func (sr *SearchRepository) Delete(key string) error {
+	ctx := context.Background()
+
+	doc, err := sr.GetByKey(key)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{
+		searchDocsCollection + "/" + key: nil,
+	}
+	for token := range doc.Terms {
+		updates[searchIndexCollection+"/"+token+"/"+key] = nil
+	}
+
+	return config.FirebaseDB.NewRef("/").Update(ctx, updates)
+}
+
+// GetPostings returns the weights of the documents containing the token
+func (sr *SearchRepository) GetPostings(token string) (map[string]int, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(searchIndexCollection + "/" + token)
+
+	var postings map[string]int
+	if err := ref.Get(ctx, &postings); err != nil {
+		return nil, err
+	}
+	if postings == nil {
+		postings = map[string]int{}
+	}
+	return postings, nil
+}
+
+// GetPostingsByPrefix returns, for the first limit indexed tokens starting with prefix, the weights of the documents
+// containing them
+func (sr *SearchRepository) GetPostingsByPrefix(prefix string, limit int) (map[string]map[string]int, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(searchIndexCollection)
+
+	query := ref.OrderByKey().
+		StartAt(prefix).
+		EndAt(prefix + "\uf8ff").
+		LimitToFirst(limit)
+
+	var postings map[string]map[string]int
+	if err := query.Get(ctx, &postings); err != nil {
+		return nil, err
+	}
+	if postings == nil {
+		postings = map[string]map[string]int{}
+	}
+	return postings, nil
+}
+
+func (sr *SearchRepository) IsEmpty() (bool, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(searchDocsCollection)
+
+	results, err := ref.OrderByKey().LimitToFirst(1).GetOrdered(ctx)
+	if err != nil {
+		return false, err
+	}
+	return len(results) == 0, nil
+}
@@ -0,0 +1,97 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	studySessionsCollection = "studySessions"
+	studySessionNotFound    = "study session not found"
+)
+
+type StudySessionRepositoryInterface interface {
+	Create(session *entity.StudySession) error
+	GetByID(id string) (*entity.StudySession, error)
+	GetByTeamID(teamId string) ([]*entity.StudySession, error)
+	GetAll() ([]*entity.StudySession, error)
+	Update(session *entity.StudySession) error
+	Delete(id string) error
+}
+
+type StudySessionRepository struct{}
+
+func NewStudySessionRepository() *StudySessionRepository {
+	return &StudySessionRepository{}
+}
+
+func (sr *StudySessionRepository) Create(session *entity.StudySession) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(studySessionsCollection + "/" + session.ID)
+	return ref.Set(ctx, session)
+}
+
+func (sr *StudySessionRepository) GetByID(id string) (*entity.StudySession, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(studySessionsCollection + "/" + id)
+
+	var session entity.StudySession
+	if err := ref.Get(ctx, &session); err != nil {
+		return nil, err
+	}
+	if session.ID == "" {
+		return nil, errors.New(studySessionNotFound)
+	}
+	return &session, nil
+}
+
+func (sr *StudySessionRepository) GetByTeamID(teamId string) ([]*entity.StudySession, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(studySessionsCollection)
+
+	results, err := ref.OrderByChild("teamId").EqualTo(teamId).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*entity.StudySession, 0, len(results))
+	for _, r := range results {
+		var session entity.StudySession
+		if err := r.Unmarshal(&session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+	return sessions, nil
+}
+
+func (sr *StudySessionRepository) GetAll() ([]*entity.StudySession, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(studySessionsCollection)
+
+	var sessionsMap map[string]*entity.StudySession
+	if err := ref.Get(ctx, &sessionsMap); err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*entity.StudySession, 0, len(sessionsMap))
+	for _, session := range sessionsMap {
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (sr *StudySessionRepository) Update(session *entity.StudySession) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(studySessionsCollection + "/" + session.ID)
+	return ref.Set(ctx, session)
+}
+
+func (sr *StudySessionRepository) Delete(id string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(studySessionsCollection + "/" + id)
+	return ref.Delete(ctx)
+}
@@ -0,0 +1,136 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	taskBoardsCollection   = "taskBoards"
+	tasksCollection        = "tasks"
+	taskCommentsCollection = "taskComments"
+	taskNotFound           = "task not found"
+)
+
+type TaskRepositoryInterface interface {
+	GetBoard(teamId string) (*entity.TaskBoard, error)
+	SaveBoard(board *entity.TaskBoard) error
+	Create(task *entity.Task) error
+	GetByID(id string) (*entity.Task, error)
+	GetByTeamID(teamId string) ([]*entity.Task, error)
+	Update(task *entity.Task) error
+	Delete(id string) error
+	CreateComment(comment *entity.TaskComment) error
+	GetComments(taskId string) ([]*entity.TaskComment, error)
+	DeleteComments(taskId string) error
+}
+
+type TaskRepository struct{}
+
+func NewTaskRepository() *TaskRepository {
+	return &TaskRepository{}
+}
+
+// GetBoard returns nil without an error when the team has no board yet
+func (tr *TaskRepository) GetBoard(teamId string) (*entity.TaskBoard, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(taskBoardsCollection + "/" + teamId)
+
+	var board entity.TaskBoard
+	if err := ref.Get(ctx, &board); err != nil {
+		return nil, err
+	}
+	if board.TeamID == "" {
+		return nil, nil
+	}
+	return &board, nil
+}
+
+func (tr *TaskRepository) SaveBoard(board *entity.TaskBoard) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(taskBoardsCollection + "/" + board.TeamID)
+	return ref.Set(ctx, board)
+}
+
+func (tr *TaskRepository) Create(task *entity.Task) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(tasksCollection + "/" + task.ID)
+	return ref.Set(ctx, task)
+}
+
+func (tr *TaskRepository) GetByID(id string) (*entity.Task, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(tasksCollection + "/" + id)
+
+	var task entity.Task
+	if err := ref.Get(ctx, &task); err != nil {
+		return nil, err
+	}
+	if task.ID == "" {
+		return nil, errors.New(taskNotFound)
+	}
+	return &task, nil
+}
+
+func (tr *TaskRepository) GetByTeamID(teamId string) ([]*entity.Task, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(tasksCollection)
+
+	results, err := ref.OrderByChild("teamId").EqualTo(teamId).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*entity.Task, 0, len(results))
+	for _, r := range results {
+		var task entity.Task
+		if err := r.Unmarshal(&task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &task)
+	}
+	return tasks, nil
+}
+
+func (tr *TaskRepository) Update(task *entity.Task) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(tasksCollection + "/" + task.ID)
+	return ref.Set(ctx, task)
+}
+
+func (tr *TaskRepository) Delete(id string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(tasksCollection + "/" + id)
+	return ref.Delete(ctx)
+}
+
+func (tr *TaskRepository) CreateComment(comment *entity.TaskComment) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(taskCommentsCollection + "/" + comment.TaskID + "/" + comment.ID)
+	return ref.Set(ctx, comment)
+}
+
+func (tr *TaskRepository) GetComments(taskId string) ([]*entity.TaskComment, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(taskCommentsCollection + "/" + taskId)
+
+	var commentsMap map[string]*entity.TaskComment
+	if err := ref.Get(ctx, &commentsMap); err != nil {
+		return nil, err
+	}
+
+	comments := make([]*entity.TaskComment, 0, len(commentsMap))
+	for _, comment := range commentsMap {
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+func (tr *TaskRepository) DeleteComments(taskId string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(taskCommentsCollection + "/" + taskId)
+	return ref.Delete(ctx)
+}
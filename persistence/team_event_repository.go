@@ -0,0 +1,44 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const teamEventsCollection = "teamEvents"
+
+type TeamEventRepositoryInterface interface {
+	Create(event *entity.TeamEvent) error
+	GetByTeamID(teamId string) ([]*entity.TeamEvent, error)
+}
+
+// TeamEventRepository stores the events grouped by team: teamEvents/<teamId>/<eventId>
+type TeamEventRepository struct{}
+
+func NewTeamEventRepository() *TeamEventRepository {
+	return &TeamEventRepository{}
+}
+
+func (ter *TeamEventRepository) Create(event *entity.TeamEvent) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(teamEventsCollection + "/" + event.TeamID + "/" + event.ID)
+	return ref.Set(ctx, event)
+}
+
+func (ter *TeamEventRepository) GetByTeamID(teamId string) ([]*entity.TeamEvent, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(teamEventsCollection + "/" + teamId)
+
+	var eventsMap map[string]*entity.TeamEvent
+	if err := ref.Get(ctx, &eventsMap); err != nil {
+		return nil, err
+	}
+
+	events := make([]*entity.TeamEvent, 0, len(eventsMap))
+	for _, event := range eventsMap {
+		events = append(events, event)
+	}
+	return events, nil
+}
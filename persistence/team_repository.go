@@ -41,7 +41,8 @@ func (tr *TeamRepository) GetTeamById(id string) (*entity.Team, error) {
 	return &team, nil
 }
 
-func (tr *TeamRepository) GetXTeamsByPrefix(prefix string, x int) ([]*entity.Team, error) {
+// GetXTeamsByPrefix returns the first x teams of the organization whose name starts with prefix
+func (tr *TeamRepository) GetXTeamsByPrefix(organizationID, prefix string, x int) ([]*entity.Team, error) {
 	ctx := context.Background()
 	ref := config.FirebaseDB.NewRef(teamsCollection)
 
@@ -54,23 +55,25 @@ func (tr *TeamRepository) GetXTeamsByPrefix(prefix string, x int) ([]*entity.Tea
 		return nil, err
 	}
 
-	if len(results) > x {
-		results = results[:x]
-	}
-
-	teams := make([]*entity.Team, 0, len(results))
+	teams := make([]*entity.Team, 0, min(len(results), x))
 	for _, r := range results {
+		if len(teams) >= x {
+			break
+		}
 		var team entity.Team
 		if err := r.Unmarshal(&team); err != nil {
 			return nil, err
 		}
-		teams = append(teams, &team)
+		if team.OrganizationID == organizationID {
+			teams = append(teams, &team)
+		}
 	}
 
 	return teams, nil
 }
 
-func (tr *TeamRepository) GetTeamsByName(name string) ([]*entity.Team, error) {
+// GetTeamsByName returns the teams of the organization with the given name
+func (tr *TeamRepository) GetTeamsByName(organizationID, name string) ([]*entity.Team, error) {
 	ctx := context.Background()
 	ref := config.FirebaseDB.NewRef(teamsCollection)
 
@@ -87,7 +90,9 @@ func (tr *TeamRepository) GetTeamsByName(name string) ([]*entity.Team, error) {
 		if err := r.Unmarshal(&team); err != nil {
 			return nil, err
 		}
-		teams = append(teams, &team)
+		if team.OrganizationID == organizationID {
+			teams = append(teams, &team)
+		}
 	}
 
 	return teams, nil
@@ -109,6 +114,42 @@ func (tr *TeamRepository) GetAll() ([]*entity.Team, error) {
 	return teams, nil
 }
 
+// GetByOrganizationID returns the teams of the organization; an empty ID returns the teams outside any organization
+func (tr *TeamRepository) GetByOrganizationID(organizationID string) ([]*entity.Team, error) {
+	if organizationID == "" {
+		// Teams outside any organization have no organizationId to query
+		all, err := tr.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		teams := make([]*entity.Team, 0, len(all))
+		for _, team := range all {
+			if team.OrganizationID == "" {
+				teams = append(teams, team)
+			}
+		}
+		return teams, nil
+	}
+
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(teamsCollection)
+
+	results, err := ref.OrderByChild(organizationField).EqualTo(organizationID).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make([]*entity.Team, 0, len(results))
+	for _, r := range results {
+		var team entity.Team
+		if err := r.Unmarshal(&team); err != nil {
+			return nil, err
+		}
+		teams = append(teams, &team)
+	}
+	return teams, nil
+}
+
 func (tr *TeamRepository) Update(team *entity.Team) error {
 	ctx := context.Background()
 	ref := config.FirebaseDB.NewRef(teamsCollection + "/" + team.Id)
@@ -0,0 +1,73 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	teamTemplatesCollection = "teamTemplates"
+	teamTemplateNotFound    = "team template not found"
+)
+
+type TeamTemplateRepositoryInterface interface {
+	Create(template *entity.TeamTemplate) error
+	GetByID(id string) (*entity.TeamTemplate, error)
+	GetByOwnerID(ownerId string) ([]*entity.TeamTemplate, error)
+	Delete(id string) error
+}
+
+type TeamTemplateRepository struct{}
+
+func NewTeamTemplateRepository() *TeamTemplateRepository {
+	return &TeamTemplateRepository{}
+}
+
+func (ttr *TeamTemplateRepository) Create(template *entity.TeamTemplate) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(teamTemplatesCollection + "/" + template.ID)
+	return ref.Set(ctx, template)
+}
+
+func (ttr *TeamTemplateRepository) GetByID(id string) (*entity.TeamTemplate, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(teamTemplatesCollection + "/" + id)
+
+	var template entity.TeamTemplate
+	if err := ref.Get(ctx, &template); err != nil {
+		return nil, err
+	}
+	if template.ID == "" {
+		return nil, errors.New(teamTemplateNotFound)
+	}
+	return &template, nil
+}
+
+func (ttr *TeamTemplateRepository) GetByOwnerID(ownerId string) ([]*entity.TeamTemplate, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(teamTemplatesCollection)
+
+	results, err := ref.OrderByChild("ownerId").EqualTo(ownerId).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]*entity.TeamTemplate, 0, len(results))
+	for _, r := range results {
+		var template entity.TeamTemplate
+		if err := r.Unmarshal(&template); err != nil {
+			return nil, err
+		}
+		templates = append(templates, &template)
+	}
+	return templates, nil
+}
+
+func (ttr *TeamTemplateRepository) Delete(id string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(teamTemplatesCollection + "/" + id)
+	return ref.Delete(ctx)
+}
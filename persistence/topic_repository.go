@@ -0,0 +1,77 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	topicsCollection = "topics"
+	topicNotFound    = "topic not found"
+)
+
+type TopicRepositoryInterface interface {
+	Create(topic *entity.Topic) error
+	GetBySlug(slug model.TopicOfInterest) (*entity.Topic, error)
+	GetAll() ([]*entity.Topic, error)
+	Update(topic *entity.Topic) error
+	Delete(slug model.TopicOfInterest) error
+}
+
+type TopicRepository struct{}
+
+func NewTopicRepository() *TopicRepository {
+	return &TopicRepository{}
+}
+
+func (tr *TopicRepository) Create(topic *entity.Topic) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(topicsCollection + "/" + string(topic.Slug))
+	return ref.Set(ctx, topic)
+}
+
+func (tr *TopicRepository) GetBySlug(slug model.TopicOfInterest) (*entity.Topic, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(topicsCollection + "/" + string(slug))
+
+	var topic entity.Topic
+	if err := ref.Get(ctx, &topic); err != nil {
+		return nil, err
+	}
+	if topic.Slug == "" {
+		return nil, errors.New(topicNotFound)
+	}
+	return &topic, nil
+}
+
+func (tr *TopicRepository) GetAll() ([]*entity.Topic, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(topicsCollection)
+
+	var topicsMap map[string]*entity.Topic
+	if err := ref.Get(ctx, &topicsMap); err != nil {
+		return nil, err
+	}
+
+	topics := make([]*entity.Topic, 0, len(topicsMap))
+	for _, topic := range topicsMap {
+		topics = append(topics, topic)
+	}
+	return topics, nil
+}
+
+func (tr *TopicRepository) Update(topic *entity.Topic) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(topicsCollection + "/" + string(topic.Slug))
+	return ref.Set(ctx, topic)
+}
+
+func (tr *TopicRepository) Delete(slug model.TopicOfInterest) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(topicsCollection + "/" + string(slug))
+	return ref.Delete(ctx)
+}
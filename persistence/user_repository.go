@@ -13,6 +13,8 @@ const (
 	emailField      = "email"
 	usernameField   = "username"
 	userNotFound    = "user not found"
+
+	organizationField = "organizationId"
 )
 
 type UserRepository struct{}
@@ -110,3 +112,39 @@ func (ur *UserRepository) GetAll() ([]*entity.User, error) {
 	}
 	return users, nil
 }
+
+// GetByOrganizationID returns the users of the organization; an empty ID returns the users outside any organization
+func (ur *UserRepository) GetByOrganizationID(organizationID string) ([]*entity.User, error) {
+	if organizationID == "" {
+		// Users outside any organization have no organizationId to query
+		all, err := ur.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		users := make([]*entity.User, 0, len(all))
+		for _, user := range all {
+			if user.OrganizationID == "" {
+				users = append(users, user)
+			}
+		}
+		return users, nil
+	}
+
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(usersCollection)
+
+	results, err := ref.OrderByChild(organizationField).EqualTo(organizationID).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*entity.User, 0, len(results))
+	for _, r := range results {
+		var user entity.User
+		if err := r.Unmarshal(&user); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+	return users, nil
+}
@@ -0,0 +1,135 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	wikiPagesCollection     = "wikiPages"
+	wikiRevisionsCollection = "wikiRevisions"
+	wikiPageNotFound        = "wiki page not found"
+	wikiRevisionNotFound    = "revision not found"
+)
+
+type WikiRepositoryInterface interface {
+	CreatePage(page *entity.WikiPage) error
+	GetPage(id string) (*entity.WikiPage, error)
+	GetPagesByTeamID(teamId string) ([]*entity.WikiPage, error)
+	UpdatePage(page *entity.WikiPage) error
+	DeletePage(id string) error
+	SaveRevision(revision *entity.WikiRevision) error
+	GetRevision(pageId string, number int) (*entity.WikiRevision, error)
+	GetRevisions(pageId string) ([]*entity.WikiRevision, error)
+	DeleteRevisions(pageId string) error
+}
+
+// WikiRepository stores the pages by ID (wikiPages/<id>) and their revisions grouped by page (wikiRevisions/<pageId>/r<number>)
+type WikiRepository struct{}
+
+func NewWikiRepository() *WikiRepository {
+	return &WikiRepository{}
+}
+
+func (wr *WikiRepository) CreatePage(page *entity.WikiPage) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(wikiPagesCollection + "/" + page.ID)
+	return ref.Set(ctx, page)
+}
+
+func (wr *WikiRepository) GetPage(id string) (*entity.WikiPage, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(wikiPagesCollection + "/" + id)
+
+	var page entity.WikiPage
+	if err := ref.Get(ctx, &page); err != nil {
+		return nil, err
+	}
+	if page.ID == "" {
+		return nil, errors.New(wikiPageNotFound)
+	}
+	return &page, nil
+}
+
+func (wr *WikiRepository) GetPagesByTeamID(teamId string) ([]*entity.WikiPage, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(wikiPagesCollection)
+
+	results, err := ref.OrderByChild("teamId").EqualTo(teamId).GetOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]*entity.WikiPage, 0, len(results))
+	for _, r := range results {
+		var page entity.WikiPage
+		if err := r.Unmarshal(&page); err != nil {
+			return nil, err
+		}
+		pages = append(pages, &page)
+	}
+	return pages, nil
+}
+
+func (wr *WikiRepository) UpdatePage(page *entity.WikiPage) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(wikiPagesCollection + "/" + page.ID)
+	return ref.Set(ctx, page)
+}
+
+func (wr *WikiRepository) DeletePage(id string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(wikiPagesCollection + "/" + id)
+	return ref.Delete(ctx)
+}
+
+func (wr *WikiRepository) SaveRevision(revision *entity.WikiRevision) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(wikiRevisionsCollection + "/" + revision.PageID + "/" + revisionKey(revision.Number))
+	return ref.Set(ctx, revision)
+}
+
+func (wr *WikiRepository) GetRevision(pageId string, number int) (*entity.WikiRevision, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(wikiRevisionsCollection + "/" + pageId + "/" + revisionKey(number))
+
+	var revision entity.WikiRevision
+	if err := ref.Get(ctx, &revision); err != nil {
+		return nil, err
+	}
+	if revision.PageID == "" {
+		return nil, errors.New(wikiRevisionNotFound)
+	}
+	return &revision, nil
+}
+
+func (wr *WikiRepository) GetRevisions(pageId string) ([]*entity.WikiRevision, error) {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(wikiRevisionsCollection + "/" + pageId)
+
+	var revisionsMap map[string]*entity.WikiRevision
+	if err := ref.Get(ctx, &revisionsMap); err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*entity.WikiRevision, 0, len(revisionsMap))
+	for _, revision := range revisionsMap {
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+func (wr *WikiRepository) DeleteRevisions(pageId string) error {
+	ctx := context.Background()
+	ref := config.FirebaseDB.NewRef(wikiRevisionsCollection + "/" + pageId)
+	return ref.Delete(ctx)
+}
+
+// revisionKey prefixes the revision number, since the database returns children with sequential numeric keys as an array
+func revisionKey(number int) string {
+	return "r" + strconv.Itoa(number)
+}
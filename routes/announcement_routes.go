@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAnnouncementRoutes(r *gin.Engine) {
+	announcementController := controller.NewAnnouncementController()
+
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.POST("/teams/:id/announcements", announcementController.CreateAnnouncement) // Post an announcement (admins)
+		protected.GET("/teams/:id/announcements", announcementController.GetAnnouncements)    // List announcements
+		protected.GET("/teams/:id/announcements/:announcementId", announcementController.GetAnnouncement)
+		protected.PUT("/teams/:id/announcements/:announcementId", announcementController.UpdateAnnouncement)       // Edit (admins)
+		protected.DELETE("/teams/:id/announcements/:announcementId", announcementController.DeleteAnnouncement)    // Delete (admins)
+		protected.POST("/teams/:id/announcements/:announcementId/pin", announcementController.PinAnnouncement)     // Pin (admins)
+		protected.DELETE("/teams/:id/announcements/:announcementId/pin", announcementController.UnpinAnnouncement) // Unpin (admins)
+		protected.POST("/teams/:id/announcements/:announcementId/ack", announcementController.AcknowledgeAnnouncement)
+
+		protected.POST("/teams/:id/messages/:messageId/pin", announcementController.PinMessage)     // Pin a chat message (admins)
+		protected.DELETE("/teams/:id/messages/:messageId/pin", announcementController.UnpinMessage) // Unpin a chat message (admins)
+		protected.GET("/teams/:id/pins", announcementController.GetPins)                            // Pinned announcements and messages
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupCalendarRoutes(r *gin.Engine) {
+	calendarController := controller.NewCalendarController()
+
+	// Public endpoint - calendar apps authenticate with the secret token in the URL
+	r.GET("/calendar/ical/:token", calendarController.GetFeedCalendar)
+
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.POST("/calendar/feeds", calendarController.CreateFeed)       // Create or rotate a feed URL
+		protected.GET("/calendar/feeds", calendarController.GetFeeds)          // List the caller's feeds
+		protected.DELETE("/calendar/feeds/:id", calendarController.RevokeFeed) // Revoke a feed URL
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupChannelRoutes(r *gin.Engine) {
+	channelController := controller.NewChannelController()
+
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.GET("/teams/:id/channels", channelController.GetChannels)
+		protected.POST("/teams/:id/channels", channelController.CreateChannel)
+		protected.PUT("/teams/:id/channels/:channelId", channelController.UpdateChannel)
+		protected.GET("/teams/:id/channels/:channelId/messages", channelController.GetChannelMessages)
+		protected.POST("/teams/:id/channels/:channelId/messages", channelController.NewChannelMessage)
+	}
+}
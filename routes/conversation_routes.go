@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupConversationRoutes(r *gin.Engine) {
+	conversationController := controller.NewConversationController()
+
+	// Protected endpoints
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.GET("/conversations", conversationController.GetConversations) // Get the caller's inbox
+	}
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupForumRoutes(r *gin.Engine) {
+	forumController := controller.NewForumController()
+
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.POST("/teams/:id/questions", forumController.AskQuestion) // Ask a question
+		protected.GET("/teams/:id/questions", forumController.GetQuestions) // List questions (?sort=&tag=&q=)
+		protected.GET("/teams/:id/questions/:questionId", forumController.GetQuestion)
+		protected.PUT("/teams/:id/questions/:questionId", forumController.UpdateQuestion)    // Edit (asker)
+		protected.DELETE("/teams/:id/questions/:questionId", forumController.DeleteQuestion) // Delete (asker or admins)
+		protected.PUT("/teams/:id/questions/:questionId/vote", forumController.VoteQuestion)
+
+		protected.POST("/teams/:id/questions/:questionId/answers", forumController.AddAnswer)
+		protected.PUT("/teams/:id/questions/:questionId/answers/:answerId", forumController.UpdateAnswer)    // Edit (author)
+		protected.DELETE("/teams/:id/questions/:questionId/answers/:answerId", forumController.DeleteAnswer) // Delete (author or admins)
+		protected.PUT("/teams/:id/questions/:questionId/answers/:answerId/vote", forumController.VoteAnswer)
+		protected.POST("/teams/:id/questions/:questionId/answers/:answerId/accept", forumController.AcceptAnswer)     // Accept (asker or admins)
+		protected.DELETE("/teams/:id/questions/:questionId/answers/:answerId/accept", forumController.UnacceptAnswer) // Unaccept (asker or admins)
+	}
+}
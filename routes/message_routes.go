@@ -16,7 +16,14 @@ func SetupMessageRoutes(r *gin.Engine) {
 		protected.GET("/messages", messageController.GetMessages)
 		protected.GET("/messages/:id", messageController.GetMessage)
 		protected.GET("/messages/connect", messageController.Connect)
+		protected.GET("/messages/threads", messageController.GetThreads) // List the threads of a conversation
 
-		// TODO: edit messages
+		protected.PATCH("/messages/:id", messageController.EditMessage)             // Edit a message (sender only)
+		protected.DELETE("/messages/:id", messageController.DeleteMessage)          // Delete a message (sender or team admins)
+		protected.GET("/messages/:id/history", messageController.GetMessageHistory) // Get the edit history of a message
+		protected.GET("/messages/:id/thread", messageController.GetThread)          // Get a message's thread with its replies
+
+		protected.PUT("/messages/:id/reactions/:emoji", messageController.AddReaction)       // React to a message
+		protected.DELETE("/messages/:id/reactions/:emoji", messageController.RemoveReaction) // Remove a reaction
 	}
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupModerationRoutes(r *gin.Engine) {
+	moderationController := controller.NewModerationController()
+
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.POST("/teams/:id/moderation/kick/:userId", moderationController.KickMember)     // Kick a member (admins)
+		protected.POST("/teams/:id/moderation/ban/:userId", moderationController.BanMember)       // Ban a user (admins)
+		protected.DELETE("/teams/:id/moderation/ban/:userId", moderationController.UnbanMember)   // Lift a ban (admins)
+		protected.POST("/teams/:id/moderation/mute/:userId", moderationController.MuteMember)     // Mute a member (admins)
+		protected.DELETE("/teams/:id/moderation/mute/:userId", moderationController.UnmuteMember) // Lift a mute (admins)
+		protected.GET("/teams/:id/moderation", moderationController.GetRestrictions)              // Bans and mutes in effect (admins)
+		protected.GET("/teams/:id/moderation/log", moderationController.GetModerationLog)         // Moderation log (admins)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupNoteRoutes(r *gin.Engine) {
+	noteController := controller.NewNoteController()
+
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.POST("/teams/:id/notes", noteController.CreateNote) // Create a note
+		protected.GET("/teams/:id/notes", noteController.GetNotes)    // List notes
+		protected.GET("/teams/:id/notes/:noteId", noteController.GetNote)
+		protected.PUT("/teams/:id/notes/:noteId", noteController.RenameNote)
+		protected.DELETE("/teams/:id/notes/:noteId", noteController.DeleteNote)   // Delete (creator or admins)
+		protected.GET("/teams/:id/notes/:noteId/connect", noteController.Connect) // Edit over a WebSocket (?token=&version=)
+	}
+}
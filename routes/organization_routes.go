@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupOrganizationRoutes(r *gin.Engine) {
+	organizationController := controller.NewOrganizationController()
+
+	r.GET("/organizations", organizationController.GetOrganizations)
+
+	protected := r.Group("/organizations")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.GET("/:id", organizationController.GetOrganization)
+		protected.PUT("/:id", organizationController.UpdateOrganization)                        // Organization admins only
+		protected.PUT("/:id/admins/:userId", organizationController.AddOrganizationAdmin)       // Organization admins only
+		protected.DELETE("/:id/admins/:userId", organizationController.RemoveOrganizationAdmin) // Organization admins only
+	}
+
+	admin := r.Group("/organizations")
+	admin.Use(controller.JWTAuthMiddleware(), controller.RequireAdmin())
+	{
+		admin.POST("", organizationController.CreateOrganization)
+		admin.PUT("/:id/users/:userId", organizationController.AddOrganizationUser)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPresenceRoutes(r *gin.Engine) {
+	presenceController := controller.NewPresenceController()
+
+	// Protected endpoints
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.GET("/presence", presenceController.GetPresence) // Get the presence of the caller's friends and teammates
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupReadRoutes(r *gin.Engine) {
+	readController := controller.NewReadController()
+
+	// Protected endpoints
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.POST("/messages/:id/read", readController.MarkRead)      // Mark a conversation as read up to a message
+		protected.GET("/messages/unread", readController.GetUnreadCounts)  // Get the caller's unread counts
+		protected.GET("/messages/receipts", readController.GetReadReceipt) // Get the other user's read marker in a direct conversation
+	}
+}
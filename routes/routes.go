@@ -30,7 +30,7 @@ func SetupRoutes() *gin.Engine {
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"https://studyflow-6qwx.onrender.com", "http://localhost:3000", "*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
@@ -44,12 +44,29 @@ func SetupRoutes() *gin.Engine {
 	})
 
 	SetupUserRoutes(r)
+	SetupOrganizationRoutes(r)
 	SetupTeamRoutes(r)
+	SetupChannelRoutes(r)
+	SetupTeamEventRoutes(r)
+	SetupStudySessionRoutes(r)
+	SetupCalendarRoutes(r)
+	SetupTaskRoutes(r)
+	SetupAnnouncementRoutes(r)
+	SetupModerationRoutes(r)
+	SetupTeamTemplateRoutes(r)
+	SetupForumRoutes(r)
+	SetupWikiRoutes(r)
+	SetupNoteRoutes(r)
 	FileRoutes(r)
 	SetupMessageRoutes(r)
+	SetupReadRoutes(r)
+	SetupConversationRoutes(r)
+	SetupPresenceRoutes(r)
 	SetupFriendRequestRoutes(r)
 	VoiceRoutes(r)
 	SetupQuizRoutes(r)
+	SetupSearchRoutes(r)
+	SetupTopicRoutes(r)
 
 	return r
 }
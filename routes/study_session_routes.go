@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupStudySessionRoutes(r *gin.Engine) {
+	studySessionController := controller.NewStudySessionController()
+
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.POST("/teams/:id/sessions", studySessionController.CreateSession)  // Schedule a session
+		protected.GET("/teams/:id/sessions", studySessionController.GetTeamSessions) // List a team's sessions
+
+		protected.GET("/sessions", studySessionController.GetAgenda)                   // The caller's upcoming occurrences
+		protected.GET("/sessions/:sessionId", studySessionController.GetSession)       // Get a session
+		protected.PUT("/sessions/:sessionId", studySessionController.UpdateSession)    // Reschedule a session
+		protected.DELETE("/sessions/:sessionId", studySessionController.DeleteSession) // Cancel a session
+		protected.PUT("/sessions/:sessionId/rsvp", studySessionController.RSVP)        // Answer going/maybe/declined
+	}
+}
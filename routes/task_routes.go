@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTaskRoutes(r *gin.Engine) {
+	taskController := controller.NewTaskController()
+
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.GET("/teams/:id/board", taskController.GetBoard)                          // Get the board with its tasks
+		protected.POST("/teams/:id/board/columns", taskController.CreateColumn)             // Add a column
+		protected.PUT("/teams/:id/board/columns/:columnId", taskController.UpdateColumn)    // Rename or reorder a column
+		protected.DELETE("/teams/:id/board/columns/:columnId", taskController.DeleteColumn) // Delete an empty column
+
+		protected.POST("/teams/:id/tasks", taskController.CreateTask)                  // Create a task
+		protected.GET("/teams/:id/tasks/:taskId", taskController.GetTask)              // Get a task with its comments
+		protected.PUT("/teams/:id/tasks/:taskId", taskController.UpdateTask)           // Replace the content of a task
+		protected.DELETE("/teams/:id/tasks/:taskId", taskController.DeleteTask)        // Delete a task
+		protected.PUT("/teams/:id/tasks/:taskId/move", taskController.MoveTask)        // Move a task
+		protected.POST("/teams/:id/tasks/:taskId/comments", taskController.AddComment) // Comment on a task
+	}
+}
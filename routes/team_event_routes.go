@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTeamEventRoutes(r *gin.Engine) {
+	teamEventController := controller.NewTeamEventController()
+
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.GET("/teams/:id/activity", teamEventController.GetTeamActivity)
+	}
+}
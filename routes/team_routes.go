@@ -19,5 +19,11 @@ func SetupTeamRoutes(r *gin.Engine) {
 		protected.GET("/teams", teamController.GetAllTeams)       // Get all teams
 		protected.PUT("/teams/:id", teamController.UpdateTeam)    // Update a team
 		protected.DELETE("/teams/:id", teamController.DeleteTeam) // Delete a team
+
+		protected.POST("/teams/:id/archive", teamController.ArchiveTeam)   // Archive a team (owner only)
+		protected.DELETE("/teams/:id/archive", teamController.RestoreTeam) // Restore an archived team (owner only)
+
+		protected.PUT("/teams/:id/admins/:userId", teamController.AddTeamAdmin)       // Make a member an admin (owner only)
+		protected.DELETE("/teams/:id/admins/:userId", teamController.RemoveTeamAdmin) // Remove an admin (owner only)
 	}
 }
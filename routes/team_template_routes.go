@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTeamTemplateRoutes(r *gin.Engine) {
+	templateController := controller.NewTeamTemplateController()
+
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.POST("/teams/:id/templates", templateController.CreateTemplate) // Save a team as a template (admins)
+		protected.POST("/teams/:id/clone", templateController.CloneTeam)          // Clone a team (admins)
+
+		protected.GET("/templates", templateController.GetTemplates)                              // The caller's templates
+		protected.GET("/templates/:templateId", templateController.GetTemplate)                   // Get a template
+		protected.DELETE("/templates/:templateId", templateController.DeleteTemplate)             // Delete a template
+		protected.POST("/templates/:templateId/teams", templateController.CreateTeamFromTemplate) // Create a team from a template
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTopicRoutes(r *gin.Engine) {
+	topicController := controller.NewTopicController()
+
+	// Counting the usage reads every user and team
+	r.GET("/topics", controller.RequireAdminForQuery("usage"), topicController.GetTopics)
+	r.GET("/topics/:slug", controller.RequireAdminForQuery("usage"), topicController.GetTopic)
+
+	admin := r.Group("/topics")
+	admin.Use(controller.JWTAuthMiddleware(), controller.RequireAdmin())
+	{
+		admin.POST("", topicController.CreateTopic)
+		admin.PUT("/:slug", topicController.UpdateTopic)
+		admin.DELETE("/:slug", topicController.DeleteTopic)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupWikiRoutes(r *gin.Engine) {
+	wikiController := controller.NewWikiController()
+
+	protected := r.Group("/")
+	protected.Use(controller.JWTAuthMiddleware())
+	{
+		protected.GET("/teams/:id/wiki", wikiController.GetPages)              // List pages in tree order (?prefix=)
+		protected.POST("/teams/:id/wiki", wikiController.CreatePage)           // Create a page
+		protected.GET("/teams/:id/wiki/pages/*path", wikiController.GetPage)   // Get a page by path
+		protected.PUT("/teams/:id/wiki/:pageId", wikiController.UpdatePage)    // Edit, saving a new revision
+		protected.DELETE("/teams/:id/wiki/:pageId", wikiController.DeletePage) // Delete (creator or admins)
+
+		protected.GET("/teams/:id/wiki/:pageId/revisions", wikiController.GetRevisions)
+		protected.GET("/teams/:id/wiki/:pageId/revisions/:revision", wikiController.GetRevision)
+		protected.GET("/teams/:id/wiki/:pageId/diff", wikiController.GetDiff) // Diff two revisions (?from=&to=)
+		protected.POST("/teams/:id/wiki/:pageId/revisions/:revision/revert", wikiController.RevertPage)
+	}
+}
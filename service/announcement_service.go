@@ -0,0 +1,388 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+)
+
+const (
+	announcementNotFound   = "announcement not found"
+	messageNotFound        = "message not found"
+	attachmentNotInTeam    = "the attachment must be one of the team's files"
+	onlyTeamMessagesPinned = "only messages of the team can be pinned"
+)
+
+type AnnouncementServiceInterface interface {
+	CreateAnnouncement(userID, teamID string, request *dto.AnnouncementRequest) (*dto.AnnouncementResponse, error)
+	GetAnnouncements(userID, teamID string, page, limit int) (*dto.AnnouncementListResponse, error)
+	GetAnnouncement(userID, teamID, announcementID string) (*dto.AnnouncementResponse, error)
+	UpdateAnnouncement(userID, teamID, announcementID string, request *dto.AnnouncementRequest) (*dto.AnnouncementResponse, error)
+	DeleteAnnouncement(userID, teamID, announcementID string) error
+	SetAnnouncementPinned(userID, teamID, announcementID string, pinned bool) (*dto.AnnouncementResponse, error)
+	Acknowledge(userID, teamID, announcementID string) (*dto.AnnouncementResponse, error)
+	SetMessagePinned(userID, teamID, messageID string, pinned bool) (*dto.MessageDTO, error)
+	GetPins(userID, teamID string) (*dto.TeamPinsResponse, error)
+}
+
+type AnnouncementService struct {
+	announcementRepo persistence.AnnouncementRepositoryInterface
+	teamRepo         TeamRepositoryInterface
+	userRepo         UserRepositoryInterface
+	fileRepo         persistence.FileRepositoryInterface
+	messageRepo      persistence.MessageRepositoryInterface
+	eventRecorder    EventRecorder
+	hub              *hub.Hub[hub.Message]
+}
+
+func NewAnnouncementService() *AnnouncementService {
+	return &AnnouncementService{
+		announcementRepo: persistence.NewAnnouncementRepository(),
+		teamRepo:         persistence.NewTeamRepository(),
+		userRepo:         persistence.NewUserRepository(),
+		fileRepo:         persistence.NewFileRepository(),
+		messageRepo:      persistence.NewMessageRepository(),
+		eventRecorder:    NewTeamEventService(),
+		hub:              GetMessageHub(),
+	}
+}
+
+func NewAnnouncementServiceWithRepo(announcementRepo persistence.AnnouncementRepositoryInterface, teamRepo TeamRepositoryInterface, userRepo UserRepositoryInterface, fileRepo persistence.FileRepositoryInterface, messageRepo persistence.MessageRepositoryInterface) *AnnouncementService {
+	return &AnnouncementService{
+		announcementRepo: announcementRepo,
+		teamRepo:         teamRepo,
+		userRepo:         userRepo,
+		fileRepo:         fileRepo,
+		messageRepo:      messageRepo,
+		eventRecorder:    noopEventRecorder{},
+		hub:              hub.NewHub[hub.Message](),
+	}
+}
+
+func (as *AnnouncementService) SetEventRecorder(recorder EventRecorder) {
+	as.eventRecorder = recorder
+}
+
+// CreateAnnouncement posts an announcement and pushes it to every member
+func (as *AnnouncementService) CreateAnnouncement(userID, teamID string, request *dto.AnnouncementRequest) (*dto.AnnouncementResponse, error) {
+	if err := validator.ValidateAnnouncementRequest(request); err != nil {
+		return nil, err
+	}
+	team, err := as.getTeamForAdminChange(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if err := as.checkAttachment(teamID, request.AttachmentFileID); err != nil {
+		return nil, err
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	announcement := entity.NewAnnouncement(id, teamID, userID, strings.TrimSpace(request.Title), request.Body, request.AttachmentFileID)
+	if request.Pinned {
+		announcement.PinnedAt = announcement.CreatedAt.Unix()
+		announcement.PinnedBy = userID
+	}
+
+	if err := as.announcementRepo.Create(announcement); err != nil {
+		return nil, err
+	}
+	as.eventRecorder.Record(entity.NewTeamEvent(teamID, entity.TeamEventAnnouncement, userID, announcement.ID, announcement.Title))
+	as.hub.SendMany(team.UsersIds, *hub.NewMessage(hub.AnnouncementPosted, dto.NewAnnouncementResponse(announcement, team, "")))
+
+	return dto.NewAnnouncementResponse(announcement, team, userID), nil
+}
+
+// GetAnnouncements returns the team's announcements, pinned ones first, then newest first
+func (as *AnnouncementService) GetAnnouncements(userID, teamID string, page, limit int) (*dto.AnnouncementListResponse, error) {
+	team, err := getTeamForMember(as.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	announcements, err := as.announcementRepo.GetByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(announcements, func(i, j int) bool {
+		if announcements[i].PinnedAt != announcements[j].PinnedAt {
+			return announcements[i].PinnedAt > announcements[j].PinnedAt
+		}
+		return announcements[i].CreatedAt.After(announcements[j].CreatedAt)
+	})
+
+	totalCount := len(announcements)
+	totalPages := (totalCount + limit - 1) / limit
+
+	start := (page - 1) * limit
+	end := start + limit
+	if start > totalCount {
+		start = totalCount
+	}
+	if end > totalCount {
+		end = totalCount
+	}
+
+	result := make([]*dto.AnnouncementResponse, 0, end-start)
+	for _, announcement := range announcements[start:end] {
+		result = append(result, dto.NewAnnouncementResponse(announcement, team, userID))
+	}
+	return &dto.AnnouncementListResponse{
+		Announcements: result,
+		Page:          page,
+		Limit:         limit,
+		TotalCount:    totalCount,
+		TotalPages:    totalPages,
+	}, nil
+}
+
+func (as *AnnouncementService) GetAnnouncement(userID, teamID, announcementID string) (*dto.AnnouncementResponse, error) {
+	team, err := getTeamForMember(as.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	announcement, err := as.getAnnouncement(teamID, announcementID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewAnnouncementResponse(announcement, team, userID), nil
+}
+
+// UpdateAnnouncement replaces the title, body and attachment, the pin and the acknowledgements are kept
+func (as *AnnouncementService) UpdateAnnouncement(userID, teamID, announcementID string, request *dto.AnnouncementRequest) (*dto.AnnouncementResponse, error) {
+	if err := validator.ValidateAnnouncementRequest(request); err != nil {
+		return nil, err
+	}
+	team, err := as.getTeamForAdminChange(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	announcement, err := as.getAnnouncement(teamID, announcementID)
+	if err != nil {
+		return nil, err
+	}
+	if err := as.checkAttachment(teamID, request.AttachmentFileID); err != nil {
+		return nil, err
+	}
+
+	announcement.Title = strings.TrimSpace(request.Title)
+	announcement.Body = request.Body
+	announcement.AttachmentFileID = request.AttachmentFileID
+	return as.saveAnnouncement(team, announcement, userID)
+}
+
+func (as *AnnouncementService) DeleteAnnouncement(userID, teamID, announcementID string) error {
+	team, err := as.getTeamForAdminChange(userID, teamID)
+	if err != nil {
+		return err
+	}
+	if _, err := as.getAnnouncement(teamID, announcementID); err != nil {
+		return err
+	}
+
+	if err := as.announcementRepo.Delete(announcementID); err != nil {
+		return err
+	}
+	as.hub.SendMany(team.UsersIds, *hub.NewMessage(hub.AnnouncementDeleted, dto.AnnouncementDeleted{AnnouncementID: announcementID, TeamID: teamID}))
+	return nil
+}
+
+func (as *AnnouncementService) SetAnnouncementPinned(userID, teamID, announcementID string, pinned bool) (*dto.AnnouncementResponse, error) {
+	team, err := as.getTeamForAdminChange(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	announcement, err := as.getAnnouncement(teamID, announcementID)
+	if err != nil {
+		return nil, err
+	}
+	if (announcement.PinnedAt != 0) == pinned {
+		return dto.NewAnnouncementResponse(announcement, team, userID), nil
+	}
+
+	announcement.PinnedAt = 0
+	announcement.PinnedBy = ""
+	if pinned {
+		announcement.PinnedAt = time.Now().Unix()
+		announcement.PinnedBy = userID
+	}
+	return as.saveAnnouncement(team, announcement, userID)
+}
+
+// Acknowledge records that the member has read the announcement, acknowledging again keeps the first time
+func (as *AnnouncementService) Acknowledge(userID, teamID, announcementID string) (*dto.AnnouncementResponse, error) {
+	team, err := getTeamForMember(as.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, err
+	}
+	announcement, err := as.getAnnouncement(teamID, announcementID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := announcement.Acknowledgements[userID]; ok {
+		return dto.NewAnnouncementResponse(announcement, team, userID), nil
+	}
+
+	if announcement.Acknowledgements == nil {
+		announcement.Acknowledgements = make(map[string]int64)
+	}
+	announcement.Acknowledgements[userID] = time.Now().Unix()
+	if err := as.announcementRepo.Update(announcement); err != nil {
+		return nil, err
+	}
+	return dto.NewAnnouncementResponse(announcement, team, userID), nil
+}
+
+// SetMessagePinned pins or unpins a message of the team's chat
+func (as *AnnouncementService) SetMessagePinned(userID, teamID, messageID string, pinned bool) (*dto.MessageDTO, error) {
+	team, err := as.getTeamForAdminChange(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := as.messageRepo.GetByID(messageID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, messageNotFound)
+		}
+		return nil, err
+	}
+	if message.TeamID != teamID {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, onlyTeamMessagesPinned)
+	}
+
+	updates := map[string]interface{}{"pinnedAt": nil, "pinnedBy": nil}
+	message.PinnedAt = 0
+	message.PinnedBy = ""
+	if pinned {
+		message.PinnedAt = time.Now().Unix()
+		message.PinnedBy = userID
+		updates = map[string]interface{}{"pinnedAt": message.PinnedAt, "pinnedBy": userID}
+	}
+	if err := as.messageRepo.Update(message.ID, updates); err != nil {
+		return nil, err
+	}
+
+	sender, err := as.userRepo.GetByID(message.SenderID)
+	if err != nil {
+		return nil, fmt.Errorf("sender not found")
+	}
+	dtoMessage := dto.NewMessageDTOFromEntity(message, "", "", *dto.NewSenderDTO(sender))
+	as.hub.SendMany(team.UsersIds, *hub.NewMessage(hub.MessagePinUpdated, dtoMessage))
+	return dtoMessage, nil
+}
+
+// GetPins returns the pinned announcements and chat messages of the team, most recently pinned first
+func (as *AnnouncementService) GetPins(userID, teamID string) (*dto.TeamPinsResponse, error) {
+	team, err := getTeamForMember(as.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	announcements, err := as.announcementRepo.GetByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(announcements, func(i, j int) bool {
+		return announcements[i].PinnedAt > announcements[j].PinnedAt
+	})
+	resp := &dto.TeamPinsResponse{Announcements: make([]*dto.AnnouncementResponse, 0), Messages: make([]*dto.MessageDTO, 0)}
+	for _, announcement := range announcements {
+		if announcement.PinnedAt != 0 {
+			resp.Announcements = append(resp.Announcements, dto.NewAnnouncementResponse(announcement, team, userID))
+		}
+	}
+
+	messages, err := as.messageRepo.GetByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].PinnedAt > messages[j].PinnedAt
+	})
+	for _, message := range messages {
+		if message.PinnedAt == 0 {
+			continue
+		}
+		sender, err := as.userRepo.GetByID(message.SenderID)
+		if err != nil {
+			return nil, fmt.Errorf("sender not found")
+		}
+		resp.Messages = append(resp.Messages, dto.NewMessageDTOFromEntity(message, "", userID, *dto.NewSenderDTO(sender)))
+	}
+	return resp, nil
+}
+
+func (as *AnnouncementService) getTeamForAdminChange(userID, teamID string) (*entity.Team, error) {
+	team, err := getTeamForAdmin(as.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// getAnnouncement returns the announcement when it belongs to the team
+func (as *AnnouncementService) getAnnouncement(teamID, announcementID string) (*entity.Announcement, error) {
+	announcement, err := as.announcementRepo.GetByID(announcementID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, announcementNotFound)
+		}
+		return nil, err
+	}
+	if announcement.TeamID != teamID {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, announcementNotFound)
+	}
+	return announcement, nil
+}
+
+func (as *AnnouncementService) checkAttachment(teamID, fileID string) error {
+	if fileID == "" {
+		return nil
+	}
+	file, err := as.fileRepo.GetByID(fileID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return fmt.Errorf("%w: %s", validator.ErrValidation, attachmentNotInTeam)
+		}
+		return err
+	}
+	if file.ContextType != entity.FileContextTeam || file.ContextID != teamID {
+		return fmt.Errorf("%w: %s", validator.ErrValidation, attachmentNotInTeam)
+	}
+	return nil
+}
+
+func (as *AnnouncementService) saveAnnouncement(team *entity.Team, announcement *entity.Announcement, userID string) (*dto.AnnouncementResponse, error) {
+	announcement.UpdatedAt = time.Now().UTC()
+	if err := as.announcementRepo.Update(announcement); err != nil {
+		return nil, err
+	}
+	as.hub.SendMany(team.UsersIds, *hub.NewMessage(hub.AnnouncementUpdated, dto.NewAnnouncementResponse(announcement, team, "")))
+	return dto.NewAnnouncementResponse(announcement, team, userID), nil
+}
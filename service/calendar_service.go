@@ -0,0 +1,185 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/mappers"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+)
+
+const (
+	calendarFeedNotFound    = "calendar feed not found"
+	userCalendarName        = "StudyWithMe study sessions"
+	calendarFeedTokenLength = 32
+)
+
+type CalendarServiceInterface interface {
+	CreateFeed(userID string, request *dto.CalendarFeedRequest) (*dto.CalendarFeedResponse, error)
+	GetFeeds(userID string) ([]dto.CalendarFeedResponse, error)
+	RevokeFeed(userID, feedID string) error
+	GetFeedCalendar(token string) (string, error)
+}
+
+type CalendarService struct {
+	feedRepo    persistence.CalendarFeedRepositoryInterface
+	sessionRepo persistence.StudySessionRepositoryInterface
+	teamRepo    TeamRepositoryInterface
+	userRepo    UserRepositoryInterface
+}
+
+func NewCalendarService() *CalendarService {
+	return &CalendarService{
+		feedRepo:    persistence.NewCalendarFeedRepository(),
+		sessionRepo: persistence.NewStudySessionRepository(),
+		teamRepo:    persistence.NewTeamRepository(),
+		userRepo:    persistence.NewUserRepository(),
+	}
+}
+
+func NewCalendarServiceWithRepo(feedRepo persistence.CalendarFeedRepositoryInterface, sessionRepo persistence.StudySessionRepositoryInterface, teamRepo TeamRepositoryInterface, userRepo UserRepositoryInterface) *CalendarService {
+	return &CalendarService{
+		feedRepo:    feedRepo,
+		sessionRepo: sessionRepo,
+		teamRepo:    teamRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// CreateFeed creates a secret feed URL for the caller's teams or for one team. An existing feed for the same
+// teams is revoked, so creating a feed again also rotates a leaked URL.
+func (cs *CalendarService) CreateFeed(userID string, request *dto.CalendarFeedRequest) (*dto.CalendarFeedResponse, error) {
+	if request.TeamID != "" {
+		if _, err := getTeamForMember(cs.teamRepo, request.TeamID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	feeds, err := cs.feedRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, feed := range feeds {
+		if feed.TeamID == request.TeamID {
+			if err := cs.feedRepo.Delete(feed.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	token, err := generateFeedToken()
+	if err != nil {
+		return nil, err
+	}
+	feed := entity.NewCalendarFeed(hashFeedToken(token), userID, request.TeamID)
+	if err := cs.feedRepo.Create(feed); err != nil {
+		return nil, err
+	}
+
+	resp := dto.NewCalendarFeedResponse(feed)
+	resp.Token = token
+	return &resp, nil
+}
+
+func (cs *CalendarService) GetFeeds(userID string) ([]dto.CalendarFeedResponse, error) {
+	feeds, err := cs.feedRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.CalendarFeedResponse, 0, len(feeds))
+	for _, feed := range feeds {
+		result = append(result, dto.NewCalendarFeedResponse(feed))
+	}
+	return result, nil
+}
+
+func (cs *CalendarService) RevokeFeed(userID, feedID string) error {
+	feed, err := cs.getFeed(feedID)
+	if err != nil {
+		return err
+	}
+	if feed.UserID != userID {
+		return fmt.Errorf("%w: %s", ErrResourceNotFound, calendarFeedNotFound)
+	}
+	return cs.feedRepo.Delete(feed.ID)
+}
+
+// GetFeedCalendar renders the feed's sessions as iCalendar. A team feed stops working when its owner leaves the team,
+// the feed of all the user's teams leaves out the sessions the user declined.
+func (cs *CalendarService) GetFeedCalendar(token string) (string, error) {
+	feed, err := cs.getFeed(hashFeedToken(token))
+	if err != nil {
+		return "", err
+	}
+
+	if feed.TeamID != "" {
+		team, err := getTeamForMember(cs.teamRepo, feed.TeamID, feed.UserID)
+		if err != nil {
+			if errors.Is(err, ErrForbidden) {
+				return "", fmt.Errorf("%w: %s", ErrResourceNotFound, calendarFeedNotFound)
+			}
+			return "", err
+		}
+		sessions, err := cs.sessionRepo.GetByTeamID(team.Id)
+		if err != nil {
+			return "", err
+		}
+		return mappers.MapSessionsToICal(team.Name+" study sessions", sessions), nil
+	}
+
+	user, err := cs.userRepo.GetByID(feed.UserID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return "", fmt.Errorf("%w: %s", ErrResourceNotFound, calendarFeedNotFound)
+		}
+		return "", err
+	}
+
+	var sessions []*entity.StudySession
+	if user.TeamsIds != nil {
+		for _, teamID := range *user.TeamsIds {
+			teamSessions, err := cs.sessionRepo.GetByTeamID(teamID)
+			if err != nil {
+				return "", err
+			}
+			for _, session := range teamSessions {
+				if session.GetRSVP(user.ID) != entity.RSVPDeclined {
+					sessions = append(sessions, session)
+				}
+			}
+		}
+	}
+	return mappers.MapSessionsToICal(userCalendarName, sessions), nil
+}
+
+func (cs *CalendarService) getFeed(id string) (*entity.CalendarFeed, error) {
+	feed, err := cs.feedRepo.GetByID(id)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, calendarFeedNotFound)
+		}
+		return nil, err
+	}
+	return feed, nil
+}
+
+func generateFeedToken() (string, error) {
+	bytes := make([]byte, calendarFeedTokenLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+)
+
+const (
+	channelNotFound           = "channel not found"
+	channelNameTaken          = "a channel with this name already exists in the team"
+	channelArchived           = "channel is archived"
+	defaultChannelNotArchived = "the default channel can not be archived"
+)
+
+type ChannelServiceInterface interface {
+	GetChannels(userID, teamID string, includeArchived bool) ([]*entity.Channel, error)
+	CreateChannel(userID, teamID string, request *dto.ChannelRequest) (*entity.Channel, error)
+	UpdateChannel(userID, teamID, channelID string, request *dto.UpdateChannelRequest) (*entity.Channel, error)
+	GetChannelMessages(userID, teamID, channelID string) ([]*dto.MessageDTO, error)
+	CreateChannelMessage(userID, teamID, channelID string, request *dto.ChannelMessageRequest) (*dto.MessageDTO, error)
+}
+
+type ChannelService struct {
+	channelRepo    persistence.ChannelRepositoryInterface
+	teamRepo       TeamRepositoryInterface
+	userRepo       UserRepositoryInterface
+	messageRepo    persistence.MessageRepositoryInterface
+	migrationRepo  persistence.MigrationRepositoryInterface
+	messageService MessageServiceInterface
+}
+
+func NewChannelService() *ChannelService {
+	return &ChannelService{
+		channelRepo:    persistence.NewChannelRepository(),
+		teamRepo:       persistence.NewTeamRepository(),
+		userRepo:       persistence.NewUserRepository(),
+		messageRepo:    persistence.NewMessageRepository(),
+		migrationRepo:  persistence.NewMigrationRepository(),
+		messageService: NewMessageService(),
+	}
+}
+
+func NewChannelServiceWithRepo(channelRepo persistence.ChannelRepositoryInterface, teamRepo TeamRepositoryInterface, userRepo UserRepositoryInterface, messageRepo persistence.MessageRepositoryInterface) *ChannelService {
+	return &ChannelService{
+		channelRepo:    channelRepo,
+		teamRepo:       teamRepo,
+		userRepo:       userRepo,
+		messageRepo:    messageRepo,
+		migrationRepo:  noopMigrationRepository{},
+		messageService: NewMessageServiceWithRepo(userRepo, teamRepo, messageRepo, channelRepo),
+	}
+}
+
+func (cs *ChannelService) SetMigrationRepository(migrationRepo persistence.MigrationRepositoryInterface) {
+	cs.migrationRepo = migrationRepo
+}
+
+// GetChannels returns the team's channels, the default channel first and the others by name
+func (cs *ChannelService) GetChannels(userID, teamID string, includeArchived bool) ([]*entity.Channel, error) {
+	if _, err := getTeamForMember(cs.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := getOrCreateDefaultChannel(cs.channelRepo, teamID); err != nil {
+		return nil, err
+	}
+
+	channels, err := cs.channelRepo.GetByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entity.Channel, 0, len(channels))
+	for _, channel := range channels {
+		if channel.Archived && !includeArchived {
+			continue
+		}
+		result = append(result, channel)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].IsDefault() != result[j].IsDefault() {
+			return result[i].IsDefault()
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func (cs *ChannelService) CreateChannel(userID, teamID string, request *dto.ChannelRequest) (*entity.Channel, error) {
+	name := normalizeChannelName(request.Name)
+	if err := validator.ValidateChannelName(name); err != nil {
+		return nil, err
+	}
+	team, err := getTeamForMember(cs.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, err
+	}
+	if _, err := getOrCreateDefaultChannel(cs.channelRepo, teamID); err != nil {
+		return nil, err
+	}
+	if err := cs.checkNameAvailable(teamID, "", name); err != nil {
+		return nil, err
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+
+	channel := entity.NewChannel(id, teamID, name, userID, time.Now().Unix())
+	if err := cs.channelRepo.Create(channel); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// UpdateChannel renames, archives or restores a channel. Only team admins can, since every member depends on the
+// channels. Archived channels keep their history but accept no new messages.
+func (cs *ChannelService) UpdateChannel(userID, teamID, channelID string, request *dto.UpdateChannelRequest) (*entity.Channel, error) {
+	team, err := getTeamForAdmin(cs.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, err
+	}
+
+	channel, err := getTeamChannel(cs.channelRepo, teamID, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != nil {
+		name := normalizeChannelName(*request.Name)
+		if err := validator.ValidateChannelName(name); err != nil {
+			return nil, err
+		}
+		if err := cs.checkNameAvailable(teamID, channel.ID, name); err != nil {
+			return nil, err
+		}
+		channel.Name = name
+	}
+	if request.Archived != nil {
+		if *request.Archived && channel.IsDefault() {
+			return nil, fmt.Errorf("%w: %s", ErrConflict, defaultChannelNotArchived)
+		}
+		channel.Archived = *request.Archived
+	}
+
+	channel.UpdatedAt = time.Now().Unix()
+	if err := cs.channelRepo.Update(channel); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+func (cs *ChannelService) GetChannelMessages(userID, teamID, channelID string) ([]*dto.MessageDTO, error) {
+	if _, err := getTeamForMember(cs.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	channel, err := getTeamChannel(cs.channelRepo, teamID, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := cs.messageRepo.GetByChannelID(channel.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].SentAt.Before(messages[j].SentAt)
+	})
+
+	senders := make(map[string]*dto.SenderDTO)
+	dtoMessages := make([]*dto.MessageDTO, 0, len(messages))
+	for _, message := range messages {
+		if message.IsReply() {
+			continue
+		}
+		sender, ok := senders[message.SenderID]
+		if !ok {
+			user, err := cs.userRepo.GetByID(message.SenderID)
+			if err != nil {
+				return nil, fmt.Errorf("sender not found")
+			}
+			sender = dto.NewSenderDTO(user)
+			senders[message.SenderID] = sender
+		}
+		dtoMessages = append(dtoMessages, dto.NewMessageDTOFromEntity(message, "", userID, *sender))
+	}
+	return dtoMessages, nil
+}
+
+func (cs *ChannelService) CreateChannelMessage(userID, teamID, channelID string, request *dto.ChannelMessageRequest) (*dto.MessageDTO, error) {
+	if err := validator.ValidateChannelMessageRequest(request); err != nil {
+		return nil, err
+	}
+	if _, err := getTeamForMember(cs.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	return cs.messageService.CreateTeamMessage(&dto.TeamMessageRequest{
+		SenderID:    userID,
+		TeamId:      teamID,
+		ChannelID:   channelID,
+		TextContent: request.TextContent,
+		ParentID:    request.ParentID,
+	})
+}
+
+// MigrateTeamMessages moves the team messages sent before channels existed into their team's default channel, once
+func (cs *ChannelService) MigrateTeamMessages() error {
+	return runMigration(cs.migrationRepo, teamMessagesMigration, cs.migrateTeamMessages)
+}
+
+func (cs *ChannelService) migrateTeamMessages() error {
+	messages, err := cs.messageRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	migrated := 0
+	for _, message := range messages {
+		if message.TeamID == "" || message.ChannelID != "" {
+			continue
+		}
+		channel, err := getOrCreateDefaultChannel(cs.channelRepo, message.TeamID)
+		if err != nil {
+			return err
+		}
+		if err := cs.messageRepo.Update(message.ID, map[string]interface{}{"channelId": channel.ID}); err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		log.Printf("channels: moved %d team messages to their default channel", migrated)
+	}
+	return nil
+}
+
+func (cs *ChannelService) checkNameAvailable(teamID, channelID, name string) error {
+	channels, err := cs.channelRepo.GetByTeamID(teamID)
+	if err != nil {
+		return err
+	}
+	for _, channel := range channels {
+		if channel.ID != channelID && channel.Name == name {
+			return fmt.Errorf("%w: %s", ErrConflict, channelNameTaken)
+		}
+	}
+	return nil
+}
+
+// normalizeChannelName accepts names typed as "#Exam-Prep" and stores them as "exam-prep"
+func normalizeChannelName(name string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+}
+
+// getTeamChannel returns the team's channel with the given ID, or its default channel when no ID is given
+func getTeamChannel(channelRepo persistence.ChannelRepositoryInterface, teamID, channelID string) (*entity.Channel, error) {
+	if channelID == "" || channelID == entity.GetDefaultChannelID(teamID) {
+		return getOrCreateDefaultChannel(channelRepo, teamID)
+	}
+
+	channel, err := channelRepo.GetByID(channelID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, channelNotFound)
+		}
+		return nil, err
+	}
+	if channel.TeamID != teamID {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, channelNotFound)
+	}
+	return channel, nil
+}
+
+// getOrCreateDefaultChannel creates the default channel the first time a team needs it
+func getOrCreateDefaultChannel(channelRepo persistence.ChannelRepositoryInterface, teamID string) (*entity.Channel, error) {
+	id := entity.GetDefaultChannelID(teamID)
+	channel, err := channelRepo.GetByID(id)
+	if err == nil {
+		return channel, nil
+	}
+	if !strings.Contains(err.Error(), NotFoundError) {
+		return nil, err
+	}
+
+	channel = entity.NewChannel(id, teamID, entity.DefaultChannelName, "", time.Now().Unix())
+	if err := channelRepo.Create(channel); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+)
+
+const (
+	defaultConversationsLimit = 20
+	maxConversationsLimit     = 100
+
+	invalidConversationsCursor = "invalid cursor"
+	invalidConversationsLimit  = "limit must be between 1 and 100"
+)
+
+// ConversationIndexer keeps the participants' conversation indexes up to date as messages are sent, edited and deleted
+type ConversationIndexer interface {
+	IndexMessage(message *entity.Message)
+	UpdateMessage(message *entity.Message)
+}
+
+type ConversationServiceInterface interface {
+	GetConversations(userID, cursor string, limit int) (*dto.ConversationsResponse, error)
+}
+
+type ConversationService struct {
+	userRepo         UserRepositoryInterface
+	teamRepo         TeamRepositoryInterface
+	channelRepo      persistence.ChannelRepositoryInterface
+	messageRepo      persistence.MessageRepositoryInterface
+	readMarkerRepo   persistence.ReadMarkerRepositoryInterface
+	conversationRepo persistence.ConversationRepositoryInterface
+}
+
+func NewConversationService() *ConversationService {
+	return &ConversationService{
+		userRepo:         persistence.NewUserRepository(),
+		teamRepo:         persistence.NewTeamRepository(),
+		channelRepo:      persistence.NewChannelRepository(),
+		messageRepo:      persistence.NewMessageRepository(),
+		readMarkerRepo:   persistence.NewReadMarkerRepository(),
+		conversationRepo: persistence.NewConversationRepository(),
+	}
+}
+
+func NewConversationServiceWithRepo(userRepo UserRepositoryInterface, teamRepo TeamRepositoryInterface, channelRepo persistence.ChannelRepositoryInterface, messageRepo persistence.MessageRepositoryInterface, readMarkerRepo persistence.ReadMarkerRepositoryInterface, conversationRepo persistence.ConversationRepositoryInterface) *ConversationService {
+	return &ConversationService{
+		userRepo:         userRepo,
+		teamRepo:         teamRepo,
+		channelRepo:      channelRepo,
+		messageRepo:      messageRepo,
+		readMarkerRepo:   readMarkerRepo,
+		conversationRepo: conversationRepo,
+	}
+}
+
+// GetConversations returns a page of the user's inbox, the most recently active conversations first. The cursor is the
+// nextCursor of the previous page.
+func (cs *ConversationService) GetConversations(userID, cursor string, limit int) (*dto.ConversationsResponse, error) {
+	if limit == 0 {
+		limit = defaultConversationsLimit
+	}
+	if limit < 0 || limit > maxConversationsLimit {
+		return nil, fmt.Errorf("%w: %s", validator.ErrValidation, invalidConversationsLimit)
+	}
+	before, afterID, err := parseConversationsCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	user, err := cs.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+
+	// Channels of teams the user has left are skipped without counting toward the limit, so pages are read until it is
+	// reached; the cursor is the last conversation read
+	response := &dto.ConversationsResponse{Conversations: []dto.ConversationDTO{}}
+	for {
+		entries, err := cs.conversationRepo.GetPage(userID, before, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		read := false
+		for _, entry := range entries {
+			// Conversations last active at the cursor's time were on the previous page up to the cursor's conversation
+			if before > 0 && entry.LastActivityAt == before && entry.ConversationID >= afterID {
+				continue
+			}
+			if len(response.Conversations) == limit {
+				response.NextCursor = strconv.FormatInt(before, 10) + "_" + afterID
+				return response, nil
+			}
+			read = true
+			before, afterID = entry.LastActivityAt, entry.ConversationID
+			if cs.isVisible(user, entry) {
+				response.Conversations = append(response.Conversations, cs.toConversationDTO(entry))
+			}
+		}
+		if len(entries) <= limit || !read {
+			return response, nil
+		}
+	}
+}
+
+// IndexMessage makes a new message the last one of its conversation for every participant, counting it as unread for
+// everyone but its sender
+func (cs *ConversationService) IndexMessage(message *entity.Message) {
+	if message.IsReply() {
+		return
+	}
+	for _, userID := range cs.participants(message) {
+		_ = cs.conversationRepo.Update(userID, message.ConversationID(), func(entry *entity.ConversationEntry) *entity.ConversationEntry {
+			if entry == nil {
+				entry = entity.NewConversationEntry(userID, message)
+			}
+			entry.SetLastMessage(message)
+			if userID == message.SenderID {
+				entry.UnreadCount = 0
+			} else {
+				entry.UnreadCount++
+			}
+			return entry
+		})
+	}
+}
+
+// UpdateMessage refreshes the previews of an edited or deleted message and stops counting deleted messages as unread
+func (cs *ConversationService) UpdateMessage(message *entity.Message) {
+	if message.IsReply() {
+		return
+	}
+	for _, userID := range cs.participants(message) {
+		wasUnread := message.IsDeleted() && userID != message.SenderID && !cs.hasRead(userID, message)
+		_ = cs.conversationRepo.Update(userID, message.ConversationID(), func(entry *entity.ConversationEntry) *entity.ConversationEntry {
+			if entry == nil {
+				return nil
+			}
+
+			changed := false
+			if entry.LastMessageID == message.ID {
+				entry.LastMessagePreview = entity.MessagePreview(message)
+				changed = true
+			}
+			if wasUnread && entry.UnreadCount > 0 {
+				entry.UnreadCount--
+				changed = true
+			}
+			if !changed {
+				return nil
+			}
+			return entry
+		})
+	}
+}
+
+// hasRead tells whether the user's read marker is past the message; when the marker can't be read it counts as read,
+// so unread counts are never lowered by mistake
+func (cs *ConversationService) hasRead(userID string, message *entity.Message) bool {
+	marker, err := cs.readMarkerRepo.Get(userID, message.ConversationID())
+	if err != nil {
+		return true
+	}
+	return marker != nil && marker.HasRead(message)
+}
+
+// BuildIndexIfEmpty builds the conversation indexes from the stored messages when they have never been built. Messages
+// sent before the user had a read marker in the conversation count as read.
+func (cs *ConversationService) BuildIndexIfEmpty() error {
+	empty, err := cs.conversationRepo.IsEmpty()
+	if err != nil || !empty {
+		return err
+	}
+
+	messages, err := cs.messageRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	conversations := make(map[string][]*entity.Message)
+	for _, message := range messages {
+		if message.IsReply() || (message.ConversationKey == "" && message.ChannelID == "") {
+			continue
+		}
+		conversations[message.ConversationID()] = append(conversations[message.ConversationID()], message)
+	}
+
+	for conversationID, conversationMessages := range conversations {
+		last := conversationMessages[0]
+		for _, message := range conversationMessages {
+			if message.SentAt.After(last.SentAt) {
+				last = message
+			}
+		}
+
+		for _, userID := range cs.participants(last) {
+			entry := entity.NewConversationEntry(userID, last)
+			entry.SetLastMessage(last)
+			if marker, err := cs.readMarkerRepo.Get(userID, conversationID); err == nil && marker != nil {
+				entry.UnreadCount = countUnread(conversationMessages, marker)
+			}
+			if err := cs.conversationRepo.Save(entry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// participants returns the users whose index has the message's conversation: both users of a direct message or the
+// members of the team
+func (cs *ConversationService) participants(message *entity.Message) []string {
+	if message.TeamID == "" {
+		return messageParticipants(message, nil)
+	}
+	team, err := cs.teamRepo.GetTeamById(message.TeamID)
+	if err != nil {
+		return nil
+	}
+	return team.UsersIds
+}
+
+// isVisible leaves out the channels of teams the user has left since
+func (cs *ConversationService) isVisible(user *entity.User, entry *entity.ConversationEntry) bool {
+	if entry.Type != entity.ConversationTypeChannel {
+		return true
+	}
+	return user.TeamsIds != nil && slices.Contains(*user.TeamsIds, entry.TeamID)
+}
+
+func (cs *ConversationService) toConversationDTO(entry *entity.ConversationEntry) dto.ConversationDTO {
+	conversation := dto.ConversationDTO{
+		ConversationID: entry.ConversationID,
+		Type:           entry.Type,
+		LastActivityAt: entry.LastActivityAt,
+		UnreadCount:    entry.UnreadCount,
+	}
+	if entry.LastMessageID != "" {
+		conversation.LastMessage = &dto.MessagePreviewDTO{
+			ID:          entry.LastMessageID,
+			SenderID:    entry.LastSenderID,
+			TextContent: entry.LastMessagePreview,
+		}
+	}
+
+	if entry.Type == entity.ConversationTypeDirect {
+		if user, err := cs.userRepo.GetByID(entry.OtherUserID); err == nil {
+			conversation.User = dto.NewSenderDTO(user)
+		}
+		return conversation
+	}
+	if team, err := cs.teamRepo.GetTeamById(entry.TeamID); err == nil {
+		conversation.Team = &dto.ConversationTeamDTO{ID: team.Id, Name: team.Name}
+	}
+	if channel, err := cs.channelRepo.GetByID(entry.ChannelID); err == nil {
+		conversation.Channel = &dto.ConversationChannelDTO{ID: channel.ID, Name: channel.Name}
+	}
+	return conversation
+}
+
+// parseConversationsCursor splits a cursor into the last activity time and the conversation it points at
+func parseConversationsCursor(cursor string) (int64, string, error) {
+	if cursor == "" {
+		return 0, "", nil
+	}
+	activity, conversationID, ok := strings.Cut(cursor, "_")
+	before, err := strconv.ParseInt(activity, 10, 64)
+	if !ok || err != nil || before <= 0 || conversationID == "" {
+		return 0, "", fmt.Errorf("%w: %s", validator.ErrValidation, invalidConversationsCursor)
+	}
+	return before, conversationID, nil
+}
+
+// getConversationMessages returns the messages of the conversation the message belongs to
+func getConversationMessages(messageRepo persistence.MessageRepositoryInterface, message *entity.Message) ([]*entity.Message, error) {
+	if message.ConversationKey == "" {
+		return messageRepo.GetByChannelID(message.ChannelID)
+	}
+	users := strings.Split(message.ConversationKey, "_")
+	if len(users) != 2 {
+		return nil, errors.New(entity.BadConversationKey)
+	}
+	return messageRepo.GetByConversation(users[0], users[1])
+}
+
+type noopConversationIndexer struct{}
+
+func (noopConversationIndexer) IndexMessage(*entity.Message) {}
+
+func (noopConversationIndexer) UpdateMessage(*entity.Message) {}
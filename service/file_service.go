@@ -15,6 +15,7 @@ const (
 	userNotInTeamErr = "user is not a member of this team"
 	teamNotFoundErr  = "team not found"
 	fileNotInTeamErr = "file does not belong to this team"
+	fileNotFoundErr  = "file not found"
 )
 
 type FileServiceInterface interface {
@@ -25,27 +26,34 @@ type FileServiceInterface interface {
 }
 
 type FileService struct {
-	fileRepo persistence.FileRepositoryInterface
-	userRepo UserRepositoryInterface
-	teamRepo TeamRepositoryInterface
+	fileRepo      persistence.FileRepositoryInterface
+	userRepo      UserRepositoryInterface
+	teamRepo      TeamRepositoryInterface
+	eventRecorder EventRecorder
 }
 
 func NewFileService() *FileService {
 	return &FileService{
-		fileRepo: persistence.NewFileRepository(),
-		userRepo: persistence.NewUserRepository(),
-		teamRepo: persistence.NewTeamRepository(),
+		fileRepo:      persistence.NewFileRepository(),
+		userRepo:      persistence.NewUserRepository(),
+		teamRepo:      persistence.NewTeamRepository(),
+		eventRecorder: NewTeamEventService(),
 	}
 }
 
 func NewFileServiceWithRepo(fileRepo persistence.FileRepositoryInterface, userRepo UserRepositoryInterface, teamRepo TeamRepositoryInterface) *FileService {
 	return &FileService{
-		fileRepo: fileRepo,
-		userRepo: userRepo,
-		teamRepo: teamRepo,
+		fileRepo:      fileRepo,
+		userRepo:      userRepo,
+		teamRepo:      teamRepo,
+		eventRecorder: noopEventRecorder{},
 	}
 }
 
+func (fs *FileService) SetEventRecorder(recorder EventRecorder) {
+	fs.eventRecorder = recorder
+}
+
 // isUserInTeam checks if user is a member of the specified team
 func (fs *FileService) isUserInTeam(userID, teamID string) error {
 	user, err := fs.userRepo.GetByID(userID)
@@ -65,6 +73,35 @@ func (fs *FileService) isUserInTeam(userID, teamID string) error {
 	return fmt.Errorf(userNotInTeamErr)
 }
 
+// checkSameOrganization hides the files of other organizations: team files belong to the team's organization and chat
+// files to their owner's
+func (fs *FileService) checkSameOrganization(userID string, file *entity.File) error {
+	user, err := fs.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+
+	var organizationID string
+	if file.ContextType == entity.FileContextTeam {
+		team, err := fs.teamRepo.GetTeamById(file.ContextID)
+		if err != nil {
+			return fmt.Errorf(teamNotFoundErr)
+		}
+		organizationID = team.OrganizationID
+	} else {
+		owner, err := fs.userRepo.GetByID(file.OwnerID)
+		if err != nil {
+			return fmt.Errorf(fileNotFoundErr)
+		}
+		organizationID = owner.OrganizationID
+	}
+
+	if !sameOrganization(user, organizationID) {
+		return fmt.Errorf(fileNotFoundErr)
+	}
+	return nil
+}
+
 func (fs *FileService) CreateFile(request *dto.FileUploadRequest, userID string) (*dto.FileUploadResponse, error) {
 	if err := validator.ValidateFileUpload(request); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
@@ -75,6 +112,13 @@ func (fs *FileService) CreateFile(request *dto.FileUploadRequest, userID string)
 		if err := fs.isUserInTeam(userID, request.ContextID); err != nil {
 			return nil, err
 		}
+		team, err := fs.teamRepo.GetTeamById(request.ContextID)
+		if err != nil {
+			return nil, fmt.Errorf(teamNotFoundErr)
+		}
+		if err := checkTeamNotArchived(team); err != nil {
+			return nil, err
+		}
 	}
 
 	id, err := generateID()
@@ -88,6 +132,9 @@ func (fs *FileService) CreateFile(request *dto.FileUploadRequest, userID string)
 	if err := fs.fileRepo.Create(file); err != nil {
 		return nil, err
 	}
+	if file.ContextType == entity.FileContextTeam {
+		fs.eventRecorder.Record(entity.NewTeamEvent(file.ContextID, entity.TeamEventFileUploaded, userID, file.ID, file.Name))
+	}
 
 	resp := &dto.FileUploadResponse{
 		ID:          file.ID,
@@ -113,6 +160,9 @@ func (fs *FileService) GetFileByID(id, userID string) (*entity.File, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := fs.checkSameOrganization(userID, file); err != nil {
+		return nil, err
+	}
 
 	// Verify user has access to this file's context
 	if file.ContextType == entity.FileContextTeam {
@@ -192,6 +242,9 @@ func (fs *FileService) DeleteFile(id, userID string) error {
 	if err != nil {
 		return err
 	}
+	if err := fs.checkSameOrganization(userID, file); err != nil {
+		return err
+	}
 
 	// Verify user has access (is in the team)
 	if file.ContextType == entity.FileContextTeam {
@@ -200,5 +253,11 @@ func (fs *FileService) DeleteFile(id, userID string) error {
 		}
 	}
 
-	return fs.fileRepo.Delete(id)
+	if err := fs.fileRepo.Delete(id); err != nil {
+		return err
+	}
+	if file.ContextType == entity.FileContextTeam {
+		fs.eventRecorder.Record(entity.NewTeamEvent(file.ContextID, entity.TeamEventFileDeleted, userID, file.ID, file.Name))
+	}
+	return nil
 }
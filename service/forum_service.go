@@ -0,0 +1,462 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+)
+
+const (
+	forumQuestionNotFound = "question not found"
+	forumAnswerNotFound   = "answer not found"
+	onlyAuthorCanEdit     = "only the author can edit this"
+	onlyAuthorOrAdmin     = "only the author or a team admin can do this"
+	onlyAskerOrAdmin      = "only the asker or a team admin can accept an answer"
+	cannotVoteOwnPost     = "you can not vote on your own post"
+)
+
+type ForumServiceInterface interface {
+	AskQuestion(userID, teamID string, request *dto.ForumQuestionRequest) (*dto.ForumQuestionResponse, error)
+	GetQuestions(userID, teamID, sortBy, tag, query string, page, limit int) (*dto.ForumQuestionListResponse, error)
+	GetQuestion(userID, teamID, questionID string) (*dto.ForumQuestionDetailsResponse, error)
+	UpdateQuestion(userID, teamID, questionID string, request *dto.ForumQuestionRequest) (*dto.ForumQuestionResponse, error)
+	DeleteQuestion(userID, teamID, questionID string) error
+	VoteQuestion(userID, teamID, questionID string, request *dto.VoteRequest) (*dto.ForumQuestionResponse, error)
+	AddAnswer(userID, teamID, questionID string, request *dto.ForumAnswerRequest) (*dto.ForumAnswerResponse, error)
+	UpdateAnswer(userID, teamID, questionID, answerID string, request *dto.ForumAnswerRequest) (*dto.ForumAnswerResponse, error)
+	DeleteAnswer(userID, teamID, questionID, answerID string) error
+	VoteAnswer(userID, teamID, questionID, answerID string, request *dto.VoteRequest) (*dto.ForumAnswerResponse, error)
+	SetAcceptedAnswer(userID, teamID, questionID, answerID string, accepted bool) (*dto.ForumQuestionResponse, error)
+}
+
+type ForumService struct {
+	forumRepo persistence.ForumRepositoryInterface
+	teamRepo  TeamRepositoryInterface
+}
+
+func NewForumService() *ForumService {
+	return &ForumService{
+		forumRepo: persistence.NewForumRepository(),
+		teamRepo:  persistence.NewTeamRepository(),
+	}
+}
+
+func NewForumServiceWithRepo(forumRepo persistence.ForumRepositoryInterface, teamRepo TeamRepositoryInterface) *ForumService {
+	return &ForumService{
+		forumRepo: forumRepo,
+		teamRepo:  teamRepo,
+	}
+}
+
+func (fs *ForumService) AskQuestion(userID, teamID string, request *dto.ForumQuestionRequest) (*dto.ForumQuestionResponse, error) {
+	if err := validator.ValidateForumQuestionRequest(request); err != nil {
+		return nil, err
+	}
+	if _, err := fs.getTeamForChange(userID, teamID); err != nil {
+		return nil, err
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	question := entity.NewForumQuestion(id, teamID, userID, strings.TrimSpace(request.Title), request.Body, normalizeTags(request.Tags))
+	if err := fs.forumRepo.CreateQuestion(question); err != nil {
+		return nil, err
+	}
+	return dto.NewForumQuestionResponse(question, userID), nil
+}
+
+// GetQuestions lists the team's questions by latest activity, by votes, or only the unanswered ones, optionally
+// with a tag and matching every word of the query in the title, body or tags
+func (fs *ForumService) GetQuestions(userID, teamID, sortBy, tag, query string, page, limit int) (*dto.ForumQuestionListResponse, error) {
+	if sortBy == "" {
+		sortBy = "recent"
+	}
+	if err := validator.ValidateForumSort(sortBy); err != nil {
+		return nil, err
+	}
+	if _, err := getTeamForMember(fs.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	questions, err := fs.forumRepo.GetQuestionsByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag = normalizeTag(tag)
+	tokens := utils.Tokenize(query)
+	filtered := make([]*entity.ForumQuestion, 0, len(questions))
+	for _, question := range questions {
+		if sortBy == "unanswered" && question.AnswerCount > 0 {
+			continue
+		}
+		if tag != "" && !slices.Contains(question.Tags, tag) {
+			continue
+		}
+		if !matchesAllTokens(tokens, question.Title+" "+question.Body+" "+strings.Join(question.Tags, " ")) {
+			continue
+		}
+		filtered = append(filtered, question)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		if sortBy == "votes" && filtered[i].Score != filtered[j].Score {
+			return filtered[i].Score > filtered[j].Score
+		}
+		return filtered[i].LastActivityAt.After(filtered[j].LastActivityAt)
+	})
+
+	totalCount := len(filtered)
+	totalPages := (totalCount + limit - 1) / limit
+
+	start := (page - 1) * limit
+	end := start + limit
+	if start > totalCount {
+		start = totalCount
+	}
+	if end > totalCount {
+		end = totalCount
+	}
+
+	result := make([]*dto.ForumQuestionResponse, 0, end-start)
+	for _, question := range filtered[start:end] {
+		result = append(result, dto.NewForumQuestionResponse(question, userID))
+	}
+	return &dto.ForumQuestionListResponse{
+		Questions:  result,
+		Page:       page,
+		Limit:      limit,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetQuestion returns the question with its answers, the accepted answer first, then by score and age
+func (fs *ForumService) GetQuestion(userID, teamID, questionID string) (*dto.ForumQuestionDetailsResponse, error) {
+	if _, err := getTeamForMember(fs.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+	question, err := fs.getQuestion(teamID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	answers, err := fs.forumRepo.GetAnswers(questionID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(answers, func(i, j int) bool {
+		iAccepted, jAccepted := answers[i].ID == question.AcceptedAnswerID, answers[j].ID == question.AcceptedAnswerID
+		if iAccepted != jAccepted {
+			return iAccepted
+		}
+		if answers[i].Score != answers[j].Score {
+			return answers[i].Score > answers[j].Score
+		}
+		return answers[i].CreatedAt.Before(answers[j].CreatedAt)
+	})
+
+	resp := &dto.ForumQuestionDetailsResponse{
+		ForumQuestionResponse: *dto.NewForumQuestionResponse(question, userID),
+		Answers:               make([]*dto.ForumAnswerResponse, 0, len(answers)),
+	}
+	for _, answer := range answers {
+		resp.Answers = append(resp.Answers, dto.NewForumAnswerResponse(answer, question.AcceptedAnswerID, userID))
+	}
+	return resp, nil
+}
+
+// UpdateQuestion replaces the title, body and tags; only the asker can edit a question
+func (fs *ForumService) UpdateQuestion(userID, teamID, questionID string, request *dto.ForumQuestionRequest) (*dto.ForumQuestionResponse, error) {
+	if err := validator.ValidateForumQuestionRequest(request); err != nil {
+		return nil, err
+	}
+	if _, err := fs.getTeamForChange(userID, teamID); err != nil {
+		return nil, err
+	}
+	question, err := fs.getQuestion(teamID, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if question.AuthorID != userID {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, onlyAuthorCanEdit)
+	}
+
+	question.Title = strings.TrimSpace(request.Title)
+	question.Body = request.Body
+	question.Tags = normalizeTags(request.Tags)
+	question.UpdatedAt = time.Now().UTC()
+	if err := fs.forumRepo.UpdateQuestion(question); err != nil {
+		return nil, err
+	}
+	return dto.NewForumQuestionResponse(question, userID), nil
+}
+
+// DeleteQuestion deletes the question and its answers
+func (fs *ForumService) DeleteQuestion(userID, teamID, questionID string) error {
+	team, err := fs.getTeamForChange(userID, teamID)
+	if err != nil {
+		return err
+	}
+	question, err := fs.getQuestion(teamID, questionID)
+	if err != nil {
+		return err
+	}
+	if question.AuthorID != userID && !team.IsAdmin(userID) {
+		return fmt.Errorf("%w: %s", ErrForbidden, onlyAuthorOrAdmin)
+	}
+
+	if err := fs.forumRepo.DeleteAnswers(questionID); err != nil {
+		return err
+	}
+	return fs.forumRepo.DeleteQuestion(questionID)
+}
+
+func (fs *ForumService) VoteQuestion(userID, teamID, questionID string, request *dto.VoteRequest) (*dto.ForumQuestionResponse, error) {
+	if err := validator.ValidateVoteRequest(request); err != nil {
+		return nil, err
+	}
+	if _, err := fs.getTeamForChange(userID, teamID); err != nil {
+		return nil, err
+	}
+	question, err := fs.getQuestion(teamID, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if question.AuthorID == userID {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, cannotVoteOwnPost)
+	}
+
+	question.Votes, question.Score = entity.ApplyVote(question.Votes, userID, request.Value)
+	if err := fs.forumRepo.UpdateQuestion(question); err != nil {
+		return nil, err
+	}
+	return dto.NewForumQuestionResponse(question, userID), nil
+}
+
+func (fs *ForumService) AddAnswer(userID, teamID, questionID string, request *dto.ForumAnswerRequest) (*dto.ForumAnswerResponse, error) {
+	if err := validator.ValidateForumAnswerRequest(request); err != nil {
+		return nil, err
+	}
+	if _, err := fs.getTeamForChange(userID, teamID); err != nil {
+		return nil, err
+	}
+	question, err := fs.getQuestion(teamID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	answer := entity.NewForumAnswer(id, questionID, teamID, userID, request.Body)
+	if err := fs.forumRepo.SaveAnswer(answer); err != nil {
+		return nil, err
+	}
+
+	question.AnswerCount++
+	question.LastActivityAt = answer.CreatedAt
+	if err := fs.forumRepo.UpdateQuestion(question); err != nil {
+		return nil, err
+	}
+	return dto.NewForumAnswerResponse(answer, question.AcceptedAnswerID, userID), nil
+}
+
+// UpdateAnswer replaces the body of an answer; only its author can edit it
+func (fs *ForumService) UpdateAnswer(userID, teamID, questionID, answerID string, request *dto.ForumAnswerRequest) (*dto.ForumAnswerResponse, error) {
+	if err := validator.ValidateForumAnswerRequest(request); err != nil {
+		return nil, err
+	}
+	if _, err := fs.getTeamForChange(userID, teamID); err != nil {
+		return nil, err
+	}
+	question, err := fs.getQuestion(teamID, questionID)
+	if err != nil {
+		return nil, err
+	}
+	answer, err := fs.getAnswer(questionID, answerID)
+	if err != nil {
+		return nil, err
+	}
+	if answer.AuthorID != userID {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, onlyAuthorCanEdit)
+	}
+
+	answer.Body = request.Body
+	answer.UpdatedAt = time.Now().UTC()
+	if err := fs.forumRepo.SaveAnswer(answer); err != nil {
+		return nil, err
+	}
+	return dto.NewForumAnswerResponse(answer, question.AcceptedAnswerID, userID), nil
+}
+
+func (fs *ForumService) DeleteAnswer(userID, teamID, questionID, answerID string) error {
+	team, err := fs.getTeamForChange(userID, teamID)
+	if err != nil {
+		return err
+	}
+	question, err := fs.getQuestion(teamID, questionID)
+	if err != nil {
+		return err
+	}
+	answer, err := fs.getAnswer(questionID, answerID)
+	if err != nil {
+		return err
+	}
+	if answer.AuthorID != userID && !team.IsAdmin(userID) {
+		return fmt.Errorf("%w: %s", ErrForbidden, onlyAuthorOrAdmin)
+	}
+
+	if err := fs.forumRepo.DeleteAnswer(questionID, answerID); err != nil {
+		return err
+	}
+	if question.AnswerCount > 0 {
+		question.AnswerCount--
+	}
+	if question.AcceptedAnswerID == answerID {
+		question.AcceptedAnswerID = ""
+	}
+	return fs.forumRepo.UpdateQuestion(question)
+}
+
+func (fs *ForumService) VoteAnswer(userID, teamID, questionID, answerID string, request *dto.VoteRequest) (*dto.ForumAnswerResponse, error) {
+	if err := validator.ValidateVoteRequest(request); err != nil {
+		return nil, err
+	}
+	if _, err := fs.getTeamForChange(userID, teamID); err != nil {
+		return nil, err
+	}
+	question, err := fs.getQuestion(teamID, questionID)
+	if err != nil {
+		return nil, err
+	}
+	answer, err := fs.getAnswer(questionID, answerID)
+	if err != nil {
+		return nil, err
+	}
+	if answer.AuthorID == userID {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, cannotVoteOwnPost)
+	}
+
+	answer.Votes, answer.Score = entity.ApplyVote(answer.Votes, userID, request.Value)
+	if err := fs.forumRepo.SaveAnswer(answer); err != nil {
+		return nil, err
+	}
+	return dto.NewForumAnswerResponse(answer, question.AcceptedAnswerID, userID), nil
+}
+
+// SetAcceptedAnswer marks an answer as the accepted one, replacing the previous one, or clears it
+func (fs *ForumService) SetAcceptedAnswer(userID, teamID, questionID, answerID string, accepted bool) (*dto.ForumQuestionResponse, error) {
+	team, err := fs.getTeamForChange(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	question, err := fs.getQuestion(teamID, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if question.AuthorID != userID && !team.IsAdmin(userID) {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, onlyAskerOrAdmin)
+	}
+	if _, err := fs.getAnswer(questionID, answerID); err != nil {
+		return nil, err
+	}
+
+	if accepted {
+		question.AcceptedAnswerID = answerID
+	} else if question.AcceptedAnswerID == answerID {
+		question.AcceptedAnswerID = ""
+	}
+	if err := fs.forumRepo.UpdateQuestion(question); err != nil {
+		return nil, err
+	}
+	return dto.NewForumQuestionResponse(question, userID), nil
+}
+
+// getTeamForChange returns the team when the user is a member and the team is not archived
+func (fs *ForumService) getTeamForChange(userID, teamID string) (*entity.Team, error) {
+	team, err := getTeamForMember(fs.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// getQuestion returns the question when it belongs to the team
+func (fs *ForumService) getQuestion(teamID, questionID string) (*entity.ForumQuestion, error) {
+	question, err := fs.forumRepo.GetQuestion(questionID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, forumQuestionNotFound)
+		}
+		return nil, err
+	}
+	if question.TeamID != teamID {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, forumQuestionNotFound)
+	}
+	return question, nil
+}
+
+func (fs *ForumService) getAnswer(questionID, answerID string) (*entity.ForumAnswer, error) {
+	answer, err := fs.forumRepo.GetAnswer(questionID, answerID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, forumAnswerNotFound)
+		}
+		return nil, err
+	}
+	return answer, nil
+}
+
+// normalizeTag stores tags typed as "Exercise 4" as "exercise-4"
+func normalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+func normalizeTags(tags []string) []string {
+	var result []string
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); tag != "" && !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// matchesAllTokens reports whether every query token is a prefix of a word of the text, like the global search
+func matchesAllTokens(tokens []string, text string) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	words := utils.Tokenize(text)
+	for _, token := range tokens {
+		if !slices.ContainsFunc(words, func(word string) bool { return strings.HasPrefix(word, token) }) {
+			return false
+		}
+	}
+	return true
+}
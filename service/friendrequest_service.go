@@ -60,7 +60,7 @@ func (fs *FriendRequestService) SendFriendRequest(fromUserID, toUserID string) e
 	}
 
 	recipient, err = fs.userService.GetUserByID(toUserID)
-	if err != nil || recipient == nil {
+	if err != nil || recipient == nil || !sameOrganization(sender, recipient.OrganizationID) {
 		return fmt.Errorf("recipient user not found")
 	}
 
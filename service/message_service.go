@@ -2,7 +2,12 @@ package service
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
@@ -10,33 +15,99 @@ import (
 )
 
 type MessageService struct {
-	userRepo    UserRepositoryInterface
-	teamRepo    TeamRepositoryInterface
-	messageRepo persistence.MessageRepositoryInterface
+	userRepo      UserRepositoryInterface
+	teamRepo      TeamRepositoryInterface
+	messageRepo   persistence.MessageRepositoryInterface
+	channelRepo   persistence.ChannelRepositoryInterface
+	searchIndexer SearchIndexer
+	moderation    ModerationChecker
+	readTracker   ReadTracker
+	conversations ConversationIndexer
+	migrationRepo persistence.MigrationRepositoryInterface
+	hub           *hub.Hub[hub.Message]
 }
 
+const (
+	messageDeleted           = "message was deleted"
+	onlySenderCanEdit        = "only the sender can edit a message"
+	onlySenderOrAdminsDelete = "only the sender or team admins can delete a message"
+	tooManyReactions         = "a message can have at most %d different reactions"
+	parentMessageNotFound    = "parent message not found in this conversation"
+	notInConversation        = "only the users of a conversation can read it"
+	cursorNotInHistory       = "cursor message not found in this conversation"
+
+	// maxMessageReactions caps the distinct emoji on one message, users can still add their reaction to those
+	maxMessageReactions = 20
+
+	defaultMessagePageSize = 50
+)
+
 func NewMessageService() *MessageService {
 	return &MessageService{
-		userRepo:    persistence.NewUserRepository(),
-		teamRepo:    persistence.NewTeamRepository(),
-		messageRepo: persistence.NewMessageRepository(),
+		userRepo:      persistence.NewUserRepository(),
+		teamRepo:      persistence.NewTeamRepository(),
+		messageRepo:   persistence.NewMessageRepository(),
+		channelRepo:   persistence.NewChannelRepository(),
+		searchIndexer: NewSearchService(),
+		moderation:    NewModerationService(),
+		readTracker:   NewReadService(),
+		conversations: NewConversationService(),
+		migrationRepo: persistence.NewMigrationRepository(),
+		hub:           GetMessageHub(),
 	}
 }
 
-func NewMessageServiceWithRepo(userRepo UserRepositoryInterface, teamRepo TeamRepositoryInterface, messageRepo persistence.MessageRepositoryInterface) *MessageService {
+func NewMessageServiceWithRepo(userRepo UserRepositoryInterface, teamRepo TeamRepositoryInterface, messageRepo persistence.MessageRepositoryInterface, channelRepo persistence.ChannelRepositoryInterface) *MessageService {
 	return &MessageService{
-		userRepo:    userRepo,
-		teamRepo:    teamRepo,
-		messageRepo: messageRepo,
+		userRepo:      userRepo,
+		teamRepo:      teamRepo,
+		messageRepo:   messageRepo,
+		channelRepo:   channelRepo,
+		searchIndexer: noopSearchIndexer{},
+		moderation:    noopModerationChecker{},
+		readTracker:   noopReadTracker{},
+		conversations: noopConversationIndexer{},
+		migrationRepo: noopMigrationRepository{},
+		hub:           hub.NewHub[hub.Message](),
 	}
 }
 
+func (ms *MessageService) SetSearchIndexer(indexer SearchIndexer) {
+	ms.searchIndexer = indexer
+}
+
+func (ms *MessageService) SetModerationChecker(moderation ModerationChecker) {
+	ms.moderation = moderation
+}
+
+func (ms *MessageService) SetReadTracker(readTracker ReadTracker) {
+	ms.readTracker = readTracker
+}
+
+func (ms *MessageService) SetConversationIndexer(conversations ConversationIndexer) {
+	ms.conversations = conversations
+}
+
+func (ms *MessageService) SetMigrationRepository(migrationRepo persistence.MigrationRepositoryInterface) {
+	ms.migrationRepo = migrationRepo
+}
+
 type MessageServiceInterface interface {
 	CreateDirectMessage(request *dto.DirectMessageRequest) (*dto.MessageDTO, error)
 	CreateTeamMessage(request *dto.TeamMessageRequest) (*dto.MessageDTO, error)
-	GetMessageByID(id string) (*dto.MessageDTO, error)
-	GetDirectMessages(user1Id, user2Id string) ([]*dto.MessageDTO, error)
-	GetTeamMessages(teamId string) ([]*dto.MessageDTO, error)
+	GetMessageByID(viewerID, id string) (*dto.MessageDTO, error)
+	GetDirectMessages(viewerID, user1Id, user2Id string) ([]*dto.MessageDTO, error)
+	GetTeamMessages(viewerID, teamId string) ([]*dto.MessageDTO, error)
+	GetDirectMessagePage(viewerID, user1Id, user2Id string, query *dto.MessagePageQuery) (*dto.MessagePageResponse, error)
+	GetTeamMessagePage(viewerID, teamId string, query *dto.MessagePageQuery) (*dto.MessagePageResponse, error)
+	EditMessage(userID, messageID string, request *dto.EditMessageRequest) (*dto.MessageDTO, error)
+	DeleteMessage(userID, messageID string) (*dto.MessageDTO, error)
+	GetMessageHistory(userID, messageID string) (*dto.MessageHistoryResponse, error)
+	GetThread(viewerID, messageID string) (*dto.ThreadResponse, error)
+	GetDirectThreads(viewerID, otherUserID string) ([]*dto.MessageDTO, error)
+	GetTeamThreads(viewerID, teamID, channelID string) ([]*dto.MessageDTO, error)
+	AddReaction(userID, messageID, emoji string) (*dto.MessageDTO, error)
+	RemoveReaction(userID, messageID, emoji string) (*dto.MessageDTO, error)
 }
 
 func (ms *MessageService) CreateDirectMessage(request *dto.DirectMessageRequest) (*dto.MessageDTO, error) {
@@ -49,7 +120,8 @@ func (ms *MessageService) CreateDirectMessage(request *dto.DirectMessageRequest)
 		return nil, fmt.Errorf("sender not found")
 	}
 
-	if _, err := ms.userRepo.GetByID(request.ReceiverID); err != nil {
+	receiver, err := ms.userRepo.GetByID(request.ReceiverID)
+	if err != nil || !sameOrganization(sender, receiver.OrganizationID) {
 		return nil, fmt.Errorf("receiver not found")
 	}
 
@@ -63,14 +135,33 @@ func (ms *MessageService) CreateDirectMessage(request *dto.DirectMessageRequest)
 		request.SenderID,
 		entity.GetConversationKey(request.SenderID, request.ReceiverID),
 		"",
+		"",
 		request.TextContent,
 	)
+	message.ParentID = request.ParentID
+	var parent *entity.Message
+	if message.IsReply() {
+		if parent, err = ms.getThreadParent(&message); err != nil {
+			return nil, err
+		}
+	}
+	message.SetHistoryKey()
 	if err := ms.messageRepo.Create(&message); err != nil {
 		return nil, err
 	}
+	ms.searchIndexer.IndexMessage(&message)
+	ms.readTracker.TrackMessage(&message)
+	// Every participant's inbox is updated, which can be a whole team, so it does not hold up sending
+	go ms.conversations.IndexMessage(&message)
+	if parent != nil {
+		if err := ms.addThreadReply(parent, &message, nil); err != nil {
+			return nil, err
+		}
+	}
 
 	senderDTO := dto.NewSenderDTO(sender)
-	dtoMessage := dto.NewMessageDTO(message.ID, request.ReceiverID, "", message.TextContent, message.SentAt, *senderDTO)
+	dtoMessage := dto.NewMessageDTO(message.ID, request.ReceiverID, "", "", message.TextContent, message.SentAt, *senderDTO)
+	dtoMessage.ParentID = message.ParentID
 	return dtoMessage, nil
 }
 
@@ -84,8 +175,24 @@ func (ms *MessageService) CreateTeamMessage(request *dto.TeamMessageRequest) (*d
 		return nil, fmt.Errorf("sender not found")
 	}
 
-	if _, err := ms.teamRepo.GetTeamById(request.TeamId); err != nil {
-		return nil, fmt.Errorf("team not found")
+	// Kicked and banned users are no longer members
+	team, err := getTeamForMember(ms.teamRepo, request.TeamId, request.SenderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, err
+	}
+	if err := ms.moderation.CheckNotMuted(team.Id, request.SenderID); err != nil {
+		return nil, err
+	}
+
+	channel, err := getTeamChannel(ms.channelRepo, request.TeamId, request.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel.Archived {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, channelArchived)
 	}
 
 	id, err := generateID()
@@ -98,35 +205,82 @@ func (ms *MessageService) CreateTeamMessage(request *dto.TeamMessageRequest) (*d
 		request.SenderID,
 		"",
 		request.TeamId,
+		channel.ID,
 		request.TextContent,
 	)
+	message.ParentID = request.ParentID
+	var parent *entity.Message
+	if message.IsReply() {
+		if parent, err = ms.getThreadParent(&message); err != nil {
+			return nil, err
+		}
+	}
+	message.SetHistoryKey()
 	if err := ms.messageRepo.Create(&message); err != nil {
 		return nil, err
 	}
+	ms.searchIndexer.IndexMessage(&message)
+	ms.readTracker.TrackMessage(&message)
+	// Every participant's inbox is updated, which can be a whole team, so it does not hold up sending
+	go ms.conversations.IndexMessage(&message)
+	if parent != nil {
+		if err := ms.addThreadReply(parent, &message, team); err != nil {
+			return nil, err
+		}
+	}
 
 	senderDTO := dto.NewSenderDTO(sender)
-	dtoMessage := dto.NewMessageDTO(message.ID, "", request.TeamId, message.TextContent, message.SentAt, *senderDTO)
+	dtoMessage := dto.NewMessageDTO(message.ID, "", request.TeamId, message.ChannelID, message.TextContent, message.SentAt, *senderDTO)
+	dtoMessage.ParentID = message.ParentID
 	return dtoMessage, nil
 }
 
-func (ms *MessageService) GetMessageByID(id string) (*dto.MessageDTO, error) {
-	message, err := ms.messageRepo.GetByID(id)
+// GetMessageByID returns a message to the users of its conversation, or the members of its team
+func (ms *MessageService) GetMessageByID(viewerID, id string) (*dto.MessageDTO, error) {
+	message, team, err := getMessageForParticipant(ms.messageRepo, ms.teamRepo, viewerID, id)
+	if err != nil {
+		return nil, err
+	}
 	receiverId, key_err := entity.GetReceiverIdFromKey(message.SenderID, message.ConversationKey)
 	if message.ConversationKey != "" && key_err != nil {
-		return nil, err
+		return nil, key_err
 	}
 
 	sender, err := ms.userRepo.GetByID(message.SenderID)
 	if err != nil {
 		return nil, fmt.Errorf("sender not found")
 	}
+	if err := ms.checkSameOrganization(viewerID, team, sender); err != nil {
+		return nil, err
+	}
 
 	senderDTO := dto.NewSenderDTO(sender)
-	dtoMessage := dto.NewMessageDTO(message.ID, receiverId, message.TeamID, message.TextContent, message.SentAt, *senderDTO)
+	dtoMessage := dto.NewMessageDTOFromEntity(message, receiverId, viewerID, *senderDTO)
 	return dtoMessage, err
 }
 
-func (ms *MessageService) GetDirectMessages(user1Id, user2Id string) ([]*dto.MessageDTO, error) {
+// checkSameOrganization hides the messages of other organizations: team messages belong to the team's organization and
+// direct messages to their sender's
+func (ms *MessageService) checkSameOrganization(viewerID string, team *entity.Team, sender *entity.User) error {
+	viewer, err := ms.userRepo.GetByID(viewerID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+
+	organizationID := sender.OrganizationID
+	if team != nil {
+		organizationID = team.OrganizationID
+	}
+	if !sameOrganization(viewer, organizationID) {
+		return fmt.Errorf("%w: %s", ErrResourceNotFound, messageNotFound)
+	}
+	return nil
+}
+
+func (ms *MessageService) GetDirectMessages(viewerID, user1Id, user2Id string) ([]*dto.MessageDTO, error) {
+	if viewerID != user1Id && viewerID != user2Id {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, notInConversation)
+	}
 	if _, err := ms.userRepo.GetByID(user1Id); err != nil {
 		return nil, fmt.Errorf("user1 not found")
 	}
@@ -137,6 +291,9 @@ func (ms *MessageService) GetDirectMessages(user1Id, user2Id string) ([]*dto.Mes
 	messages, err := ms.messageRepo.GetByConversation(user1Id, user2Id)
 	dtoMessages := []*dto.MessageDTO{}
 	for _, message := range messages {
+		if message.IsReply() {
+			continue
+		}
 		receiverId, key_err := entity.GetReceiverIdFromKey(message.SenderID, message.ConversationKey)
 		if message.ConversationKey != "" && key_err != nil {
 			return nil, err
@@ -148,20 +305,23 @@ func (ms *MessageService) GetDirectMessages(user1Id, user2Id string) ([]*dto.Mes
 		}
 
 		senderDTO := dto.NewSenderDTO(sender)
-		dtoMessage := dto.NewMessageDTO(message.ID, receiverId, message.TeamID, message.TextContent, message.SentAt, *senderDTO)
+		dtoMessage := dto.NewMessageDTOFromEntity(message, receiverId, viewerID, *senderDTO)
 		dtoMessages = append(dtoMessages, dtoMessage)
 	}
 	return dtoMessages, err
 }
 
-func (ms *MessageService) GetTeamMessages(teamId string) ([]*dto.MessageDTO, error) {
-	if _, err := ms.teamRepo.GetTeamById(teamId); err != nil {
-		return nil, fmt.Errorf("team not found")
+func (ms *MessageService) GetTeamMessages(viewerID, teamId string) ([]*dto.MessageDTO, error) {
+	if _, err := getTeamForMember(ms.teamRepo, teamId, viewerID); err != nil {
+		return nil, err
 	}
 
 	messages, err := ms.messageRepo.GetByTeamID(teamId)
 	dtoMessages := []*dto.MessageDTO{}
 	for _, message := range messages {
+		if message.IsReply() {
+			continue
+		}
 
 		sender, err := ms.userRepo.GetByID(message.SenderID)
 		if err != nil {
@@ -169,8 +329,596 @@ func (ms *MessageService) GetTeamMessages(teamId string) ([]*dto.MessageDTO, err
 		}
 
 		senderDTO := dto.NewSenderDTO(sender)
-		dtoMessage := dto.NewMessageDTO(message.ID, "", message.TeamID, message.TextContent, message.SentAt, *senderDTO)
+		dtoMessage := dto.NewMessageDTOFromEntity(message, "", viewerID, *senderDTO)
 		dtoMessages = append(dtoMessages, dtoMessage)
 	}
 	return dtoMessages, err
 }
+
+// GetDirectMessagePage returns a page of the history of a direct conversation, which only its users can read
+func (ms *MessageService) GetDirectMessagePage(viewerID, user1Id, user2Id string, query *dto.MessagePageQuery) (*dto.MessagePageResponse, error) {
+	if viewerID != user1Id && viewerID != user2Id {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, notInConversation)
+	}
+	if _, err := ms.userRepo.GetByID(user1Id); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+	if _, err := ms.userRepo.GetByID(user2Id); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+	return ms.getMessagePage(viewerID, entity.DirectConversationID(entity.GetConversationKey(user1Id, user2Id)), query)
+}
+
+// GetTeamMessagePage returns a page of the history of a team, across its channels
+func (ms *MessageService) GetTeamMessagePage(viewerID, teamId string, query *dto.MessagePageQuery) (*dto.MessagePageResponse, error) {
+	if _, err := getTeamForMember(ms.teamRepo, teamId, viewerID); err != nil {
+		return nil, err
+	}
+	return ms.getMessagePage(viewerID, entity.TeamHistoryScope(teamId), query)
+}
+
+// MigrateHistoryKeys gives the messages stored before history pages existed their history key, once
+func (ms *MessageService) MigrateHistoryKeys() error {
+	return runMigration(ms.migrationRepo, historyKeysMigration, ms.migrateHistoryKeys)
+}
+
+func (ms *MessageService) migrateHistoryKeys() error {
+	messages, err := ms.messageRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, message := range messages {
+		if message.HistoryKey != "" || message.IsReply() {
+			continue
+		}
+		message.SetHistoryKey()
+		if err := ms.messageRepo.Update(message.ID, map[string]interface{}{"historyKey": message.HistoryKey}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getMessagePage pages a history by history key: the latest messages, the ones before or after a cursor, or the ones
+// around a message, which is the only kind of page holding its cursor message
+func (ms *MessageService) getMessagePage(viewerID, scope string, query *dto.MessagePageQuery) (*dto.MessagePageResponse, error) {
+	if err := validator.ValidateMessagePageQuery(query); err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultMessagePageSize
+	}
+
+	var messages []*entity.Message
+	var hasOlder, hasNewer bool
+	switch {
+	case query.Before != "":
+		key, err := ms.getHistoryCursor(scope, query.Before, false)
+		if err != nil {
+			return nil, err
+		}
+		if messages, hasOlder, err = ms.getHistoryBefore(scope, key, limit); err != nil {
+			return nil, err
+		}
+		hasNewer = true
+	case query.After != "":
+		key, err := ms.getHistoryCursor(scope, query.After, true)
+		if err != nil {
+			return nil, err
+		}
+		if messages, hasNewer, err = ms.getHistoryAfter(scope, key, limit); err != nil {
+			return nil, err
+		}
+		hasOlder = true
+	case query.Around != "":
+		cursor, err := ms.getHistoryMessage(scope, query.Around)
+		if err != nil {
+			return nil, err
+		}
+		older, olderLeft, err := ms.getHistoryBefore(scope, cursor.HistoryKey, limit/2)
+		if err != nil {
+			return nil, err
+		}
+		newer, newerLeft, err := ms.getHistoryAfter(scope, cursor.HistoryKey, limit-limit/2-1)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(append(older, cursor), newer...)
+		hasOlder, hasNewer = olderLeft, newerLeft
+	default:
+		var err error
+		if messages, hasOlder, err = ms.getHistoryBefore(scope, scope+"|\uf8ff", limit); err != nil {
+			return nil, err
+		}
+	}
+
+	dtoMessages, err := ms.toMessageDTOs(messages, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	page := &dto.MessagePageResponse{Messages: dtoMessages}
+	if len(messages) > 0 && hasOlder {
+		page.PrevCursor = messages[0].ID
+	}
+	if len(messages) > 0 && hasNewer {
+		page.NextCursor = messages[len(messages)-1].ID
+	}
+	return page, nil
+}
+
+// getHistoryBefore returns up to limit messages before the key, oldest first, and whether there are older ones
+func (ms *MessageService) getHistoryBefore(scope, key string, limit int) ([]*entity.Message, bool, error) {
+	// One more than the limit tells whether there are older messages, and one more in case the key is the cursor's
+	messages, err := ms.messageRepo.GetHistory(scope+"|", key, limit+2, true)
+	if err != nil {
+		return nil, false, err
+	}
+	messages = withoutHistoryKey(messages, key)
+	if len(messages) > limit {
+		return messages[len(messages)-limit:], true, nil
+	}
+	return messages, false, nil
+}
+
+// getHistoryAfter returns up to limit messages after the key, oldest first, and whether there are newer ones
+func (ms *MessageService) getHistoryAfter(scope, key string, limit int) ([]*entity.Message, bool, error) {
+	messages, err := ms.messageRepo.GetHistory(key, scope+"|\uf8ff", limit+2, false)
+	if err != nil {
+		return nil, false, err
+	}
+	messages = withoutHistoryKey(messages, key)
+	if len(messages) > limit {
+		return messages[:limit], true, nil
+	}
+	return messages, false, nil
+}
+
+// withoutHistoryKey leaves out the message at the key, the cursor of the page
+func withoutHistoryKey(messages []*entity.Message, key string) []*entity.Message {
+	filtered := make([]*entity.Message, 0, len(messages))
+	for _, message := range messages {
+		if message.HistoryKey != key {
+			filtered = append(filtered, message)
+		}
+	}
+	return filtered
+}
+
+// getHistoryCursor turns a message ID or RFC 3339 timestamp into a history key. Timestamps leave out the messages sent
+// at that very time: keys before them sort before the messages, and keys after them after.
+func (ms *MessageService) getHistoryCursor(scope, cursor string, after bool) (string, error) {
+	if sentAt, err := time.Parse(time.RFC3339Nano, cursor); err == nil {
+		key := entity.NewHistoryKey(scope, sentAt, "")
+		if after {
+			key += "|\uf8ff"
+		}
+		return key, nil
+	}
+
+	message, err := ms.getHistoryMessage(scope, cursor)
+	if err != nil {
+		return "", err
+	}
+	return message.HistoryKey, nil
+}
+
+// getHistoryMessage returns the message if it is part of the history
+func (ms *MessageService) getHistoryMessage(scope, messageID string) (*entity.Message, error) {
+	message, err := ms.messageRepo.GetByID(messageID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, cursorNotInHistory)
+		}
+		return nil, err
+	}
+	if message.IsReply() || message.HistoryScope() != scope {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, cursorNotInHistory)
+	}
+	if message.HistoryKey == "" {
+		message.SetHistoryKey()
+	}
+	return message, nil
+}
+
+// EditMessage replaces the text of the user's message, keeping the previous text in the message's edit history
+func (ms *MessageService) EditMessage(userID, messageID string, request *dto.EditMessageRequest) (*dto.MessageDTO, error) {
+	if err := validator.ValidateEditMessageRequest(request); err != nil {
+		return nil, err
+	}
+	message, team, err := ms.getMessageForChange(userID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.SenderID != userID {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, onlySenderCanEdit)
+	}
+	if message.IsDeleted() {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, messageDeleted)
+	}
+	if team != nil {
+		if err := ms.moderation.CheckNotMuted(team.Id, userID); err != nil {
+			return nil, err
+		}
+	}
+	if message.TextContent == request.TextContent {
+		return ms.toMessageDTO(message, userID)
+	}
+
+	now := time.Now().Unix()
+	message.Edits = append(message.Edits, entity.MessageEdit{TextContent: message.TextContent, EditedAt: now})
+	message.TextContent = request.TextContent
+	message.EditedAt = now
+	updates := map[string]interface{}{
+		"textContent": message.TextContent,
+		"editedAt":    message.EditedAt,
+		"edits":       message.Edits,
+	}
+	if err := ms.messageRepo.Update(message.ID, updates); err != nil {
+		return nil, err
+	}
+	ms.searchIndexer.IndexMessage(message)
+	ms.conversations.UpdateMessage(message)
+
+	dtoMessage, err := ms.toMessageDTO(message, "")
+	if err != nil {
+		return nil, err
+	}
+	ms.hub.SendMany(messageParticipants(message, team), *hub.NewMessage(hub.MessageEdited, dtoMessage))
+	return dtoMessage, nil
+}
+
+// DeleteMessage turns the message into a tombstone: it stays in the history, without its text, edit history or pin
+func (ms *MessageService) DeleteMessage(userID, messageID string) (*dto.MessageDTO, error) {
+	message, team, err := ms.getMessageForChange(userID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.IsDeleted() {
+		return ms.toMessageDTO(message, userID)
+	}
+	if message.SenderID != userID {
+		if team == nil || !team.IsAdmin(userID) {
+			return nil, fmt.Errorf("%w: %s", ErrForbidden, onlySenderOrAdminsDelete)
+		}
+	}
+
+	message.TextContent = ""
+	message.Edits = nil
+	message.PinnedAt = 0
+	message.PinnedBy = ""
+	message.DeletedAt = time.Now().Unix()
+	message.DeletedBy = userID
+	updates := map[string]interface{}{
+		"textContent": "",
+		"edits":       nil,
+		"pinnedAt":    nil,
+		"pinnedBy":    nil,
+		"deletedAt":   message.DeletedAt,
+		"deletedBy":   userID,
+	}
+	if err := ms.messageRepo.Update(message.ID, updates); err != nil {
+		return nil, err
+	}
+	ms.searchIndexer.RemoveFromIndex(entity.SearchTypeMessage, message.ID)
+	ms.conversations.UpdateMessage(message)
+
+	dtoMessage, err := ms.toMessageDTO(message, "")
+	if err != nil {
+		return nil, err
+	}
+	ms.hub.SendMany(messageParticipants(message, team), *hub.NewMessage(hub.MessageDeleted, dtoMessage))
+	return dtoMessage, nil
+}
+
+// GetMessageHistory returns every version of the message's text, oldest first
+func (ms *MessageService) GetMessageHistory(userID, messageID string) (*dto.MessageHistoryResponse, error) {
+	message, _, err := getMessageForParticipant(ms.messageRepo, ms.teamRepo, userID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.IsDeleted() {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, messageDeleted)
+	}
+
+	versions := append([]entity.MessageEdit{}, message.Edits...)
+	versions = append(versions, entity.MessageEdit{TextContent: message.TextContent, EditedAt: message.EditedAt})
+	return &dto.MessageHistoryResponse{MessageID: message.ID, Versions: versions}, nil
+}
+
+// AddReaction adds the user's reaction to the message; reacting twice with the same emoji changes nothing
+func (ms *MessageService) AddReaction(userID, messageID, emoji string) (*dto.MessageDTO, error) {
+	return ms.setReaction(userID, messageID, emoji, true)
+}
+
+func (ms *MessageService) RemoveReaction(userID, messageID, emoji string) (*dto.MessageDTO, error) {
+	return ms.setReaction(userID, messageID, emoji, false)
+}
+
+func (ms *MessageService) setReaction(userID, messageID, emoji string, added bool) (*dto.MessageDTO, error) {
+	if err := validator.ValidateReaction(emoji); err != nil {
+		return nil, err
+	}
+	message, team, err := ms.getMessageForChange(userID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.IsDeleted() {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, messageDeleted)
+	}
+	if message.HasReacted(userID, emoji) == added {
+		return ms.toMessageDTO(message, userID)
+	}
+
+	reactions, err := ms.messageRepo.UpdateReactions(message.ID, func(reactions map[string][]string) error {
+		userIDs := removeString(reactions[emoji], userID)
+		if added {
+			if len(userIDs) == 0 && len(reactions) >= maxMessageReactions {
+				return fmt.Errorf("%w: "+tooManyReactions, ErrConflict, maxMessageReactions)
+			}
+			userIDs = append(userIDs, userID)
+		}
+		if len(userIDs) == 0 {
+			delete(reactions, emoji)
+		} else {
+			reactions[emoji] = userIDs
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	message.Reactions = reactions
+
+	dtoMessage, err := ms.toMessageDTO(message, userID)
+	if err != nil {
+		return nil, err
+	}
+	event := &dto.MessageReactionEvent{
+		MessageID:  message.ID,
+		ReceiverID: dtoMessage.ReceiverID,
+		TeamID:     message.TeamID,
+		ChannelID:  message.ChannelID,
+		UserID:     userID,
+		Emoji:      emoji,
+		Added:      added,
+		Reactions:  dto.NewReactionDTOs(message, ""),
+	}
+	ms.hub.SendMany(messageParticipants(message, team), *hub.NewMessage(hub.MessageReaction, event))
+	return dtoMessage, nil
+}
+
+// GetThread returns the thread of the message with its replies; for a reply, the thread it belongs to
+func (ms *MessageService) GetThread(viewerID, messageID string) (*dto.ThreadResponse, error) {
+	parent, _, err := getMessageForParticipant(ms.messageRepo, ms.teamRepo, viewerID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.IsReply() {
+		if parent, _, err = getMessageForParticipant(ms.messageRepo, ms.teamRepo, viewerID, parent.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	replies, err := ms.messageRepo.GetByParentID(parent.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(replies, func(i, j int) bool {
+		return replies[i].SentAt.Before(replies[j].SentAt)
+	})
+
+	parentDTO, err := ms.toMessageDTO(parent, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	thread := &dto.ThreadResponse{Parent: parentDTO, Replies: make([]*dto.MessageDTO, 0, len(replies))}
+	for _, reply := range replies {
+		replyDTO, err := ms.toMessageDTO(reply, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		thread.Replies = append(thread.Replies, replyDTO)
+	}
+	return thread, nil
+}
+
+// GetDirectThreads returns the messages of the viewer's conversation with the other user that have replies,
+// the most recently active first
+func (ms *MessageService) GetDirectThreads(viewerID, otherUserID string) ([]*dto.MessageDTO, error) {
+	if _, err := ms.userRepo.GetByID(otherUserID); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+
+	messages, err := ms.messageRepo.GetByConversation(viewerID, otherUserID)
+	if err != nil {
+		return nil, err
+	}
+	return ms.toThreadDTOs(messages, viewerID)
+}
+
+// GetTeamThreads returns the team messages that have replies, the most recently active first; channelID is optional
+func (ms *MessageService) GetTeamThreads(viewerID, teamID, channelID string) ([]*dto.MessageDTO, error) {
+	if _, err := getTeamForMember(ms.teamRepo, teamID, viewerID); err != nil {
+		return nil, err
+	}
+
+	var messages []*entity.Message
+	if channelID == "" {
+		teamMessages, err := ms.messageRepo.GetByTeamID(teamID)
+		if err != nil {
+			return nil, err
+		}
+		messages = teamMessages
+	} else {
+		channel, err := getTeamChannel(ms.channelRepo, teamID, channelID)
+		if err != nil {
+			return nil, err
+		}
+		if messages, err = ms.messageRepo.GetByChannelID(channel.ID); err != nil {
+			return nil, err
+		}
+	}
+	return ms.toThreadDTOs(messages, viewerID)
+}
+
+func (ms *MessageService) toThreadDTOs(messages []*entity.Message, viewerID string) ([]*dto.MessageDTO, error) {
+	threads := make([]*entity.Message, 0)
+	for _, message := range messages {
+		if message.ReplyCount > 0 && !message.IsReply() {
+			threads = append(threads, message)
+		}
+	}
+	sort.SliceStable(threads, func(i, j int) bool {
+		return threads[i].LastReplyAt > threads[j].LastReplyAt
+	})
+
+	dtoMessages := make([]*dto.MessageDTO, 0, len(threads))
+	for _, thread := range threads {
+		dtoMessage, err := ms.toMessageDTO(thread, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		dtoMessages = append(dtoMessages, dtoMessage)
+	}
+	return dtoMessages, nil
+}
+
+// getThreadParent returns the first message of the thread the reply joins and points the reply at it; replying to a
+// reply joins the same thread, so threads stay one level deep
+func (ms *MessageService) getThreadParent(reply *entity.Message) (*entity.Message, error) {
+	parent, err := ms.messageRepo.GetByID(reply.ParentID)
+	if err == nil && parent.IsReply() {
+		parent, err = ms.messageRepo.GetByID(parent.ParentID)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, parentMessageNotFound)
+		}
+		return nil, err
+	}
+	if !parent.InSameConversation(reply) {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, parentMessageNotFound)
+	}
+	if parent.IsDeleted() {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, messageDeleted)
+	}
+
+	reply.ParentID = parent.ID
+	return parent, nil
+}
+
+// addThreadReply counts the reply on its thread's first message and tells the conversation, so thread badges update
+// without refetching
+func (ms *MessageService) addThreadReply(parent, reply *entity.Message, team *entity.Team) error {
+	thread, err := ms.messageRepo.AddReply(parent.ID, reply.SentAt.Unix())
+	if err != nil {
+		return err
+	}
+	parent.ReplyCount = thread.ReplyCount
+	parent.LastReplyAt = thread.LastReplyAt
+
+	receiverID, _ := entity.GetReceiverIdFromKey(parent.SenderID, parent.ConversationKey)
+	event := &dto.ThreadUpdatedEvent{
+		MessageID:   parent.ID,
+		ReceiverID:  receiverID,
+		TeamID:      parent.TeamID,
+		ChannelID:   parent.ChannelID,
+		ReplyID:     reply.ID,
+		ReplyCount:  parent.ReplyCount,
+		LastReplyAt: parent.LastReplyAt,
+	}
+	ms.hub.SendMany(messageParticipants(parent, team), *hub.NewMessage(hub.ThreadUpdated, event))
+	return nil
+}
+
+// getMessageForParticipant returns the message if the user takes part in its conversation, with its team for team messages
+func getMessageForParticipant(messageRepo persistence.MessageRepositoryInterface, teamRepo TeamRepositoryInterface, userID, messageID string) (*entity.Message, *entity.Team, error) {
+	message, err := messageRepo.GetByID(messageID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, nil, fmt.Errorf("%w: %s", ErrResourceNotFound, messageNotFound)
+		}
+		return nil, nil, err
+	}
+
+	if message.TeamID == "" {
+		if !slices.Contains(messageParticipants(message, nil), userID) {
+			return nil, nil, fmt.Errorf("%w: %s", ErrResourceNotFound, messageNotFound)
+		}
+		return message, nil, nil
+	}
+
+	team, err := getTeamForMember(teamRepo, message.TeamID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return message, team, nil
+}
+
+// getMessageForChange is getMessageForParticipant for edits and deletions, which archived teams do not accept
+func (ms *MessageService) getMessageForChange(userID, messageID string) (*entity.Message, *entity.Team, error) {
+	message, team, err := getMessageForParticipant(ms.messageRepo, ms.teamRepo, userID, messageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if team != nil {
+		if err := checkTeamNotArchived(team); err != nil {
+			return nil, nil, err
+		}
+	}
+	return message, team, nil
+}
+
+// messageParticipants returns the users of the message's conversation: both users of a direct message or the team's members
+func messageParticipants(message *entity.Message, team *entity.Team) []string {
+	if team != nil {
+		return team.UsersIds
+	}
+	return strings.Split(message.ConversationKey, "_")
+}
+
+// toMessageDTOs maps a page of messages, looking up each sender once
+func (ms *MessageService) toMessageDTOs(messages []*entity.Message, viewerID string) ([]*dto.MessageDTO, error) {
+	senders := make(map[string]*dto.SenderDTO)
+	dtoMessages := make([]*dto.MessageDTO, 0, len(messages))
+	for _, message := range messages {
+		sender, ok := senders[message.SenderID]
+		if !ok {
+			user, err := ms.userRepo.GetByID(message.SenderID)
+			if err != nil {
+				return nil, fmt.Errorf("sender not found")
+			}
+			sender = dto.NewSenderDTO(user)
+			senders[message.SenderID] = sender
+		}
+
+		receiverId := ""
+		if message.ConversationKey != "" {
+			id, err := entity.GetReceiverIdFromKey(message.SenderID, message.ConversationKey)
+			if err != nil {
+				return nil, err
+			}
+			receiverId = id
+		}
+		dtoMessages = append(dtoMessages, dto.NewMessageDTOFromEntity(message, receiverId, viewerID, *sender))
+	}
+	return dtoMessages, nil
+}
+
+func (ms *MessageService) toMessageDTO(message *entity.Message, viewerID string) (*dto.MessageDTO, error) {
+	receiverId := ""
+	if message.ConversationKey != "" {
+		id, err := entity.GetReceiverIdFromKey(message.SenderID, message.ConversationKey)
+		if err != nil {
+			return nil, err
+		}
+		receiverId = id
+	}
+
+	sender, err := ms.userRepo.GetByID(message.SenderID)
+	if err != nil {
+		return nil, fmt.Errorf("sender not found")
+	}
+	return dto.NewMessageDTOFromEntity(message, receiverId, viewerID, *dto.NewSenderDTO(sender)), nil
+}
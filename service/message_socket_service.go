@@ -0,0 +1,207 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gorilla/websocket"
+)
+
+const invalidCommand = "commands must be JSON objects with a type"
+
+type MessageSocketServiceInterface interface {
+	Connect(userID string, since int64, conn *websocket.Conn)
+}
+
+// MessageSocketService runs the message WebSocket: it registers the users' connections and runs the commands they
+// send, the same way the REST endpoints do
+type MessageSocketService struct {
+	userRepo       UserRepositoryInterface
+	teamRepo       TeamRepositoryInterface
+	messageService MessageServiceInterface
+	readService    ReadServiceInterface
+	presence       *PresenceService
+	outbox         *OutboxService
+	hub            *hub.Hub[hub.Message]
+}
+
+func NewMessageSocketService() *MessageSocketService {
+	return newMessageSocketService(persistence.NewUserRepository(), persistence.NewTeamRepository(), NewMessageService(), NewReadService(), NewPresenceService(), NewOutboxService())
+}
+
+// NewMessageSocketServiceWithRepo connects the users to the hub of the presence service
+func NewMessageSocketServiceWithRepo(userRepo UserRepositoryInterface, teamRepo TeamRepositoryInterface, messageService MessageServiceInterface, readService ReadServiceInterface, presence *PresenceService, outboxRepo persistence.OutboxRepositoryInterface) *MessageSocketService {
+	return newMessageSocketService(userRepo, teamRepo, messageService, readService, presence, newOutboxService(outboxRepo, presence.hub, newOutboxState()))
+}
+
+func newMessageSocketService(userRepo UserRepositoryInterface, teamRepo TeamRepositoryInterface, messageService MessageServiceInterface, readService ReadServiceInterface, presence *PresenceService, outbox *OutboxService) *MessageSocketService {
+	mss := &MessageSocketService{
+		userRepo:       userRepo,
+		teamRepo:       teamRepo,
+		messageService: messageService,
+		readService:    readService,
+		presence:       presence,
+		outbox:         outbox,
+		hub:            presence.hub,
+	}
+	mss.hub.OnMessage(mss.handleCommand)
+	return mss
+}
+
+// Connect registers the user's connection, which then gets the user's events and can send commands. The events after
+// since, the last sequence number the user got, are sent first.
+func (mss *MessageSocketService) Connect(userID string, since int64, conn *websocket.Conn) {
+	mss.outbox.Connect(userID, since, conn)
+}
+
+// handleCommand runs a command sent by a connection of the message hub. Commands with a requestId get an ack when they
+// succeed; failed commands always get an error. Both go to the connection that sent the command only.
+func (mss *MessageSocketService) handleCommand(client *hub.Client[hub.Message], data []byte) {
+	var command dto.MessageCommand
+	if err := json.Unmarshal(data, &command); err != nil {
+		mss.sendError(client, "", fmt.Errorf("%w: %s", validator.ErrValidation, invalidCommand))
+		return
+	}
+
+	ack, err := mss.runCommand(client.ClientID, &command)
+	if err != nil {
+		mss.sendError(client, command.RequestID, err)
+		return
+	}
+	if command.RequestID != "" {
+		ack.RequestID = command.RequestID
+		mss.hub.SendToClient(client, *hub.NewMessage(hub.CommandAck, ack))
+	}
+}
+
+func (mss *MessageSocketService) runCommand(userID string, command *dto.MessageCommand) (*dto.CommandAck, error) {
+	if err := validator.ValidateMessageCommand(command); err != nil {
+		return nil, err
+	}
+
+	var message *dto.MessageDTO
+	var err error
+	switch command.Type {
+	case dto.MessageCommandSend:
+		message, err = mss.sendMessage(userID, command)
+	case dto.MessageCommandEdit:
+		message, err = mss.messageService.EditMessage(userID, command.MessageID, &dto.EditMessageRequest{TextContent: command.TextContent})
+	case dto.MessageCommandDelete:
+		message, err = mss.messageService.DeleteMessage(userID, command.MessageID)
+	case dto.MessageCommandTyping:
+		err = mss.sendTyping(userID, command)
+	case dto.MessageCommandPresence:
+		mss.presence.SetStatus(userID, command.Status)
+	case dto.MessageCommandMarkRead:
+		marker, err := mss.readService.MarkRead(userID, command.MessageID)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.CommandAck{ReadMarker: marker}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CommandAck{Message: message}, nil
+}
+
+// sendMessage creates the message and sends it to the conversation, like POST /messages
+func (mss *MessageSocketService) sendMessage(userID string, command *dto.MessageCommand) (*dto.MessageDTO, error) {
+	if command.TeamID != "" {
+		message, err := mss.messageService.CreateTeamMessage(&dto.TeamMessageRequest{
+			SenderID:    userID,
+			TeamId:      command.TeamID,
+			ChannelID:   command.ChannelID,
+			TextContent: command.TextContent,
+			ParentID:    command.ParentID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if team, err := mss.teamRepo.GetTeamById(command.TeamID); err == nil {
+			mss.hub.SendMany(team.UsersIds, *hub.NewMessage(hub.TeamBroadcast, message))
+		}
+		mss.presence.SetTyping(entity.ChannelConversationID(message.ChannelID), nil, dto.TypingEvent{UserID: userID}, false)
+		return message, nil
+	}
+
+	message, err := mss.messageService.CreateDirectMessage(&dto.DirectMessageRequest{
+		SenderID:    userID,
+		ReceiverID:  command.ReceiverID,
+		TextContent: command.TextContent,
+		ParentID:    command.ParentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	mss.hub.SendMany([]string{command.ReceiverID, userID}, *hub.NewMessage(hub.DirectMessage, message))
+	mss.presence.SetTyping(typingConversationID(userID, command), nil, dto.TypingEvent{UserID: userID}, false)
+	return message, nil
+}
+
+// sendTyping starts or stops the user's typing indicator for the other users of the conversation
+func (mss *MessageSocketService) sendTyping(userID string, command *dto.MessageCommand) error {
+	event := dto.TypingEvent{
+		UserID:     userID,
+		ReceiverID: command.ReceiverID,
+		TeamID:     command.TeamID,
+		ChannelID:  command.ChannelID,
+	}
+	recipients := []string{command.ReceiverID}
+	if command.TeamID != "" {
+		team, err := getTeamForMember(mss.teamRepo, command.TeamID, userID)
+		if err != nil {
+			return err
+		}
+		if event.ChannelID == "" {
+			event.ChannelID = entity.GetDefaultChannelID(command.TeamID)
+		}
+		recipients = removeString(team.UsersIds, userID)
+	} else if _, err := mss.userRepo.GetByID(command.ReceiverID); err != nil {
+		return fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+
+	mss.presence.SetTyping(typingConversationID(userID, command), recipients, event, !command.Stopped)
+	return nil
+}
+
+// typingConversationID returns the conversation a typing command or a sent message belongs to
+func typingConversationID(userID string, command *dto.MessageCommand) string {
+	if command.TeamID == "" {
+		return entity.DirectConversationID(entity.GetConversationKey(userID, command.ReceiverID))
+	}
+	if command.ChannelID == "" {
+		return entity.ChannelConversationID(entity.GetDefaultChannelID(command.TeamID))
+	}
+	return entity.ChannelConversationID(command.ChannelID)
+}
+
+func (mss *MessageSocketService) sendError(client *hub.Client[hub.Message], requestID string, err error) {
+	mss.hub.SendToClient(client, *hub.NewMessage(hub.CommandError, dto.CommandError{
+		RequestID: requestID,
+		Code:      commandErrorCode(err),
+		Error:     err.Error(),
+	}))
+}
+
+// commandErrorCode is the code of the status the REST endpoints answer the error with
+func commandErrorCode(err error) string {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		return dto.CommandErrorInvalid
+	case errors.Is(err, ErrForbidden):
+		return dto.CommandErrorForbidden
+	case errors.Is(err, ErrResourceNotFound):
+		return dto.CommandErrorNotFound
+	case errors.Is(err, ErrConflict):
+		return dto.CommandErrorConflict
+	default:
+		return dto.CommandErrorInternal
+	}
+}
@@ -0,0 +1,28 @@
+package service
+
+import "github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+
+// The data migrations run at startup, by the name recording that they completed
+const (
+	teamMessagesMigration = "teamMessageChannels"
+	historyKeysMigration  = "messageHistoryKeys"
+)
+
+// runMigration runs the migration unless it already completed, and records it once it has, so later startups do not
+// scan the data again
+func runMigration(migrationRepo persistence.MigrationRepositoryInterface, name string, migrate func() error) error {
+	done, err := migrationRepo.IsDone(name)
+	if err != nil || done {
+		return err
+	}
+	if err := migrate(); err != nil {
+		return err
+	}
+	return migrationRepo.MarkDone(name)
+}
+
+// noopMigrationRepository runs the migrations every time
+type noopMigrationRepository struct{}
+
+func (noopMigrationRepository) IsDone(string) (bool, error) { return false, nil }
+func (noopMigrationRepository) MarkDone(string) error       { return nil }
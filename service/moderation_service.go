@@ -0,0 +1,380 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+)
+
+const (
+	cannotModerateSelf       = "you can not moderate yourself"
+	ownerCannotBeModerated   = "the team owner can not be moderated"
+	onlyOwnerModeratesAdmins = "only the team owner can moderate admins"
+	userNotBanned            = "user is not banned from this team"
+	userNotMuted             = "user is not muted in this team"
+	userBanned               = "user is banned from this team"
+	userMuted                = "you are muted in this team"
+)
+
+// ModerationChecker enforces bans and mutes in the services that let users join a team or post in it
+type ModerationChecker interface {
+	CheckNotBanned(teamID, userID string) error
+	CheckNotMuted(teamID, userID string) error
+}
+
+// MemberKicker removes members from a team on behalf of its admins, keeping the moderation log
+type MemberKicker interface {
+	Kick(moderatorID, teamID, userID string, request *dto.ModerationRequest) (*entity.ModerationLogEntry, error)
+}
+
+type ModerationServiceInterface interface {
+	Kick(moderatorID, teamID, userID string, request *dto.ModerationRequest) (*entity.ModerationLogEntry, error)
+	Ban(moderatorID, teamID, userID string, request *dto.ModerationRequest) (*entity.TeamRestriction, error)
+	Unban(moderatorID, teamID, userID string) error
+	Mute(moderatorID, teamID, userID string, request *dto.ModerationRequest) (*entity.TeamRestriction, error)
+	Unmute(moderatorID, teamID, userID string) error
+	GetRestrictions(userID, teamID string) (*dto.TeamRestrictionsResponse, error)
+	GetModerationLog(userID, teamID string, page, limit int) (*dto.ModerationLogResponse, error)
+}
+
+type ModerationService struct {
+	moderationRepo persistence.ModerationRepositoryInterface
+	teamRepo       TeamRepositoryInterface
+	userRepo       UserRepositoryInterface
+	eventRecorder  EventRecorder
+	hub            *hub.Hub[hub.Message]
+}
+
+func NewModerationService() *ModerationService {
+	return &ModerationService{
+		moderationRepo: persistence.NewModerationRepository(),
+		teamRepo:       persistence.NewTeamRepository(),
+		userRepo:       persistence.NewUserRepository(),
+		eventRecorder:  NewTeamEventService(),
+		hub:            GetMessageHub(),
+	}
+}
+
+func NewModerationServiceWithRepo(moderationRepo persistence.ModerationRepositoryInterface, teamRepo TeamRepositoryInterface, userRepo UserRepositoryInterface) *ModerationService {
+	return &ModerationService{
+		moderationRepo: moderationRepo,
+		teamRepo:       teamRepo,
+		userRepo:       userRepo,
+		eventRecorder:  noopEventRecorder{},
+		hub:            hub.NewHub[hub.Message](),
+	}
+}
+
+func (ms *ModerationService) SetEventRecorder(recorder EventRecorder) {
+	ms.eventRecorder = recorder
+}
+
+// Kick removes a member from the team. Unlike a ban, they can join again right away.
+func (ms *ModerationService) Kick(moderatorID, teamID, userID string, request *dto.ModerationRequest) (*entity.ModerationLogEntry, error) {
+	if err := validator.ValidateModerationRequest(request, false); err != nil {
+		return nil, err
+	}
+	team, err := ms.getTeamForModeration(moderatorID, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(team.UsersIds, userID) {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotInTeam)
+	}
+	if err := ms.removeMember(team, userID); err != nil {
+		return nil, err
+	}
+
+	return ms.log(team, entity.ModerationKick, moderatorID, userID, request.Reason, 0)
+}
+
+// Ban removes the user from the team if they are a member and keeps them from joining it or its voice room until the ban expires
+func (ms *ModerationService) Ban(moderatorID, teamID, userID string, request *dto.ModerationRequest) (*entity.TeamRestriction, error) {
+	if err := validator.ValidateModerationRequest(request, false); err != nil {
+		return nil, err
+	}
+	team, err := ms.getTeamForModeration(moderatorID, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(team.UsersIds, userID) {
+		if err := ms.removeMember(team, userID); err != nil {
+			return nil, err
+		}
+	} else if _, err := ms.getUser(userID); err != nil {
+		return nil, err
+	}
+
+	ban := newRestriction(teamID, userID, moderatorID, request)
+	if err := ms.moderationRepo.SaveBan(ban); err != nil {
+		return nil, err
+	}
+	if _, err := ms.log(team, entity.ModerationBan, moderatorID, userID, ban.Reason, ban.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return ban, nil
+}
+
+func (ms *ModerationService) Unban(moderatorID, teamID, userID string) error {
+	team, err := getTeamForAdmin(ms.teamRepo, teamID, moderatorID)
+	if err != nil {
+		return err
+	}
+	ban, err := ms.moderationRepo.GetBan(teamID, userID)
+	if err != nil {
+		return err
+	}
+	if ban == nil || !ban.IsActive(time.Now().Unix()) {
+		return fmt.Errorf("%w: %s", ErrResourceNotFound, userNotBanned)
+	}
+
+	if err := ms.moderationRepo.DeleteBan(teamID, userID); err != nil {
+		return err
+	}
+	_, err = ms.log(team, entity.ModerationUnban, moderatorID, userID, "", 0)
+	return err
+}
+
+// Mute keeps a member from posting in the team chat until the mute expires
+func (ms *ModerationService) Mute(moderatorID, teamID, userID string, request *dto.ModerationRequest) (*entity.TeamRestriction, error) {
+	if err := validator.ValidateModerationRequest(request, true); err != nil {
+		return nil, err
+	}
+	team, err := ms.getTeamForModeration(moderatorID, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(team.UsersIds, userID) {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotInTeam)
+	}
+
+	mute := newRestriction(teamID, userID, moderatorID, request)
+	if err := ms.moderationRepo.SaveMute(mute); err != nil {
+		return nil, err
+	}
+	if _, err := ms.log(team, entity.ModerationMute, moderatorID, userID, mute.Reason, mute.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return mute, nil
+}
+
+func (ms *ModerationService) Unmute(moderatorID, teamID, userID string) error {
+	team, err := getTeamForAdmin(ms.teamRepo, teamID, moderatorID)
+	if err != nil {
+		return err
+	}
+	mute, err := ms.moderationRepo.GetMute(teamID, userID)
+	if err != nil {
+		return err
+	}
+	if mute == nil || !mute.IsActive(time.Now().Unix()) {
+		return fmt.Errorf("%w: %s", ErrResourceNotFound, userNotMuted)
+	}
+
+	if err := ms.moderationRepo.DeleteMute(teamID, userID); err != nil {
+		return err
+	}
+	_, err = ms.log(team, entity.ModerationUnmute, moderatorID, userID, "", 0)
+	return err
+}
+
+// GetRestrictions returns the bans and mutes of the team that are still in effect
+func (ms *ModerationService) GetRestrictions(userID, teamID string) (*dto.TeamRestrictionsResponse, error) {
+	if _, err := getTeamForAdmin(ms.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	bans, err := ms.moderationRepo.GetBans(teamID)
+	if err != nil {
+		return nil, err
+	}
+	mutes, err := ms.moderationRepo.GetMutes(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	return &dto.TeamRestrictionsResponse{
+		Bans:  activeRestrictions(bans, now),
+		Mutes: activeRestrictions(mutes, now),
+	}, nil
+}
+
+// GetModerationLog returns the team's moderation log, newest first
+func (ms *ModerationService) GetModerationLog(userID, teamID string, page, limit int) (*dto.ModerationLogResponse, error) {
+	if _, err := getTeamForAdmin(ms.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	entries, err := ms.moderationRepo.GetLog(teamID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt > entries[j].CreatedAt
+	})
+
+	totalCount := len(entries)
+	totalPages := (totalCount + limit - 1) / limit
+
+	start := (page - 1) * limit
+	end := start + limit
+	if start > totalCount {
+		start = totalCount
+	}
+	if end > totalCount {
+		end = totalCount
+	}
+
+	return &dto.ModerationLogResponse{
+		Entries:    entries[start:end],
+		Page:       page,
+		Limit:      limit,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (ms *ModerationService) CheckNotBanned(teamID, userID string) error {
+	ban, err := ms.moderationRepo.GetBan(teamID, userID)
+	if err != nil {
+		return err
+	}
+	if ban == nil || !ban.IsActive(time.Now().Unix()) {
+		return nil
+	}
+	if ban.ExpiresAt != 0 {
+		return fmt.Errorf("%w: %s until %s", ErrForbidden, userBanned, time.Unix(ban.ExpiresAt, 0).UTC().Format(time.RFC3339))
+	}
+	return fmt.Errorf("%w: %s", ErrForbidden, userBanned)
+}
+
+func (ms *ModerationService) CheckNotMuted(teamID, userID string) error {
+	mute, err := ms.moderationRepo.GetMute(teamID, userID)
+	if err != nil {
+		return err
+	}
+	if mute == nil || !mute.IsActive(time.Now().Unix()) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s until %s", ErrForbidden, userMuted, time.Unix(mute.ExpiresAt, 0).UTC().Format(time.RFC3339))
+}
+
+// getTeamForModeration checks that the moderator is an admin and may act on the target:
+// nobody can moderate the owner and only the owner can moderate other admins
+func (ms *ModerationService) getTeamForModeration(moderatorID, teamID, userID string) (*entity.Team, error) {
+	team, err := getTeamForAdmin(ms.teamRepo, teamID, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+	if moderatorID == userID {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, cannotModerateSelf)
+	}
+	if team.IsOwner(userID) {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, ownerCannotBeModerated)
+	}
+	if team.IsAdmin(userID) && !team.IsOwner(moderatorID) {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, onlyOwnerModeratesAdmins)
+	}
+	return team, nil
+}
+
+func (ms *ModerationService) getUser(userID string) (*entity.User, error) {
+	user, err := ms.userRepo.GetByID(userID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// removeMember takes the user out of the team; the activity feed shows it as the member leaving, the reason stays in the moderation log
+func (ms *ModerationService) removeMember(team *entity.Team, userID string) error {
+	user, err := ms.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if err := removeTeamMember(ms.userRepo, ms.teamRepo, user, team); err != nil {
+		return err
+	}
+	ms.eventRecorder.Record(entity.NewTeamEvent(team.Id, entity.TeamEventMemberLeft, userID, "", ""))
+	return nil
+}
+
+// log adds the action to the team's moderation log and tells the target about it
+func (ms *ModerationService) log(team *entity.Team, action entity.ModerationAction, moderatorID, userID, reason string, expiresAt int64) (*entity.ModerationLogEntry, error) {
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &entity.ModerationLogEntry{
+		ID:          id,
+		TeamID:      team.Id,
+		Action:      action,
+		ModeratorID: moderatorID,
+		TargetID:    userID,
+		Reason:      reason,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   time.Now().UnixMilli(),
+	}
+	if err := ms.moderationRepo.CreateLogEntry(entry); err != nil {
+		return nil, err
+	}
+	ms.hub.Send(userID, *hub.NewMessage(hub.TeamModeration, entry))
+	return entry, nil
+}
+
+func newRestriction(teamID, userID, moderatorID string, request *dto.ModerationRequest) *entity.TeamRestriction {
+	now := time.Now()
+	var expiresAt int64
+	if request.DurationMinutes > 0 {
+		expiresAt = now.Add(time.Duration(request.DurationMinutes) * time.Minute).Unix()
+	}
+	return entity.NewTeamRestriction(teamID, userID, moderatorID, strings.TrimSpace(request.Reason), now.Unix(), expiresAt)
+}
+
+func activeRestrictions(restrictions []*entity.TeamRestriction, now int64) []*entity.TeamRestriction {
+	result := make([]*entity.TeamRestriction, 0, len(restrictions))
+	for _, restriction := range restrictions {
+		if restriction.IsActive(now) {
+			result = append(result, restriction)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt > result[j].CreatedAt
+	})
+	return result
+}
+
+type noopModerationChecker struct{}
+
+func (noopModerationChecker) CheckNotBanned(string, string) error { return nil }
+
+func (noopModerationChecker) CheckNotMuted(string, string) error { return nil }
+
+type noopMemberKicker struct{}
+
+func (noopMemberKicker) Kick(string, string, string, *dto.ModerationRequest) (*entity.ModerationLogEntry, error) {
+	return nil, nil
+}
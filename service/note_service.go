@@ -0,0 +1,662 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/crdt"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	noteNotFound         = "note not found"
+	noteTooLong          = "a note can have at most 200000 characters"
+	noteReadOnly         = "the team is archived, its notes can not be changed"
+	noteInvalidMessage   = "invalid message"
+	noteInvalidCursor    = "the cursor refers to an unknown character"
+	onlyNoteOwnerOrAdmin = "only the creator of the note or a team admin can delete it"
+
+	maxNoteLength = 200000
+	// maxNoteLog is how many operations are kept to catch clients up; clients further behind get the whole text
+	maxNoteLog = 5000
+)
+
+type NoteServiceInterface interface {
+	CreateNote(userID, teamID string, request *dto.NoteRequest) (*dto.NoteResponse, error)
+	GetNotes(userID, teamID string) ([]*dto.NoteResponse, error)
+	GetNote(userID, teamID, noteID string) (*dto.NoteResponse, error)
+	RenameNote(userID, teamID, noteID string, request *dto.NoteRequest) (*dto.NoteResponse, error)
+	DeleteNote(userID, teamID, noteID string) error
+	CheckNoteAccess(userID, teamID, noteID string) error
+	Join(userID, teamID, noteID string, version int, conn *websocket.Conn) error
+}
+
+type NoteService struct {
+	noteRepo persistence.NoteRepositoryInterface
+	teamRepo TeamRepositoryInterface
+	hub      *hub.Hub[hub.Message]
+	live     *liveNotes
+}
+
+// liveNotes are the notes open in at least one connection, shared by every NoteService using the same hub
+type liveNotes struct {
+	mu          sync.Mutex
+	notes       map[string]*liveNote
+	connections map[string]*noteConnection
+}
+
+// liveNote is the replicated text of an open note with the operations applied since it was opened
+type liveNote struct {
+	mu           sync.Mutex
+	note         *entity.Note
+	text         *crdt.Text
+	version      int
+	savedVersion int
+	readOnly     bool
+	log          []crdt.Operation // the operations that brought the text from version-len(log) to version
+	connections  map[string]*noteConnection
+}
+
+type noteConnection struct {
+	id     string
+	userID string
+	noteID string
+	cursor *dto.NoteCursor
+}
+
+var (
+	sharedLiveNotes     *liveNotes
+	sharedLiveNotesOnce sync.Once
+)
+
+func NewNoteService() *NoteService {
+	sharedLiveNotesOnce.Do(func() {
+		sharedLiveNotes = newLiveNotes()
+	})
+	return newNoteService(persistence.NewNoteRepository(), persistence.NewTeamRepository(), hub.GetNoteHub(), sharedLiveNotes)
+}
+
+func NewNoteServiceWithRepo(noteRepo persistence.NoteRepositoryInterface, teamRepo TeamRepositoryInterface) *NoteService {
+	return newNoteService(noteRepo, teamRepo, hub.NewHub[hub.Message](), newLiveNotes())
+}
+
+func newNoteService(noteRepo persistence.NoteRepositoryInterface, teamRepo TeamRepositoryInterface, noteHub *hub.Hub[hub.Message], live *liveNotes) *NoteService {
+	ns := &NoteService{
+		noteRepo: noteRepo,
+		teamRepo: teamRepo,
+		hub:      noteHub,
+		live:     live,
+	}
+	noteHub.OnMessage(ns.handleMessage)
+	noteHub.OnDisconnect(ns.leave)
+	return ns
+}
+
+func newLiveNotes() *liveNotes {
+	return &liveNotes{
+		notes:       make(map[string]*liveNote),
+		connections: make(map[string]*noteConnection),
+	}
+}
+
+func (ns *NoteService) CreateNote(userID, teamID string, request *dto.NoteRequest) (*dto.NoteResponse, error) {
+	if err := validator.ValidateNoteRequest(request); err != nil {
+		return nil, err
+	}
+	team, err := getTeamForMember(ns.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, err
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	note := entity.NewNote(id, teamID, strings.TrimSpace(request.Title), userID)
+	if err := ns.noteRepo.Create(note); err != nil {
+		return nil, err
+	}
+	return &dto.NoteResponse{Note: *note, EditorIDs: []string{}}, nil
+}
+
+// GetNotes lists the team's notes without their text, the last changed first
+func (ns *NoteService) GetNotes(userID, teamID string) ([]*dto.NoteResponse, error) {
+	if _, err := getTeamForMember(ns.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	notes, err := ns.noteRepo.GetByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].UpdatedAt.After(notes[j].UpdatedAt)
+	})
+
+	result := make([]*dto.NoteResponse, 0, len(notes))
+	for _, note := range notes {
+		resp := &dto.NoteResponse{Note: *note, EditorIDs: []string{}}
+		if live := ns.live.get(note.ID); live != nil {
+			live.mu.Lock()
+			resp.Version = live.version
+			resp.EditorIDs = live.editorIDs()
+			live.mu.Unlock()
+		}
+		result = append(result, resp)
+	}
+	return result, nil
+}
+
+// GetNote returns the note's current text, including the changes not saved yet
+func (ns *NoteService) GetNote(userID, teamID, noteID string) (*dto.NoteResponse, error) {
+	if _, err := getTeamForMember(ns.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+	note, err := ns.getNote(teamID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if live := ns.live.get(noteID); live != nil {
+		live.mu.Lock()
+		defer live.mu.Unlock()
+		resp := &dto.NoteResponse{Note: *live.note, Text: live.text.String(), EditorIDs: live.editorIDs()}
+		resp.Version = live.version
+		return resp, nil
+	}
+
+	snapshot, err := ns.noteRepo.GetSnapshot(noteID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.NoteResponse{Note: *note, EditorIDs: []string{}}
+	if snapshot != nil {
+		resp.Text = crdt.NewTextFromElements(snapshot.Elements).String()
+	}
+	return resp, nil
+}
+
+func (ns *NoteService) RenameNote(userID, teamID, noteID string, request *dto.NoteRequest) (*dto.NoteResponse, error) {
+	if err := validator.ValidateNoteRequest(request); err != nil {
+		return nil, err
+	}
+	team, err := getTeamForMember(ns.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, err
+	}
+	note, err := ns.getNote(teamID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	note.Title = strings.TrimSpace(request.Title)
+	note.UpdatedAt = time.Now().UTC()
+	if live := ns.live.get(noteID); live != nil {
+		live.mu.Lock()
+		live.note.Title = note.Title
+		note.Version = live.savedVersion
+		live.mu.Unlock()
+	}
+	if err := ns.noteRepo.Update(note); err != nil {
+		return nil, err
+	}
+	return &dto.NoteResponse{Note: *note, EditorIDs: []string{}}, nil
+}
+
+// DeleteNote deletes the note and disconnects the members editing it
+func (ns *NoteService) DeleteNote(userID, teamID, noteID string) error {
+	team, err := getTeamForMember(ns.teamRepo, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return err
+	}
+	note, err := ns.getNote(teamID, noteID)
+	if err != nil {
+		return err
+	}
+	if note.CreatedBy != userID && !team.IsAdmin(userID) {
+		return fmt.Errorf("%w: %s", ErrForbidden, onlyNoteOwnerOrAdmin)
+	}
+
+	if live := ns.live.remove(noteID); live != nil {
+		live.mu.Lock()
+		ids := live.connectionIDs()
+		live.connections = map[string]*noteConnection{}
+		live.mu.Unlock()
+		ns.hub.SendMany(ids, *hub.NewMessage(hub.NoteDeleted, map[string]string{"noteId": noteID}))
+	}
+
+	if err := ns.noteRepo.DeleteSnapshot(noteID); err != nil {
+		return err
+	}
+	return ns.noteRepo.Delete(noteID)
+}
+
+// CheckNoteAccess checks that the user can open the note, before the WebSocket is accepted
+func (ns *NoteService) CheckNoteAccess(userID, teamID, noteID string) error {
+	if _, err := getTeamForMember(ns.teamRepo, teamID, userID); err != nil {
+		return err
+	}
+	_, err := ns.getNote(teamID, noteID)
+	return err
+}
+
+// Join opens the note for a new connection. The client gets the operations made since version when they are still
+// known, otherwise (or when version is negative) the whole text, then the changes and cursors of the other editors.
+func (ns *NoteService) Join(userID, teamID, noteID string, version int, conn *websocket.Conn) error {
+	team, err := getTeamForMember(ns.teamRepo, teamID, userID)
+	if err != nil {
+		return err
+	}
+	connectionID, err := generateID()
+	if err != nil {
+		return err
+	}
+	connection := &noteConnection{id: connectionID, userID: userID, noteID: noteID}
+	live, err := ns.open(teamID, noteID, connection)
+	if err != nil {
+		return err
+	}
+
+	live.mu.Lock()
+	defer live.mu.Unlock()
+	live.readOnly = team.Archived
+
+	ns.hub.Register(hub.NewClient[hub.Message](connectionID, conn))
+	ns.hub.Send(connectionID, *hub.NewMessage(hub.NoteState, live.state(connectionID, version)))
+	ns.hub.SendMany(live.otherConnectionIDs(connectionID), *hub.NewMessage(hub.NotePresence, connection.presence()))
+	return nil
+}
+
+// RunSnapshots saves the changed notes every interval, it never returns
+func (ns *NoteService) RunSnapshots(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for _, live := range ns.live.all() {
+			if err := ns.saveSnapshot(live); err != nil {
+				log.Printf("notes: saving note %s: %v", live.note.ID, err)
+			}
+		}
+	}
+}
+
+// handleMessage applies a message sent by a client of the note hub
+func (ns *NoteService) handleMessage(client *hub.Client[hub.Message], data []byte) {
+	connection := ns.live.connection(client.ClientID)
+	if connection == nil {
+		ns.sendError(client.ClientID, noteNotFound)
+		return
+	}
+	live := ns.live.get(connection.noteID)
+	if live == nil {
+		ns.sendError(client.ClientID, noteNotFound)
+		return
+	}
+
+	var message dto.NoteClientMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		ns.sendError(client.ClientID, noteInvalidMessage)
+		return
+	}
+
+	live.mu.Lock()
+	defer live.mu.Unlock()
+
+	switch message.Type {
+	case dto.NoteClientOperations:
+		ns.applyOperations(live, connection, message.Ops)
+	case dto.NoteClientCursor:
+		if message.Cursor != nil && !(live.knows(message.Cursor.Anchor) && live.knows(message.Cursor.Head)) {
+			ns.sendError(connection.id, noteInvalidCursor)
+			return
+		}
+		connection.cursor = message.Cursor
+		ns.hub.SendMany(live.otherConnectionIDs(connection.id), *hub.NewMessage(hub.NotePresence, connection.presence()))
+	case dto.NoteClientSync:
+		if ops, ok := live.operationsSince(message.Version); ok {
+			ns.hub.Send(connection.id, *hub.NewMessage(hub.NoteOperations, dto.NoteOperationsPayload{
+				NoteID:      live.note.ID,
+				BaseVersion: message.Version,
+				Version:     live.version,
+				Ops:         ops,
+			}))
+		} else {
+			ns.hub.Send(connection.id, *hub.NewMessage(hub.NoteState, live.state(connection.id, -1)))
+		}
+	default:
+		ns.sendError(connection.id, noteInvalidMessage)
+	}
+}
+
+// applyOperations applies the operations in order until one fails and sends the applied ones to every editor,
+// the sender included so it learns the new version
+func (ns *NoteService) applyOperations(live *liveNote, connection *noteConnection, ops []crdt.Operation) {
+	if live.readOnly {
+		ns.sendError(connection.id, noteReadOnly)
+		return
+	}
+	if err := validator.ValidateNoteOperations(ops); err != nil {
+		ns.sendError(connection.id, err.Error())
+		return
+	}
+
+	baseVersion := live.version
+	applied := make([]crdt.Operation, 0, len(ops))
+	var failure error
+	length := live.text.Len()
+	for _, op := range ops {
+		if op.Type == crdt.OperationInsert && length+len([]rune(op.Value)) > maxNoteLength {
+			failure = fmt.Errorf("%s", noteTooLong)
+			break
+		}
+		changed, err := live.text.Apply(op)
+		if err != nil {
+			failure = err
+			break
+		}
+		if !changed {
+			continue
+		}
+		if op.Type == crdt.OperationInsert {
+			length += len([]rune(op.Value))
+		} else {
+			length--
+		}
+		applied = append(applied, op)
+	}
+
+	if len(applied) > 0 {
+		live.version += len(applied)
+		live.log = append(live.log, applied...)
+		if len(live.log) > maxNoteLog {
+			live.log = append([]crdt.Operation(nil), live.log[len(live.log)-maxNoteLog:]...)
+		}
+		ns.hub.SendMany(live.connectionIDs(), *hub.NewMessage(hub.NoteOperations, dto.NoteOperationsPayload{
+			NoteID:       live.note.ID,
+			BaseVersion:  baseVersion,
+			Version:      live.version,
+			Ops:          applied,
+			ConnectionID: connection.id,
+			UserID:       connection.userID,
+		}))
+	}
+	if failure != nil {
+		ns.sendError(connection.id, failure.Error())
+	}
+}
+
+// leave removes a closed connection; the note is saved and closed when its last editor leaves
+func (ns *NoteService) leave(client *hub.Client[hub.Message]) {
+	connection := ns.live.removeConnection(client.ClientID)
+	if connection == nil {
+		return
+	}
+	live := ns.live.get(connection.noteID)
+	if live == nil {
+		return
+	}
+
+	live.mu.Lock()
+	delete(live.connections, connection.id)
+	remaining := len(live.connections)
+	presence := connection.presence()
+	presence.Left = true
+	ns.hub.SendMany(live.connectionIDs(), *hub.NewMessage(hub.NotePresence, presence))
+	live.mu.Unlock()
+
+	if remaining == 0 {
+		if err := ns.saveSnapshot(live); err != nil {
+			log.Printf("notes: saving note %s: %v", live.note.ID, err)
+		}
+		ns.live.closeIfIdle(live)
+	}
+}
+
+// saveSnapshot saves the note's text when it changed since the last snapshot
+func (ns *NoteService) saveSnapshot(live *liveNote) error {
+	live.mu.Lock()
+	if live.version == live.savedVersion {
+		live.mu.Unlock()
+		return nil
+	}
+	snapshot := &entity.NoteSnapshot{
+		NoteID:   live.note.ID,
+		Version:  live.version,
+		Elements: live.text.Elements(),
+		SavedAt:  time.Now().UTC(),
+	}
+	note := *live.note
+	live.mu.Unlock()
+
+	if err := ns.noteRepo.SaveSnapshot(snapshot); err != nil {
+		return err
+	}
+	note.Version = snapshot.Version
+	note.UpdatedAt = snapshot.SavedAt
+	if err := ns.noteRepo.Update(&note); err != nil {
+		return err
+	}
+
+	live.mu.Lock()
+	live.savedVersion = max(live.savedVersion, snapshot.Version)
+	live.note.Version = live.savedVersion
+	live.note.UpdatedAt = snapshot.SavedAt
+	live.mu.Unlock()
+	return nil
+}
+
+// open adds the connection to the live note, loading the note from its last snapshot when nobody has it open
+func (ns *NoteService) open(teamID, noteID string, connection *noteConnection) (*liveNote, error) {
+	ns.live.mu.Lock()
+	defer ns.live.mu.Unlock()
+
+	live, ok := ns.live.notes[noteID]
+	if !ok {
+		var err error
+		if live, err = ns.load(teamID, noteID); err != nil {
+			return nil, err
+		}
+		ns.live.notes[noteID] = live
+	}
+
+	live.mu.Lock()
+	live.connections[connection.id] = connection
+	live.mu.Unlock()
+	ns.live.connections[connection.id] = connection
+	return live, nil
+}
+
+func (ns *NoteService) load(teamID, noteID string) (*liveNote, error) {
+	note, err := ns.getNote(teamID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := ns.noteRepo.GetSnapshot(noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	live := &liveNote{
+		note:        note,
+		text:        crdt.NewText(),
+		version:     note.Version,
+		connections: make(map[string]*noteConnection),
+	}
+	if snapshot != nil {
+		live.text = crdt.NewTextFromElements(snapshot.Elements)
+		live.version = snapshot.Version
+	}
+	live.savedVersion = live.version
+	return live, nil
+}
+
+func (ns *NoteService) sendError(connectionID, message string) {
+	ns.hub.Send(connectionID, *hub.NewMessage(hub.NoteError, map[string]string{"error": message}))
+}
+
+// getNote returns the note when it belongs to the team
+func (ns *NoteService) getNote(teamID, noteID string) (*entity.Note, error) {
+	note, err := ns.noteRepo.GetByID(noteID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, noteNotFound)
+		}
+		return nil, err
+	}
+	if note.TeamID != teamID {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, noteNotFound)
+	}
+	return note, nil
+}
+
+func (ln *liveNotes) get(noteID string) *liveNote {
+	ln.mu.Lock()
+	defer ln.mu.Unlock()
+	return ln.notes[noteID]
+}
+
+func (ln *liveNotes) all() []*liveNote {
+	ln.mu.Lock()
+	defer ln.mu.Unlock()
+	notes := make([]*liveNote, 0, len(ln.notes))
+	for _, live := range ln.notes {
+		notes = append(notes, live)
+	}
+	return notes
+}
+
+// remove closes a deleted note and forgets its connections
+func (ln *liveNotes) remove(noteID string) *liveNote {
+	ln.mu.Lock()
+	defer ln.mu.Unlock()
+	live, ok := ln.notes[noteID]
+	if !ok {
+		return nil
+	}
+	delete(ln.notes, noteID)
+	for id, connection := range ln.connections {
+		if connection.noteID == noteID {
+			delete(ln.connections, id)
+		}
+	}
+	return live
+}
+
+// closeIfIdle closes the note unless someone opened it again meanwhile
+func (ln *liveNotes) closeIfIdle(live *liveNote) {
+	ln.mu.Lock()
+	defer ln.mu.Unlock()
+	live.mu.Lock()
+	defer live.mu.Unlock()
+	if len(live.connections) == 0 && live.version == live.savedVersion && ln.notes[live.note.ID] == live {
+		delete(ln.notes, live.note.ID)
+	}
+}
+
+func (ln *liveNotes) connection(connectionID string) *noteConnection {
+	ln.mu.Lock()
+	defer ln.mu.Unlock()
+	return ln.connections[connectionID]
+}
+
+func (ln *liveNotes) removeConnection(connectionID string) *noteConnection {
+	ln.mu.Lock()
+	defer ln.mu.Unlock()
+	connection := ln.connections[connectionID]
+	delete(ln.connections, connectionID)
+	return connection
+}
+
+// operationsSince returns the operations made after version, or false when they are no longer known
+func (live *liveNote) operationsSince(version int) ([]crdt.Operation, bool) {
+	first := live.version - len(live.log)
+	if version < 0 || version < first || version > live.version {
+		return nil, false
+	}
+	return append([]crdt.Operation{}, live.log[version-first:]...), true
+}
+
+// state is the payload sent to a client that connects with the given version, negative when it has no text yet
+func (live *liveNote) state(connectionID string, version int) dto.NoteStatePayload {
+	presence := make([]*dto.NotePresence, 0, len(live.connections))
+	for _, connection := range live.connections {
+		if connection.id != connectionID {
+			presence = append(presence, connection.presence())
+		}
+	}
+	state := dto.NoteStatePayload{
+		NoteID:       live.note.ID,
+		ConnectionID: connectionID,
+		Site:         connectionID,
+		Version:      live.version,
+		Clock:        live.text.Clock(),
+		Presence:     presence,
+	}
+	if ops, ok := live.operationsSince(version); ok {
+		state.CatchUp = true
+		state.BaseVersion = version
+		state.Ops = ops
+	} else {
+		state.Elements = live.text.Elements()
+	}
+	return state
+}
+
+// knows reports whether the ID is the start of the text or one of its characters
+func (live *liveNote) knows(id crdt.ID) bool {
+	return id.IsZero() || live.text.Contains(id)
+}
+
+func (live *liveNote) connectionIDs() []string {
+	return live.otherConnectionIDs("")
+}
+
+func (live *liveNote) otherConnectionIDs(connectionID string) []string {
+	ids := make([]string, 0, len(live.connections))
+	for id := range live.connections {
+		if id != connectionID {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (live *liveNote) editorIDs() []string {
+	ids := make([]string, 0, len(live.connections))
+	for _, connection := range live.connections {
+		if !slices.Contains(ids, connection.userID) {
+			ids = append(ids, connection.userID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (connection *noteConnection) presence() *dto.NotePresence {
+	return &dto.NotePresence{
+		ConnectionID: connection.id,
+		UserID:       connection.userID,
+		Cursor:       connection.cursor,
+	}
+}
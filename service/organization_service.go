@@ -0,0 +1,325 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+)
+
+const (
+	organizationNotFound          = "organization not found"
+	onlyOrganizationAdminsAllowed = "only organization admins can do this"
+	emailDomainTaken              = "email domain %s already belongs to another organization"
+	emailNotInOrganization        = "email must be on one of the organization's domains"
+	userHasTeams                  = "the user must leave their teams before changing organization"
+	organizationNeedsAdmin        = "the organization must keep at least one admin"
+)
+
+type OrganizationServiceInterface interface {
+	CreateOrganization(userID string, request *dto.OrganizationRequest) (*entity.Organization, error)
+	GetOrganizations() ([]*entity.Organization, error)
+	GetOrganization(userID, organizationID string) (*entity.Organization, error)
+	UpdateOrganization(userID, organizationID string, request *dto.OrganizationRequest) (*entity.Organization, error)
+	SetAdmin(userID, organizationID, memberID string, admin bool) (*entity.Organization, error)
+	AddUser(organizationID, userID string) (*entity.User, error)
+}
+
+// OrganizationResolver places users in organizations by their email domain
+type OrganizationResolver interface {
+	// ResolveSignupOrganization returns the organization a new user joins: the requested one, which must allow the email,
+	// or else the one owning the email's domain. Users whose domain belongs to no organization join none.
+	ResolveSignupOrganization(email, organizationID string) (string, error)
+	// CheckMemberEmail checks that a member of the organization can change their email to this one
+	CheckMemberEmail(organizationID, email string) error
+}
+
+type OrganizationService struct {
+	organizationRepo persistence.OrganizationRepositoryInterface
+	userRepo         UserRepositoryInterface
+	searchIndexer    SearchIndexer
+}
+
+func NewOrganizationService() *OrganizationService {
+	return &OrganizationService{
+		organizationRepo: persistence.NewOrganizationRepository(),
+		userRepo:         persistence.NewUserRepository(),
+		searchIndexer:    NewSearchService(),
+	}
+}
+
+func NewOrganizationServiceWithRepo(organizationRepo persistence.OrganizationRepositoryInterface, userRepo UserRepositoryInterface) *OrganizationService {
+	return &OrganizationService{
+		organizationRepo: organizationRepo,
+		userRepo:         userRepo,
+		searchIndexer:    noopSearchIndexer{},
+	}
+}
+
+func (orgs *OrganizationService) SetSearchIndexer(indexer SearchIndexer) {
+	orgs.searchIndexer = indexer
+}
+
+// CreateOrganization creates an organization without members; users join it by signing up on its domains or through AddUser
+func (orgs *OrganizationService) CreateOrganization(userID string, request *dto.OrganizationRequest) (*entity.Organization, error) {
+	if err := validator.ValidateOrganizationRequest(request); err != nil {
+		return nil, err
+	}
+	domains := normalizeEmailDomains(request.EmailDomains)
+	if err := orgs.checkDomainsAvailable("", domains); err != nil {
+		return nil, err
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	organization := entity.NewOrganization(id, strings.TrimSpace(request.Name), domains, userID)
+	if err := orgs.organizationRepo.Create(organization); err != nil {
+		return nil, err
+	}
+	return organization, nil
+}
+
+// GetOrganizations returns every organization by name
+func (orgs *OrganizationService) GetOrganizations() ([]*entity.Organization, error) {
+	organizations, err := orgs.organizationRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(organizations, func(i, j int) bool {
+		return organizations[i].Name < organizations[j].Name
+	})
+	return organizations, nil
+}
+
+// GetOrganization returns the organization to its members and the platform admins
+func (orgs *OrganizationService) GetOrganization(userID, organizationID string) (*entity.Organization, error) {
+	user, err := orgs.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.OrganizationID != organizationID && !user.IsAdmin {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, organizationNotFound)
+	}
+	return orgs.getOrganization(organizationID)
+}
+
+// UpdateOrganization renames the organization and replaces its email domains. Members keep their organization when their domain is removed.
+func (orgs *OrganizationService) UpdateOrganization(userID, organizationID string, request *dto.OrganizationRequest) (*entity.Organization, error) {
+	if err := validator.ValidateOrganizationRequest(request); err != nil {
+		return nil, err
+	}
+	organization, err := orgs.getOrganizationForAdmin(organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	domains := normalizeEmailDomains(request.EmailDomains)
+	if err := orgs.checkDomainsAvailable(organization.ID, domains); err != nil {
+		return nil, err
+	}
+
+	organization.Name = strings.TrimSpace(request.Name)
+	organization.EmailDomains = domains
+	if err := orgs.organizationRepo.Update(organization); err != nil {
+		return nil, err
+	}
+	return organization, nil
+}
+
+// SetAdmin makes a member an admin of the organization or removes them from the admins
+func (orgs *OrganizationService) SetAdmin(userID, organizationID, memberID string, admin bool) (*entity.Organization, error) {
+	organization, err := orgs.getOrganizationForAdmin(organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	member, err := orgs.getUser(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member.OrganizationID != organization.ID {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+	if organization.IsAdmin(memberID) == admin {
+		return organization, nil
+	}
+	if !admin && len(organization.AdminIds) == 1 {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, organizationNeedsAdmin)
+	}
+
+	organization.AdminIds = removeString(organization.AdminIds, memberID)
+	if admin {
+		organization.AdminIds = append(organization.AdminIds, memberID)
+	}
+	if err := orgs.organizationRepo.Update(organization); err != nil {
+		return nil, err
+	}
+	return organization, nil
+}
+
+// AddUser moves an existing user into the organization, whatever their email. Users in teams can not move,
+// as their teams belong to their current organization.
+func (orgs *OrganizationService) AddUser(organizationID, userID string) (*entity.User, error) {
+	organization, err := orgs.getOrganization(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := orgs.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.OrganizationID == organization.ID {
+		return user, nil
+	}
+	if user.TeamsIds != nil && len(*user.TeamsIds) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, userHasTeams)
+	}
+
+	if user.OrganizationID != "" {
+		previous, err := orgs.organizationRepo.GetByID(user.OrganizationID)
+		if err == nil && previous.IsAdmin(user.ID) {
+			previous.AdminIds = removeString(previous.AdminIds, user.ID)
+			if err := orgs.organizationRepo.Update(previous); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	user.OrganizationID = organization.ID
+	if err := orgs.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	orgs.searchIndexer.IndexUser(user)
+	return user, nil
+}
+
+func (orgs *OrganizationService) ResolveSignupOrganization(email, organizationID string) (string, error) {
+	if organizationID != "" {
+		organization, err := orgs.organizationRepo.GetByID(organizationID)
+		if err != nil {
+			if strings.Contains(err.Error(), NotFoundError) {
+				return "", fmt.Errorf("%w: %s", validator.ErrValidation, organizationNotFound)
+			}
+			return "", err
+		}
+		if !organization.AllowsEmail(email) {
+			return "", fmt.Errorf("%w: %s", validator.ErrValidation, emailNotInOrganization)
+		}
+		return organization.ID, nil
+	}
+
+	organizations, err := orgs.organizationRepo.GetAll()
+	if err != nil {
+		return "", err
+	}
+	for _, organization := range organizations {
+		if organization.AllowsEmail(email) {
+			return organization.ID, nil
+		}
+	}
+	return "", nil
+}
+
+func (orgs *OrganizationService) CheckMemberEmail(organizationID, email string) error {
+	if organizationID == "" {
+		return nil
+	}
+	organization, err := orgs.organizationRepo.GetByID(organizationID)
+	if err != nil {
+		return err
+	}
+	// Organizations without domains add their users by hand
+	if len(organization.EmailDomains) > 0 && !organization.AllowsEmail(email) {
+		return fmt.Errorf("%w: %s", validator.ErrValidation, emailNotInOrganization)
+	}
+	return nil
+}
+
+// getOrganizationForAdmin returns the organization if the user is one of its admins or a platform admin
+func (orgs *OrganizationService) getOrganizationForAdmin(organizationID, userID string) (*entity.Organization, error) {
+	user, err := orgs.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.OrganizationID != organizationID && !user.IsAdmin {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, organizationNotFound)
+	}
+	organization, err := orgs.getOrganization(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !organization.IsAdmin(userID) && !user.IsAdmin {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, onlyOrganizationAdminsAllowed)
+	}
+	return organization, nil
+}
+
+func (orgs *OrganizationService) getOrganization(organizationID string) (*entity.Organization, error) {
+	organization, err := orgs.organizationRepo.GetByID(organizationID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, organizationNotFound)
+		}
+		return nil, err
+	}
+	return organization, nil
+}
+
+func (orgs *OrganizationService) getUser(userID string) (*entity.User, error) {
+	user, err := orgs.userRepo.GetByID(userID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkDomainsAvailable fails when another organization already owns one of the domains
+func (orgs *OrganizationService) checkDomainsAvailable(organizationID string, domains []string) error {
+	organizations, err := orgs.organizationRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, organization := range organizations {
+		if organization.ID == organizationID {
+			continue
+		}
+		for _, domain := range domains {
+			if slices.Contains(organization.EmailDomains, domain) {
+				return fmt.Errorf("%w: "+emailDomainTaken, ErrConflict, domain)
+			}
+		}
+	}
+	return nil
+}
+
+func normalizeEmailDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if !slices.Contains(normalized, domain) {
+			normalized = append(normalized, domain)
+		}
+	}
+	return normalized
+}
+
+// sameOrganization reports whether the user belongs to the organization; users and teams created before
+// organizations existed belong to none and only see each other
+func sameOrganization(user *entity.User, organizationID string) bool {
+	return user.OrganizationID == organizationID
+}
+
+type noopOrganizationResolver struct{}
+
+func (noopOrganizationResolver) ResolveSignupOrganization(string, string) (string, error) {
+	return "", nil
+}
+func (noopOrganizationResolver) CheckMemberEmail(string, string) error { return nil }
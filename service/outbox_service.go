@@ -0,0 +1,243 @@
+package service
+
+import (
+	"cmp"
+	"encoding/json"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// maxOutboxEntries is how many of their latest events users can get back after reconnecting
+	maxOutboxEntries = 500
+	// outboxTrimInterval is how many events are added to an outbox between removing its oldest entries
+	outboxTrimInterval = 50
+)
+
+// OutboxService numbers the events of the message hub per user and keeps the latest ones, so users reconnecting get
+// the events they missed before any new one. The events are numbered in memory and saved in the background, so one
+// instance of the server must own the outboxes.
+type OutboxService struct {
+	outboxRepo persistence.OutboxRepositoryInterface
+	hub        *hub.Hub[hub.Message]
+	state      *outboxState
+}
+
+type outboxState struct {
+	mu    sync.Mutex
+	boxes map[string]*userOutbox
+}
+
+// userOutbox is locked while an event is numbered, so the events are numbered in the order they are sent. Numbered
+// events wait in pending until the outbox's writer has saved them.
+type userOutbox struct {
+	mu      sync.Mutex
+	loaded  bool
+	seq     int64
+	pending []*entity.OutboxEntry
+	writing bool
+}
+
+var (
+	sharedOutbox     *OutboxService
+	sharedOutboxOnce sync.Once
+)
+
+// GetMessageHub returns the hub shared by everything that pushes real-time messages to connected users, its events
+// numbered by the shared outbox from the first one sent
+func GetMessageHub() *hub.Hub[hub.Message] {
+	return NewOutboxService().hub
+}
+
+func NewOutboxService() *OutboxService {
+	sharedOutboxOnce.Do(func() {
+		sharedOutbox = newOutboxService(persistence.NewOutboxRepository(), hub.GetMessageHub(), newOutboxState())
+	})
+	return sharedOutbox
+}
+
+func NewOutboxServiceWithRepo(outboxRepo persistence.OutboxRepositoryInterface) *OutboxService {
+	return newOutboxService(outboxRepo, hub.NewHub[hub.Message](), newOutboxState())
+}
+
+func newOutboxService(outboxRepo persistence.OutboxRepositoryInterface, messageHub *hub.Hub[hub.Message], state *outboxState) *OutboxService {
+	obs := &OutboxService{
+		outboxRepo: outboxRepo,
+		hub:        messageHub,
+		state:      state,
+	}
+	messageHub.OnSend(obs.record)
+	return obs
+}
+
+func newOutboxState() *outboxState {
+	return &outboxState{
+		boxes: make(map[string]*userOutbox),
+	}
+}
+
+// Connect registers the connection, which first gets the user's events after since, then a connected event, and only
+// then the events sent after them. With since 0 nothing is replayed.
+func (obs *OutboxService) Connect(userID string, since int64, conn *websocket.Conn) {
+	obs.hub.RegisterWithBacklog(hub.NewClient[hub.Message](userID, conn), func() ([]hub.Message, func(hub.Message) bool, error) {
+		return obs.replay(userID, since)
+	})
+}
+
+// replay returns the events of a registered connection after since, and the connected event. The events sent meanwhile
+// that are numbered up to the connected event's sequence number are already replayed or older than since.
+func (obs *OutboxService) replay(userID string, since int64) ([]hub.Message, func(hub.Message) bool, error) {
+	box := obs.state.get(userID)
+	box.mu.Lock()
+	if err := obs.load(userID, box); err != nil {
+		box.mu.Unlock()
+		return nil, nil, err
+	}
+	seq := box.seq
+	pending := slices.Clone(box.pending)
+	box.mu.Unlock()
+
+	var msgs []hub.Message
+	connected := dto.ConnectedEvent{Seq: seq}
+	if since > 0 && since < seq {
+		entries, err := obs.getEntries(userID, since, seq, pending)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, entry := range entries {
+			msgs = append(msgs, hub.Message{Type: hub.MessageType(entry.Type), Payload: entry.Payload, Seq: entry.Seq})
+		}
+		connected.Replayed = len(entries)
+		connected.Truncated = len(entries) == 0 || entries[0].Seq > since+1
+	} else if since > seq {
+		// The client saw events this outbox does not have
+		connected.Truncated = true
+	}
+	msgs = append(msgs, *hub.NewMessage(hub.Connected, connected))
+
+	seen := func(msg hub.Message) bool {
+		return msg.Seq > 0 && msg.Seq <= seq
+	}
+	return msgs, seen, nil
+}
+
+// getEntries returns the user's entries numbered after since and up to seq, saved or still pending, in order
+func (obs *OutboxService) getEntries(userID string, since, seq int64, pending []*entity.OutboxEntry) ([]*entity.OutboxEntry, error) {
+	saved, err := obs.outboxRepo.GetAfter(userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	bySeq := make(map[int64]*entity.OutboxEntry, len(saved)+len(pending))
+	for _, entry := range slices.Concat(saved, pending) {
+		if entry.Seq > since && entry.Seq <= seq {
+			bySeq[entry.Seq] = entry
+		}
+	}
+	entries := slices.Collect(maps.Values(bySeq))
+	slices.SortFunc(entries, func(a, b *entity.OutboxEntry) int {
+		return cmp.Compare(a.Seq, b.Seq)
+	})
+	return entries, nil
+}
+
+// record gives the event the user's next sequence number and queues it for the user's outbox. Ephemeral events, and
+// events of outboxes that could not be loaded, are sent without a number.
+func (obs *OutboxService) record(userID string, msg hub.Message) hub.Message {
+	if msg.Type.IsEphemeral() {
+		return msg
+	}
+
+	box := obs.state.get(userID)
+	box.mu.Lock()
+	defer box.mu.Unlock()
+
+	if err := obs.load(userID, box); err != nil {
+		log.Printf("outbox: loading the outbox of %s: %v", userID, err)
+		return msg
+	}
+	payload, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return msg
+	}
+	entry := entity.NewOutboxEntry(userID, box.seq+1, string(msg.Type), payload)
+	box.seq = entry.Seq
+	box.pending = append(box.pending, entry)
+	// Events older than the kept ones are not worth waiting for a slow database
+	if len(box.pending) > maxOutboxEntries {
+		box.pending = box.pending[len(box.pending)-maxOutboxEntries:]
+	}
+	if !box.writing {
+		box.writing = true
+		go obs.write(userID, box)
+	}
+
+	msg.Seq = entry.Seq
+	return msg
+}
+
+// write saves the outbox's pending entries, all those queued meanwhile in one update, until none is left
+func (obs *OutboxService) write(userID string, box *userOutbox) {
+	for {
+		box.mu.Lock()
+		if len(box.pending) == 0 {
+			box.writing = false
+			box.mu.Unlock()
+			return
+		}
+		entries := slices.Clone(box.pending)
+		box.mu.Unlock()
+
+		first, last := entries[0].Seq, entries[len(entries)-1].Seq
+		if err := obs.outboxRepo.SaveAll(userID, entries); err != nil {
+			log.Printf("outbox: saving events %d to %d of %s: %v", first, last, userID, err)
+		}
+		if last/outboxTrimInterval > (first-1)/outboxTrimInterval && last > maxOutboxEntries {
+			if err := obs.outboxRepo.DeleteUpTo(userID, last-maxOutboxEntries); err != nil {
+				log.Printf("outbox: trimming the outbox of %s: %v", userID, err)
+			}
+		}
+
+		box.mu.Lock()
+		box.pending = slices.DeleteFunc(box.pending, func(entry *entity.OutboxEntry) bool {
+			return entry.Seq <= last
+		})
+		box.mu.Unlock()
+	}
+}
+
+// load reads the user's latest sequence number the first time the outbox is used; the caller holds the box's lock
+func (obs *OutboxService) load(userID string, box *userOutbox) error {
+	if box.loaded {
+		return nil
+	}
+	last, err := obs.outboxRepo.GetLast(userID)
+	if err != nil {
+		return err
+	}
+	if last != nil {
+		box.seq = last.Seq
+	}
+	box.loaded = true
+	return nil
+}
+
+func (state *outboxState) get(userID string) *userOutbox {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	box, ok := state.boxes[userID]
+	if !ok {
+		box = &userOutbox{}
+		state.boxes[userID] = box
+	}
+	return box
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+)
+
+// typingTimeout is how long a user stays typing after their last typing command
+const typingTimeout = 6 * time.Second
+
+type PresenceServiceInterface interface {
+	GetContactsPresence(userID string) ([]*dto.PresenceDTO, error)
+}
+
+// PresenceService tracks who is connected to the message hub and who is typing where. Users are online while they
+// have a connection, from any of their devices, and their contacts, their friends and the members of their teams, get a presence event whenever
+// their status changes.
+type PresenceService struct {
+	userRepo          UserRepositoryInterface
+	teamRepo          TeamRepositoryInterface
+	friendRequestRepo FriendRequestRepositoryInterface
+	presenceRepo      persistence.PresenceRepositoryInterface
+	hub               *hub.Hub[hub.Message]
+	state             *presenceState
+}
+
+// presenceState is what only lives in memory: the connections of each user, the connected users who are away and the
+// users typing
+type presenceState struct {
+	mu          sync.Mutex
+	connections map[string]int
+	away        map[string]bool
+	typing      map[string]*typingIndicator // by conversation and user
+}
+
+type typingIndicator struct {
+	timer      *time.Timer
+	recipients []string
+	event      dto.TypingEvent
+}
+
+var (
+	sharedPresenceState     *presenceState
+	sharedPresenceStateOnce sync.Once
+)
+
+func NewPresenceService() *PresenceService {
+	sharedPresenceStateOnce.Do(func() {
+		sharedPresenceState = newPresenceState()
+	})
+	return newPresenceService(persistence.NewUserRepository(), persistence.NewTeamRepository(), persistence.NewFriendRequestRepository(), persistence.NewPresenceRepository(), GetMessageHub(), sharedPresenceState)
+}
+
+func NewPresenceServiceWithRepo(userRepo UserRepositoryInterface, teamRepo TeamRepositoryInterface, friendRequestRepo FriendRequestRepositoryInterface, presenceRepo persistence.PresenceRepositoryInterface) *PresenceService {
+	return newPresenceService(userRepo, teamRepo, friendRequestRepo, presenceRepo, hub.NewHub[hub.Message](), newPresenceState())
+}
+
+func newPresenceService(userRepo UserRepositoryInterface, teamRepo TeamRepositoryInterface, friendRequestRepo FriendRequestRepositoryInterface, presenceRepo persistence.PresenceRepositoryInterface, messageHub *hub.Hub[hub.Message], state *presenceState) *PresenceService {
+	ps := &PresenceService{
+		userRepo:          userRepo,
+		teamRepo:          teamRepo,
+		friendRequestRepo: friendRequestRepo,
+		presenceRepo:      presenceRepo,
+		hub:               messageHub,
+		state:             state,
+	}
+	messageHub.OnConnect(ps.connect)
+	messageHub.OnDisconnect(ps.disconnect)
+	return ps
+}
+
+func newPresenceState() *presenceState {
+	return &presenceState{
+		connections: make(map[string]int),
+		away:        make(map[string]bool),
+		typing:      make(map[string]*typingIndicator),
+	}
+}
+
+// GetContactsPresence returns the presence of the user's friends and teammates
+func (ps *PresenceService) GetContactsPresence(userID string) ([]*dto.PresenceDTO, error) {
+	contacts, err := ps.getContacts(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	presences := make([]*dto.PresenceDTO, 0, len(contacts))
+	for _, contactID := range contacts {
+		presence, err := ps.getPresence(contactID)
+		if err != nil {
+			return nil, err
+		}
+		presences = append(presences, presence)
+	}
+	return presences, nil
+}
+
+// SetStatus switches a connected user between online and away
+func (ps *PresenceService) SetStatus(userID, status string) {
+	ps.state.mu.Lock()
+	changed := ps.state.away[userID] != (status == entity.PresenceAway)
+	if status == entity.PresenceAway {
+		ps.state.away[userID] = true
+	} else {
+		delete(ps.state.away, userID)
+	}
+	ps.state.mu.Unlock()
+
+	if changed {
+		ps.broadcast(&dto.PresenceDTO{UserID: userID, Status: status, LastSeenAt: time.Now().Unix()})
+	}
+}
+
+// SetTyping starts or stops the user's typing indicator in the conversation. The recipients only get an event when
+// the user starts or stops typing; typing commands in between just keep the indicator from expiring.
+func (ps *PresenceService) SetTyping(conversationID string, recipients []string, event dto.TypingEvent, typing bool) {
+	key := conversationID + "/" + event.UserID
+
+	ps.state.mu.Lock()
+	defer ps.state.mu.Unlock()
+
+	indicator, ok := ps.state.typing[key]
+	if !typing {
+		if ok {
+			ps.stopTyping(key, indicator)
+		}
+		return
+	}
+	if ok && indicator.timer.Reset(typingTimeout) {
+		return
+	}
+
+	indicator = &typingIndicator{recipients: recipients, event: event}
+	indicator.event.Typing = true
+	indicator.timer = time.AfterFunc(typingTimeout, func() {
+		ps.state.mu.Lock()
+		defer ps.state.mu.Unlock()
+		if ps.state.typing[key] == indicator {
+			ps.stopTyping(key, indicator)
+		}
+	})
+	ps.state.typing[key] = indicator
+	ps.hub.SendMany(recipients, *hub.NewMessage(hub.Typing, indicator.event))
+}
+
+// stopTyping removes the indicator and tells its recipients; the caller holds the state lock
+func (ps *PresenceService) stopTyping(key string, indicator *typingIndicator) {
+	indicator.timer.Stop()
+	delete(ps.state.typing, key)
+	indicator.event.Typing = false
+	ps.hub.SendMany(indicator.recipients, *hub.NewMessage(hub.Typing, indicator.event))
+}
+
+// connect tells the user's contacts that the user is online, when this is the user's first connection or the user
+// was away
+func (ps *PresenceService) connect(client *hub.Client[hub.Message]) {
+	ps.state.mu.Lock()
+	ps.state.connections[client.ClientID]++
+	changed := ps.state.connections[client.ClientID] == 1 || ps.state.away[client.ClientID]
+	delete(ps.state.away, client.ClientID)
+	ps.state.mu.Unlock()
+
+	if changed {
+		ps.broadcast(&dto.PresenceDTO{UserID: client.ClientID, Status: entity.PresenceOnline, LastSeenAt: time.Now().Unix()})
+	}
+}
+
+// disconnect stops the user's typing indicators, keeps when the user was last seen and tells the contacts, once the
+// user's last connection is closed
+func (ps *PresenceService) disconnect(client *hub.Client[hub.Message]) {
+	userID := client.ClientID
+
+	ps.state.mu.Lock()
+	ps.state.connections[userID]--
+	if ps.state.connections[userID] > 0 {
+		ps.state.mu.Unlock()
+		return
+	}
+	delete(ps.state.connections, userID)
+	delete(ps.state.away, userID)
+	for key, indicator := range ps.state.typing {
+		if indicator.event.UserID == userID {
+			ps.stopTyping(key, indicator)
+		}
+	}
+	ps.state.mu.Unlock()
+
+	presence := entity.NewPresence(userID, time.Now().Unix())
+	_ = ps.presenceRepo.Save(presence)
+	ps.broadcast(&dto.PresenceDTO{UserID: userID, Status: entity.PresenceOffline, LastSeenAt: presence.LastSeenAt})
+}
+
+// broadcast sends the presence to the user's contacts; it is best effort
+func (ps *PresenceService) broadcast(presence *dto.PresenceDTO) {
+	contacts, err := ps.getContacts(presence.UserID)
+	if err != nil {
+		return
+	}
+	ps.hub.SendMany(contacts, *hub.NewMessage(hub.Presence, presence))
+}
+
+func (ps *PresenceService) getPresence(userID string) (*dto.PresenceDTO, error) {
+	if ps.hub.IsConnected(userID) {
+		status := entity.PresenceOnline
+		ps.state.mu.Lock()
+		if ps.state.away[userID] {
+			status = entity.PresenceAway
+		}
+		ps.state.mu.Unlock()
+		return &dto.PresenceDTO{UserID: userID, Status: status, LastSeenAt: time.Now().Unix()}, nil
+	}
+
+	presence := &dto.PresenceDTO{UserID: userID, Status: entity.PresenceOffline}
+	lastSeen, err := ps.presenceRepo.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+	if lastSeen != nil {
+		presence.LastSeenAt = lastSeen.LastSeenAt
+	}
+	return presence, nil
+}
+
+// getContacts returns the users who see the user's presence: the user's friends and the members of the user's teams
+func (ps *PresenceService) getContacts(userID string) ([]string, error) {
+	user, err := ps.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+	contacts, err := ps.friendRequestRepo.GetFriendsForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	contacts = slices.Clone(contacts)
+	if user.TeamsIds != nil {
+		for _, teamID := range *user.TeamsIds {
+			team, err := ps.teamRepo.GetTeamById(teamID)
+			if err != nil {
+				continue
+			}
+			contacts = append(contacts, team.UsersIds...)
+		}
+	}
+
+	sort.Strings(contacts)
+	return removeString(slices.Compact(contacts), userID), nil
+}
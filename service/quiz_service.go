@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/SerbanEduard/ProiectColectivBackEnd/mappers"
@@ -18,6 +19,7 @@ import (
 var (
 	ErrResourceNotFound = errors.New("resource not found")
 	ErrForbidden        = errors.New("forbidden")
+	ErrConflict         = errors.New("conflict")
 )
 
 const (
@@ -30,35 +32,49 @@ const (
 
 type QuizServiceInterface interface {
 	CreateQuiz(request entity.Quiz) (dto.CreateQuizResponse, error)
-	GetQuizWithAnswersById(id string) (entity.Quiz, error)
-	GetQuizWithoutAnswersById(id string) (dto.ReadQuizResponse, error)
+	GetQuizWithAnswersById(viewerID, id string) (entity.Quiz, error)
+	GetQuizWithoutAnswersById(viewerID, id string) (dto.ReadQuizResponse, error)
 	SolveQuiz(request dto.SolveQuizRequest, userId string, quizId string) (dto.SolveQuizResponse, error)
 	GetQuizzesByUserAndTeam(userId string, teamId string, pageSize int, lastKey string) ([]dto.ReadQuizResponse, string, error)
 	GetQuizzesByTeam(userId string, teamId string, pageSize int, lastKey string) ([]dto.ReadQuizResponse, string, error)
 }
 
 type QuizService struct {
-	teamRepo TeamRepositoryInterface
-	userRepo UserRepositoryInterface
-	quizRepo persistence.QuizRepositoryInterface
+	teamRepo      TeamRepositoryInterface
+	userRepo      UserRepositoryInterface
+	quizRepo      persistence.QuizRepositoryInterface
+	searchIndexer SearchIndexer
+	eventRecorder EventRecorder
 }
 
 func NewQuizService() *QuizService {
 	return &QuizService{
-		teamRepo: persistence.NewTeamRepository(),
-		userRepo: persistence.NewUserRepository(),
-		quizRepo: persistence.NewQuizRepository(),
+		teamRepo:      persistence.NewTeamRepository(),
+		userRepo:      persistence.NewUserRepository(),
+		quizRepo:      persistence.NewQuizRepository(),
+		searchIndexer: NewSearchService(),
+		eventRecorder: NewTeamEventService(),
 	}
 }
 
 func NewQuizServiceWithRepo(teamRepo TeamRepositoryInterface, userRepo UserRepositoryInterface, quizRepo persistence.QuizRepositoryInterface) *QuizService {
 	return &QuizService{
-		teamRepo: teamRepo,
-		userRepo: userRepo,
-		quizRepo: quizRepo,
+		teamRepo:      teamRepo,
+		userRepo:      userRepo,
+		quizRepo:      quizRepo,
+		searchIndexer: noopSearchIndexer{},
+		eventRecorder: noopEventRecorder{},
 	}
 }
 
+func (qs *QuizService) SetSearchIndexer(indexer SearchIndexer) {
+	qs.searchIndexer = indexer
+}
+
+func (qs *QuizService) SetEventRecorder(recorder EventRecorder) {
+	qs.eventRecorder = recorder
+}
+
 func (qs *QuizService) isUserInTeam(userId string, teamId string) (bool, error) {
 	user, err := qs.userRepo.GetByID(userId)
 	if err != nil {
@@ -100,6 +116,9 @@ func (qs *QuizService) CreateQuiz(request entity.Quiz) (dto.CreateQuizResponse,
 	} else if !isPartOf {
 		return dto.CreateQuizResponse{}, fmt.Errorf("%w: %s", ErrForbidden, userNotInTeam)
 	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return dto.CreateQuizResponse{}, err
+	}
 
 	for i := range request.Questions {
 		questionID, err := utils.GenerateID()
@@ -120,41 +139,49 @@ func (qs *QuizService) CreateQuiz(request entity.Quiz) (dto.CreateQuizResponse,
 	if err != nil {
 		return dto.CreateQuizResponse{}, err
 	}
+	qs.searchIndexer.IndexQuiz(request)
+	qs.eventRecorder.Record(entity.NewTeamEvent(request.TeamID, entity.TeamEventQuizCreated, request.UserID, id, request.QuizName))
 
 	return dto.NewCreateQuizResponse(id), nil
 }
 
-func (qs *QuizService) GetQuizWithAnswersById(id string) (entity.Quiz, error) {
-	if err := validator.ValidateQuizId(id); err != nil {
-		return entity.Quiz{}, err
-	}
+func (qs *QuizService) GetQuizWithAnswersById(viewerID, id string) (entity.Quiz, error) {
+	return qs.getVisibleQuiz(viewerID, id)
+}
 
-	quiz, err := qs.quizRepo.GetById(id)
+func (qs *QuizService) GetQuizWithoutAnswersById(viewerID, id string) (dto.ReadQuizResponse, error) {
+	quiz, err := qs.getVisibleQuiz(viewerID, id)
 	if err != nil {
-		if strings.Contains(err.Error(), NotFoundError) {
-			return entity.Quiz{}, fmt.Errorf("%w: %s", ErrResourceNotFound, quizNotFound)
-		}
-		return entity.Quiz{}, err
+		return dto.ReadQuizResponse{}, err
 	}
 
-	return quiz, nil
+	quizWithoutAnswers := mappers.MapDomainToReadDTO(quiz)
+	return quizWithoutAnswers, nil
 }
 
-func (qs *QuizService) GetQuizWithoutAnswersById(id string) (dto.ReadQuizResponse, error) {
+// getVisibleQuiz returns the quiz if its team belongs to the viewer's organization
+func (qs *QuizService) getVisibleQuiz(viewerID, id string) (entity.Quiz, error) {
 	if err := validator.ValidateQuizId(id); err != nil {
-		return dto.ReadQuizResponse{}, err
+		return entity.Quiz{}, err
 	}
 
 	quiz, err := qs.quizRepo.GetById(id)
 	if err != nil {
 		if strings.Contains(err.Error(), NotFoundError) {
-			return dto.ReadQuizResponse{}, fmt.Errorf("%w: %s", ErrResourceNotFound, quizNotFound)
+			return entity.Quiz{}, fmt.Errorf("%w: %s", ErrResourceNotFound, quizNotFound)
 		}
-		return dto.ReadQuizResponse{}, err
+		return entity.Quiz{}, err
 	}
 
-	quizWithoutAnswers := mappers.MapDomainToReadDTO(quiz)
-	return quizWithoutAnswers, nil
+	viewer, err := qs.userRepo.GetByID(viewerID)
+	if err != nil {
+		return entity.Quiz{}, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+	team, err := qs.teamRepo.GetTeamById(quiz.TeamID)
+	if err != nil || !sameOrganization(viewer, team.OrganizationID) {
+		return entity.Quiz{}, fmt.Errorf("%w: %s", ErrResourceNotFound, quizNotFound)
+	}
+	return quiz, nil
 }
 
 func (qs *QuizService) SolveQuiz(request dto.SolveQuizRequest, userId string, quizId string) (dto.SolveQuizResponse, error) {
@@ -197,6 +224,7 @@ func (qs *QuizService) SolveQuiz(request dto.SolveQuizRequest, userId string, qu
 	}
 
 	allCorrect := true
+	correctCount := 0
 	questionResponses := make([]dto.SolveQuestionResponse, len(questions))
 
 	for i, question := range questions {
@@ -208,10 +236,16 @@ func (qs *QuizService) SolveQuiz(request dto.SolveQuizRequest, userId string, qu
 		isCorrect := slices.Equal(correctFields, submittedFields)
 		if !isCorrect {
 			allCorrect = false
+		} else {
+			correctCount++
 		}
 		questionResponses[i] = dto.NewSolveQuestionResponse(question.ID, isCorrect, correctFields)
 	}
 
+	event := entity.NewTeamEvent(quiz.TeamID, entity.TeamEventQuizCompleted, userId, quiz.ID, quiz.QuizName)
+	event.Details = map[string]string{"correct": strconv.Itoa(correctCount), "total": strconv.Itoa(len(questions))}
+	qs.eventRecorder.Record(event)
+
 	return dto.SolveQuizResponse{
 		IsCorrect:         allCorrect,
 		QuestionResponses: questionResponses,
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+)
+
+const repliesCanNotBeMarkedRead = "thread replies can not be marked as read, mark a message of the conversation instead"
+
+// ReadTracker is told about new messages, so senders have read their own messages
+type ReadTracker interface {
+	TrackMessage(message *entity.Message)
+}
+
+type ReadServiceInterface interface {
+	MarkRead(userID, messageID string) (*dto.ReadMarkerDTO, error)
+	GetUnreadCounts(userID string) (*dto.UnreadCountsResponse, error)
+	GetReadReceipt(userID, otherUserID string) (*dto.ReadMarkerDTO, error)
+}
+
+type ReadService struct {
+	userRepo         UserRepositoryInterface
+	teamRepo         TeamRepositoryInterface
+	channelRepo      persistence.ChannelRepositoryInterface
+	messageRepo      persistence.MessageRepositoryInterface
+	readMarkerRepo   persistence.ReadMarkerRepositoryInterface
+	conversationRepo persistence.ConversationRepositoryInterface
+	hub              *hub.Hub[hub.Message]
+}
+
+func NewReadService() *ReadService {
+	return &ReadService{
+		userRepo:         persistence.NewUserRepository(),
+		teamRepo:         persistence.NewTeamRepository(),
+		channelRepo:      persistence.NewChannelRepository(),
+		messageRepo:      persistence.NewMessageRepository(),
+		readMarkerRepo:   persistence.NewReadMarkerRepository(),
+		conversationRepo: persistence.NewConversationRepository(),
+		hub:              GetMessageHub(),
+	}
+}
+
+func NewReadServiceWithRepo(userRepo UserRepositoryInterface, teamRepo TeamRepositoryInterface, channelRepo persistence.ChannelRepositoryInterface, messageRepo persistence.MessageRepositoryInterface, readMarkerRepo persistence.ReadMarkerRepositoryInterface, conversationRepo persistence.ConversationRepositoryInterface) *ReadService {
+	return &ReadService{
+		userRepo:         userRepo,
+		teamRepo:         teamRepo,
+		channelRepo:      channelRepo,
+		messageRepo:      messageRepo,
+		readMarkerRepo:   readMarkerRepo,
+		conversationRepo: conversationRepo,
+		hub:              hub.NewHub[hub.Message](),
+	}
+}
+
+// MarkRead marks the message's conversation as read up to the message; markers only move forward. Direct
+// conversations get a message_read event, so the sender can show the message as seen.
+func (rs *ReadService) MarkRead(userID, messageID string) (*dto.ReadMarkerDTO, error) {
+	message, _, err := getMessageForParticipant(rs.messageRepo, rs.teamRepo, userID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.IsReply() {
+		return nil, fmt.Errorf("%w: %s", validator.ErrValidation, repliesCanNotBeMarkedRead)
+	}
+
+	marker, err := rs.getMarker(userID, message.ConversationID())
+	if err != nil {
+		return nil, err
+	}
+	if marker.HasRead(message) {
+		return dto.NewReadMarkerDTO(marker), nil
+	}
+
+	marker.MessageID = message.ID
+	marker.SentAt = message.SentAt
+	marker.ReadAt = time.Now().Unix()
+	if err := rs.readMarkerRepo.Save(marker); err != nil {
+		return nil, err
+	}
+	if err := rs.updateUnreadCount(marker, message); err != nil {
+		return nil, err
+	}
+
+	markerDTO := dto.NewReadMarkerDTO(marker)
+	if message.ConversationKey != "" {
+		rs.hub.SendMany(messageParticipants(message, nil), *hub.NewMessage(hub.MessageRead, markerDTO))
+	}
+	return markerDTO, nil
+}
+
+// GetUnreadCounts returns the unread counts of the user's conversation index: the direct conversations and the channels
+// of the user's teams that have messages
+func (rs *ReadService) GetUnreadCounts(userID string) (*dto.UnreadCountsResponse, error) {
+	user, err := rs.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+	entries, err := rs.conversationRepo.GetByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	markers, err := rs.readMarkerRepo.GetByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	lastRead := make(map[string]string, len(markers))
+	for _, marker := range markers {
+		lastRead[marker.ConversationID] = marker.MessageID
+	}
+
+	response := &dto.UnreadCountsResponse{Conversations: []dto.UnreadCountDTO{}}
+	for _, entry := range entries {
+		if entry.Type == entity.ConversationTypeChannel && (user.TeamsIds == nil || !slices.Contains(*user.TeamsIds, entry.TeamID)) {
+			continue
+		}
+		response.TotalUnread += entry.UnreadCount
+		response.Conversations = append(response.Conversations, dto.UnreadCountDTO{
+			ConversationID:    entry.ConversationID,
+			Type:              entry.Type,
+			UserID:            entry.OtherUserID,
+			TeamID:            entry.TeamID,
+			ChannelID:         entry.ChannelID,
+			UnreadCount:       entry.UnreadCount,
+			LastReadMessageID: lastRead[entry.ConversationID],
+		})
+	}
+
+	sort.Slice(response.Conversations, func(i, j int) bool {
+		return response.Conversations[i].ConversationID < response.Conversations[j].ConversationID
+	})
+	return response, nil
+}
+
+// GetReadReceipt returns how far the other user has read the direct conversation with the user
+func (rs *ReadService) GetReadReceipt(userID, otherUserID string) (*dto.ReadMarkerDTO, error) {
+	if _, err := rs.userRepo.GetByID(otherUserID); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+
+	marker, err := rs.getMarker(otherUserID, entity.DirectConversationID(entity.GetConversationKey(userID, otherUserID)))
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewReadMarkerDTO(marker), nil
+}
+
+// TrackMessage moves the sender's marker to their message. It is best effort, like search indexing.
+func (rs *ReadService) TrackMessage(message *entity.Message) {
+	if message.IsReply() {
+		return
+	}
+
+	marker := entity.NewReadMarker(message.SenderID, message.ConversationID())
+	marker.MessageID = message.ID
+	marker.SentAt = message.SentAt
+	marker.ReadAt = time.Now().Unix()
+	_ = rs.readMarkerRepo.Save(marker)
+}
+
+// updateUnreadCount recounts the unread messages of the user's conversation index entry after the marker moved. Only
+// the count is written, and only when no message was indexed meanwhile.
+func (rs *ReadService) updateUnreadCount(marker *entity.ReadMarker, message *entity.Message) error {
+	entry, err := rs.conversationRepo.Get(marker.UserID, marker.ConversationID)
+	if err != nil || entry == nil {
+		return err
+	}
+
+	lastMessageID := entry.LastMessageID
+	unread := 0
+	if lastMessageID != message.ID {
+		messages, err := getConversationMessages(rs.messageRepo, message)
+		if err != nil {
+			return err
+		}
+		unread = countUnread(messages, marker)
+	}
+	return rs.conversationRepo.Update(marker.UserID, marker.ConversationID, func(entry *entity.ConversationEntry) *entity.ConversationEntry {
+		// A message indexed meanwhile was counted on top of the old count and is not read yet
+		if entry == nil || entry.LastMessageID != lastMessageID {
+			return nil
+		}
+		entry.UnreadCount = unread
+		return entry
+	})
+}
+
+// getMarker returns the user's marker in the conversation, an empty one when the user has not read anything yet
+func (rs *ReadService) getMarker(userID, conversationID string) (*entity.ReadMarker, error) {
+	marker, err := rs.readMarkerRepo.Get(userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if marker == nil {
+		return entity.NewReadMarker(userID, conversationID), nil
+	}
+	return marker, nil
+}
+
+// countUnread counts the conversation's messages after the marker, without thread replies, deleted messages and the
+// user's own messages
+func countUnread(messages []*entity.Message, marker *entity.ReadMarker) int {
+	unread := 0
+	for _, message := range messages {
+		if message.IsReply() || message.IsDeleted() || message.SenderID == marker.UserID || marker.HasRead(message) {
+			continue
+		}
+		unread++
+	}
+	return unread
+}
+
+type noopReadTracker struct{}
+
+func (noopReadTracker) TrackMessage(*entity.Message) {}
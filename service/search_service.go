@@ -0,0 +1,357 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/mappers"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+)
+
+const (
+	// exactMatchBoost multiplies the weight of a posting whose token equals the query word (instead of only starting with it)
+	exactMatchBoost = 2
+	// minSearchPrefixLength is the length from which query words also match the tokens they start; shorter words only
+	// match themselves
+	minSearchPrefixLength = 3
+	// maxSearchPrefixTerms is how many indexed tokens a query word matches at most
+	maxSearchPrefixTerms = 50
+	// maxSearchPostingsPerWord is how many documents, the best weighted, a query word keeps
+	maxSearchPostingsPerWord = 500
+)
+
+// SearchIndexer keeps the search index up to date. Indexing failures are logged and never fail the write itself.
+type SearchIndexer interface {
+	IndexTeam(team *entity.Team)
+	IndexUser(user *entity.User)
+	IndexQuiz(quiz entity.Quiz)
+	IndexMessage(message *entity.Message)
+	RemoveFromIndex(docType, id string)
+}
+
+type SearchServiceInterface interface {
+	Search(userID, query string, types []string, page, limit int) (*dto.SearchResponse, error)
+}
+
+type SearchService struct {
+	searchRepo  persistence.SearchRepositoryInterface
+	userRepo    UserRepositoryInterface
+	teamRepo    TeamRepositoryInterface
+	quizRepo    persistence.QuizRepositoryInterface
+	messageRepo persistence.MessageRepositoryInterface
+}
+
+func NewSearchService() *SearchService {
+	return &SearchService{
+		searchRepo:  persistence.NewSearchRepository(),
+		userRepo:    persistence.NewUserRepository(),
+		teamRepo:    persistence.NewTeamRepository(),
+		quizRepo:    persistence.NewQuizRepository(),
+		messageRepo: persistence.NewMessageRepository(),
+	}
+}
+
+func NewSearchServiceWithRepo(searchRepo persistence.SearchRepositoryInterface, userRepo UserRepositoryInterface, teamRepo TeamRepositoryInterface, quizRepo persistence.QuizRepositoryInterface, messageRepo persistence.MessageRepositoryInterface) *SearchService {
+	return &SearchService{
+		searchRepo:  searchRepo,
+		userRepo:    userRepo,
+		teamRepo:    teamRepo,
+		quizRepo:    quizRepo,
+		messageRepo: messageRepo,
+	}
+}
+
+func (ss *SearchService) IndexTeam(team *entity.Team) {
+	ss.save(mappers.MapTeamToSearchDocument(team))
+}
+
+func (ss *SearchService) IndexUser(user *entity.User) {
+	ss.save(mappers.MapUserToSearchDocument(user))
+}
+
+func (ss *SearchService) IndexQuiz(quiz entity.Quiz) {
+	ss.save(mappers.MapQuizToSearchDocument(quiz))
+}
+
+func (ss *SearchService) IndexMessage(message *entity.Message) {
+	ss.save(mappers.MapMessageToSearchDocument(message))
+}
+
+func (ss *SearchService) RemoveFromIndex(docType, id string) {
+	if err := ss.searchRepo.Delete(entity.GetSearchDocumentKey(docType, id)); err != nil {
+		log.Printf("search: remove %s %s from index: %v", docType, id, err)
+	}
+}
+
+func (ss *SearchService) save(doc *entity.SearchDocument) {
+	if err := ss.searchRepo.Save(doc); err != nil {
+		log.Printf("search: index %s: %v", doc.Key, err)
+	}
+}
+
+// ReindexIfEmpty builds the index from the stored teams, users, quizzes and messages when it has never been built
+func (ss *SearchService) ReindexIfEmpty() error {
+	empty, err := ss.searchRepo.IsEmpty()
+	if err != nil || !empty {
+		return err
+	}
+
+	teams, err := ss.teamRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, team := range teams {
+		ss.IndexTeam(team)
+	}
+
+	users, err := ss.userRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		ss.IndexUser(user)
+	}
+
+	quizzes, err := ss.quizRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, quiz := range quizzes {
+		ss.IndexQuiz(quiz)
+	}
+
+	messages, err := ss.messageRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, message := range messages {
+		ss.IndexMessage(message)
+	}
+
+	log.Printf("search: indexed %d teams, %d users, %d quizzes and %d messages", len(teams), len(users), len(quizzes), len(messages))
+	return nil
+}
+
+// Search returns the documents matching every word of the query that the user is allowed to see.
+// The last words may be incomplete, so every word also matches the tokens it is a prefix of.
+func (ss *SearchService) Search(userID, query string, types []string, page, limit int) (*dto.SearchResponse, error) {
+	tokens := utils.Tokenize(query)
+	if err := validator.ValidateSearchQuery(tokens); err != nil {
+		return nil, err
+	}
+	if err := validator.ValidateSearchTypes(types); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	user, err := ss.userRepo.GetByID(userID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+		}
+		return nil, err
+	}
+
+	scores, err := ss.scoreDocuments(tokens, types)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(scores))
+	for key := range scores {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	docs, err := ss.searchRepo.GetByKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]dto.SearchResult, 0, len(docs))
+	for _, key := range keys {
+		doc, ok := docs[key]
+		if !ok {
+			// The posting outlived its document
+			continue
+		}
+		if !canSeeSearchDocument(user, doc) {
+			continue
+		}
+		results = append(results, dto.NewSearchResult(doc.Type, doc.ID, doc.Title, doc.Snippet, doc.TeamID, scores[key]))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Title != results[j].Title {
+			return results[i].Title < results[j].Title
+		}
+		return results[i].ID < results[j].ID
+	})
+
+	totalCount := len(results)
+	totalPages := (totalCount + limit - 1) / limit
+
+	start := (page - 1) * limit
+	end := start + limit
+	if start > totalCount {
+		start = totalCount
+	}
+	if end > totalCount {
+		end = totalCount
+	}
+
+	return &dto.SearchResponse{
+		Query:      query,
+		Results:    results[start:end],
+		Page:       page,
+		Limit:      limit,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// scoreDocuments sums, per document, the best posting weight of every query word and drops documents missing a word
+func (ss *SearchService) scoreDocuments(tokens []string, types []string) (map[string]int, error) {
+	var scores map[string]int
+	for _, token := range tokens {
+		postings, err := ss.getPostings(token)
+		if err != nil {
+			return nil, err
+		}
+
+		tokenScores := make(map[string]int)
+		for term, docs := range postings {
+			for key, weight := range docs {
+				if !hasSearchType(key, types) {
+					continue
+				}
+				if term == token {
+					weight *= exactMatchBoost
+				}
+				if weight > tokenScores[key] {
+					tokenScores[key] = weight
+				}
+			}
+		}
+
+		keepBestPostings(tokenScores, maxSearchPostingsPerWord)
+
+		if scores == nil {
+			scores = tokenScores
+			continue
+		}
+		for key := range scores {
+			if score, ok := tokenScores[key]; ok {
+				scores[key] += score
+			} else {
+				delete(scores, key)
+			}
+		}
+	}
+	return scores, nil
+}
+
+// getPostings returns the postings of the tokens the query word matches, by token
+func (ss *SearchService) getPostings(token string) (map[string]map[string]int, error) {
+	if utf8.RuneCountInString(token) >= minSearchPrefixLength {
+		return ss.searchRepo.GetPostingsByPrefix(token, maxSearchPrefixTerms)
+	}
+	postings, err := ss.searchRepo.GetPostings(token)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]map[string]int{token: postings}, nil
+}
+
+// keepBestPostings removes all but the limit documents with the highest weights
+func keepBestPostings(weights map[string]int, limit int) {
+	if len(weights) <= limit {
+		return
+	}
+	keys := make([]string, 0, len(weights))
+	for key := range weights {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if weights[keys[i]] != weights[keys[j]] {
+			return weights[keys[i]] > weights[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys[limit:] {
+		delete(weights, key)
+	}
+}
+
+func hasSearchType(key string, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if strings.HasPrefix(key, t+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// canSeeSearchDocument hides private teams, team quizzes and team messages from non-members and direct messages from non-participants
+func canSeeSearchDocument(user *entity.User, doc *entity.SearchDocument) bool {
+	switch doc.Type {
+	case entity.SearchTypeUser:
+		return sameOrganization(user, doc.OrganizationID)
+	case entity.SearchTypeTeam:
+		return (doc.IsPublic && sameOrganization(user, doc.OrganizationID)) || isMemberOfTeam(user, doc.TeamID)
+	case entity.SearchTypeQuiz:
+		return isMemberOfTeam(user, doc.TeamID)
+	case entity.SearchTypeMessage:
+		if doc.TeamID != "" {
+			return isMemberOfTeam(user, doc.TeamID)
+		}
+		for _, id := range doc.MemberIDs {
+			if id == user.ID {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func isMemberOfTeam(user *entity.User, teamID string) bool {
+	if user.TeamsIds == nil {
+		return false
+	}
+	for _, id := range *user.TeamsIds {
+		if id == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+type noopSearchIndexer struct{}
+
+func (noopSearchIndexer) IndexTeam(*entity.Team)         {}
+func (noopSearchIndexer) IndexUser(*entity.User)         {}
+func (noopSearchIndexer) IndexQuiz(entity.Quiz)          {}
+func (noopSearchIndexer) IndexMessage(*entity.Message)   {}
+func (noopSearchIndexer) RemoveFromIndex(string, string) {}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+)
+
+const (
+	studySessionNotFound      = "study session not found"
+	onlyCreatorOrOwnerSession = "only the session creator or the team owner can change the session"
+
+	defaultReminderMinutes = 15
+	// conflictHorizon limits how far ahead recurring sessions are compared
+	conflictHorizon = 90 * 24 * time.Hour
+)
+
+type StudySessionServiceInterface interface {
+	CreateSession(userID, teamID string, request *dto.StudySessionRequest) (*dto.StudySessionResponse, error)
+	GetTeamSessions(userID, teamID string) ([]*entity.StudySession, error)
+	GetSession(userID, sessionID string) (*dto.StudySessionResponse, error)
+	UpdateSession(userID, sessionID string, request *dto.StudySessionRequest) (*dto.StudySessionResponse, error)
+	DeleteSession(userID, sessionID string) error
+	RSVP(userID, sessionID string, request *dto.RSVPRequest) (*dto.StudySessionResponse, error)
+	GetUserAgenda(userID string, from, to time.Time) ([]dto.SessionOccurrence, error)
+}
+
+type StudySessionService struct {
+	sessionRepo   persistence.StudySessionRepositoryInterface
+	teamRepo      TeamRepositoryInterface
+	userRepo      UserRepositoryInterface
+	eventRecorder EventRecorder
+	hub           *hub.Hub[hub.Message]
+}
+
+func NewStudySessionService() *StudySessionService {
+	return &StudySessionService{
+		sessionRepo:   persistence.NewStudySessionRepository(),
+		teamRepo:      persistence.NewTeamRepository(),
+		userRepo:      persistence.NewUserRepository(),
+		eventRecorder: NewTeamEventService(),
+		hub:           GetMessageHub(),
+	}
+}
+
+func NewStudySessionServiceWithRepo(sessionRepo persistence.StudySessionRepositoryInterface, teamRepo TeamRepositoryInterface, userRepo UserRepositoryInterface) *StudySessionService {
+	return &StudySessionService{
+		sessionRepo:   sessionRepo,
+		teamRepo:      teamRepo,
+		userRepo:      userRepo,
+		eventRecorder: noopEventRecorder{},
+		hub:           hub.NewHub[hub.Message](),
+	}
+}
+
+func (ss *StudySessionService) SetEventRecorder(recorder EventRecorder) {
+	ss.eventRecorder = recorder
+}
+
+func (ss *StudySessionService) CreateSession(userID, teamID string, request *dto.StudySessionRequest) (*dto.StudySessionResponse, error) {
+	if err := validator.ValidateStudySessionRequest(request); err != nil {
+		return nil, err
+	}
+
+	team, err := getTeamForMember(ss.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, err
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+
+	session := entity.NewStudySession(id, teamID, strings.TrimSpace(request.Title), request.Description,
+		request.StartAt.UTC(), request.EndAt.UTC(), request.Timezone, request.Recurrence, reminderMinutes(request), userID)
+	if request.LinkVoiceRoom {
+		// Team voice rooms are keyed by the team ID
+		session.VoiceRoomID = teamID
+	}
+
+	if err := ss.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+	ss.eventRecorder.Record(entity.NewTeamEvent(teamID, entity.TeamEventSessionScheduled, userID, session.ID, session.Title))
+
+	return ss.withConflicts(userID, session)
+}
+
+func (ss *StudySessionService) GetTeamSessions(userID, teamID string) ([]*entity.StudySession, error) {
+	if _, err := getTeamForMember(ss.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := ss.sessionRepo.GetByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartAt.Before(sessions[j].StartAt)
+	})
+	return sessions, nil
+}
+
+func (ss *StudySessionService) GetSession(userID, sessionID string) (*dto.StudySessionResponse, error) {
+	session, _, err := ss.getSessionForMember(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return ss.withConflicts(userID, session)
+}
+
+// UpdateSession replaces the schedule of a session, the RSVPs are kept
+func (ss *StudySessionService) UpdateSession(userID, sessionID string, request *dto.StudySessionRequest) (*dto.StudySessionResponse, error) {
+	if err := validator.ValidateStudySessionRequest(request); err != nil {
+		return nil, err
+	}
+
+	session, team, err := ss.getSessionForEditor(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	startAt, endAt := request.StartAt.UTC(), request.EndAt.UTC()
+	if !startAt.Equal(session.StartAt) || request.Timezone != session.Timezone || !reflect.DeepEqual(request.Recurrence, session.Recurrence) {
+		// Occurrences may have moved, remind members again
+		session.LastReminderAt = 0
+	}
+
+	session.Title = strings.TrimSpace(request.Title)
+	session.Description = request.Description
+	session.StartAt = startAt
+	session.EndAt = endAt
+	session.Timezone = request.Timezone
+	session.Recurrence = request.Recurrence
+	session.ReminderMinutes = reminderMinutes(request)
+	session.VoiceRoomID = ""
+	if request.LinkVoiceRoom {
+		session.VoiceRoomID = team.Id
+	}
+	session.Sequence++
+	session.UpdatedAt = time.Now().UTC()
+
+	if err := ss.sessionRepo.Update(session); err != nil {
+		return nil, err
+	}
+	return ss.withConflicts(userID, session)
+}
+
+func (ss *StudySessionService) DeleteSession(userID, sessionID string) error {
+	session, _, err := ss.getSessionForEditor(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	return ss.sessionRepo.Delete(session.ID)
+}
+
+// RSVP stores the member's answer. Answering going returns the member's other sessions that overlap this one.
+func (ss *StudySessionService) RSVP(userID, sessionID string, request *dto.RSVPRequest) (*dto.StudySessionResponse, error) {
+	if err := validator.ValidateRSVPRequest(request); err != nil {
+		return nil, err
+	}
+
+	session, _, err := ss.getSessionForMember(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.RSVPs == nil {
+		session.RSVPs = make(map[string]entity.RSVPStatus)
+	}
+	session.RSVPs[userID] = request.Status
+	session.UpdatedAt = time.Now().UTC()
+
+	if err := ss.sessionRepo.Update(session); err != nil {
+		return nil, err
+	}
+
+	if request.Status != entity.RSVPGoing {
+		return &dto.StudySessionResponse{Session: session}, nil
+	}
+	return ss.withConflicts(userID, session)
+}
+
+// GetUserAgenda returns the occurrences of the sessions of all the user's teams between from and to, by start time
+func (ss *StudySessionService) GetUserAgenda(userID string, from, to time.Time) ([]dto.SessionOccurrence, error) {
+	if err := validator.ValidateAgendaRange(from, to); err != nil {
+		return nil, err
+	}
+
+	sessions, err := ss.getUserSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	agenda := make([]dto.SessionOccurrence, 0)
+	for _, session := range sessions {
+		for _, start := range session.OccurrencesBetween(from, to) {
+			agenda = append(agenda, dto.NewSessionOccurrence(session, start, userID))
+		}
+	}
+	sort.Slice(agenda, func(i, j int) bool {
+		return agenda[i].StartAt.Before(agenda[j].StartAt)
+	})
+	return agenda, nil
+}
+
+// SendDueReminders pushes a "session_reminder" to the members who have not declined, once per occurrence,
+// when the occurrence starts within the session's reminder time
+func (ss *StudySessionService) SendDueReminders(now time.Time) error {
+	sessions, err := ss.sessionRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ReminderMinutes <= 0 {
+			continue
+		}
+		start, ok := nextReminder(session, now)
+		if !ok {
+			continue
+		}
+
+		team, err := ss.teamRepo.GetTeamById(session.TeamID)
+		if err != nil || team.Archived {
+			continue
+		}
+
+		session.LastReminderAt = start.Unix()
+		if err := ss.sessionRepo.Update(session); err != nil {
+			log.Printf("sessions: reminder for %s: %v", session.ID, err)
+			continue
+		}
+
+		for _, memberID := range team.UsersIds {
+			if session.GetRSVP(memberID) == entity.RSVPDeclined {
+				continue
+			}
+			ss.hub.Send(memberID, *hub.NewMessage(hub.SessionReminder, dto.NewSessionOccurrence(session, start, memberID)))
+		}
+	}
+	return nil
+}
+
+// RunReminders checks for due reminders every interval, it never returns
+func (ss *StudySessionService) RunReminders(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := ss.SendDueReminders(now); err != nil {
+			log.Printf("sessions: sending reminders: %v", err)
+		}
+	}
+}
+
+// nextReminder returns the first occurrence after now that is within the reminder time and was not reminded yet
+func nextReminder(session *entity.StudySession, now time.Time) (time.Time, bool) {
+	window := time.Duration(session.ReminderMinutes) * time.Minute
+	for _, start := range session.OccurrencesBetween(now, now.Add(window+time.Second)) {
+		if start.After(now) && !start.After(now.Add(window)) && start.Unix() > session.LastReminderAt {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func reminderMinutes(request *dto.StudySessionRequest) int {
+	if request.ReminderMinutes == nil {
+		return defaultReminderMinutes
+	}
+	return *request.ReminderMinutes
+}
+
+func (ss *StudySessionService) getSessionForMember(userID, sessionID string) (*entity.StudySession, *entity.Team, error) {
+	session, err := ss.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, nil, fmt.Errorf("%w: %s", ErrResourceNotFound, studySessionNotFound)
+		}
+		return nil, nil, err
+	}
+
+	team, err := getTeamForMember(ss.teamRepo, session.TeamID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, team, nil
+}
+
+func (ss *StudySessionService) getSessionForEditor(userID, sessionID string) (*entity.StudySession, *entity.Team, error) {
+	session, team, err := ss.getSessionForMember(userID, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if session.CreatedBy != userID && !team.IsOwner(userID) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrForbidden, onlyCreatorOrOwnerSession)
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, nil, err
+	}
+	return session, team, nil
+}
+
+// getUserSessions returns the sessions of all the user's teams
+func (ss *StudySessionService) getUserSessions(userID string) ([]*entity.StudySession, error) {
+	user, err := ss.userRepo.GetByID(userID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+		}
+		return nil, err
+	}
+	if user.TeamsIds == nil {
+		return nil, nil
+	}
+
+	var sessions []*entity.StudySession
+	for _, teamID := range *user.TeamsIds {
+		teamSessions, err := ss.sessionRepo.GetByTeamID(teamID)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, teamSessions...)
+	}
+	return sessions, nil
+}
+
+func (ss *StudySessionService) withConflicts(userID string, session *entity.StudySession) (*dto.StudySessionResponse, error) {
+	conflicts, err := ss.findConflicts(userID, session)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.StudySessionResponse{Session: session, Conflicts: conflicts}, nil
+}
+
+// findConflicts returns the upcoming occurrences of the sessions the user is going to that overlap the session
+func (ss *StudySessionService) findConflicts(userID string, session *entity.StudySession) ([]dto.SessionConflict, error) {
+	others, err := ss.getUserSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Now()
+	to := from.Add(conflictHorizon)
+	duration := session.EndAt.Sub(session.StartAt)
+	occurrences := session.OccurrencesBetween(from, to)
+
+	var conflicts []dto.SessionConflict
+	for _, other := range others {
+		if other.ID == session.ID || other.GetRSVP(userID) != entity.RSVPGoing {
+			continue
+		}
+		otherDuration := other.EndAt.Sub(other.StartAt)
+		for _, otherStart := range other.OccurrencesBetween(from, to) {
+			otherEnd := otherStart.Add(otherDuration)
+			for _, start := range occurrences {
+				if start.Before(otherEnd) && otherStart.Before(start.Add(duration)) {
+					conflicts = append(conflicts, dto.SessionConflict{
+						SessionID: other.ID,
+						TeamID:    other.TeamID,
+						Title:     other.Title,
+						StartAt:   otherStart,
+						EndAt:     otherEnd,
+					})
+					break
+				}
+			}
+		}
+	}
+	return conflicts, nil
+}
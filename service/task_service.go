@@ -0,0 +1,508 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+)
+
+const (
+	taskNotFound       = "task not found"
+	columnNotFound     = "column not found"
+	columnNotEmpty     = "move or delete the tasks of the column first"
+	lastColumn         = "a board needs at least one column"
+	tooManyColumns     = "a board can have at most 20 columns"
+	assigneesNotInTeam = "assignees must be members of the team"
+	maxBoardColumns    = 20
+)
+
+type TaskServiceInterface interface {
+	GetBoard(userID, teamID string) (*dto.TaskBoardResponse, error)
+	CreateColumn(userID, teamID string, request *dto.BoardColumnRequest) (*entity.TaskBoard, error)
+	UpdateColumn(userID, teamID, columnID string, request *dto.UpdateBoardColumnRequest) (*entity.TaskBoard, error)
+	DeleteColumn(userID, teamID, columnID string) (*entity.TaskBoard, error)
+	CreateTask(userID, teamID string, request *dto.TaskRequest) (*entity.Task, error)
+	GetTask(userID, teamID, taskID string) (*dto.TaskDetailsResponse, error)
+	UpdateTask(userID, teamID, taskID string, request *dto.TaskRequest) (*entity.Task, error)
+	MoveTask(userID, teamID, taskID string, request *dto.MoveTaskRequest) (*entity.Task, error)
+	DeleteTask(userID, teamID, taskID string) error
+	AddComment(userID, teamID, taskID string, request *dto.TaskCommentRequest) (*entity.TaskComment, error)
+}
+
+type TaskService struct {
+	taskRepo persistence.TaskRepositoryInterface
+	teamRepo TeamRepositoryInterface
+	hub      *hub.Hub[hub.Message]
+}
+
+func NewTaskService() *TaskService {
+	return &TaskService{
+		taskRepo: persistence.NewTaskRepository(),
+		teamRepo: persistence.NewTeamRepository(),
+		hub:      GetMessageHub(),
+	}
+}
+
+func NewTaskServiceWithRepo(taskRepo persistence.TaskRepositoryInterface, teamRepo TeamRepositoryInterface) *TaskService {
+	return &TaskService{
+		taskRepo: taskRepo,
+		teamRepo: teamRepo,
+		hub:      hub.NewHub[hub.Message](),
+	}
+}
+
+// GetBoard returns the columns of the team's board in order, each with its tasks in order
+func (ts *TaskService) GetBoard(userID, teamID string) (*dto.TaskBoardResponse, error) {
+	if _, err := getTeamForMember(ts.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	board, err := ts.getBoard(teamID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := ts.taskRepo.GetByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	sortTasks(tasks)
+
+	resp := &dto.TaskBoardResponse{TeamID: teamID, Columns: make([]dto.BoardColumnResponse, len(board.Columns))}
+	for i, column := range board.Columns {
+		resp.Columns[i] = dto.BoardColumnResponse{BoardColumn: column, Tasks: make([]*entity.Task, 0)}
+	}
+	for _, task := range tasks {
+		i := board.ColumnIndex(task.ColumnID)
+		if i < 0 {
+			i = 0
+		}
+		resp.Columns[i].Tasks = append(resp.Columns[i].Tasks, task)
+	}
+	return resp, nil
+}
+
+func (ts *TaskService) CreateColumn(userID, teamID string, request *dto.BoardColumnRequest) (*entity.TaskBoard, error) {
+	if err := validator.ValidateColumnName(request.Name); err != nil {
+		return nil, err
+	}
+	team, err := ts.getTeamForChange(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := ts.getBoard(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if len(board.Columns) >= maxBoardColumns {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, tooManyColumns)
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	board.Columns = append(board.Columns, entity.BoardColumn{ID: id, Name: strings.TrimSpace(request.Name)})
+	return ts.saveBoard(team, board)
+}
+
+// UpdateColumn renames a column or moves it to another index on the board
+func (ts *TaskService) UpdateColumn(userID, teamID, columnID string, request *dto.UpdateBoardColumnRequest) (*entity.TaskBoard, error) {
+	if err := validator.ValidateUpdateBoardColumnRequest(request); err != nil {
+		return nil, err
+	}
+	team, err := ts.getTeamForChange(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := ts.getBoard(teamID)
+	if err != nil {
+		return nil, err
+	}
+	i := board.ColumnIndex(columnID)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, columnNotFound)
+	}
+
+	column := board.Columns[i]
+	if request.Name != nil {
+		column.Name = strings.TrimSpace(*request.Name)
+	}
+	board.Columns[i] = column
+	if request.Position != nil {
+		board.Columns = slices.Delete(board.Columns, i, i+1)
+		board.Columns = slices.Insert(board.Columns, min(*request.Position, len(board.Columns)), column)
+	}
+	return ts.saveBoard(team, board)
+}
+
+// DeleteColumn removes an empty column, the last column of a board can not be deleted
+func (ts *TaskService) DeleteColumn(userID, teamID, columnID string) (*entity.TaskBoard, error) {
+	team, err := ts.getTeamForChange(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := ts.getBoard(teamID)
+	if err != nil {
+		return nil, err
+	}
+	i := board.ColumnIndex(columnID)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, columnNotFound)
+	}
+	if len(board.Columns) == 1 {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, lastColumn)
+	}
+
+	tasks, err := ts.getColumnTasks(teamID, columnID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, columnNotEmpty)
+	}
+
+	board.Columns = slices.Delete(board.Columns, i, i+1)
+	return ts.saveBoard(team, board)
+}
+
+// CreateTask adds a task at the end of its column
+func (ts *TaskService) CreateTask(userID, teamID string, request *dto.TaskRequest) (*entity.Task, error) {
+	if err := validator.ValidateTaskRequest(request); err != nil {
+		return nil, err
+	}
+	team, err := ts.getTeamForChange(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := ts.getBoard(teamID)
+	if err != nil {
+		return nil, err
+	}
+	columnID := request.ColumnID
+	if columnID == "" {
+		columnID = board.Columns[0].ID
+	}
+	if board.ColumnIndex(columnID) < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, columnNotFound)
+	}
+
+	columnTasks, err := ts.getColumnTasks(teamID, columnID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	task := entity.NewTask(id, teamID, columnID, len(columnTasks), userID)
+	if err := applyTaskRequest(task, team, request); err != nil {
+		return nil, err
+	}
+
+	if err := ts.taskRepo.Create(task); err != nil {
+		return nil, err
+	}
+	ts.hub.SendMany(team.UsersIds, *hub.NewMessage(hub.TaskCreated, task))
+	return task, nil
+}
+
+func (ts *TaskService) GetTask(userID, teamID, taskID string) (*dto.TaskDetailsResponse, error) {
+	if _, err := getTeamForMember(ts.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+	task, err := ts.getTask(teamID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := ts.taskRepo.GetComments(taskID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+	return &dto.TaskDetailsResponse{Task: task, Comments: comments}, nil
+}
+
+// UpdateTask replaces the content of a task, its column and position are kept
+func (ts *TaskService) UpdateTask(userID, teamID, taskID string, request *dto.TaskRequest) (*entity.Task, error) {
+	if err := validator.ValidateTaskRequest(request); err != nil {
+		return nil, err
+	}
+	team, err := ts.getTeamForChange(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := ts.getTask(teamID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyTaskRequest(task, team, request); err != nil {
+		return nil, err
+	}
+	task.UpdatedAt = time.Now().UTC()
+
+	if err := ts.taskRepo.Update(task); err != nil {
+		return nil, err
+	}
+	ts.hub.SendMany(team.UsersIds, *hub.NewMessage(hub.TaskUpdated, task))
+	return task, nil
+}
+
+// MoveTask moves a task to an index of a column and renumbers the tasks of the columns it left and entered
+func (ts *TaskService) MoveTask(userID, teamID, taskID string, request *dto.MoveTaskRequest) (*entity.Task, error) {
+	if err := validator.ValidateMoveTaskRequest(request); err != nil {
+		return nil, err
+	}
+	team, err := ts.getTeamForChange(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := ts.getTask(teamID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := ts.getBoard(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if board.ColumnIndex(request.ColumnID) < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, columnNotFound)
+	}
+
+	fromColumnID := task.ColumnID
+	if fromColumnID != request.ColumnID {
+		source, err := ts.getColumnTasks(teamID, fromColumnID)
+		if err != nil {
+			return nil, err
+		}
+		if err := ts.renumber(withoutTask(source, taskID)); err != nil {
+			return nil, err
+		}
+	}
+
+	target, err := ts.getColumnTasks(teamID, request.ColumnID)
+	if err != nil {
+		return nil, err
+	}
+	target = withoutTask(target, taskID)
+	position := min(request.Position, len(target))
+	task.ColumnID = request.ColumnID
+	task.UpdatedAt = time.Now().UTC()
+	target = slices.Insert(target, position, task)
+	// The moved task always gets stored, even when its position number did not change
+	task.Position = -1
+	if err := ts.renumber(target); err != nil {
+		return nil, err
+	}
+
+	ts.hub.SendMany(team.UsersIds, *hub.NewMessage(hub.TaskMoved, dto.TaskMoved{
+		TaskID:       task.ID,
+		TeamID:       teamID,
+		FromColumnID: fromColumnID,
+		ColumnID:     task.ColumnID,
+		Position:     task.Position,
+	}))
+	return task, nil
+}
+
+func (ts *TaskService) DeleteTask(userID, teamID, taskID string) error {
+	team, err := ts.getTeamForChange(userID, teamID)
+	if err != nil {
+		return err
+	}
+	task, err := ts.getTask(teamID, taskID)
+	if err != nil {
+		return err
+	}
+
+	if err := ts.taskRepo.Delete(taskID); err != nil {
+		return err
+	}
+	if err := ts.taskRepo.DeleteComments(taskID); err != nil {
+		return err
+	}
+
+	remaining, err := ts.getColumnTasks(teamID, task.ColumnID)
+	if err != nil {
+		return err
+	}
+	if err := ts.renumber(withoutTask(remaining, taskID)); err != nil {
+		return err
+	}
+
+	ts.hub.SendMany(team.UsersIds, *hub.NewMessage(hub.TaskDeleted, dto.TaskDeleted{TaskID: taskID, TeamID: teamID}))
+	return nil
+}
+
+func (ts *TaskService) AddComment(userID, teamID, taskID string, request *dto.TaskCommentRequest) (*entity.TaskComment, error) {
+	if err := validator.ValidateTaskCommentRequest(request); err != nil {
+		return nil, err
+	}
+	team, err := ts.getTeamForChange(userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ts.getTask(teamID, taskID); err != nil {
+		return nil, err
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	comment := entity.NewTaskComment(id, taskID, teamID, userID, strings.TrimSpace(request.Text))
+	if err := ts.taskRepo.CreateComment(comment); err != nil {
+		return nil, err
+	}
+	ts.hub.SendMany(team.UsersIds, *hub.NewMessage(hub.TaskCommentAdded, comment))
+	return comment, nil
+}
+
+// getTeamForChange returns the team when the user is a member and the team is not archived
+func (ts *TaskService) getTeamForChange(userID, teamID string) (*entity.Team, error) {
+	team, err := getTeamForMember(ts.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (ts *TaskService) getBoard(teamID string) (*entity.TaskBoard, error) {
+	board, err := ts.taskRepo.GetBoard(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if board == nil || len(board.Columns) == 0 {
+		return entity.NewDefaultTaskBoard(teamID), nil
+	}
+	return board, nil
+}
+
+func (ts *TaskService) saveBoard(team *entity.Team, board *entity.TaskBoard) (*entity.TaskBoard, error) {
+	board.UpdatedAt = time.Now().UTC()
+	if err := ts.taskRepo.SaveBoard(board); err != nil {
+		return nil, err
+	}
+	ts.hub.SendMany(team.UsersIds, *hub.NewMessage(hub.TaskBoardUpdated, board))
+	return board, nil
+}
+
+// getTask returns the task when it belongs to the team
+func (ts *TaskService) getTask(teamID, taskID string) (*entity.Task, error) {
+	task, err := ts.taskRepo.GetByID(taskID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, taskNotFound)
+		}
+		return nil, err
+	}
+	if task.TeamID != teamID {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, taskNotFound)
+	}
+	return task, nil
+}
+
+func (ts *TaskService) getColumnTasks(teamID, columnID string) ([]*entity.Task, error) {
+	tasks, err := ts.taskRepo.GetByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	var columnTasks []*entity.Task
+	for _, task := range tasks {
+		if task.ColumnID == columnID {
+			columnTasks = append(columnTasks, task)
+		}
+	}
+	sortTasks(columnTasks)
+	return columnTasks, nil
+}
+
+// renumber stores the tasks whose position differs from their index
+func (ts *TaskService) renumber(tasks []*entity.Task) error {
+	for i, task := range tasks {
+		if task.Position == i {
+			continue
+		}
+		task.Position = i
+		if err := ts.taskRepo.Update(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyTaskRequest(task *entity.Task, team *entity.Team, request *dto.TaskRequest) error {
+	var assignees []string
+	for _, assigneeID := range request.AssigneeIDs {
+		if !slices.Contains(team.UsersIds, assigneeID) {
+			return fmt.Errorf("%w: %s", validator.ErrValidation, assigneesNotInTeam)
+		}
+		if !slices.Contains(assignees, assigneeID) {
+			assignees = append(assignees, assigneeID)
+		}
+	}
+
+	var labels []string
+	for _, label := range request.Labels {
+		label = strings.TrimSpace(label)
+		if !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+
+	checklist := make([]entity.ChecklistItem, 0, len(request.Checklist))
+	for _, item := range request.Checklist {
+		if item.ID == "" {
+			id, err := generateID()
+			if err != nil {
+				return err
+			}
+			item.ID = id
+		}
+		item.Text = strings.TrimSpace(item.Text)
+		checklist = append(checklist, item)
+	}
+
+	task.Title = strings.TrimSpace(request.Title)
+	task.Description = request.Description
+	task.AssigneeIDs = assignees
+	task.DueAt = request.DueAt
+	task.Labels = labels
+	task.Checklist = checklist
+	return nil
+}
+
+func sortTasks(tasks []*entity.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Position != tasks[j].Position {
+			return tasks[i].Position < tasks[j].Position
+		}
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+}
+
+func withoutTask(tasks []*entity.Task, taskID string) []*entity.Task {
+	return slices.DeleteFunc(tasks, func(task *entity.Task) bool {
+		return task.ID == taskID
+	})
+}
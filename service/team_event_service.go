@@ -0,0 +1,116 @@
+package service
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+)
+
+// EventRecorder records team activity and pushes it to the online members. Failures are logged and never fail the action itself.
+type EventRecorder interface {
+	Record(event *entity.TeamEvent)
+}
+
+type TeamEventServiceInterface interface {
+	GetTeamActivity(userID, teamID string, page, limit int) (*dto.TeamActivityResponse, error)
+}
+
+type TeamEventService struct {
+	eventRepo persistence.TeamEventRepositoryInterface
+	teamRepo  TeamRepositoryInterface
+	hub       *hub.Hub[hub.Message]
+}
+
+func NewTeamEventService() *TeamEventService {
+	return &TeamEventService{
+		eventRepo: persistence.NewTeamEventRepository(),
+		teamRepo:  persistence.NewTeamRepository(),
+		hub:       GetMessageHub(),
+	}
+}
+
+func NewTeamEventServiceWithRepo(eventRepo persistence.TeamEventRepositoryInterface, teamRepo TeamRepositoryInterface) *TeamEventService {
+	return &TeamEventService{
+		eventRepo: eventRepo,
+		teamRepo:  teamRepo,
+		hub:       hub.NewHub[hub.Message](),
+	}
+}
+
+func (tes *TeamEventService) Record(event *entity.TeamEvent) {
+	id, err := generateID()
+	if err != nil {
+		log.Printf("activity: %s in team %s: %v", event.Type, event.TeamID, err)
+		return
+	}
+	event.ID = id
+	event.CreatedAt = time.Now().UnixMilli()
+
+	if err := tes.eventRepo.Create(event); err != nil {
+		log.Printf("activity: %s in team %s: %v", event.Type, event.TeamID, err)
+		return
+	}
+
+	team, err := tes.teamRepo.GetTeamById(event.TeamID)
+	if err != nil {
+		return
+	}
+	tes.hub.SendMany(team.UsersIds, *hub.NewMessage(hub.TeamActivity, event))
+}
+
+// GetTeamActivity returns the team's events, newest first
+func (tes *TeamEventService) GetTeamActivity(userID, teamID string, page, limit int) (*dto.TeamActivityResponse, error) {
+	if _, err := getTeamForMember(tes.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	events, err := tes.eventRepo.GetByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].CreatedAt != events[j].CreatedAt {
+			return events[i].CreatedAt > events[j].CreatedAt
+		}
+		return events[i].ID > events[j].ID
+	})
+
+	totalCount := len(events)
+	totalPages := (totalCount + limit - 1) / limit
+
+	start := (page - 1) * limit
+	end := start + limit
+	if start > totalCount {
+		start = totalCount
+	}
+	if end > totalCount {
+		end = totalCount
+	}
+
+	return &dto.TeamActivityResponse{
+		Events:     events[start:end],
+		Page:       page,
+		Limit:      limit,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	}, nil
+}
+
+type noopEventRecorder struct{}
+
+func (noopEventRecorder) Record(*entity.TeamEvent) {}
@@ -2,7 +2,12 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
 	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
@@ -12,22 +17,44 @@ import (
 type TeamService struct {
 	userRepository UserRepositoryInterface
 	teamRepository TeamRepositoryInterface
+	searchIndexer  SearchIndexer
+	topicValidator TopicValidator
+	eventRecorder  EventRecorder
+	moderation     ModerationChecker
+	memberKicker   MemberKicker
 }
 
 type TeamRepositoryInterface interface {
 	Create(team *entity.Team) error
 	GetTeamById(id string) (*entity.Team, error)
-	GetXTeamsByPrefix(prefix string, x int) ([]*entity.Team, error)
-	GetTeamsByName(name string) ([]*entity.Team, error)
+	GetXTeamsByPrefix(organizationID, prefix string, x int) ([]*entity.Team, error)
+	GetTeamsByName(organizationID, name string) ([]*entity.Team, error)
 	GetAll() ([]*entity.Team, error)
+	GetByOrganizationID(organizationID string) ([]*entity.Team, error)
 	Update(team *entity.Team) error
 	Delete(id string) error
 }
 
+const (
+	teamArchived           = "team is archived"
+	onlyOwnerCanArchive    = "only the team owner can archive or restore the team"
+	onlyOwnerCanDelete     = "only the team owner can delete the team"
+	userAlreadyInTeamError = "user is already part of the team"
+	onlyOwnerCanSetAdmins  = "only the team owner can add or remove admins"
+	ownerIsAlwaysAdmin     = "the team owner is always an admin"
+	onlyAdminsAllowed      = "only team admins can do this"
+	privateTeamAdminsOnly  = "only team admins can add members to a private team"
+)
+
 func NewTeamService() *TeamService {
 	return &TeamService{
 		userRepository: persistence.NewUserRepository(),
 		teamRepository: persistence.NewTeamRepository(),
+		searchIndexer:  NewSearchService(),
+		topicValidator: NewTopicService(),
+		eventRecorder:  NewTeamEventService(),
+		moderation:     NewModerationService(),
+		memberKicker:   NewModerationService(),
 	}
 }
 
@@ -35,14 +62,42 @@ func NewTeamServiceWithRepo(UserRepositoryInterface UserRepositoryInterface, tea
 	return &TeamService{
 		userRepository: UserRepositoryInterface,
 		teamRepository: teamRepositoryInterface,
+		searchIndexer:  noopSearchIndexer{},
+		topicValidator: noopTopicValidator{},
+		eventRecorder:  noopEventRecorder{},
+		moderation:     noopModerationChecker{},
+		memberKicker:   noopMemberKicker{},
 	}
 }
 
+func (ts *TeamService) SetSearchIndexer(indexer SearchIndexer) {
+	ts.searchIndexer = indexer
+}
+
+func (ts *TeamService) SetTopicValidator(topicValidator TopicValidator) {
+	ts.topicValidator = topicValidator
+}
+
+func (ts *TeamService) SetEventRecorder(recorder EventRecorder) {
+	ts.eventRecorder = recorder
+}
+
+func (ts *TeamService) SetModerationChecker(moderation ModerationChecker) {
+	ts.moderation = moderation
+}
+
+func (ts *TeamService) SetMemberKicker(memberKicker MemberKicker) {
+	ts.memberKicker = memberKicker
+}
+
 func (ts *TeamService) CreateTeam(request *dto.TeamRequest) (*entity.Team, error) {
 	if err := validator.ValidateTeamRequest(request); err != nil {
 		return nil, err
 	}
-	_, err := ts.userRepository.GetByID(request.UserId)
+	if err := ts.validateTeamTopic(request.TeamTopic); err != nil {
+		return nil, err
+	}
+	owner, err := ts.userRepository.GetByID(request.UserId)
 	if err != nil {
 		return nil, err
 	}
@@ -58,14 +113,18 @@ func (ts *TeamService) CreateTeam(request *dto.TeamRequest) (*entity.Team, error
 		nil,
 		request.TeamTopic,
 	)
+	team.OwnerId = request.UserId
+	team.OrganizationID = owner.OrganizationID
 	if err := ts.teamRepository.Create(&team); err != nil {
 		return nil, err
 	}
-	ts.AddUserToTeam(request.UserId, id)
+	ts.AddUserToTeam(request.UserId, request.UserId, id)
+	ts.searchIndexer.IndexTeam(&team)
 	return ts.teamRepository.GetTeamById(id)
 }
 
-func (ts *TeamService) AddUserToTeam(idUser string, idTeam string) (*entity.User, *entity.Team, error) {
+// AddUserToTeam adds the user to the team. Users can join public teams themselves; admins can add anyone who is not banned.
+func (ts *TeamService) AddUserToTeam(callerID, idUser, idTeam string) (*entity.User, *entity.Team, error) {
 	user, err := ts.userRepository.GetByID(idUser)
 	if err != nil {
 		return nil, nil, err
@@ -74,9 +133,26 @@ func (ts *TeamService) AddUserToTeam(idUser string, idTeam string) (*entity.User
 	if err != nil {
 		return nil, nil, err
 	}
+	if !sameOrganization(user, team.OrganizationID) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrResourceNotFound, teamNotFound)
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, nil, err
+	}
+	if err := ts.moderation.CheckNotBanned(idTeam, idUser); err != nil {
+		return nil, nil, err
+	}
+	if !team.IsAdmin(callerID) {
+		if callerID != idUser {
+			return nil, nil, fmt.Errorf("%w: %s", ErrForbidden, onlyAdminsAllowed)
+		}
+		if !team.IsPublic {
+			return nil, nil, fmt.Errorf("%w: %s", ErrForbidden, privateTeamAdminsOnly)
+		}
+	}
 	for _, u := range team.UsersIds {
 		if idUser == u {
-			return nil, nil, errors.New("user is already part of the team")
+			return nil, nil, errors.New(userAlreadyInTeamError)
 		}
 	}
 	team.UsersIds = append(team.UsersIds, idUser)
@@ -91,10 +167,16 @@ func (ts *TeamService) AddUserToTeam(idUser string, idTeam string) (*entity.User
 	if err := ts.teamRepository.Update(team); err != nil {
 		return nil, nil, err
 	}
+	ts.eventRecorder.Record(entity.NewTeamEvent(team.Id, entity.TeamEventMemberJoined, user.ID, "", ""))
 	return user, team, nil
 }
 
-func (ts *TeamService) DeleteUserFromTeam(idUser string, idTeam string) (*entity.User, *entity.Team, error) {
+// DeleteUserFromTeam lets users leave a team; removing someone else is a kick, which only admins can do
+func (ts *TeamService) DeleteUserFromTeam(callerID, idUser, idTeam string) (*entity.User, *entity.Team, error) {
+	if callerID != idUser {
+		return ts.kickMember(callerID, idUser, idTeam)
+	}
+
 	user, err := ts.userRepository.GetByID(idUser)
 	if err != nil {
 		return nil, nil, err
@@ -113,16 +195,24 @@ func (ts *TeamService) DeleteUserFromTeam(idUser string, idTeam string) (*entity
 	if !ok {
 		return nil, nil, errors.New("the user is not a part of this team")
 	}
-	usersIds := removeString(team.UsersIds, user.ID)
-	teamsIds := removeString(*user.TeamsIds, team.Id)
-
-	team.UsersIds = usersIds
-	user.TeamsIds = &teamsIds
+	if err := removeTeamMember(ts.userRepository, ts.teamRepository, user, team); err != nil {
+		return nil, nil, err
+	}
+	ts.eventRecorder.Record(entity.NewTeamEvent(team.Id, entity.TeamEventMemberLeft, user.ID, "", ""))
+	return user, team, nil
+}
 
-	if err := ts.userRepository.Update(user); err != nil {
+// kickMember removes the member through the moderation log and returns them and the team without them
+func (ts *TeamService) kickMember(moderatorID, idUser, idTeam string) (*entity.User, *entity.Team, error) {
+	if _, err := ts.memberKicker.Kick(moderatorID, idTeam, idUser, &dto.ModerationRequest{}); err != nil {
 		return nil, nil, err
 	}
-	if err := ts.teamRepository.Update(team); err != nil {
+	user, err := ts.userRepository.GetByID(idUser)
+	if err != nil {
+		return nil, nil, err
+	}
+	team, err := ts.teamRepository.GetTeamById(idTeam)
+	if err != nil {
 		return nil, nil, err
 	}
 	return user, team, nil
@@ -132,28 +222,173 @@ func (ts *TeamService) GetTeamById(id string) (*entity.Team, error) {
 	return ts.teamRepository.GetTeamById(id)
 }
 
-func (ts *TeamService) GetXTeamsByPrefix(prefix string, x int) ([]*entity.Team, error) {
-	return ts.teamRepository.GetXTeamsByPrefix(prefix, x)
+// GetTeamForMember returns the team if the user is one of its members
+func (ts *TeamService) GetTeamForMember(userID, id string) (*entity.Team, error) {
+	return getTeamForMember(ts.teamRepository, id, userID)
 }
 
-func (ts *TeamService) GetTeamsByName(name string) ([]*entity.Team, error) {
-	return ts.teamRepository.GetTeamsByName(name)
+// GetVisibleTeam returns the team if it belongs to the viewer's organization
+func (ts *TeamService) GetVisibleTeam(viewerID, id string) (*entity.Team, error) {
+	viewer, err := ts.userRepository.GetByID(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	team, err := ts.teamRepository.GetTeamById(id)
+	if err != nil {
+		return nil, err
+	}
+	if !sameOrganization(viewer, team.OrganizationID) {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, teamNotFound)
+	}
+	return team, nil
 }
 
-func (ts *TeamService) GetAll() ([]*entity.Team, error) {
-	return ts.teamRepository.GetAll()
+// GetXTeamsByPrefix returns the first x teams of the viewer's organization whose name starts with prefix, leaving out archived teams
+func (ts *TeamService) GetXTeamsByPrefix(viewerID, prefix string, x int) ([]*entity.Team, error) {
+	viewer, err := ts.userRepository.GetByID(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := ts.teamRepository.GetXTeamsByPrefix(viewer.OrganizationID, prefix, x)
+	return withoutArchivedTeams(teams), err
 }
 
-func (ts *TeamService) Update(team *entity.Team) error {
-	return ts.teamRepository.Update(team)
+func (ts *TeamService) GetTeamsByName(viewerID, name string) ([]*entity.Team, error) {
+	viewer, err := ts.userRepository.GetByID(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := ts.teamRepository.GetTeamsByName(viewer.OrganizationID, name)
+	return withoutArchivedTeams(teams), err
+}
+
+// GetAll returns the active teams of the viewer's organization
+func (ts *TeamService) GetAll(viewerID string) ([]*entity.Team, error) {
+	viewer, err := ts.userRepository.GetByID(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := ts.teamRepository.GetByOrganizationID(viewer.OrganizationID)
+	return withoutArchivedTeams(teams), err
+}
+
+// Update saves the team details; only team admins can. The members, the owner, the admins and the archived state can
+// not be changed this way and archived teams are read-only.
+func (ts *TeamService) Update(userID string, team *entity.Team) error {
+	existing, err := getTeamForAdmin(ts.teamRepository, team.Id, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkTeamNotArchived(existing); err != nil {
+		return err
+	}
+	// Teams may still have a topic from before the taxonomy, so only a new topic is checked
+	if team.TeamTopic != existing.TeamTopic {
+		if err := ts.validateTeamTopic(team.TeamTopic); err != nil {
+			return err
+		}
+	}
+
+	// Members join and leave through PUT /teams/users, which checks bans
+	team.UsersIds = existing.UsersIds
+	team.OwnerId = existing.OwnerId
+	team.OrganizationID = existing.OrganizationID
+	team.AdminIds = existing.AdminIds
+	team.Archived = existing.Archived
+	team.ArchivedAt = existing.ArchivedAt
+	if err := ts.teamRepository.Update(team); err != nil {
+		return err
+	}
+	ts.searchIndexer.IndexTeam(team)
+	return nil
+}
+
+// SetArchived archives or restores a team. Archived teams keep their history readable but accept no new content.
+func (ts *TeamService) SetArchived(userID, teamID string, archived bool) (*entity.Team, error) {
+	team, err := ts.teamRepository.GetTeamById(teamID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, teamNotFound)
+		}
+		return nil, err
+	}
+	if !team.IsOwner(userID) {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, onlyOwnerCanArchive)
+	}
+	if team.Archived == archived {
+		return team, nil
+	}
+
+	team.Archived = archived
+	team.ArchivedAt = 0
+	if archived {
+		team.ArchivedAt = time.Now().Unix()
+	}
+	if err := ts.teamRepository.Update(team); err != nil {
+		return nil, err
+	}
+	ts.searchIndexer.IndexTeam(team)
+	return team, nil
+}
+
+// SetAdmin makes a member an admin of the team or removes them from the admins. Only the owner can change the admins.
+func (ts *TeamService) SetAdmin(ownerID, teamID, userID string, admin bool) (*entity.Team, error) {
+	team, err := getTeamForMember(ts.teamRepository, teamID, userID)
+	if err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotInTeam)
+		}
+		return nil, err
+	}
+	if !team.IsOwner(ownerID) {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, onlyOwnerCanSetAdmins)
+	}
+	if team.IsOwner(userID) {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, ownerIsAlwaysAdmin)
+	}
+
+	team.AdminIds = removeString(team.AdminIds, userID)
+	if admin {
+		team.AdminIds = append(team.AdminIds, userID)
+	}
+	if err := ts.teamRepository.Update(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// getTeamForAdmin returns the team if the user is one of its admins
+func getTeamForAdmin(teamRepo TeamRepositoryInterface, teamID, userID string) (*entity.Team, error) {
+	team, err := getTeamForMember(teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !team.IsAdmin(userID) {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, onlyAdminsAllowed)
+	}
+	return team, nil
 }
 
 // also deletes all references to the team in the Users' saved teams
-func (ts *TeamService) Delete(id string) error {
+// Delete deletes a team of the user's organization that the user owns
+func (ts *TeamService) Delete(userID, id string) error {
 	team, err := ts.teamRepository.GetTeamById(id)
 	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return fmt.Errorf("%w: %s", ErrResourceNotFound, teamNotFound)
+		}
 		return err
 	}
+	owner, err := ts.userRepository.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+	if !sameOrganization(owner, team.OrganizationID) {
+		return fmt.Errorf("%w: %s", ErrResourceNotFound, teamNotFound)
+	}
+	if !team.IsOwner(userID) {
+		return fmt.Errorf("%w: %s", ErrForbidden, onlyOwnerCanDelete)
+	}
 	for _, user := range team.UsersIds {
 		user, err := ts.userRepository.GetByID(user)
 		if err != nil {
@@ -167,7 +402,18 @@ func (ts *TeamService) Delete(id string) error {
 			return err
 		}
 	}
-	return ts.teamRepository.Delete(id)
+	if err := ts.teamRepository.Delete(id); err != nil {
+		return err
+	}
+	ts.searchIndexer.RemoveFromIndex(entity.SearchTypeTeam, id)
+	return nil
+}
+
+func (ts *TeamService) validateTeamTopic(topic model.TopicOfInterest) error {
+	if topic == "" {
+		return nil
+	}
+	return ts.topicValidator.ValidateTopics([]model.TopicOfInterest{topic})
 }
 
 func removeString(slice []string, value string) []string {
@@ -179,3 +425,54 @@ func removeString(slice []string, value string) []string {
 	}
 	return result
 }
+
+// getTeamForMember returns the team if the user is one of its members
+func getTeamForMember(teamRepo TeamRepositoryInterface, teamID, userID string) (*entity.Team, error) {
+	team, err := teamRepo.GetTeamById(teamID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, teamNotFound)
+		}
+		return nil, err
+	}
+	if !slices.Contains(team.UsersIds, userID) {
+		return nil, fmt.Errorf("%w: %s", ErrForbidden, userNotInTeam)
+	}
+	return team, nil
+}
+
+// removeTeamMember removes the user from the team, from its admins and the team from the user's teams
+func removeTeamMember(userRepo UserRepositoryInterface, teamRepo TeamRepositoryInterface, user *entity.User, team *entity.Team) error {
+	team.UsersIds = removeString(team.UsersIds, user.ID)
+	team.AdminIds = removeString(team.AdminIds, user.ID)
+	if user.TeamsIds != nil {
+		teamsIds := removeString(*user.TeamsIds, team.Id)
+		user.TeamsIds = &teamsIds
+	}
+
+	if err := userRepo.Update(user); err != nil {
+		return err
+	}
+	return teamRepo.Update(team)
+}
+
+// checkTeamNotArchived rejects new content in archived teams
+func checkTeamNotArchived(team *entity.Team) error {
+	if team.Archived {
+		return fmt.Errorf("%w: %s", ErrConflict, teamArchived)
+	}
+	return nil
+}
+
+func withoutArchivedTeams(teams []*entity.Team) []*entity.Team {
+	if teams == nil {
+		return nil
+	}
+	active := make([]*entity.Team, 0, len(teams))
+	for _, team := range teams {
+		if !team.Archived {
+			active = append(active, team)
+		}
+	}
+	return active
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+)
+
+const teamTemplateNotFound = "team template not found"
+
+type TeamTemplateServiceInterface interface {
+	CreateTemplate(userID, teamID string, request *dto.TeamTemplateRequest) (*entity.TeamTemplate, error)
+	GetTemplates(userID string) ([]*dto.TeamTemplateSummary, error)
+	GetTemplate(userID, templateID string) (*entity.TeamTemplate, error)
+	DeleteTemplate(userID, templateID string) error
+	CreateTeamFromTemplate(userID, templateID string, request *dto.NewTeamFromTemplateRequest) (*dto.NewTeamFromTemplateResponse, error)
+	CloneTeam(userID, teamID string, request *dto.NewTeamFromTemplateRequest) (*dto.NewTeamFromTemplateResponse, error)
+}
+
+type TeamTemplateService struct {
+	templateRepo  persistence.TeamTemplateRepositoryInterface
+	teamRepo      TeamRepositoryInterface
+	channelRepo   persistence.ChannelRepositoryInterface
+	quizRepo      persistence.QuizRepositoryInterface
+	taskRepo      persistence.TaskRepositoryInterface
+	teamService   *TeamService
+	searchIndexer SearchIndexer
+}
+
+func NewTeamTemplateService() *TeamTemplateService {
+	return &TeamTemplateService{
+		templateRepo:  persistence.NewTeamTemplateRepository(),
+		teamRepo:      persistence.NewTeamRepository(),
+		channelRepo:   persistence.NewChannelRepository(),
+		quizRepo:      persistence.NewQuizRepository(),
+		taskRepo:      persistence.NewTaskRepository(),
+		teamService:   NewTeamService(),
+		searchIndexer: NewSearchService(),
+	}
+}
+
+func NewTeamTemplateServiceWithRepo(templateRepo persistence.TeamTemplateRepositoryInterface, teamRepo TeamRepositoryInterface, userRepo UserRepositoryInterface, channelRepo persistence.ChannelRepositoryInterface, quizRepo persistence.QuizRepositoryInterface, taskRepo persistence.TaskRepositoryInterface) *TeamTemplateService {
+	return &TeamTemplateService{
+		templateRepo:  templateRepo,
+		teamRepo:      teamRepo,
+		channelRepo:   channelRepo,
+		quizRepo:      quizRepo,
+		taskRepo:      taskRepo,
+		teamService:   NewTeamServiceWithRepo(userRepo, teamRepo),
+		searchIndexer: noopSearchIndexer{},
+	}
+}
+
+func (tts *TeamTemplateService) SetSearchIndexer(indexer SearchIndexer) {
+	tts.searchIndexer = indexer
+}
+
+// CreateTemplate saves the team's settings and the selected content as a template owned by the user
+func (tts *TeamTemplateService) CreateTemplate(userID, teamID string, request *dto.TeamTemplateRequest) (*entity.TeamTemplate, error) {
+	if err := validator.ValidateTeamTemplateRequest(request); err != nil {
+		return nil, err
+	}
+	team, err := getTeamForAdmin(tts.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := tts.snapshot(team, request.Include)
+	if err != nil {
+		return nil, err
+	}
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	template.ID = id
+	template.Name = strings.TrimSpace(request.Name)
+	template.OwnerID = userID
+
+	if err := tts.templateRepo.Create(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// GetTemplates returns the user's templates, newest first
+func (tts *TeamTemplateService) GetTemplates(userID string) ([]*dto.TeamTemplateSummary, error) {
+	templates, err := tts.templateRepo.GetByOwnerID(userID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].CreatedAt.After(templates[j].CreatedAt)
+	})
+
+	summaries := make([]*dto.TeamTemplateSummary, 0, len(templates))
+	for _, template := range templates {
+		summaries = append(summaries, dto.NewTeamTemplateSummary(template))
+	}
+	return summaries, nil
+}
+
+func (tts *TeamTemplateService) GetTemplate(userID, templateID string) (*entity.TeamTemplate, error) {
+	return tts.getTemplate(userID, templateID)
+}
+
+func (tts *TeamTemplateService) DeleteTemplate(userID, templateID string) error {
+	if _, err := tts.getTemplate(userID, templateID); err != nil {
+		return err
+	}
+	return tts.templateRepo.Delete(templateID)
+}
+
+// CreateTeamFromTemplate creates a team owned by the user with the template's settings and the selected content
+func (tts *TeamTemplateService) CreateTeamFromTemplate(userID, templateID string, request *dto.NewTeamFromTemplateRequest) (*dto.NewTeamFromTemplateResponse, error) {
+	if err := validator.ValidateNewTeamFromTemplateRequest(request); err != nil {
+		return nil, err
+	}
+	template, err := tts.getTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return tts.createTeam(userID, template, request)
+}
+
+// CloneTeam creates a team owned by the user with the settings and the selected content of a team they administer.
+// Archived teams can be cloned, that is how last semester's team is reused.
+func (tts *TeamTemplateService) CloneTeam(userID, teamID string, request *dto.NewTeamFromTemplateRequest) (*dto.NewTeamFromTemplateResponse, error) {
+	if err := validator.ValidateNewTeamFromTemplateRequest(request); err != nil {
+		return nil, err
+	}
+	team, err := getTeamForAdmin(tts.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := tts.snapshot(team, request.Include)
+	if err != nil {
+		return nil, err
+	}
+	return tts.createTeam(userID, template, &dto.NewTeamFromTemplateRequest{
+		Name:        request.Name,
+		Description: request.Description,
+		IsPublic:    request.IsPublic,
+	})
+}
+
+// snapshot copies the team's settings and the selected content, never its members or messages
+func (tts *TeamTemplateService) snapshot(team *entity.Team, include []entity.TemplateContent) (*entity.TeamTemplate, error) {
+	template := &entity.TeamTemplate{
+		SourceTeamID: team.Id,
+		Description:  team.Description,
+		IsPublic:     team.IsPublic,
+		TeamTopic:    team.TeamTopic,
+		Contents:     selectedContents(include),
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	if template.Has(entity.TemplateContentChannels) {
+		channels, err := tts.channelRepo.GetByTeamID(team.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, channel := range channels {
+			if !channel.IsDefault() && !channel.Archived {
+				template.Channels = append(template.Channels, channel.Name)
+			}
+		}
+		sort.Strings(template.Channels)
+	}
+
+	if template.Has(entity.TemplateContentQuizzes) {
+		quizzes, err := tts.quizRepo.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, quiz := range quizzes {
+			if quiz.TeamID == team.Id {
+				template.Quizzes = append(template.Quizzes, entity.TemplateQuiz{QuizName: quiz.QuizName, Questions: quiz.Questions})
+			}
+		}
+		sort.Slice(template.Quizzes, func(i, j int) bool {
+			return template.Quizzes[i].QuizName < template.Quizzes[j].QuizName
+		})
+	}
+
+	if template.Has(entity.TemplateContentTaskBoard) {
+		board, err := tts.taskRepo.GetBoard(team.Id)
+		if err != nil {
+			return nil, err
+		}
+		if board == nil || len(board.Columns) == 0 {
+			board = entity.NewDefaultTaskBoard(team.Id)
+		}
+		template.BoardColumns = board.Columns
+
+		tasks, err := tts.taskRepo.GetByTeamID(team.Id)
+		if err != nil {
+			return nil, err
+		}
+		sortTasks(tasks)
+		for _, task := range tasks {
+			if board.ColumnIndex(task.ColumnID) < 0 {
+				continue
+			}
+			templateTask := entity.TemplateTask{
+				ColumnID:    task.ColumnID,
+				Position:    task.Position,
+				Title:       task.Title,
+				Description: task.Description,
+				Labels:      task.Labels,
+			}
+			for _, item := range task.Checklist {
+				templateTask.Checklist = append(templateTask.Checklist, item.Text)
+			}
+			template.Tasks = append(template.Tasks, templateTask)
+		}
+	}
+
+	return template, nil
+}
+
+// createTeam creates the team through the team service, so it is validated, owned and indexed like any new team, then copies the content
+func (tts *TeamTemplateService) createTeam(userID string, template *entity.TeamTemplate, request *dto.NewTeamFromTemplateRequest) (*dto.NewTeamFromTemplateResponse, error) {
+	teamRequest := &dto.TeamRequest{
+		Name:        strings.TrimSpace(request.Name),
+		Description: template.Description,
+		IsPublic:    template.IsPublic,
+		UserId:      userID,
+		TeamTopic:   template.TeamTopic,
+	}
+	if request.Description != nil {
+		teamRequest.Description = *request.Description
+	}
+	if request.IsPublic != nil {
+		teamRequest.IsPublic = *request.IsPublic
+	}
+
+	team, err := tts.teamService.CreateTeam(teamRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := selectedContents(request.Include)
+	resp := &dto.NewTeamFromTemplateResponse{Team: team}
+	now := time.Now()
+
+	if template.Has(entity.TemplateContentChannels) && slices.Contains(contents, entity.TemplateContentChannels) {
+		for _, name := range template.Channels {
+			id, err := generateID()
+			if err != nil {
+				return nil, err
+			}
+			if err := tts.channelRepo.Create(entity.NewChannel(id, team.Id, name, userID, now.Unix())); err != nil {
+				return nil, err
+			}
+			resp.Channels++
+		}
+	}
+
+	if template.Has(entity.TemplateContentQuizzes) && slices.Contains(contents, entity.TemplateContentQuizzes) {
+		for _, templateQuiz := range template.Quizzes {
+			quiz, err := newQuizFromTemplate(templateQuiz, userID, team.Id)
+			if err != nil {
+				return nil, err
+			}
+			if err := tts.quizRepo.Create(*quiz); err != nil {
+				return nil, err
+			}
+			tts.searchIndexer.IndexQuiz(*quiz)
+			resp.Quizzes++
+		}
+	}
+
+	if template.Has(entity.TemplateContentTaskBoard) && slices.Contains(contents, entity.TemplateContentTaskBoard) && len(template.BoardColumns) > 0 {
+		if err := tts.taskRepo.SaveBoard(entity.NewTaskBoard(team.Id, template.BoardColumns)); err != nil {
+			return nil, err
+		}
+		for _, templateTask := range template.Tasks {
+			task, err := newTaskFromTemplate(templateTask, userID, team.Id)
+			if err != nil {
+				return nil, err
+			}
+			if err := tts.taskRepo.Create(task); err != nil {
+				return nil, err
+			}
+			resp.Tasks++
+		}
+	}
+
+	return resp, nil
+}
+
+// getTemplate returns the template when the user owns it, templates of other users are reported as not found
+func (tts *TeamTemplateService) getTemplate(userID, templateID string) (*entity.TeamTemplate, error) {
+	template, err := tts.templateRepo.GetByID(templateID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, teamTemplateNotFound)
+		}
+		return nil, err
+	}
+	if template.OwnerID != userID {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, teamTemplateNotFound)
+	}
+	return template, nil
+}
+
+func newQuizFromTemplate(templateQuiz entity.TemplateQuiz, userID, teamID string) (*entity.Quiz, error) {
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	questions := make([]entity.Question, len(templateQuiz.Questions))
+	for i, question := range templateQuiz.Questions {
+		questionID, err := generateID()
+		if err != nil {
+			return nil, err
+		}
+		question.ID = questionID
+		questions[i] = question
+	}
+	return entity.NewQuiz(id, templateQuiz.QuizName, userID, teamID, questions), nil
+}
+
+func newTaskFromTemplate(templateTask entity.TemplateTask, userID, teamID string) (*entity.Task, error) {
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	task := entity.NewTask(id, teamID, templateTask.ColumnID, templateTask.Position, userID)
+	task.Title = templateTask.Title
+	task.Description = templateTask.Description
+	task.Labels = templateTask.Labels
+	for _, text := range templateTask.Checklist {
+		itemID, err := generateID()
+		if err != nil {
+			return nil, err
+		}
+		task.Checklist = append(task.Checklist, entity.ChecklistItem{ID: itemID, Text: text})
+	}
+	return task, nil
+}
+
+// selectedContents returns the requested content types, all of them when none is requested
+func selectedContents(include []entity.TemplateContent) []entity.TemplateContent {
+	if len(include) == 0 {
+		return entity.TemplateContents
+	}
+	var contents []entity.TemplateContent
+	for _, content := range entity.TemplateContents {
+		if slices.Contains(include, content) {
+			contents = append(contents, content)
+		}
+	}
+	return contents
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+)
+
+const (
+	topicNotFound       = "topic not found"
+	topicAlreadyExists  = "topic already exists"
+	topicParentNotFound = "parent topic not found"
+	topicParentCycle    = "parent would create a cycle"
+	topicHasChildren    = "topic has child topics"
+	topicInUse          = "topic is still used by users or teams"
+	invalidTopic        = "invalid topic of interest"
+)
+
+// TopicValidator checks that submitted topics of interest exist in the taxonomy
+type TopicValidator interface {
+	ValidateTopics(topics []model.TopicOfInterest) error
+}
+
+type TopicServiceInterface interface {
+	GetTopics(lang string, withUsage bool) ([]*dto.TopicResponse, error)
+	GetTopic(slug model.TopicOfInterest, lang string, withUsage bool) (*dto.TopicResponse, error)
+	CreateTopic(request *dto.TopicRequest) (*dto.TopicResponse, error)
+	UpdateTopic(slug model.TopicOfInterest, request *dto.UpdateTopicRequest) (*dto.TopicResponse, error)
+	DeleteTopic(slug model.TopicOfInterest) error
+}
+
+type TopicService struct {
+	topicRepo persistence.TopicRepositoryInterface
+	userRepo  UserRepositoryInterface
+	teamRepo  TeamRepositoryInterface
+}
+
+func NewTopicService() *TopicService {
+	return &TopicService{
+		topicRepo: persistence.NewTopicRepository(),
+		userRepo:  persistence.NewUserRepository(),
+		teamRepo:  persistence.NewTeamRepository(),
+	}
+}
+
+func NewTopicServiceWithRepo(topicRepo persistence.TopicRepositoryInterface, userRepo UserRepositoryInterface, teamRepo TeamRepositoryInterface) *TopicService {
+	return &TopicService{
+		topicRepo: topicRepo,
+		userRepo:  userRepo,
+		teamRepo:  teamRepo,
+	}
+}
+
+// SeedDefaultTopics stores the default topics when the taxonomy is empty
+func (ts *TopicService) SeedDefaultTopics() error {
+	topics, err := ts.topicRepo.GetAll()
+	if err != nil || len(topics) > 0 {
+		return err
+	}
+
+	now := time.Now().Unix()
+	for _, def := range model.DefaultTopics {
+		if err := ts.topicRepo.Create(entity.NewTopic(def.Slug, def.Names, def.Parent, now, now)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTopics returns every topic sorted by slug, with its children and optionally how many users and teams use it
+func (ts *TopicService) GetTopics(lang string, withUsage bool) ([]*dto.TopicResponse, error) {
+	topics, err := ts.topicRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var userCounts, teamCounts map[model.TopicOfInterest]int
+	if withUsage {
+		if userCounts, teamCounts, err = ts.countUsage(); err != nil {
+			return nil, err
+		}
+	}
+
+	children := childrenBySlug(topics)
+	responses := make([]*dto.TopicResponse, 0, len(topics))
+	for _, topic := range topics {
+		responses = append(responses, newTopicResponse(topic, lang, children, userCounts, teamCounts))
+	}
+
+	sort.Slice(responses, func(i, j int) bool {
+		return responses[i].Slug < responses[j].Slug
+	})
+	return responses, nil
+}
+
+func (ts *TopicService) GetTopic(slug model.TopicOfInterest, lang string, withUsage bool) (*dto.TopicResponse, error) {
+	topics, err := ts.topicRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	topic := findTopic(topics, slug)
+	if topic == nil {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, topicNotFound)
+	}
+
+	var userCounts, teamCounts map[model.TopicOfInterest]int
+	if withUsage {
+		if userCounts, teamCounts, err = ts.countUsage(); err != nil {
+			return nil, err
+		}
+	}
+
+	return newTopicResponse(topic, lang, childrenBySlug(topics), userCounts, teamCounts), nil
+}
+
+func (ts *TopicService) CreateTopic(request *dto.TopicRequest) (*dto.TopicResponse, error) {
+	if err := validator.ValidateTopicRequest(request); err != nil {
+		return nil, err
+	}
+
+	topics, err := ts.topicRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	if findTopic(topics, request.Slug) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, topicAlreadyExists)
+	}
+	if request.ParentSlug != "" && findTopic(topics, request.ParentSlug) == nil {
+		return nil, fmt.Errorf("%w: %s", validator.ErrValidation, topicParentNotFound)
+	}
+
+	now := time.Now().Unix()
+	topic := entity.NewTopic(request.Slug, request.Names, request.ParentSlug, now, now)
+	if err := ts.topicRepo.Create(topic); err != nil {
+		return nil, err
+	}
+
+	return dto.NewTopicResponse(topic, model.DefaultTopicLanguage), nil
+}
+
+// UpdateTopic replaces the display names and the parent of a topic. The slug can not change since users and teams reference it.
+func (ts *TopicService) UpdateTopic(slug model.TopicOfInterest, request *dto.UpdateTopicRequest) (*dto.TopicResponse, error) {
+	if err := validator.ValidateUpdateTopicRequest(slug, request); err != nil {
+		return nil, err
+	}
+
+	topics, err := ts.topicRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	topic := findTopic(topics, slug)
+	if topic == nil {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, topicNotFound)
+	}
+	if request.ParentSlug != "" {
+		if findTopic(topics, request.ParentSlug) == nil {
+			return nil, fmt.Errorf("%w: %s", validator.ErrValidation, topicParentNotFound)
+		}
+		if isTopicDescendant(topics, request.ParentSlug, slug) {
+			return nil, fmt.Errorf("%w: %s", validator.ErrValidation, topicParentCycle)
+		}
+	}
+
+	topic.Names = request.Names
+	topic.ParentSlug = request.ParentSlug
+	topic.UpdatedAt = time.Now().Unix()
+	if err := ts.topicRepo.Update(topic); err != nil {
+		return nil, err
+	}
+
+	return newTopicResponse(topic, model.DefaultTopicLanguage, childrenBySlug(topics), nil, nil), nil
+}
+
+// DeleteTopic removes a topic that has no children and is not used by any user or team
+func (ts *TopicService) DeleteTopic(slug model.TopicOfInterest) error {
+	topics, err := ts.topicRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	if findTopic(topics, slug) == nil {
+		return fmt.Errorf("%w: %s", ErrResourceNotFound, topicNotFound)
+	}
+	if len(childrenBySlug(topics)[slug]) > 0 {
+		return fmt.Errorf("%w: %s", ErrConflict, topicHasChildren)
+	}
+
+	userCounts, teamCounts, err := ts.countUsage()
+	if err != nil {
+		return err
+	}
+	if userCounts[slug] > 0 || teamCounts[slug] > 0 {
+		return fmt.Errorf("%w: %s", ErrConflict, topicInUse)
+	}
+
+	return ts.topicRepo.Delete(slug)
+}
+
+// ValidateTopics rejects the first topic that is not part of the taxonomy
+func (ts *TopicService) ValidateTopics(topics []model.TopicOfInterest) error {
+	if len(topics) == 0 {
+		return nil
+	}
+
+	for _, slug := range topics {
+		if _, err := ts.topicRepo.GetBySlug(slug); err != nil {
+			if strings.Contains(err.Error(), NotFoundError) {
+				return fmt.Errorf("%w: %s %q", validator.ErrValidation, invalidTopic, slug)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (ts *TopicService) countUsage() (map[model.TopicOfInterest]int, map[model.TopicOfInterest]int, error) {
+	users, err := ts.userRepo.GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	teams, err := ts.teamRepo.GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userCounts := make(map[model.TopicOfInterest]int)
+	for _, user := range users {
+		if user.TopicsOfInterest == nil {
+			continue
+		}
+		for _, topic := range *user.TopicsOfInterest {
+			userCounts[topic]++
+		}
+	}
+
+	teamCounts := make(map[model.TopicOfInterest]int)
+	for _, team := range teams {
+		if team.TeamTopic != "" {
+			teamCounts[team.TeamTopic]++
+		}
+	}
+	return userCounts, teamCounts, nil
+}
+
+func findTopic(topics []*entity.Topic, slug model.TopicOfInterest) *entity.Topic {
+	for _, topic := range topics {
+		if topic.Slug == slug {
+			return topic
+		}
+	}
+	return nil
+}
+
+func childrenBySlug(topics []*entity.Topic) map[model.TopicOfInterest][]model.TopicOfInterest {
+	children := make(map[model.TopicOfInterest][]model.TopicOfInterest)
+	for _, topic := range topics {
+		if topic.ParentSlug != "" {
+			children[topic.ParentSlug] = append(children[topic.ParentSlug], topic.Slug)
+		}
+	}
+	for _, slugs := range children {
+		sort.Slice(slugs, func(i, j int) bool { return slugs[i] < slugs[j] })
+	}
+	return children
+}
+
+// isTopicDescendant walks up the parents of slug and reports whether ancestor is among them (or is slug itself)
+func isTopicDescendant(topics []*entity.Topic, slug, ancestor model.TopicOfInterest) bool {
+	visited := make(map[model.TopicOfInterest]bool)
+	for slug != "" && !visited[slug] {
+		if slug == ancestor {
+			return true
+		}
+		visited[slug] = true
+		topic := findTopic(topics, slug)
+		if topic == nil {
+			return false
+		}
+		slug = topic.ParentSlug
+	}
+	return false
+}
+
+func newTopicResponse(topic *entity.Topic, lang string, children map[model.TopicOfInterest][]model.TopicOfInterest, userCounts, teamCounts map[model.TopicOfInterest]int) *dto.TopicResponse {
+	resp := dto.NewTopicResponse(topic, lang)
+	resp.Children = children[topic.Slug]
+	if userCounts != nil {
+		userCount := userCounts[topic.Slug]
+		resp.UserCount = &userCount
+	}
+	if teamCounts != nil {
+		teamCount := teamCounts[topic.Slug]
+		resp.TeamCount = &teamCount
+	}
+	return resp
+}
+
+type noopTopicValidator struct{}
+
+func (noopTopicValidator) ValidateTopics([]model.TopicOfInterest) error { return nil }
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
@@ -24,24 +25,45 @@ const (
 )
 
 type UserService struct {
-	userRepo UserRepositoryInterface
-	teamRepo TeamRepositoryInterface
+	userRepo       UserRepositoryInterface
+	teamRepo       TeamRepositoryInterface
+	searchIndexer  SearchIndexer
+	topicValidator TopicValidator
+	organizations  OrganizationResolver
 }
 
 func NewUserService() *UserService {
 	return &UserService{
-		userRepo: persistence.NewUserRepository(),
-		teamRepo: persistence.NewTeamRepository(),
+		userRepo:       persistence.NewUserRepository(),
+		teamRepo:       persistence.NewTeamRepository(),
+		searchIndexer:  NewSearchService(),
+		topicValidator: NewTopicService(),
+		organizations:  NewOrganizationService(),
 	}
 }
 
 func NewUserServiceWithRepo(userRepo interface{}, teamRepo interface{}) *UserService {
 	return &UserService{
-		userRepo: userRepo.(UserRepositoryInterface),
-		teamRepo: teamRepo.(TeamRepositoryInterface),
+		userRepo:       userRepo.(UserRepositoryInterface),
+		teamRepo:       teamRepo.(TeamRepositoryInterface),
+		searchIndexer:  noopSearchIndexer{},
+		topicValidator: noopTopicValidator{},
+		organizations:  noopOrganizationResolver{},
 	}
 }
 
+func (us *UserService) SetSearchIndexer(indexer SearchIndexer) {
+	us.searchIndexer = indexer
+}
+
+func (us *UserService) SetTopicValidator(topicValidator TopicValidator) {
+	us.topicValidator = topicValidator
+}
+
+func (us *UserService) SetOrganizationResolver(organizations OrganizationResolver) {
+	us.organizations = organizations
+}
+
 type UserRepositoryInterface interface {
 	Create(user *entity.User) error
 	GetByID(id string) (*entity.User, error)
@@ -50,6 +72,7 @@ type UserRepositoryInterface interface {
 	Update(user *entity.User) error
 	Delete(id string) error
 	GetAll() ([]*entity.User, error)
+	GetByOrganizationID(organizationID string) ([]*entity.User, error)
 }
 
 func (us *UserService) SignUp(request *dto.SignUpUserRequest) (*dto.SignUpUserResponse, error) {
@@ -57,6 +80,12 @@ func (us *UserService) SignUp(request *dto.SignUpUserRequest) (*dto.SignUpUserRe
 		return nil, err
 	}
 
+	if request.TopicsOfInterest != nil {
+		if err := us.topicValidator.ValidateTopics(*request.TopicsOfInterest); err != nil {
+			return nil, err
+		}
+	}
+
 	if _, err := us.userRepo.GetByUsername(request.Username); err == nil {
 		return nil, fmt.Errorf(usernameAlreadyExistsError)
 	}
@@ -65,6 +94,11 @@ func (us *UserService) SignUp(request *dto.SignUpUserRequest) (*dto.SignUpUserRe
 		return nil, fmt.Errorf(emailAlreadyExistsError)
 	}
 
+	organizationID, err := us.organizations.ResolveSignupOrganization(request.Email, request.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
@@ -84,10 +118,12 @@ func (us *UserService) SignUp(request *dto.SignUpUserRequest) (*dto.SignUpUserRe
 		string(hashedPassword),
 		request.TopicsOfInterest,
 	)
+	user.OrganizationID = organizationID
 
 	if err := us.userRepo.Create(user); err != nil {
 		return nil, err
 	}
+	us.searchIndexer.IndexUser(user)
 
 	return dto.NewSignUpUserResponse(user.FirstName, user.LastName, user.Username), nil
 }
@@ -105,7 +141,11 @@ func (us *UserService) GetUserByEmail(email string) (*entity.User, error) {
 }
 
 func (us *UserService) UpdateUser(user *entity.User) error {
-	return us.userRepo.Update(user)
+	if err := us.userRepo.Update(user); err != nil {
+		return err
+	}
+	us.searchIndexer.IndexUser(user)
+	return nil
 }
 
 // UpdateUserProfile updates only the provided fields in the user profile (firstname, lastname, username, email, topicsOfInterest)
@@ -127,19 +167,41 @@ func (us *UserService) UpdateUserProfile(userID string, req *dto.UserUpdateReque
 		user.Username = req.Username
 	}
 	if req.Email != "" {
+		if err := us.organizations.CheckMemberEmail(user.OrganizationID, req.Email); err != nil {
+			return nil, err
+		}
 		user.Email = req.Email
 	}
 	if req.TopicsOfInterest != nil {
+		// Users may still have topics from before the taxonomy, so only the added ones are checked
+		if err := us.topicValidator.ValidateTopics(addedTopics(user.TopicsOfInterest, *req.TopicsOfInterest)); err != nil {
+			return nil, err
+		}
 		user.TopicsOfInterest = req.TopicsOfInterest
 	}
 
 	if err := us.userRepo.Update(user); err != nil {
 		return nil, err
 	}
+	us.searchIndexer.IndexUser(user)
 
 	return dto.NewUserUpdateResponseDTO(user), nil
 }
 
+// addedTopics returns the topics that are not already in current
+func addedTopics(current *[]model.TopicOfInterest, topics []model.TopicOfInterest) []model.TopicOfInterest {
+	if current == nil {
+		return topics
+	}
+	added := []model.TopicOfInterest{}
+	for _, topic := range topics {
+		if !slices.Contains(*current, topic) {
+			added = append(added, topic)
+		}
+	}
+	return added
+}
+
 // UpdateUserPassword updates the user's password (requires old password verification)
 func (us *UserService) UpdateUserPassword(userID string, req *dto.UserPasswordRequestDTO) error {
 	if userID != req.ID {
@@ -173,24 +235,48 @@ func (us *UserService) DeleteUser(id string) error {
 	if err != nil {
 		return err
 	}
-	if user.TeamsIds == nil {
-		return us.userRepo.Delete(id)
-	}
-	for _, teamId := range *user.TeamsIds {
-		team, err := us.teamRepo.GetTeamById(teamId)
-		if err != nil {
-			return err
-		}
-		team.UsersIds = removeString(team.UsersIds, user.ID)
-		if err := us.teamRepo.Update(team); err != nil {
-			return err
+	if user.TeamsIds != nil {
+		for _, teamId := range *user.TeamsIds {
+			team, err := us.teamRepo.GetTeamById(teamId)
+			if err != nil {
+				return err
+			}
+			team.UsersIds = removeString(team.UsersIds, user.ID)
+			if err := us.teamRepo.Update(team); err != nil {
+				return err
+			}
 		}
 	}
-	return us.userRepo.Delete(id)
+	if err := us.userRepo.Delete(id); err != nil {
+		return err
+	}
+	us.searchIndexer.RemoveFromIndex(entity.SearchTypeUser, id)
+	return nil
+}
+
+// GetAllUsers returns the users of the viewer's organization
+func (us *UserService) GetAllUsers(viewerID string) ([]*entity.User, error) {
+	viewer, err := us.userRepo.GetByID(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return us.userRepo.GetByOrganizationID(viewer.OrganizationID)
 }
 
-func (us *UserService) GetAllUsers() ([]*entity.User, error) {
-	return us.userRepo.GetAll()
+// GetVisibleUser returns the user if the viewer belongs to the same organization
+func (us *UserService) GetVisibleUser(viewerID, id string) (*entity.User, error) {
+	viewer, err := us.userRepo.GetByID(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := us.userRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !sameOrganization(user, viewer.OrganizationID) {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, userNotFound)
+	}
+	return user, nil
 }
 
 func (us *UserService) GetUserStatistics(id string) (*dto.StatisticsResponse, error) {
@@ -286,6 +372,7 @@ func (us *UserService) Login(request *dto.LoginRequest) (*dto.LoginResponse, err
 		"sub":      user.ID,
 		"username": user.Username,
 		"email":    user.Email,
+		"admin":    user.IsAdmin,
 		"exp":      expiration.Unix(),
 		"iat":      time.Now().Unix(),
 	}
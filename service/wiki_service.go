@@ -0,0 +1,474 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/persistence"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	wikiPageNotFound      = "wiki page not found"
+	wikiRevisionNotFound  = "revision not found"
+	wikiPathTaken         = "a page with this path already exists"
+	wikiPageChanged       = "the page was changed since revision %d, reload it before saving"
+	wikiAlreadyAtRevision = "the page is already at this revision"
+	onlyCreatorOrAdmin    = "only the creator of the page or a team admin can delete it"
+
+	// wikiLinkFile, wikiLinkQuiz and wikiLinkPage are the link kinds resolved when rendering, e.g. [Notes](file:<fileId>)
+	wikiLinkFile = "file"
+	wikiLinkQuiz = "quiz"
+	wikiLinkPage = "wiki"
+)
+
+var wikiLinkKinds = []string{wikiLinkFile, wikiLinkQuiz, wikiLinkPage}
+
+type WikiServiceInterface interface {
+	GetPages(userID, teamID, prefix string) ([]*dto.WikiPageSummary, error)
+	GetPage(userID, teamID, path string) (*dto.WikiPageResponse, error)
+	CreatePage(userID, teamID string, request *dto.WikiPageRequest) (*dto.WikiPageResponse, error)
+	UpdatePage(userID, teamID, pageID string, request *dto.UpdateWikiPageRequest) (*dto.WikiPageResponse, error)
+	DeletePage(userID, teamID, pageID string) error
+	GetRevisions(userID, teamID, pageID string) ([]*dto.WikiRevisionSummary, error)
+	GetRevision(userID, teamID, pageID string, number int) (*dto.WikiRevisionResponse, error)
+	GetDiff(userID, teamID, pageID string, from, to int) (*dto.WikiDiffResponse, error)
+	RevertPage(userID, teamID, pageID string, number int) (*dto.WikiPageResponse, error)
+}
+
+type WikiService struct {
+	wikiRepo persistence.WikiRepositoryInterface
+	teamRepo TeamRepositoryInterface
+	fileRepo persistence.FileRepositoryInterface
+	quizRepo persistence.QuizRepositoryInterface
+}
+
+func NewWikiService() *WikiService {
+	return &WikiService{
+		wikiRepo: persistence.NewWikiRepository(),
+		teamRepo: persistence.NewTeamRepository(),
+		fileRepo: persistence.NewFileRepository(),
+		quizRepo: persistence.NewQuizRepository(),
+	}
+}
+
+func NewWikiServiceWithRepo(wikiRepo persistence.WikiRepositoryInterface, teamRepo TeamRepositoryInterface, fileRepo persistence.FileRepositoryInterface, quizRepo persistence.QuizRepositoryInterface) *WikiService {
+	return &WikiService{
+		wikiRepo: wikiRepo,
+		teamRepo: teamRepo,
+		fileRepo: fileRepo,
+		quizRepo: quizRepo,
+	}
+}
+
+// GetPages lists the team's pages in tree order, only the pages under prefix when it is given
+func (ws *WikiService) GetPages(userID, teamID, prefix string) ([]*dto.WikiPageSummary, error) {
+	if _, err := getTeamForMember(ws.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	pages, err := ws.wikiRepo.GetPagesByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	sortWikiPages(pages)
+
+	prefix = normalizeWikiPath(prefix)
+	result := make([]*dto.WikiPageSummary, 0, len(pages))
+	for _, page := range pages {
+		if prefix != "" && page.Path != prefix && !strings.HasPrefix(page.Path, prefix+"/") {
+			continue
+		}
+		result = append(result, dto.NewWikiPageSummary(page))
+	}
+	return result, nil
+}
+
+func (ws *WikiService) GetPage(userID, teamID, path string) (*dto.WikiPageResponse, error) {
+	if _, err := getTeamForMember(ws.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	pages, err := ws.wikiRepo.GetPagesByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	path = normalizeWikiPath(path)
+	for _, page := range pages {
+		if page.Path == path {
+			return ws.newPageResponse(page, pages), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, wikiPageNotFound)
+}
+
+func (ws *WikiService) CreatePage(userID, teamID string, request *dto.WikiPageRequest) (*dto.WikiPageResponse, error) {
+	path := normalizeWikiPath(request.Path)
+	if err := validator.ValidateWikiPath(path); err != nil {
+		return nil, err
+	}
+	if err := validator.ValidateWikiPageRequest(request); err != nil {
+		return nil, err
+	}
+	if _, err := ws.getTeamForChange(userID, teamID); err != nil {
+		return nil, err
+	}
+
+	pages, err := ws.wikiRepo.GetPagesByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWikiPathAvailable(pages, "", path); err != nil {
+		return nil, err
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+	page := entity.NewWikiPage(id, teamID, path, strings.TrimSpace(request.Title), request.Content, userID)
+	if err := ws.wikiRepo.CreatePage(page); err != nil {
+		return nil, err
+	}
+	if err := ws.wikiRepo.SaveRevision(entity.NewWikiRevision(page, strings.TrimSpace(request.Summary))); err != nil {
+		return nil, err
+	}
+	return ws.newPageResponse(page, append(pages, page)), nil
+}
+
+// UpdatePage saves a new revision of the page; saving it without changes keeps the current revision
+func (ws *WikiService) UpdatePage(userID, teamID, pageID string, request *dto.UpdateWikiPageRequest) (*dto.WikiPageResponse, error) {
+	if err := validator.ValidateUpdateWikiPageRequest(request); err != nil {
+		return nil, err
+	}
+	if _, err := ws.getTeamForChange(userID, teamID); err != nil {
+		return nil, err
+	}
+	page, err := ws.getPage(teamID, pageID)
+	if err != nil {
+		return nil, err
+	}
+	if request.BaseRevision != 0 && request.BaseRevision != page.Revision {
+		return nil, fmt.Errorf("%w: "+wikiPageChanged, ErrConflict, request.BaseRevision)
+	}
+
+	pages, err := ws.wikiRepo.GetPagesByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	path := page.Path
+	if request.Path != "" {
+		path = normalizeWikiPath(request.Path)
+		if err := validator.ValidateWikiPath(path); err != nil {
+			return nil, err
+		}
+		if err := checkWikiPathAvailable(pages, page.ID, path); err != nil {
+			return nil, err
+		}
+	}
+
+	title := strings.TrimSpace(request.Title)
+	if path != page.Path || title != page.Title || request.Content != page.Content {
+		page.Path = path
+		page.Title = title
+		page.Content = request.Content
+		if err := ws.saveRevision(page, userID, strings.TrimSpace(request.Summary), 0); err != nil {
+			return nil, err
+		}
+	}
+	return ws.newPageResponse(page, replaceWikiPage(pages, page)), nil
+}
+
+// DeletePage deletes the page and its history; its child pages are kept
+func (ws *WikiService) DeletePage(userID, teamID, pageID string) error {
+	team, err := ws.getTeamForChange(userID, teamID)
+	if err != nil {
+		return err
+	}
+	page, err := ws.getPage(teamID, pageID)
+	if err != nil {
+		return err
+	}
+	if page.CreatedBy != userID && !team.IsAdmin(userID) {
+		return fmt.Errorf("%w: %s", ErrForbidden, onlyCreatorOrAdmin)
+	}
+
+	if err := ws.wikiRepo.DeleteRevisions(pageID); err != nil {
+		return err
+	}
+	return ws.wikiRepo.DeletePage(pageID)
+}
+
+// GetRevisions returns the page's history, newest first
+func (ws *WikiService) GetRevisions(userID, teamID, pageID string) ([]*dto.WikiRevisionSummary, error) {
+	if _, err := getTeamForMember(ws.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := ws.getPage(teamID, pageID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := ws.wikiRepo.GetRevisions(pageID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Number > revisions[j].Number
+	})
+
+	result := make([]*dto.WikiRevisionSummary, 0, len(revisions))
+	for _, revision := range revisions {
+		result = append(result, dto.NewWikiRevisionSummary(revision))
+	}
+	return result, nil
+}
+
+func (ws *WikiService) GetRevision(userID, teamID, pageID string, number int) (*dto.WikiRevisionResponse, error) {
+	if _, err := getTeamForMember(ws.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := ws.getPage(teamID, pageID); err != nil {
+		return nil, err
+	}
+	revision, err := ws.getRevision(pageID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.WikiRevisionResponse{
+		WikiRevision: *revision,
+		HTML:         utils.RenderMarkdown(revision.Content, wikiLinkKinds, ws.linkResolver(teamID, nil)),
+	}, nil
+}
+
+// GetDiff compares the content of two revisions; to defaults to the current revision and from to the one before it
+func (ws *WikiService) GetDiff(userID, teamID, pageID string, from, to int) (*dto.WikiDiffResponse, error) {
+	if _, err := getTeamForMember(ws.teamRepo, teamID, userID); err != nil {
+		return nil, err
+	}
+	page, err := ws.getPage(teamID, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == 0 {
+		to = page.Revision
+	}
+	if from == 0 {
+		from = max(to-1, 1)
+	}
+	fromRevision, err := ws.getRevision(pageID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := ws.getRevision(pageID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromRevision.Content),
+		B:        difflib.SplitLines(toRevision.Content),
+		FromFile: fmt.Sprintf("revision %d", from),
+		ToFile:   fmt.Sprintf("revision %d", to),
+		Context:  3,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.WikiDiffResponse{
+		PageID:    pageID,
+		From:      from,
+		To:        to,
+		FromTitle: fromRevision.Title,
+		ToTitle:   toRevision.Title,
+		Diff:      diff,
+	}
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			resp.Added++
+		case strings.HasPrefix(line, "-"):
+			resp.Removed++
+		}
+	}
+	return resp, nil
+}
+
+// RevertPage saves the title and content of an older revision as a new revision; the page keeps its current path
+func (ws *WikiService) RevertPage(userID, teamID, pageID string, number int) (*dto.WikiPageResponse, error) {
+	if _, err := ws.getTeamForChange(userID, teamID); err != nil {
+		return nil, err
+	}
+	page, err := ws.getPage(teamID, pageID)
+	if err != nil {
+		return nil, err
+	}
+	if number == page.Revision {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, wikiAlreadyAtRevision)
+	}
+	revision, err := ws.getRevision(pageID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	page.Title = revision.Title
+	page.Content = revision.Content
+	if err := ws.saveRevision(page, userID, fmt.Sprintf("Reverted to revision %d", number), number); err != nil {
+		return nil, err
+	}
+
+	pages, err := ws.wikiRepo.GetPagesByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	return ws.newPageResponse(page, replaceWikiPage(pages, page)), nil
+}
+
+func (ws *WikiService) saveRevision(page *entity.WikiPage, userID, summary string, revertedFrom int) error {
+	page.Revision++
+	page.UpdatedBy = userID
+	page.UpdatedAt = time.Now().UTC()
+
+	revision := entity.NewWikiRevision(page, summary)
+	revision.RevertedFrom = revertedFrom
+	if err := ws.wikiRepo.SaveRevision(revision); err != nil {
+		return err
+	}
+	return ws.wikiRepo.UpdatePage(page)
+}
+
+// newPageResponse renders the page and lists its direct children among the team's pages
+func (ws *WikiService) newPageResponse(page *entity.WikiPage, pages []*entity.WikiPage) *dto.WikiPageResponse {
+	sortWikiPages(pages)
+	children := make([]*dto.WikiPageSummary, 0)
+	for _, other := range pages {
+		if summary := dto.NewWikiPageSummary(other); summary.ParentPath == page.Path {
+			children = append(children, summary)
+		}
+	}
+
+	return &dto.WikiPageResponse{
+		WikiPage: *page,
+		HTML:     utils.RenderMarkdown(page.Content, wikiLinkKinds, ws.linkResolver(page.TeamID, pages)),
+		Children: children,
+	}
+}
+
+// linkResolver resolves links to the team's files, quizzes and wiki pages; anything else, including references that
+// fail to load, is rendered as a broken link. pages may be nil, the team's pages are then loaded on the first wiki link.
+func (ws *WikiService) linkResolver(teamID string, pages []*entity.WikiPage) utils.MarkdownLinkResolver {
+	return func(kind, id string) (string, bool) {
+		switch kind {
+		case wikiLinkFile:
+			file, err := ws.fileRepo.GetByID(id)
+			if err != nil || file.ContextType != entity.FileContextTeam || file.ContextID != teamID {
+				return "", false
+			}
+			return "/teams/" + teamID + "/files/" + id, true
+		case wikiLinkQuiz:
+			quiz, err := ws.quizRepo.GetById(id)
+			if err != nil || quiz.TeamID != teamID {
+				return "", false
+			}
+			return "/quizzes/" + id, true
+		case wikiLinkPage:
+			if pages == nil {
+				loaded, err := ws.wikiRepo.GetPagesByTeamID(teamID)
+				if err != nil {
+					return "", false
+				}
+				pages = loaded
+			}
+			path := normalizeWikiPath(id)
+			for _, page := range pages {
+				if page.Path == path {
+					return "/teams/" + teamID + "/wiki/pages/" + path, true
+				}
+			}
+		}
+		return "", false
+	}
+}
+
+func (ws *WikiService) getTeamForChange(userID, teamID string) (*entity.Team, error) {
+	team, err := getTeamForMember(ws.teamRepo, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTeamNotArchived(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// getPage returns the page when it belongs to the team
+func (ws *WikiService) getPage(teamID, pageID string) (*entity.WikiPage, error) {
+	page, err := ws.wikiRepo.GetPage(pageID)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, wikiPageNotFound)
+		}
+		return nil, err
+	}
+	if page.TeamID != teamID {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, wikiPageNotFound)
+	}
+	return page, nil
+}
+
+func (ws *WikiService) getRevision(pageID string, number int) (*entity.WikiRevision, error) {
+	revision, err := ws.wikiRepo.GetRevision(pageID, number)
+	if err != nil {
+		if strings.Contains(err.Error(), NotFoundError) {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, wikiRevisionNotFound)
+		}
+		return nil, err
+	}
+	return revision, nil
+}
+
+func checkWikiPathAvailable(pages []*entity.WikiPage, pageID, path string) error {
+	for _, page := range pages {
+		if page.ID != pageID && page.Path == path {
+			return fmt.Errorf("%w: %s", ErrConflict, wikiPathTaken)
+		}
+	}
+	return nil
+}
+
+// replaceWikiPage returns the team's pages with the stored copy of page replaced by page
+func replaceWikiPage(pages []*entity.WikiPage, page *entity.WikiPage) []*entity.WikiPage {
+	result := make([]*entity.WikiPage, 0, len(pages))
+	for _, other := range pages {
+		if other.ID != page.ID {
+			result = append(result, other)
+		}
+	}
+	return append(result, page)
+}
+
+// sortWikiPages orders the pages as a tree: every page is followed by its children, siblings by path
+func sortWikiPages(pages []*entity.WikiPage) {
+	sort.Slice(pages, func(i, j int) bool {
+		return strings.ReplaceAll(pages[i].Path, "/", "\x00") < strings.ReplaceAll(pages[j].Path, "/", "\x00")
+	})
+}
+
+// normalizeWikiPath accepts paths typed as "/Courses/Week 1/" and stores them as "courses/week-1"
+func normalizeWikiPath(path string) string {
+	segments := strings.Split(strings.Trim(strings.TrimSpace(path), "/"), "/")
+	for i, segment := range segments {
+		segments[i] = strings.Join(strings.Fields(strings.ToLower(segment)), "-")
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,44 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/config"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/controller"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signToken(t *testing.T, admin bool) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": tests.TestUserID, "admin": admin}).SignedString([]byte(config.GetJWTSecret()))
+	require.NoError(t, err)
+	return token
+}
+
+func TestRequireAdminForQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/topics", controller.RequireAdminForQuery("usage"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	get := func(url, token string) int {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, get("/topics", ""))
+	assert.Equal(t, http.StatusUnauthorized, get("/topics?usage=true", ""))
+	assert.Equal(t, http.StatusForbidden, get("/topics?usage=true", signToken(t, false)))
+	assert.Equal(t, http.StatusOK, get("/topics?usage=true", signToken(t, true)))
+}
@@ -102,10 +102,11 @@ func TestQuizController_GetQuizWithAnswers_Success(t *testing.T) {
 	qc := controller.NewQuizControllerWithService(mockService)
 
 	expectedQuiz := entity.Quiz{ID: "q-1", QuizName: "Quiz 1"}
-	mockService.On("GetQuizWithAnswersById", "q-1").Return(expectedQuiz, nil)
+	mockService.On("GetQuizWithAnswersById", TestUserID, "q-1").Return(expectedQuiz, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Set("userClaims", jwt.MapClaims{"sub": TestUserID})
 	c.Params = []gin.Param{{Key: "id", Value: "q-1"}}
 
 	qc.GetQuizWithAnswers(c)
@@ -124,10 +125,11 @@ func TestQuizController_GetQuizWithAnswers_NotFound(t *testing.T) {
 	mockService := new(tests.MockQuizService)
 	qc := controller.NewQuizControllerWithService(mockService)
 
-	mockService.On("GetQuizWithAnswersById", "missing").Return(entity.Quiz{}, fmt.Errorf("%w: %s", service.ErrResourceNotFound, "quiz not found"))
+	mockService.On("GetQuizWithAnswersById", TestUserID, "missing").Return(entity.Quiz{}, fmt.Errorf("%w: %s", service.ErrResourceNotFound, "quiz not found"))
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Set("userClaims", jwt.MapClaims{"sub": TestUserID})
 	c.Params = []gin.Param{{Key: "id", Value: "missing"}}
 
 	qc.GetQuizWithAnswers(c)
@@ -150,10 +152,11 @@ func TestQuizController_GetQuizWithoutAnswers_Success(t *testing.T) {
 		},
 	}
 
-	mockService.On("GetQuizWithoutAnswersById", "q-1").Return(expectedResponse, nil)
+	mockService.On("GetQuizWithoutAnswersById", TestUserID, "q-1").Return(expectedResponse, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Set("userClaims", jwt.MapClaims{"sub": TestUserID})
 	c.Params = []gin.Param{{Key: "id", Value: "q-1"}}
 
 	qc.GetQuizWithoutAnswers(c)
@@ -174,10 +177,11 @@ func TestQuizController_GetQuizWithoutAnswers_NotFound(t *testing.T) {
 	mockService := new(tests.MockQuizService)
 	qc := controller.NewQuizControllerWithService(mockService)
 
-	mockService.On("GetQuizWithoutAnswersById", "missing").Return(dto.ReadQuizResponse{}, fmt.Errorf("%w: %s", service.ErrResourceNotFound, "quiz not found"))
+	mockService.On("GetQuizWithoutAnswersById", TestUserID, "missing").Return(dto.ReadQuizResponse{}, fmt.Errorf("%w: %s", service.ErrResourceNotFound, "quiz not found"))
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Set("userClaims", jwt.MapClaims{"sub": TestUserID})
 	c.Params = []gin.Param{{Key: "id", Value: "missing"}}
 
 	qc.GetQuizWithoutAnswers(c)
@@ -192,10 +196,11 @@ func TestQuizController_GetQuizWithoutAnswers_ValidationError(t *testing.T) {
 	mockService := new(tests.MockQuizService)
 	qc := controller.NewQuizControllerWithService(mockService)
 
-	mockService.On("GetQuizWithoutAnswersById", "").Return(dto.ReadQuizResponse{}, fmt.Errorf("%w: %s", validator.ErrValidation, "no id specified"))
+	mockService.On("GetQuizWithoutAnswersById", TestUserID, "").Return(dto.ReadQuizResponse{}, fmt.Errorf("%w: %s", validator.ErrValidation, "no id specified"))
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Set("userClaims", jwt.MapClaims{"sub": TestUserID})
 	c.Params = []gin.Param{{Key: "id", Value: ""}}
 
 	qc.GetQuizWithoutAnswers(c)
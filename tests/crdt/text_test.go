@@ -0,0 +1,88 @@
+package crdt_test
+
+import (
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/crdt"
+	"github.com/stretchr/testify/assert"
+)
+
+func insert(counter int, site string, after crdt.ID, value string) crdt.Operation {
+	return crdt.Operation{Type: crdt.OperationInsert, ID: crdt.ID{Counter: counter, Site: site}, After: after, Value: value}
+}
+
+func apply(t *testing.T, text *crdt.Text, ops ...crdt.Operation) {
+	for _, op := range ops {
+		_, err := text.Apply(op)
+		assert.NoError(t, err)
+	}
+}
+
+func TestText_InsertRunAndDelete(t *testing.T) {
+	text := crdt.NewText()
+
+	apply(t, text, insert(1, "a", crdt.ID{}, "helo"))
+	apply(t, text, insert(5, "a", crdt.ID{Counter: 3, Site: "a"}, "l"))
+	apply(t, text, crdt.Operation{Type: crdt.OperationDelete, ID: crdt.ID{Counter: 1, Site: "a"}})
+
+	assert.Equal(t, "ello", text.String())
+	assert.Equal(t, 4, text.Len())
+	assert.Equal(t, 5, text.Clock())
+}
+
+func TestText_ConcurrentEditsConvergeInAnyOrder(t *testing.T) {
+	base := insert(1, "a", crdt.ID{}, "ac")
+	first := crdt.ID{Counter: 1, Site: "a"}
+	// two sites type after "a" at the same time, a third one deletes "c"
+	fromB := insert(3, "b", first, "b")
+	fromC := insert(3, "c", first, "x")
+	deleteC := crdt.Operation{Type: crdt.OperationDelete, ID: crdt.ID{Counter: 2, Site: "a"}}
+	afterB := insert(4, "b", crdt.ID{Counter: 3, Site: "b"}, "!")
+
+	one := crdt.NewText()
+	apply(t, one, base, fromB, afterB, fromC, deleteC)
+	two := crdt.NewText()
+	apply(t, two, base, deleteC, fromC, fromB, afterB)
+
+	assert.Equal(t, one.String(), two.String())
+	assert.Equal(t, "axb!", one.String())
+}
+
+func TestText_ApplyIsIdempotent(t *testing.T) {
+	text := crdt.NewText()
+	op := insert(1, "a", crdt.ID{}, "hi")
+
+	changed, err := text.Apply(op)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = text.Apply(op)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, "hi", text.String())
+
+	_, err = text.Apply(insert(2, "a", crdt.ID{}, "xy"))
+	assert.ErrorIs(t, err, crdt.ErrInvalidOperation)
+}
+
+func TestText_UnknownReferencesAreRejected(t *testing.T) {
+	text := crdt.NewText()
+
+	_, err := text.Apply(insert(2, "a", crdt.ID{Counter: 1, Site: "b"}, "x"))
+	assert.ErrorIs(t, err, crdt.ErrUnknownElement)
+
+	_, err = text.Apply(crdt.Operation{Type: crdt.OperationDelete, ID: crdt.ID{Counter: 1, Site: "b"}})
+	assert.ErrorIs(t, err, crdt.ErrUnknownElement)
+}
+
+func TestText_RestoredTextKeepsTombstones(t *testing.T) {
+	text := crdt.NewText()
+	apply(t, text, insert(1, "a", crdt.ID{}, "abc"), crdt.Operation{Type: crdt.OperationDelete, ID: crdt.ID{Counter: 2, Site: "a"}})
+
+	restored := crdt.NewTextFromElements(text.Elements())
+	// an offline site still refers to the deleted "b"
+	apply(t, restored, insert(4, "z", crdt.ID{Counter: 2, Site: "a"}, "B"))
+
+	assert.Equal(t, "aBc", restored.String())
+	assert.Equal(t, 4, restored.Clock())
+}
@@ -0,0 +1,137 @@
+package hub_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startHub serves a hub whose connections are registered for ?client=; the registered clients are passed to connected
+func startHub(t *testing.T) (*hub.Hub[hub.Message], *httptest.Server, chan *hub.Client[hub.Message]) {
+	h := hub.NewHub[hub.Message]()
+	connected := make(chan *hub.Client[hub.Message], 4)
+	h.OnConnect(func(client *hub.Client[hub.Message]) {
+		connected <- client
+	})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/connect", func(c *gin.Context) {
+		conn, err := hub.AcceptConnection(c)
+		require.NoError(t, err)
+		h.Register(hub.NewClient[hub.Message](c.Query("client"), conn))
+	})
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return h, server, connected
+}
+
+func dial(t *testing.T, server *httptest.Server, clientID string, connected chan *hub.Client[hub.Message]) (*websocket.Conn, *hub.Client[hub.Message]) {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/connect?client="+clientID, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	select {
+	case client := <-connected:
+		return conn, client
+	case <-time.After(2 * time.Second):
+		t.Fatal("the connection was not registered")
+		return nil, nil
+	}
+}
+
+func readType(t *testing.T, conn *websocket.Conn) hub.MessageType {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var msg hub.Message
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg.Type
+}
+
+func TestHub_Send_ReachesEveryConnectionOfTheClient(t *testing.T) {
+	h, server, connected := startHub(t)
+	phone, phoneClient := dial(t, server, "user1", connected)
+	laptop, laptopClient := dial(t, server, "user1", connected)
+
+	assert.NotEqual(t, phoneClient.ConnectionID, laptopClient.ConnectionID)
+	h.Send("user1", *hub.NewMessage(hub.DirectMessage, nil))
+
+	assert.Equal(t, hub.DirectMessage, readType(t, phone))
+	assert.Equal(t, hub.DirectMessage, readType(t, laptop))
+}
+
+func TestHub_Unregister_KeepsTheOtherConnections(t *testing.T) {
+	h, server, connected := startHub(t)
+	var disconnected []string
+	h.OnDisconnect(func(client *hub.Client[hub.Message]) {
+		disconnected = append(disconnected, client.ConnectionID)
+	})
+	_, phoneClient := dial(t, server, "user1", connected)
+	laptop, laptopClient := dial(t, server, "user1", connected)
+
+	h.Unregister(phoneClient)
+	h.Unregister(phoneClient)
+
+	assert.True(t, h.IsConnected("user1"))
+	assert.Equal(t, []string{phoneClient.ConnectionID}, disconnected)
+	h.Send("user1", *hub.NewMessage(hub.MessageEdited, nil))
+	assert.Equal(t, hub.MessageEdited, readType(t, laptop))
+
+	h.Unregister(laptopClient)
+	assert.False(t, h.IsConnected("user1"))
+}
+
+func TestHub_SendToClient_ReachesThatConnectionOnly(t *testing.T) {
+	h, server, connected := startHub(t)
+	phone, phoneClient := dial(t, server, "user1", connected)
+	laptop, _ := dial(t, server, "user1", connected)
+
+	h.SendToClient(phoneClient, *hub.NewMessage(hub.CommandAck, nil))
+	h.Send("user1", *hub.NewMessage(hub.DirectMessage, nil))
+
+	assert.Equal(t, hub.CommandAck, readType(t, phone))
+	assert.Equal(t, hub.DirectMessage, readType(t, phone))
+	assert.Equal(t, hub.DirectMessage, readType(t, laptop))
+}
+
+func TestHub_RegisterWithBacklog_WritesTheBacklogFirst(t *testing.T) {
+	h := hub.NewHub[hub.Message]()
+	backlogRead := make(chan struct{})
+	sent := make(chan struct{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/connect", func(c *gin.Context) {
+		conn, err := hub.AcceptConnection(c)
+		require.NoError(t, err)
+		h.RegisterWithBacklog(hub.NewClient[hub.Message]("user1", conn), func() ([]hub.Message, func(hub.Message) bool, error) {
+			close(backlogRead)
+			// The messages sent while the backlog is read wait for it
+			<-sent
+			seen := func(msg hub.Message) bool {
+				return msg.Seq == 1
+			}
+			return []hub.Message{{Type: hub.MessageEdited, Seq: 1}}, seen, nil
+		})
+	})
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/connect", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	<-backlogRead
+	h.Send("user1", hub.Message{Type: hub.MessageEdited, Seq: 1})
+	h.Send("user1", hub.Message{Type: hub.MessageDeleted, Seq: 2})
+	close(sent)
+
+	assert.Equal(t, hub.MessageEdited, readType(t, conn))
+	assert.Equal(t, hub.MessageDeleted, readType(t, conn))
+}
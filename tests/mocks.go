@@ -58,6 +58,14 @@ func (m *MockUserRepository) GetAll() ([]*entity.User, error) {
 	return args.Get(0).([]*entity.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByOrganizationID(organizationID string) ([]*entity.User, error) {
+	args := m.Called(organizationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.User), args.Error(1)
+}
+
 type MockUserService struct {
 	mock.Mock
 }
@@ -112,14 +120,22 @@ func (m *MockUserService) DeleteUser(id string) error {
 	return args.Error(0)
 }
 
-func (m *MockUserService) GetAllUsers() ([]*entity.User, error) {
-	args := m.Called()
+func (m *MockUserService) GetAllUsers(viewerID string) ([]*entity.User, error) {
+	args := m.Called(viewerID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*entity.User), args.Error(1)
 }
 
+func (m *MockUserService) GetVisibleUser(viewerID, id string) (*entity.User, error) {
+	args := m.Called(viewerID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
 func (m *MockUserService) GetUserStatistics(id string) (*dto.StatisticsResponse, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -239,16 +255,16 @@ func (m *MockTeamRepository) GetTeamById(id string) (*entity.Team, error) {
 	return args.Get(0).(*entity.Team), args.Error(1)
 }
 
-func (m *MockTeamRepository) GetXTeamsByPrefix(prefix string, x int) ([]*entity.Team, error) {
-	args := m.Called(prefix, x)
+func (m *MockTeamRepository) GetXTeamsByPrefix(organizationID, prefix string, x int) ([]*entity.Team, error) {
+	args := m.Called(organizationID, prefix, x)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*entity.Team), args.Error(1)
 }
 
-func (m *MockTeamRepository) GetTeamsByName(name string) ([]*entity.Team, error) {
-	args := m.Called(name)
+func (m *MockTeamRepository) GetTeamsByName(organizationID, name string) ([]*entity.Team, error) {
+	args := m.Called(organizationID, name)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -263,6 +279,14 @@ func (m *MockTeamRepository) GetAll() ([]*entity.Team, error) {
 	return args.Get(0).([]*entity.Team), args.Error(1)
 }
 
+func (m *MockTeamRepository) GetByOrganizationID(organizationID string) ([]*entity.Team, error) {
+	args := m.Called(organizationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Team), args.Error(1)
+}
+
 func (m *MockTeamRepository) Update(team *entity.Team) error {
 	args := m.Called(team)
 	return args.Error(0)
@@ -320,6 +344,14 @@ func (m *MockQuizRepository) GetByTeam(id string, pageSize int, lastKey string)
 	return args.Get(0).([]entity.Quiz), args.String(1), args.Error(2)
 }
 
+func (m *MockQuizRepository) GetAll() ([]entity.Quiz, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Quiz), args.Error(1)
+}
+
 // MockFileRepository is used for file service tests
 type MockFileRepository struct {
 	mock.Mock
@@ -378,16 +410,16 @@ func (m *MockQuizService) CreateQuiz(request entity.Quiz) (dto.CreateQuizRespons
 	return resp, args.Error(1)
 }
 
-func (m *MockQuizService) GetQuizWithAnswersById(id string) (entity.Quiz, error) {
-	args := m.Called(id)
+func (m *MockQuizService) GetQuizWithAnswersById(viewerID, id string) (entity.Quiz, error) {
+	args := m.Called(viewerID, id)
 	if args.Get(0) == nil {
 		return entity.Quiz{}, args.Error(1)
 	}
 	return args.Get(0).(entity.Quiz), args.Error(1)
 }
 
-func (m *MockQuizService) GetQuizWithoutAnswersById(id string) (dto.ReadQuizResponse, error) {
-	args := m.Called(id)
+func (m *MockQuizService) GetQuizWithoutAnswersById(viewerID, id string) (dto.ReadQuizResponse, error) {
+	args := m.Called(viewerID, id)
 	if args.Get(0) == nil {
 		return dto.ReadQuizResponse{}, args.Error(1)
 	}
@@ -417,3 +449,877 @@ func (m *MockQuizService) GetQuizzesByTeam(userId string, teamId string, pageSiz
 	}
 	return args.Get(0).([]dto.ReadQuizResponse), args.String(1), args.Error(2)
 }
+
+// MockSearchRepository is used for search service tests
+type MockSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepository) Save(doc *entity.SearchDocument) error {
+	args := m.Called(doc)
+	return args.Error(0)
+}
+
+func (m *MockSearchRepository) GetByKey(key string) (*entity.SearchDocument, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SearchDocument), args.Error(1)
+}
+
+func (m *MockSearchRepository) GetByKeys(keys []string) (map[string]*entity.SearchDocument, error) {
+	args := m.Called(keys)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*entity.SearchDocument), args.Error(1)
+}
+
+func (m *MockSearchRepository) Delete(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockSearchRepository) GetPostings(token string) (map[string]int, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockSearchRepository) GetPostingsByPrefix(prefix string, limit int) (map[string]map[string]int, error) {
+	args := m.Called(prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]map[string]int), args.Error(1)
+}
+
+func (m *MockSearchRepository) IsEmpty() (bool, error) {
+	args := m.Called()
+	return args.Bool(0), args.Error(1)
+}
+
+// MockTopicRepository is used for topic service tests
+type MockTopicRepository struct {
+	mock.Mock
+}
+
+func (m *MockTopicRepository) Create(topic *entity.Topic) error {
+	args := m.Called(topic)
+	return args.Error(0)
+}
+
+func (m *MockTopicRepository) GetBySlug(slug model.TopicOfInterest) (*entity.Topic, error) {
+	args := m.Called(slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Topic), args.Error(1)
+}
+
+func (m *MockTopicRepository) GetAll() ([]*entity.Topic, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Topic), args.Error(1)
+}
+
+func (m *MockTopicRepository) Update(topic *entity.Topic) error {
+	args := m.Called(topic)
+	return args.Error(0)
+}
+
+func (m *MockTopicRepository) Delete(slug model.TopicOfInterest) error {
+	args := m.Called(slug)
+	return args.Error(0)
+}
+
+// MockChannelRepository is used for channel service tests
+type MockChannelRepository struct {
+	mock.Mock
+}
+
+func (m *MockChannelRepository) Create(channel *entity.Channel) error {
+	args := m.Called(channel)
+	return args.Error(0)
+}
+
+func (m *MockChannelRepository) GetByID(id string) (*entity.Channel, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Channel), args.Error(1)
+}
+
+func (m *MockChannelRepository) GetByTeamID(teamId string) ([]*entity.Channel, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Channel), args.Error(1)
+}
+
+func (m *MockChannelRepository) Update(channel *entity.Channel) error {
+	args := m.Called(channel)
+	return args.Error(0)
+}
+
+// MockMessageRepository is used for channel service tests
+type MockMessageRepository struct {
+	mock.Mock
+}
+
+func (m *MockMessageRepository) Create(message *entity.Message) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepository) GetByID(id string) (*entity.Message, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) GetByConversation(user1Id, user2Id string) ([]*entity.Message, error) {
+	args := m.Called(user1Id, user2Id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) GetByTeamID(teamId string) ([]*entity.Message, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) GetByChannelID(channelId string) ([]*entity.Message, error) {
+	args := m.Called(channelId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) GetByParentID(parentId string) ([]*entity.Message, error) {
+	args := m.Called(parentId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) GetHistory(startAt, endAt string, limit int, newest bool) ([]*entity.Message, error) {
+	args := m.Called(startAt, endAt, limit, newest)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) GetAll() ([]*entity.Message, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) Update(id string, updates map[string]interface{}) error {
+	args := m.Called(id, updates)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepository) AddReply(id string, lastReplyAt int64) (*entity.Message, error) {
+	args := m.Called(id, lastReplyAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) UpdateReactions(id string, update func(reactions map[string][]string) error) (map[string][]string, error) {
+	args := m.Called(id)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	reactions := args.Get(0).(map[string][]string)
+	if err := update(reactions); err != nil {
+		return nil, err
+	}
+	return reactions, nil
+}
+
+func (m *MockMessageRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockTeamEventRepository is used for team activity tests
+type MockTeamEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockTeamEventRepository) Create(event *entity.TeamEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockTeamEventRepository) GetByTeamID(teamId string) ([]*entity.TeamEvent, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.TeamEvent), args.Error(1)
+}
+
+// MockEventRecorder captures the recorded team events
+type MockEventRecorder struct {
+	Events []*entity.TeamEvent
+}
+
+func (m *MockEventRecorder) Record(event *entity.TeamEvent) {
+	m.Events = append(m.Events, event)
+}
+
+// MockStudySessionRepository is used for study session tests
+type MockStudySessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockStudySessionRepository) Create(session *entity.StudySession) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockStudySessionRepository) GetByID(id string) (*entity.StudySession, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.StudySession), args.Error(1)
+}
+
+func (m *MockStudySessionRepository) GetByTeamID(teamId string) ([]*entity.StudySession, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StudySession), args.Error(1)
+}
+
+func (m *MockStudySessionRepository) GetAll() ([]*entity.StudySession, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StudySession), args.Error(1)
+}
+
+func (m *MockStudySessionRepository) Update(session *entity.StudySession) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockStudySessionRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockCalendarFeedRepository is used for calendar feed tests
+type MockCalendarFeedRepository struct {
+	mock.Mock
+}
+
+func (m *MockCalendarFeedRepository) Create(feed *entity.CalendarFeed) error {
+	args := m.Called(feed)
+	return args.Error(0)
+}
+
+func (m *MockCalendarFeedRepository) GetByID(id string) (*entity.CalendarFeed, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.CalendarFeed), args.Error(1)
+}
+
+func (m *MockCalendarFeedRepository) GetByUserID(userId string) ([]*entity.CalendarFeed, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.CalendarFeed), args.Error(1)
+}
+
+func (m *MockCalendarFeedRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockTaskRepository is used for task board tests
+type MockTaskRepository struct {
+	mock.Mock
+}
+
+func (m *MockTaskRepository) GetBoard(teamId string) (*entity.TaskBoard, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TaskBoard), args.Error(1)
+}
+
+func (m *MockTaskRepository) SaveBoard(board *entity.TaskBoard) error {
+	args := m.Called(board)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) Create(task *entity.Task) error {
+	args := m.Called(task)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) GetByID(id string) (*entity.Task, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetByTeamID(teamId string) ([]*entity.Task, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) Update(task *entity.Task) error {
+	args := m.Called(task)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) CreateComment(comment *entity.TaskComment) error {
+	args := m.Called(comment)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) GetComments(taskId string) ([]*entity.TaskComment, error) {
+	args := m.Called(taskId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.TaskComment), args.Error(1)
+}
+
+func (m *MockTaskRepository) DeleteComments(taskId string) error {
+	args := m.Called(taskId)
+	return args.Error(0)
+}
+
+// MockAnnouncementRepository is used for announcement tests
+type MockAnnouncementRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnnouncementRepository) Create(announcement *entity.Announcement) error {
+	args := m.Called(announcement)
+	return args.Error(0)
+}
+
+func (m *MockAnnouncementRepository) GetByID(id string) (*entity.Announcement, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementRepository) GetByTeamID(teamId string) ([]*entity.Announcement, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementRepository) Update(announcement *entity.Announcement) error {
+	args := m.Called(announcement)
+	return args.Error(0)
+}
+
+func (m *MockAnnouncementRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockModerationRepository is used for team moderation tests
+type MockModerationRepository struct {
+	mock.Mock
+}
+
+func (m *MockModerationRepository) GetBan(teamId, userId string) (*entity.TeamRestriction, error) {
+	args := m.Called(teamId, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TeamRestriction), args.Error(1)
+}
+
+func (m *MockModerationRepository) GetBans(teamId string) ([]*entity.TeamRestriction, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.TeamRestriction), args.Error(1)
+}
+
+func (m *MockModerationRepository) SaveBan(ban *entity.TeamRestriction) error {
+	args := m.Called(ban)
+	return args.Error(0)
+}
+
+func (m *MockModerationRepository) DeleteBan(teamId, userId string) error {
+	args := m.Called(teamId, userId)
+	return args.Error(0)
+}
+
+func (m *MockModerationRepository) GetMute(teamId, userId string) (*entity.TeamRestriction, error) {
+	args := m.Called(teamId, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TeamRestriction), args.Error(1)
+}
+
+func (m *MockModerationRepository) GetMutes(teamId string) ([]*entity.TeamRestriction, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.TeamRestriction), args.Error(1)
+}
+
+func (m *MockModerationRepository) SaveMute(mute *entity.TeamRestriction) error {
+	args := m.Called(mute)
+	return args.Error(0)
+}
+
+func (m *MockModerationRepository) DeleteMute(teamId, userId string) error {
+	args := m.Called(teamId, userId)
+	return args.Error(0)
+}
+
+func (m *MockModerationRepository) CreateLogEntry(entry *entity.ModerationLogEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockModerationRepository) GetLog(teamId string) ([]*entity.ModerationLogEntry, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ModerationLogEntry), args.Error(1)
+}
+
+// MockTeamTemplateRepository is used for team template tests
+type MockTeamTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockTeamTemplateRepository) Create(template *entity.TeamTemplate) error {
+	args := m.Called(template)
+	return args.Error(0)
+}
+
+func (m *MockTeamTemplateRepository) GetByID(id string) (*entity.TeamTemplate, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TeamTemplate), args.Error(1)
+}
+
+func (m *MockTeamTemplateRepository) GetByOwnerID(ownerId string) ([]*entity.TeamTemplate, error) {
+	args := m.Called(ownerId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.TeamTemplate), args.Error(1)
+}
+
+func (m *MockTeamTemplateRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockForumRepository is used for Q&A board tests
+type MockForumRepository struct {
+	mock.Mock
+}
+
+func (m *MockForumRepository) CreateQuestion(question *entity.ForumQuestion) error {
+	args := m.Called(question)
+	return args.Error(0)
+}
+
+func (m *MockForumRepository) GetQuestion(id string) (*entity.ForumQuestion, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ForumQuestion), args.Error(1)
+}
+
+func (m *MockForumRepository) GetQuestionsByTeamID(teamId string) ([]*entity.ForumQuestion, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ForumQuestion), args.Error(1)
+}
+
+func (m *MockForumRepository) UpdateQuestion(question *entity.ForumQuestion) error {
+	args := m.Called(question)
+	return args.Error(0)
+}
+
+func (m *MockForumRepository) DeleteQuestion(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockForumRepository) SaveAnswer(answer *entity.ForumAnswer) error {
+	args := m.Called(answer)
+	return args.Error(0)
+}
+
+func (m *MockForumRepository) GetAnswer(questionId, id string) (*entity.ForumAnswer, error) {
+	args := m.Called(questionId, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ForumAnswer), args.Error(1)
+}
+
+func (m *MockForumRepository) GetAnswers(questionId string) ([]*entity.ForumAnswer, error) {
+	args := m.Called(questionId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ForumAnswer), args.Error(1)
+}
+
+func (m *MockForumRepository) DeleteAnswer(questionId, id string) error {
+	args := m.Called(questionId, id)
+	return args.Error(0)
+}
+
+func (m *MockForumRepository) DeleteAnswers(questionId string) error {
+	args := m.Called(questionId)
+	return args.Error(0)
+}
+
+// MockWikiRepository is used for team wiki tests
+type MockWikiRepository struct {
+	mock.Mock
+}
+
+func (m *MockWikiRepository) CreatePage(page *entity.WikiPage) error {
+	args := m.Called(page)
+	return args.Error(0)
+}
+
+func (m *MockWikiRepository) GetPage(id string) (*entity.WikiPage, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WikiPage), args.Error(1)
+}
+
+func (m *MockWikiRepository) GetPagesByTeamID(teamId string) ([]*entity.WikiPage, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.WikiPage), args.Error(1)
+}
+
+func (m *MockWikiRepository) UpdatePage(page *entity.WikiPage) error {
+	args := m.Called(page)
+	return args.Error(0)
+}
+
+func (m *MockWikiRepository) DeletePage(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWikiRepository) SaveRevision(revision *entity.WikiRevision) error {
+	args := m.Called(revision)
+	return args.Error(0)
+}
+
+func (m *MockWikiRepository) GetRevision(pageId string, number int) (*entity.WikiRevision, error) {
+	args := m.Called(pageId, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WikiRevision), args.Error(1)
+}
+
+func (m *MockWikiRepository) GetRevisions(pageId string) ([]*entity.WikiRevision, error) {
+	args := m.Called(pageId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.WikiRevision), args.Error(1)
+}
+
+func (m *MockWikiRepository) DeleteRevisions(pageId string) error {
+	args := m.Called(pageId)
+	return args.Error(0)
+}
+
+// MockNoteRepository is used for collaborative note tests
+type MockNoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockNoteRepository) Create(note *entity.Note) error {
+	args := m.Called(note)
+	return args.Error(0)
+}
+
+func (m *MockNoteRepository) GetByID(id string) (*entity.Note, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Note), args.Error(1)
+}
+
+func (m *MockNoteRepository) GetByTeamID(teamId string) ([]*entity.Note, error) {
+	args := m.Called(teamId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Note), args.Error(1)
+}
+
+func (m *MockNoteRepository) Update(note *entity.Note) error {
+	args := m.Called(note)
+	return args.Error(0)
+}
+
+func (m *MockNoteRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockNoteRepository) SaveSnapshot(snapshot *entity.NoteSnapshot) error {
+	args := m.Called(snapshot)
+	return args.Error(0)
+}
+
+func (m *MockNoteRepository) GetSnapshot(noteId string) (*entity.NoteSnapshot, error) {
+	args := m.Called(noteId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.NoteSnapshot), args.Error(1)
+}
+
+func (m *MockNoteRepository) DeleteSnapshot(noteId string) error {
+	args := m.Called(noteId)
+	return args.Error(0)
+}
+
+type MockOrganizationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationRepository) Create(organization *entity.Organization) error {
+	args := m.Called(organization)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) GetByID(id string) (*entity.Organization, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) GetAll() ([]*entity.Organization, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) Update(organization *entity.Organization) error {
+	args := m.Called(organization)
+	return args.Error(0)
+}
+
+type MockReadMarkerRepository struct {
+	mock.Mock
+}
+
+func (m *MockReadMarkerRepository) Get(userId, conversationId string) (*entity.ReadMarker, error) {
+	args := m.Called(userId, conversationId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReadMarker), args.Error(1)
+}
+
+func (m *MockReadMarkerRepository) GetByUser(userId string) ([]*entity.ReadMarker, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ReadMarker), args.Error(1)
+}
+
+func (m *MockReadMarkerRepository) Save(marker *entity.ReadMarker) error {
+	args := m.Called(marker)
+	return args.Error(0)
+}
+
+type MockConversationRepository struct {
+	mock.Mock
+}
+
+func (m *MockConversationRepository) Get(userId, conversationId string) (*entity.ConversationEntry, error) {
+	args := m.Called(userId, conversationId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ConversationEntry), args.Error(1)
+}
+
+func (m *MockConversationRepository) GetByUser(userId string) ([]*entity.ConversationEntry, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ConversationEntry), args.Error(1)
+}
+
+func (m *MockConversationRepository) GetPage(userId string, before int64, limit int) ([]*entity.ConversationEntry, error) {
+	args := m.Called(userId, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ConversationEntry), args.Error(1)
+}
+
+func (m *MockConversationRepository) Save(entry *entity.ConversationEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+// Update applies the update to the entry set up for the conversation and saves the result, so tests can assert on Save
+func (m *MockConversationRepository) Update(userId, conversationId string, update func(entry *entity.ConversationEntry) *entity.ConversationEntry) error {
+	args := m.Called(userId, conversationId)
+	if args.Error(1) != nil {
+		return args.Error(1)
+	}
+	var entry *entity.ConversationEntry
+	if args.Get(0) != nil {
+		entry = args.Get(0).(*entity.ConversationEntry)
+	}
+	if updated := update(entry); updated != nil {
+		return m.Save(updated)
+	}
+	return nil
+}
+
+func (m *MockConversationRepository) IsEmpty() (bool, error) {
+	args := m.Called()
+	return args.Bool(0), args.Error(1)
+}
+
+type MockPresenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockPresenceRepository) Get(userId string) (*entity.Presence, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Presence), args.Error(1)
+}
+
+func (m *MockPresenceRepository) Save(presence *entity.Presence) error {
+	args := m.Called(presence)
+	return args.Error(0)
+}
+
+type MockMigrationRepository struct {
+	mock.Mock
+}
+
+func (m *MockMigrationRepository) IsDone(name string) (bool, error) {
+	args := m.Called(name)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMigrationRepository) MarkDone(name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) GetLast(userId string) (*entity.OutboxEntry, error) {
+	args := m.Called(userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.OutboxEntry), args.Error(1)
+}
+
+func (m *MockOutboxRepository) GetAfter(userId string, seq int64) ([]*entity.OutboxEntry, error) {
+	args := m.Called(userId, seq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.OutboxEntry), args.Error(1)
+}
+
+func (m *MockOutboxRepository) SaveAll(userId string, entries []*entity.OutboxEntry) error {
+	args := m.Called(userId, entries)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) DeleteUpTo(userId string, seq int64) error {
+	args := m.Called(userId, seq)
+	return args.Error(0)
+}
@@ -0,0 +1,135 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newAnnouncementTeam returns a team owned by TestUserID1 with TestUserID2 as a plain member
+func newAnnouncementTeam() *entity.Team {
+	return &entity.Team{Id: tests.TestTeamID, OwnerId: tests.TestUserID1, UsersIds: []string{tests.TestUserID1, tests.TestUserID2, tests.TestUserID}}
+}
+
+type announcementMocks struct {
+	announcementRepo *tests.MockAnnouncementRepository
+	teamRepo         *tests.MockTeamRepository
+	userRepo         *tests.MockUserRepository
+	fileRepo         *tests.MockFileRepository
+	messageRepo      *tests.MockMessageRepository
+}
+
+func newAnnouncementService() (*service.AnnouncementService, *announcementMocks) {
+	m := &announcementMocks{
+		announcementRepo: new(tests.MockAnnouncementRepository),
+		teamRepo:         new(tests.MockTeamRepository),
+		userRepo:         new(tests.MockUserRepository),
+		fileRepo:         new(tests.MockFileRepository),
+		messageRepo:      new(tests.MockMessageRepository),
+	}
+	return service.NewAnnouncementServiceWithRepo(m.announcementRepo, m.teamRepo, m.userRepo, m.fileRepo, m.messageRepo), m
+}
+
+func TestAnnouncementService_CreateAnnouncement_OnlyAdmins(t *testing.T) {
+	as, m := newAnnouncementService()
+	m.teamRepo.On("GetTeamById", tests.TestTeamID).Return(newAnnouncementTeam(), nil)
+
+	_, err := as.CreateAnnouncement(tests.TestUserID2, tests.TestTeamID, &dto.AnnouncementRequest{Title: "Exam", Body: "Friday"})
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	m.announcementRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAnnouncementService_CreateAnnouncement_RecordsEventForAdmin(t *testing.T) {
+	as, m := newAnnouncementService()
+	recorder := &tests.MockEventRecorder{}
+	as.SetEventRecorder(recorder)
+	team := newAnnouncementTeam()
+	team.AdminIds = []string{tests.TestUserID2}
+	m.teamRepo.On("GetTeamById", tests.TestTeamID).Return(team, nil)
+	m.fileRepo.On("GetByID", "file1").Return(&entity.File{ID: "file1", ContextType: entity.FileContextTeam, ContextID: tests.TestTeamID}, nil)
+	m.announcementRepo.On("Create", mock.AnythingOfType("*entity.Announcement")).Return(nil)
+
+	resp, err := as.CreateAnnouncement(tests.TestUserID2, tests.TestTeamID, &dto.AnnouncementRequest{Title: " Exam ", Body: "**Friday**", AttachmentFileID: "file1", Pinned: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Exam", resp.Title)
+	assert.NotZero(t, resp.PinnedAt)
+	assert.ElementsMatch(t, team.UsersIds, resp.PendingMemberIDs)
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, entity.TeamEventAnnouncement, recorder.Events[0].Type)
+}
+
+func TestAnnouncementService_CreateAnnouncement_AttachmentFromAnotherTeam(t *testing.T) {
+	as, m := newAnnouncementService()
+	m.teamRepo.On("GetTeamById", tests.TestTeamID).Return(newAnnouncementTeam(), nil)
+	m.fileRepo.On("GetByID", "file1").Return(&entity.File{ID: "file1", ContextType: entity.FileContextTeam, ContextID: tests.TestTeamID2}, nil)
+
+	_, err := as.CreateAnnouncement(tests.TestUserID1, tests.TestTeamID, &dto.AnnouncementRequest{Title: "Exam", Body: "Friday", AttachmentFileID: "file1"})
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+	m.announcementRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAnnouncementService_Acknowledge_KeepsFirstTime(t *testing.T) {
+	as, m := newAnnouncementService()
+	m.teamRepo.On("GetTeamById", tests.TestTeamID).Return(newAnnouncementTeam(), nil)
+	m.announcementRepo.On("GetByID", "a1").Return(&entity.Announcement{
+		ID:               "a1",
+		TeamID:           tests.TestTeamID,
+		Acknowledgements: map[string]int64{tests.TestUserID2: 100},
+	}, nil)
+
+	resp, err := as.Acknowledge(tests.TestUserID2, tests.TestTeamID, "a1")
+
+	assert.NoError(t, err)
+	assert.True(t, resp.AcknowledgedByMe)
+	assert.Equal(t, 1, resp.AcknowledgedCount)
+	assert.Nil(t, resp.Acknowledgements)
+	m.announcementRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestAnnouncementService_GetAnnouncement_AdminSeesPendingMembers(t *testing.T) {
+	as, m := newAnnouncementService()
+	m.teamRepo.On("GetTeamById", tests.TestTeamID).Return(newAnnouncementTeam(), nil)
+	m.announcementRepo.On("GetByID", "a1").Return(&entity.Announcement{
+		ID:               "a1",
+		TeamID:           tests.TestTeamID,
+		Acknowledgements: map[string]int64{tests.TestUserID2: 100},
+	}, nil)
+
+	resp, err := as.GetAnnouncement(tests.TestUserID1, tests.TestTeamID, "a1")
+
+	assert.NoError(t, err)
+	assert.False(t, resp.AcknowledgedByMe)
+	assert.Equal(t, []string{tests.TestUserID1, tests.TestUserID}, resp.PendingMemberIDs)
+}
+
+func TestAnnouncementService_SetMessagePinned_MessageOfAnotherTeam(t *testing.T) {
+	as, m := newAnnouncementService()
+	m.teamRepo.On("GetTeamById", tests.TestTeamID).Return(newAnnouncementTeam(), nil)
+	m.messageRepo.On("GetByID", "m1").Return(&entity.Message{ID: "m1", TeamID: tests.TestTeamID2}, nil)
+
+	_, err := as.SetMessagePinned(tests.TestUserID1, tests.TestTeamID, "m1", true)
+
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+	m.messageRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestTeamService_SetAdmin_OnlyOwner(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTeamServiceWithRepo(mockUserRepo, mockTeamRepo)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(newAnnouncementTeam(), nil)
+
+	_, err := ts.SetAdmin(tests.TestUserID2, tests.TestTeamID, tests.TestUserID, true)
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockTeamRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newCalendarServiceMocks() (*service.CalendarService, *tests.MockCalendarFeedRepository, *tests.MockStudySessionRepository, *tests.MockTeamRepository, *tests.MockUserRepository) {
+	mockFeedRepo := new(tests.MockCalendarFeedRepository)
+	mockSessionRepo := new(tests.MockStudySessionRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockUserRepo := new(tests.MockUserRepository)
+	cs := service.NewCalendarServiceWithRepo(mockFeedRepo, mockSessionRepo, mockTeamRepo, mockUserRepo)
+	return cs, mockFeedRepo, mockSessionRepo, mockTeamRepo, mockUserRepo
+}
+
+func TestCalendarService_CreateFeed_RotatesExistingFeed(t *testing.T) {
+	cs, mockFeedRepo, _, mockTeamRepo, _ := newCalendarServiceMocks()
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID}}, nil)
+	mockFeedRepo.On("GetByUserID", tests.TestUserID).Return([]*entity.CalendarFeed{
+		{ID: "old-team-feed", UserID: tests.TestUserID, TeamID: tests.TestTeamID},
+		{ID: "personal-feed", UserID: tests.TestUserID},
+	}, nil)
+	mockFeedRepo.On("Delete", "old-team-feed").Return(nil)
+	var stored *entity.CalendarFeed
+	mockFeedRepo.On("Create", mock.AnythingOfType("*entity.CalendarFeed")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*entity.CalendarFeed)
+	}).Return(nil)
+
+	resp, err := cs.CreateFeed(tests.TestUserID, &dto.CalendarFeedRequest{TeamID: tests.TestTeamID})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
+	// Only the hash of the token is stored
+	assert.NotEqual(t, resp.Token, stored.ID)
+	assert.NotContains(t, stored.ID, resp.Token)
+	mockFeedRepo.AssertNotCalled(t, "Delete", "personal-feed")
+}
+
+func TestCalendarService_GetFeedCalendar_TeamFeed(t *testing.T) {
+	cs, mockFeedRepo, mockSessionRepo, mockTeamRepo, _ := newCalendarServiceMocks()
+
+	loc, _ := time.LoadLocation("Europe/Bucharest")
+	start := time.Date(2025, time.June, 2, 19, 0, 0, 0, loc)
+	mockFeedRepo.On("GetByID", mock.AnythingOfType("string")).Return(&entity.CalendarFeed{ID: "f1", UserID: tests.TestUserID, TeamID: tests.TestTeamID}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, Name: "Algebra", UsersIds: []string{tests.TestUserID}}, nil)
+	mockSessionRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.StudySession{{
+		ID:              "s1",
+		TeamID:          tests.TestTeamID,
+		Title:           "Exam prep, chapter 4; limits",
+		Description:     strings.Repeat("Bring the exercises from the last seminar. ", 3),
+		StartAt:         start.UTC(),
+		EndAt:           start.Add(2 * time.Hour).UTC(),
+		Timezone:        "Europe/Bucharest",
+		Recurrence:      &entity.RecurrenceRule{Frequency: entity.RecurrenceWeekly, Interval: 2, Count: 6},
+		ReminderMinutes: 30,
+		Sequence:        3,
+	}}, nil)
+
+	calendar, err := cs.GetFeedCalendar("token")
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(calendar, "BEGIN:VCALENDAR\r\n"))
+	assert.Contains(t, calendar, "X-WR-CALNAME:Algebra study sessions\r\n")
+	assert.Contains(t, calendar, "BEGIN:VTIMEZONE\r\nTZID:Europe/Bucharest\r\n")
+	assert.Contains(t, calendar, "TZOFFSETFROM:+0200\r\nTZOFFSETTO:+0300\r\n")
+	assert.Contains(t, calendar, "DTSTART;TZID=Europe/Bucharest:20250602T190000\r\n")
+	assert.Contains(t, calendar, "RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=6\r\n")
+	assert.Contains(t, calendar, "SEQUENCE:3\r\n")
+	assert.Contains(t, calendar, `SUMMARY:Exam prep\, chapter 4\; limits`)
+	assert.Contains(t, calendar, "TRIGGER:-PT30M\r\n")
+	for _, line := range strings.Split(calendar, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+}
+
+func TestCalendarService_GetFeedCalendar_TeamFeedStopsAfterLeaving(t *testing.T) {
+	cs, mockFeedRepo, _, mockTeamRepo, _ := newCalendarServiceMocks()
+
+	mockFeedRepo.On("GetByID", mock.AnythingOfType("string")).Return(&entity.CalendarFeed{ID: "f1", UserID: tests.TestUserID, TeamID: tests.TestTeamID}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID2}}, nil)
+
+	_, err := cs.GetFeedCalendar("token")
+
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+}
+
+func TestCalendarService_GetFeedCalendar_UserFeedSkipsDeclined(t *testing.T) {
+	cs, mockFeedRepo, mockSessionRepo, _, mockUserRepo := newCalendarServiceMocks()
+
+	start := time.Date(2025, time.June, 2, 16, 0, 0, 0, time.UTC)
+	teamIDs := []string{tests.TestTeamID}
+	mockFeedRepo.On("GetByID", mock.AnythingOfType("string")).Return(&entity.CalendarFeed{ID: "f1", UserID: tests.TestUserID}, nil)
+	mockUserRepo.On("GetByID", tests.TestUserID).Return(&entity.User{ID: tests.TestUserID, TeamsIds: &teamIDs}, nil)
+	mockSessionRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.StudySession{
+		{ID: "going", Title: "Going", StartAt: start, EndAt: start.Add(time.Hour)},
+		{ID: "declined", Title: "Declined", StartAt: start, EndAt: start.Add(time.Hour), RSVPs: map[string]entity.RSVPStatus{tests.TestUserID: entity.RSVPDeclined}},
+	}, nil)
+
+	calendar, err := cs.GetFeedCalendar("token")
+
+	assert.NoError(t, err)
+	assert.Contains(t, calendar, "UID:going@studywithme\r\n")
+	assert.Contains(t, calendar, "DTSTART:20250602T160000Z\r\n")
+	assert.NotContains(t, calendar, "declined@studywithme")
+	assert.NotContains(t, calendar, "VTIMEZONE")
+}
+
+func TestCalendarService_RevokeFeed_OtherUser(t *testing.T) {
+	cs, mockFeedRepo, _, _, _ := newCalendarServiceMocks()
+
+	mockFeedRepo.On("GetByID", "f1").Return(&entity.CalendarFeed{ID: "f1", UserID: tests.TestUserID2}, nil)
+	mockFeedRepo.On("GetByID", "missing").Return(nil, errors.New("calendar feed not found"))
+
+	assert.ErrorIs(t, cs.RevokeFeed(tests.TestUserID, "f1"), service.ErrResourceNotFound)
+	assert.ErrorIs(t, cs.RevokeFeed(tests.TestUserID, "missing"), service.ErrResourceNotFound)
+	mockFeedRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
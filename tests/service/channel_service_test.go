@@ -0,0 +1,165 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type channelServiceMocks struct {
+	channelRepo *tests.MockChannelRepository
+	teamRepo    *tests.MockTeamRepository
+	userRepo    *tests.MockUserRepository
+	messageRepo *tests.MockMessageRepository
+}
+
+func newChannelService() (*service.ChannelService, *channelServiceMocks) {
+	m := &channelServiceMocks{
+		channelRepo: new(tests.MockChannelRepository),
+		teamRepo:    new(tests.MockTeamRepository),
+		userRepo:    new(tests.MockUserRepository),
+		messageRepo: new(tests.MockMessageRepository),
+	}
+	m.teamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, OwnerId: tests.TestUserID, UsersIds: []string{tests.TestUserID, tests.TestUserID2}}, nil)
+	return service.NewChannelServiceWithRepo(m.channelRepo, m.teamRepo, m.userRepo, m.messageRepo), m
+}
+
+func defaultChannel() *entity.Channel {
+	return entity.NewChannel(entity.GetDefaultChannelID(tests.TestTeamID), tests.TestTeamID, entity.DefaultChannelName, "", 0)
+}
+
+func TestChannelService_GetChannels_CreatesDefaultChannel(t *testing.T) {
+	cs, m := newChannelService()
+
+	archived := entity.NewChannel("c2", tests.TestTeamID, "old", tests.TestUserID, 0)
+	archived.Archived = true
+	m.channelRepo.On("GetByID", entity.GetDefaultChannelID(tests.TestTeamID)).Return(nil, errors.New("channel not found"))
+	m.channelRepo.On("Create", mock.AnythingOfType("*entity.Channel")).Return(nil)
+	m.channelRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.Channel{
+		entity.NewChannel("c1", tests.TestTeamID, "exam-prep", tests.TestUserID, 0),
+		archived,
+		defaultChannel(),
+	}, nil)
+
+	channels, err := cs.GetChannels(tests.TestUserID, tests.TestTeamID, false)
+
+	assert.NoError(t, err)
+	assert.Len(t, channels, 2)
+	assert.Equal(t, entity.DefaultChannelName, channels[0].Name)
+	assert.Equal(t, "exam-prep", channels[1].Name)
+	m.channelRepo.AssertCalled(t, "Create", mock.MatchedBy(func(c *entity.Channel) bool { return c.IsDefault() }))
+}
+
+func TestChannelService_GetChannels_NotMember(t *testing.T) {
+	cs, _ := newChannelService()
+
+	_, err := cs.GetChannels("stranger", tests.TestTeamID, false)
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+}
+
+func TestChannelService_CreateChannel_NormalizesAndRejectsDuplicates(t *testing.T) {
+	cs, m := newChannelService()
+
+	m.channelRepo.On("GetByID", entity.GetDefaultChannelID(tests.TestTeamID)).Return(defaultChannel(), nil)
+	m.channelRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.Channel{defaultChannel()}, nil)
+	m.channelRepo.On("Create", mock.AnythingOfType("*entity.Channel")).Return(nil)
+
+	channel, err := cs.CreateChannel(tests.TestUserID, tests.TestTeamID, &dto.ChannelRequest{Name: " #Exam-Prep "})
+	assert.NoError(t, err)
+	assert.Equal(t, "exam-prep", channel.Name)
+	assert.Equal(t, tests.TestUserID, channel.CreatedBy)
+
+	_, err = cs.CreateChannel(tests.TestUserID, tests.TestTeamID, &dto.ChannelRequest{Name: "#General"})
+	assert.ErrorIs(t, err, service.ErrConflict)
+
+	_, err = cs.CreateChannel(tests.TestUserID, tests.TestTeamID, &dto.ChannelRequest{Name: "exam prep"})
+	assert.ErrorIs(t, err, validator.ErrValidation)
+}
+
+func TestChannelService_UpdateChannel_DefaultCanNotBeArchived(t *testing.T) {
+	cs, m := newChannelService()
+
+	m.channelRepo.On("GetByID", entity.GetDefaultChannelID(tests.TestTeamID)).Return(defaultChannel(), nil)
+
+	archived := true
+	_, err := cs.UpdateChannel(tests.TestUserID, tests.TestTeamID, entity.GetDefaultChannelID(tests.TestTeamID), &dto.UpdateChannelRequest{Archived: &archived})
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+	m.channelRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestChannelService_UpdateChannel_AdminsOnly(t *testing.T) {
+	cs, m := newChannelService()
+
+	name := "renamed"
+	_, err := cs.UpdateChannel(tests.TestUserID2, tests.TestTeamID, "c1", &dto.UpdateChannelRequest{Name: &name})
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	m.channelRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestChannelService_CreateChannelMessage_ArchivedChannel(t *testing.T) {
+	cs, m := newChannelService()
+
+	archived := entity.NewChannel("c1", tests.TestTeamID, "old", tests.TestUserID, 0)
+	archived.Archived = true
+	m.userRepo.On("GetByID", tests.TestUserID).Return(&entity.User{ID: tests.TestUserID}, nil)
+	m.channelRepo.On("GetByID", "c1").Return(archived, nil)
+
+	_, err := cs.CreateChannelMessage(tests.TestUserID, tests.TestTeamID, "c1", &dto.ChannelMessageRequest{TextContent: "hello"})
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+	m.messageRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestChannelService_GetChannelMessages_RejectsOtherTeamsChannel(t *testing.T) {
+	cs, m := newChannelService()
+
+	m.channelRepo.On("GetByID", "c1").Return(entity.NewChannel("c1", tests.TestTeamID2, "random", "", 0), nil)
+
+	_, err := cs.GetChannelMessages(tests.TestUserID, tests.TestTeamID, "c1")
+
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+}
+
+func TestChannelService_MigrateTeamMessages(t *testing.T) {
+	cs, m := newChannelService()
+
+	m.messageRepo.On("GetAll").Return([]*entity.Message{
+		{ID: "m1", TeamID: tests.TestTeamID, SentAt: time.Now()},
+		{ID: "m2", TeamID: tests.TestTeamID, ChannelID: "c1"},
+		{ID: "m3", ConversationKey: entity.GetConversationKey(tests.TestUserID1, tests.TestUserID2)},
+	}, nil)
+	m.channelRepo.On("GetByID", entity.GetDefaultChannelID(tests.TestTeamID)).Return(defaultChannel(), nil)
+	m.messageRepo.On("Update", "m1", map[string]interface{}{"channelId": entity.GetDefaultChannelID(tests.TestTeamID)}).Return(nil)
+
+	err := cs.MigrateTeamMessages()
+
+	assert.NoError(t, err)
+	m.messageRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestChannelService_MigrateTeamMessages_OnlyOnce(t *testing.T) {
+	cs, m := newChannelService()
+	migrationRepo := new(tests.MockMigrationRepository)
+	cs.SetMigrationRepository(migrationRepo)
+	migrationRepo.On("IsDone", mock.Anything).Return(false, nil).Once()
+	migrationRepo.On("MarkDone", mock.Anything).Return(nil)
+	m.messageRepo.On("GetAll").Return([]*entity.Message{}, nil)
+
+	assert.NoError(t, cs.MigrateTeamMessages())
+	migrationRepo.On("IsDone", mock.Anything).Return(true, nil)
+	assert.NoError(t, cs.MigrateTeamMessages())
+
+	m.messageRepo.AssertNumberOfCalls(t, "GetAll", 1)
+	migrationRepo.AssertNumberOfCalls(t, "MarkDone", 1)
+}
@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newConversationService() (*service.ConversationService, *tests.MockUserRepository, *tests.MockReadMarkerRepository, *tests.MockConversationRepository) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockReadMarkerRepo := new(tests.MockReadMarkerRepository)
+	mockConversationRepo := new(tests.MockConversationRepository)
+	cs := service.NewConversationServiceWithRepo(mockUserRepo, new(tests.MockTeamRepository), new(tests.MockChannelRepository), new(tests.MockMessageRepository), mockReadMarkerRepo, mockConversationRepo)
+	return cs, mockUserRepo, mockReadMarkerRepo, mockConversationRepo
+}
+
+func testDirectConversation(conversationID string, lastActivityAt int64) *entity.ConversationEntry {
+	return &entity.ConversationEntry{UserID: tests.TestUserID1, ConversationID: conversationID, Type: entity.ConversationTypeDirect, OtherUserID: tests.TestUserID2, LastActivityAt: lastActivityAt}
+}
+
+func TestConversationService_IndexMessage(t *testing.T) {
+	cs, _, _, mockConversationRepo := newConversationService()
+
+	existing := testDirectConversation(testDirectConversationID, 1)
+	existing.UserID, existing.OtherUserID, existing.UnreadCount = tests.TestUserID2, tests.TestUserID1, 2
+	mockConversationRepo.On("Update", tests.TestUserID1, testDirectConversationID).Return(nil, nil)
+	mockConversationRepo.On("Update", tests.TestUserID2, testDirectConversationID).Return(existing, nil)
+	mockConversationRepo.On("Save", mock.Anything).Return(nil)
+
+	cs.IndexMessage(testDirectMessage())
+
+	mockConversationRepo.AssertCalled(t, "Save", mock.MatchedBy(func(entry *entity.ConversationEntry) bool {
+		return entry.UserID == tests.TestUserID1 && entry.OtherUserID == tests.TestUserID2 && entry.UnreadCount == 0 && entry.LastMessagePreview == "helo"
+	}))
+	mockConversationRepo.AssertCalled(t, "Save", mock.MatchedBy(func(entry *entity.ConversationEntry) bool {
+		return entry.UserID == tests.TestUserID2 && entry.UnreadCount == 3 && entry.LastMessageID == testMessageID
+	}))
+}
+
+func TestConversationService_UpdateMessage_DeletedUnreadMessage(t *testing.T) {
+	cs, _, mockReadMarkerRepo, mockConversationRepo := newConversationService()
+
+	message := testDirectMessage()
+	message.TextContent, message.DeletedAt = "", 1
+	entry := testDirectConversation(testDirectConversationID, 1)
+	entry.UserID, entry.LastMessageID, entry.LastMessagePreview, entry.UnreadCount = tests.TestUserID2, testMessageID, "helo", 1
+	mockConversationRepo.On("Update", tests.TestUserID1, testDirectConversationID).Return(nil, nil)
+	mockConversationRepo.On("Update", tests.TestUserID2, testDirectConversationID).Return(entry, nil)
+	mockReadMarkerRepo.On("Get", tests.TestUserID2, testDirectConversationID).Return(nil, nil)
+	mockConversationRepo.On("Save", mock.Anything).Return(nil)
+
+	cs.UpdateMessage(message)
+
+	mockConversationRepo.AssertCalled(t, "Save", mock.MatchedBy(func(saved *entity.ConversationEntry) bool {
+		return saved.UnreadCount == 0 && saved.LastMessagePreview == ""
+	}))
+}
+
+func TestConversationService_GetConversations_Pages(t *testing.T) {
+	cs, mockUserRepo, _, mockConversationRepo := newConversationService()
+
+	mockUserRepo.On("GetByID", mock.Anything).Return(&entity.User{ID: tests.TestUserID1}, nil)
+	mockConversationRepo.On("GetPage", tests.TestUserID1, int64(0), 3).Return([]*entity.ConversationEntry{
+		testDirectConversation("direct:c", 30),
+		testDirectConversation("direct:b", 20),
+		testDirectConversation("direct:a", 20),
+	}, nil)
+	mockConversationRepo.On("GetPage", tests.TestUserID1, int64(20), 3).Return([]*entity.ConversationEntry{
+		testDirectConversation("direct:b", 20),
+		testDirectConversation("direct:a", 20),
+	}, nil)
+
+	first, err := cs.GetConversations(tests.TestUserID1, "", 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, first.Conversations, 2)
+	assert.Equal(t, "20_direct:b", first.NextCursor)
+	assert.Equal(t, tests.TestUserID1, first.Conversations[0].User.ID)
+
+	second, err := cs.GetConversations(tests.TestUserID1, first.NextCursor, 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, second.Conversations, 1)
+	assert.Equal(t, "direct:a", second.Conversations[0].ConversationID)
+	assert.Empty(t, second.NextCursor)
+}
+
+func TestConversationService_GetConversations_FillsPageAfterHiddenChannels(t *testing.T) {
+	cs, mockUserRepo, _, mockConversationRepo := newConversationService()
+
+	left := &entity.ConversationEntry{UserID: tests.TestUserID1, ConversationID: "channel:left", Type: entity.ConversationTypeChannel, TeamID: "left", LastActivityAt: 40}
+	mockUserRepo.On("GetByID", mock.Anything).Return(&entity.User{ID: tests.TestUserID1}, nil)
+	mockConversationRepo.On("GetPage", tests.TestUserID1, int64(0), 3).Return([]*entity.ConversationEntry{
+		left,
+		testDirectConversation("direct:c", 30),
+		testDirectConversation("direct:b", 20),
+	}, nil)
+	mockConversationRepo.On("GetPage", tests.TestUserID1, int64(20), 3).Return([]*entity.ConversationEntry{
+		testDirectConversation("direct:b", 20),
+		testDirectConversation("direct:a", 10),
+	}, nil)
+
+	page, err := cs.GetConversations(tests.TestUserID1, "", 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Conversations, 2)
+	assert.Equal(t, "direct:c", page.Conversations[0].ConversationID)
+	assert.Equal(t, "direct:b", page.Conversations[1].ConversationID)
+	assert.Equal(t, "20_direct:b", page.NextCursor)
+}
+
+func TestConversationService_GetConversations_InvalidCursor(t *testing.T) {
+	cs, _, _, _ := newConversationService()
+
+	_, err := cs.GetConversations(tests.TestUserID1, "yesterday", 0)
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+}
@@ -34,6 +34,7 @@ func TestFileService_CreateFile_Success(t *testing.T) {
 	}
 
 	mockUserRepo.On("GetByID", userID).Return(&entity.User{ID: userID, TeamsIds: &teamIDs}, nil)
+	mockTeamRepo.On("GetTeamById", teamID).Return(&entity.Team{Id: teamID, UsersIds: []string{userID}}, nil)
 	mockFileRepo.On("Create", mock.MatchedBy(func(f *entity.File) bool {
 		return f.Name == req.Name && f.OwnerID == req.OwnerID && f.ContextType == entity.FileContextTeam && f.ContextID == teamID
 	})).Return(nil)
@@ -109,3 +110,28 @@ func TestFileService_GetFilesByTeam_Success(t *testing.T) {
 	mockFileRepo.AssertExpectations(t)
 	mockUserRepo.AssertExpectations(t)
 }
+
+func TestFileService_GetFileByID_OtherOrganization(t *testing.T) {
+	mockFileRepo := new(tests.MockFileRepository)
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	fs := service.NewFileServiceWithRepo(mockFileRepo, mockUserRepo, mockTeamRepo)
+
+	chatFile := entity.NewFile("f1", "notes.txt", "text/plain", "txt", "", "owner", entity.FileContextChat, "chat1", 4, 0, 0)
+	teamFile := entity.NewFile("f2", "notes.txt", "text/plain", "txt", "", "owner", entity.FileContextTeam, "team1", 4, 0, 0)
+	mockFileRepo.On("GetByID", "f1").Return(chatFile, nil)
+	mockFileRepo.On("GetByID", "f2").Return(teamFile, nil)
+	mockUserRepo.On("GetByID", "outsider").Return(&entity.User{ID: "outsider", OrganizationID: "org2"}, nil)
+	mockUserRepo.On("GetByID", "owner").Return(&entity.User{ID: "owner", OrganizationID: "org1"}, nil)
+	mockTeamRepo.On("GetTeamById", "team1").Return(&entity.Team{Id: "team1", OrganizationID: "org1"}, nil)
+
+	_, err := fs.GetFileByID("f1", "outsider")
+	assert.ErrorContains(t, err, "file not found")
+
+	_, err = fs.GetFileByID("f2", "outsider")
+	assert.ErrorContains(t, err, "file not found")
+
+	err = fs.DeleteFile("f1", "outsider")
+	assert.ErrorContains(t, err, "file not found")
+	mockFileRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
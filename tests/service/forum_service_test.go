@@ -0,0 +1,170 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newForumService() (*service.ForumService, *tests.MockForumRepository, *tests.MockTeamRepository) {
+	forumRepo := new(tests.MockForumRepository)
+	teamRepo := new(tests.MockTeamRepository)
+	return service.NewForumServiceWithRepo(forumRepo, teamRepo), forumRepo, teamRepo
+}
+
+// newForumTeam returns a team owned by TestUserID with TestUserID1 and TestUserID2 as plain members
+func newForumTeam() *entity.Team {
+	return &entity.Team{
+		Id:       tests.TestTeamID,
+		OwnerId:  tests.TestUserID,
+		UsersIds: []string{tests.TestUserID, tests.TestUserID1, tests.TestUserID2},
+	}
+}
+
+func newForumQuestion(id, authorId string, score, answers int, age time.Duration, tags ...string) *entity.ForumQuestion {
+	question := entity.NewForumQuestion(id, tests.TestTeamID, authorId, "Question "+id, "", tags)
+	question.Score = score
+	question.AnswerCount = answers
+	question.LastActivityAt = question.LastActivityAt.Add(-age)
+	return question
+}
+
+func TestForumService_AskQuestion_NormalizesTags(t *testing.T) {
+	fs, forumRepo, teamRepo := newForumService()
+
+	teamRepo.On("GetTeamById", tests.TestTeamID).Return(newForumTeam(), nil)
+	forumRepo.On("CreateQuestion", mock.AnythingOfType("*entity.ForumQuestion")).Return(nil)
+
+	resp, err := fs.AskQuestion(tests.TestUserID1, tests.TestTeamID, &dto.ForumQuestionRequest{
+		Title: "  Eigenvalues of a rotation?  ",
+		Body:  "Why are they complex?",
+		Tags:  []string{"Linear Algebra", "linear algebra", "exam"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Eigenvalues of a rotation?", resp.Title)
+	assert.Equal(t, []string{"linear-algebra", "exam"}, resp.Tags)
+	assert.Equal(t, tests.TestUserID1, resp.AuthorID)
+}
+
+func TestForumService_GetQuestions_NonMemberForbidden(t *testing.T) {
+	fs, _, teamRepo := newForumService()
+
+	teamRepo.On("GetTeamById", tests.TestTeamID).Return(newForumTeam(), nil)
+
+	_, err := fs.GetQuestions("stranger", tests.TestTeamID, "", "", "", 1, 10)
+
+	assert.True(t, errors.Is(err, service.ErrForbidden))
+}
+
+func TestForumService_GetQuestions_SortsAndFilters(t *testing.T) {
+	fs, forumRepo, teamRepo := newForumService()
+
+	old := newForumQuestion("q1", tests.TestUserID1, 5, 2, 2*time.Hour, "exam")
+	newest := newForumQuestion("q2", tests.TestUserID2, 1, 0, 0, "exam")
+	unanswered := newForumQuestion("q3", tests.TestUserID2, 3, 0, time.Hour, "homework")
+	unanswered.Body = "Proof of the determinant formula"
+
+	teamRepo.On("GetTeamById", tests.TestTeamID).Return(newForumTeam(), nil)
+	forumRepo.On("GetQuestionsByTeamID", tests.TestTeamID).Return([]*entity.ForumQuestion{old, newest, unanswered}, nil)
+
+	ids := func(resp *dto.ForumQuestionListResponse) []string {
+		var result []string
+		for _, question := range resp.Questions {
+			result = append(result, question.ID)
+		}
+		return result
+	}
+
+	resp, err := fs.GetQuestions(tests.TestUserID, tests.TestTeamID, "", "", "", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"q2", "q3", "q1"}, ids(resp))
+
+	resp, err = fs.GetQuestions(tests.TestUserID, tests.TestTeamID, "votes", "", "", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"q1", "q3", "q2"}, ids(resp))
+
+	resp, err = fs.GetQuestions(tests.TestUserID, tests.TestTeamID, "unanswered", "exam", "", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"q2"}, ids(resp))
+
+	resp, err = fs.GetQuestions(tests.TestUserID, tests.TestTeamID, "votes", "", "determ proof", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"q3"}, ids(resp))
+	assert.Equal(t, 1, resp.TotalCount)
+
+	_, err = fs.GetQuestions(tests.TestUserID, tests.TestTeamID, "oldest", "", "", 1, 10)
+	assert.Error(t, err)
+}
+
+func TestForumService_VoteQuestion_OwnQuestionConflict(t *testing.T) {
+	fs, forumRepo, teamRepo := newForumService()
+
+	teamRepo.On("GetTeamById", tests.TestTeamID).Return(newForumTeam(), nil)
+	forumRepo.On("GetQuestion", "q1").Return(newForumQuestion("q1", tests.TestUserID1, 0, 0, 0), nil)
+
+	_, err := fs.VoteQuestion(tests.TestUserID1, tests.TestTeamID, "q1", &dto.VoteRequest{Value: 1})
+
+	assert.True(t, errors.Is(err, service.ErrConflict))
+	forumRepo.AssertNotCalled(t, "UpdateQuestion", mock.Anything)
+}
+
+func TestForumService_VoteAnswer_ChangesScore(t *testing.T) {
+	fs, forumRepo, teamRepo := newForumService()
+	answer := entity.NewForumAnswer("a1", "q1", tests.TestTeamID, tests.TestUserID1, "Use the trace")
+	answer.Votes = map[string]int{tests.TestUserID: 1}
+	answer.Score = 1
+
+	teamRepo.On("GetTeamById", tests.TestTeamID).Return(newForumTeam(), nil)
+	forumRepo.On("GetQuestion", "q1").Return(newForumQuestion("q1", tests.TestUserID2, 0, 1, 0), nil)
+	forumRepo.On("GetAnswer", "q1", "a1").Return(answer, nil)
+	forumRepo.On("SaveAnswer", answer).Return(nil)
+
+	resp, err := fs.VoteAnswer(tests.TestUserID2, tests.TestTeamID, "q1", "a1", &dto.VoteRequest{Value: -1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.Score)
+	assert.Equal(t, -1, resp.MyVote)
+}
+
+func TestForumService_SetAcceptedAnswer_OnlyAskerOrAdmin(t *testing.T) {
+	fs, forumRepo, teamRepo := newForumService()
+	question := newForumQuestion("q1", tests.TestUserID1, 0, 1, 0)
+
+	teamRepo.On("GetTeamById", tests.TestTeamID).Return(newForumTeam(), nil)
+	forumRepo.On("GetQuestion", "q1").Return(question, nil)
+	forumRepo.On("GetAnswer", "q1", "a1").Return(entity.NewForumAnswer("a1", "q1", tests.TestTeamID, tests.TestUserID2, "Answer"), nil)
+	forumRepo.On("UpdateQuestion", question).Return(nil)
+
+	_, err := fs.SetAcceptedAnswer(tests.TestUserID2, tests.TestTeamID, "q1", "a1", true)
+	assert.True(t, errors.Is(err, service.ErrForbidden))
+
+	resp, err := fs.SetAcceptedAnswer(tests.TestUserID, tests.TestTeamID, "q1", "a1", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "a1", resp.AcceptedAnswerID)
+}
+
+func TestForumService_DeleteAnswer_ClearsAcceptedAnswer(t *testing.T) {
+	fs, forumRepo, teamRepo := newForumService()
+	question := newForumQuestion("q1", tests.TestUserID1, 0, 1, 0)
+	question.AcceptedAnswerID = "a1"
+
+	teamRepo.On("GetTeamById", tests.TestTeamID).Return(newForumTeam(), nil)
+	forumRepo.On("GetQuestion", "q1").Return(question, nil)
+	forumRepo.On("GetAnswer", "q1", "a1").Return(entity.NewForumAnswer("a1", "q1", tests.TestTeamID, tests.TestUserID2, "Answer"), nil)
+	forumRepo.On("DeleteAnswer", "q1", "a1").Return(nil)
+	forumRepo.On("UpdateQuestion", question).Return(nil)
+
+	err := fs.DeleteAnswer(tests.TestUserID2, tests.TestTeamID, "q1", "a1")
+
+	assert.NoError(t, err)
+	assert.Empty(t, question.AcceptedAnswerID)
+	assert.Equal(t, 0, question.AnswerCount)
+}
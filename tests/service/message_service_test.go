@@ -0,0 +1,430 @@
+package service_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testMessageID = "msg1"
+
+func newMessageService() (*service.MessageService, *tests.MockUserRepository, *tests.MockTeamRepository, *tests.MockMessageRepository) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockMessageRepo := new(tests.MockMessageRepository)
+	ms := service.NewMessageServiceWithRepo(mockUserRepo, mockTeamRepo, mockMessageRepo, new(tests.MockChannelRepository))
+	return ms, mockUserRepo, mockTeamRepo, mockMessageRepo
+}
+
+func testDirectMessage() *entity.Message {
+	return entity.NewMessage(testMessageID, tests.TestUserID1, entity.GetConversationKey(tests.TestUserID1, tests.TestUserID2), "", "", "helo")
+}
+
+func TestMessageService_EditMessage_KeepsHistory(t *testing.T) {
+	ms, mockUserRepo, _, mockMessageRepo := newMessageService()
+
+	mockMessageRepo.On("GetByID", testMessageID).Return(testDirectMessage(), nil)
+	mockMessageRepo.On("Update", testMessageID, mock.Anything).Return(nil)
+	mockUserRepo.On("GetByID", tests.TestUserID1).Return(&entity.User{ID: tests.TestUserID1}, nil)
+
+	message, err := ms.EditMessage(tests.TestUserID1, testMessageID, &dto.EditMessageRequest{TextContent: "hello"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", message.TextContent)
+	assert.True(t, message.Edited)
+	assert.Equal(t, tests.TestUserID2, message.ReceiverID)
+	mockMessageRepo.AssertCalled(t, "Update", testMessageID, mock.MatchedBy(func(updates map[string]interface{}) bool {
+		edits := updates["edits"].([]entity.MessageEdit)
+		return updates["textContent"] == "hello" && len(edits) == 1 && edits[0].TextContent == "helo"
+	}))
+}
+
+func TestMessageService_EditMessage_OnlySender(t *testing.T) {
+	ms, _, _, mockMessageRepo := newMessageService()
+
+	mockMessageRepo.On("GetByID", testMessageID).Return(testDirectMessage(), nil)
+
+	_, err := ms.EditMessage(tests.TestUserID2, testMessageID, &dto.EditMessageRequest{TextContent: "hello"})
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockMessageRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestMessageService_EditMessage_DeletedMessage(t *testing.T) {
+	ms, _, _, mockMessageRepo := newMessageService()
+
+	message := testDirectMessage()
+	message.DeletedAt = 1
+	mockMessageRepo.On("GetByID", testMessageID).Return(message, nil)
+
+	_, err := ms.EditMessage(tests.TestUserID1, testMessageID, &dto.EditMessageRequest{TextContent: "hello"})
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+}
+
+func TestMessageService_DeleteMessage_OutsiderNotFound(t *testing.T) {
+	ms, _, _, mockMessageRepo := newMessageService()
+
+	mockMessageRepo.On("GetByID", testMessageID).Return(testDirectMessage(), nil)
+
+	_, err := ms.DeleteMessage("stranger", testMessageID)
+
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+}
+
+func TestMessageService_DeleteMessage_TeamAdminLeavesTombstone(t *testing.T) {
+	ms, mockUserRepo, mockTeamRepo, mockMessageRepo := newMessageService()
+
+	message := entity.NewMessage(testMessageID, tests.TestUserID2, "", tests.TestTeamID, "general", "spam")
+	message.PinnedAt = 1
+	mockMessageRepo.On("GetByID", testMessageID).Return(message, nil)
+	mockMessageRepo.On("Update", testMessageID, mock.Anything).Return(nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, OwnerId: tests.TestUserID1, UsersIds: []string{tests.TestUserID1, tests.TestUserID2}}, nil)
+	mockUserRepo.On("GetByID", tests.TestUserID2).Return(&entity.User{ID: tests.TestUserID2}, nil)
+
+	deleted, err := ms.DeleteMessage(tests.TestUserID1, testMessageID)
+
+	assert.NoError(t, err)
+	assert.True(t, deleted.Deleted)
+	assert.Empty(t, deleted.TextContent)
+	assert.Zero(t, deleted.PinnedAt)
+}
+
+func TestMessageService_DeleteMessage_MemberCanNotDeleteOthers(t *testing.T) {
+	ms, _, mockTeamRepo, mockMessageRepo := newMessageService()
+
+	mockMessageRepo.On("GetByID", testMessageID).Return(entity.NewMessage(testMessageID, tests.TestUserID1, "", tests.TestTeamID, "general", "hi"), nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, OwnerId: tests.TestUserID1, UsersIds: []string{tests.TestUserID1, tests.TestUserID2}}, nil)
+
+	_, err := ms.DeleteMessage(tests.TestUserID2, testMessageID)
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockMessageRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestMessageService_GetMessageHistory(t *testing.T) {
+	ms, _, _, mockMessageRepo := newMessageService()
+
+	message := testDirectMessage()
+	message.Edits = []entity.MessageEdit{{TextContent: "hi", EditedAt: 10}}
+	message.EditedAt = 10
+	mockMessageRepo.On("GetByID", testMessageID).Return(message, nil)
+
+	history, err := ms.GetMessageHistory(tests.TestUserID2, testMessageID)
+
+	assert.NoError(t, err)
+	assert.Len(t, history.Versions, 2)
+	assert.Equal(t, "hi", history.Versions[0].TextContent)
+	assert.Equal(t, "helo", history.Versions[1].TextContent)
+}
+
+func TestMessageService_AddReaction(t *testing.T) {
+	ms, mockUserRepo, _, mockMessageRepo := newMessageService()
+
+	message := testDirectMessage()
+	message.Reactions = map[string][]string{"👍": {tests.TestUserID1}}
+	mockMessageRepo.On("GetByID", testMessageID).Return(message, nil)
+	mockMessageRepo.On("UpdateReactions", testMessageID).Return(map[string][]string{"👍": {tests.TestUserID1}}, nil)
+	mockUserRepo.On("GetByID", tests.TestUserID1).Return(&entity.User{ID: tests.TestUserID1}, nil)
+
+	reacted, err := ms.AddReaction(tests.TestUserID2, testMessageID, "👍")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.ReactionDTO{{Emoji: "👍", Count: 2, ReactedByMe: true}}, reacted.Reactions)
+}
+
+func TestMessageService_AddReaction_KeepsConcurrentReactions(t *testing.T) {
+	ms, mockUserRepo, _, mockMessageRepo := newMessageService()
+
+	message := testDirectMessage()
+	message.Reactions = map[string][]string{"👍": {tests.TestUserID1}}
+	mockMessageRepo.On("GetByID", testMessageID).Return(message, nil)
+	// Another participant reacted after the message was read
+	mockMessageRepo.On("UpdateReactions", testMessageID).Return(map[string][]string{"👍": {tests.TestUserID1, "other"}}, nil)
+	mockUserRepo.On("GetByID", tests.TestUserID1).Return(&entity.User{ID: tests.TestUserID1}, nil)
+
+	reacted, err := ms.AddReaction(tests.TestUserID2, testMessageID, "👍")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.ReactionDTO{{Emoji: "👍", Count: 3, ReactedByMe: true}}, reacted.Reactions)
+}
+
+func TestMessageService_AddReaction_CapsDistinctReactions(t *testing.T) {
+	ms, _, _, mockMessageRepo := newMessageService()
+
+	message := testDirectMessage()
+	message.Reactions = map[string][]string{}
+	for i := 0; i < 20; i++ {
+		message.Reactions[string(rune('a'+i))] = []string{tests.TestUserID1}
+	}
+	mockMessageRepo.On("GetByID", testMessageID).Return(message, nil)
+	mockMessageRepo.On("UpdateReactions", testMessageID).Return(message.Reactions, nil)
+
+	_, err := ms.AddReaction(tests.TestUserID2, testMessageID, "🎉")
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+	assert.NotContains(t, message.Reactions, "🎉")
+}
+
+func TestMessageService_RemoveReaction_DropsEmptyEmoji(t *testing.T) {
+	ms, mockUserRepo, _, mockMessageRepo := newMessageService()
+
+	message := testDirectMessage()
+	message.Reactions = map[string][]string{"👍": {tests.TestUserID2}}
+	mockMessageRepo.On("GetByID", testMessageID).Return(message, nil)
+	mockMessageRepo.On("UpdateReactions", testMessageID).Return(map[string][]string{"👍": {tests.TestUserID2}}, nil)
+	mockUserRepo.On("GetByID", tests.TestUserID1).Return(&entity.User{ID: tests.TestUserID1}, nil)
+
+	reacted, err := ms.RemoveReaction(tests.TestUserID2, testMessageID, "👍")
+
+	assert.NoError(t, err)
+	assert.Empty(t, reacted.Reactions)
+}
+
+func TestMessageService_AddReaction_InvalidEmoji(t *testing.T) {
+	ms, _, _, _ := newMessageService()
+
+	_, err := ms.AddReaction(tests.TestUserID1, testMessageID, "a.b")
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+}
+
+func TestMessageService_CreateDirectMessage_ReplyJoinsThread(t *testing.T) {
+	ms, mockUserRepo, _, mockMessageRepo := newMessageService()
+
+	reply := entity.NewMessage("reply1", tests.TestUserID2, entity.GetConversationKey(tests.TestUserID1, tests.TestUserID2), "", "", "first")
+	reply.ParentID = testMessageID
+	mockUserRepo.On("GetByID", tests.TestUserID1).Return(&entity.User{ID: tests.TestUserID1}, nil)
+	mockUserRepo.On("GetByID", tests.TestUserID2).Return(&entity.User{ID: tests.TestUserID2}, nil)
+	mockMessageRepo.On("GetByID", "reply1").Return(reply, nil)
+	mockMessageRepo.On("GetByID", testMessageID).Return(testDirectMessage(), nil)
+	mockMessageRepo.On("Create", mock.Anything).Return(nil)
+	thread := testDirectMessage()
+	thread.ReplyCount = 2
+	mockMessageRepo.On("AddReply", testMessageID, mock.AnythingOfType("int64")).Return(thread, nil)
+
+	message, err := ms.CreateDirectMessage(&dto.DirectMessageRequest{SenderID: tests.TestUserID1, ReceiverID: tests.TestUserID2, TextContent: "second", ParentID: "reply1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, testMessageID, message.ParentID)
+	mockMessageRepo.AssertCalled(t, "AddReply", testMessageID, mock.AnythingOfType("int64"))
+	mockMessageRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestMessageService_CreateTeamMessage_ParentInOtherConversation(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockMessageRepo := new(tests.MockMessageRepository)
+	mockChannelRepo := new(tests.MockChannelRepository)
+	ms := service.NewMessageServiceWithRepo(mockUserRepo, mockTeamRepo, mockMessageRepo, mockChannelRepo)
+
+	mockUserRepo.On("GetByID", tests.TestUserID1).Return(&entity.User{ID: tests.TestUserID1}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID1}}, nil)
+	mockChannelRepo.On("GetByID", entity.GetDefaultChannelID(tests.TestTeamID)).Return(&entity.Channel{ID: entity.GetDefaultChannelID(tests.TestTeamID), TeamID: tests.TestTeamID}, nil)
+	mockMessageRepo.On("GetByID", testMessageID).Return(testDirectMessage(), nil)
+
+	request := dto.NewTeamMessageRequest(tests.TestUserID1, tests.TestTeamID, "hi")
+	request.ParentID = testMessageID
+	_, err := ms.CreateTeamMessage(request)
+
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+	mockMessageRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestMessageService_GetDirectMessages_HidesReplies(t *testing.T) {
+	ms, mockUserRepo, _, mockMessageRepo := newMessageService()
+
+	reply := testDirectMessage()
+	reply.ID = "reply1"
+	reply.ParentID = testMessageID
+	mockUserRepo.On("GetByID", mock.Anything).Return(&entity.User{ID: tests.TestUserID1}, nil)
+	mockMessageRepo.On("GetByConversation", tests.TestUserID1, tests.TestUserID2).Return([]*entity.Message{testDirectMessage(), reply}, nil)
+
+	messages, err := ms.GetDirectMessages(tests.TestUserID1, tests.TestUserID1, tests.TestUserID2)
+
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, testMessageID, messages[0].ID)
+}
+
+func TestMessageService_GetDirectThreads_MostRecentFirst(t *testing.T) {
+	ms, mockUserRepo, _, mockMessageRepo := newMessageService()
+
+	older := testDirectMessage()
+	older.ReplyCount, older.LastReplyAt = 3, 10
+	newer := testDirectMessage()
+	newer.ID, newer.ReplyCount, newer.LastReplyAt = "msg2", 1, 20
+	quiet := testDirectMessage()
+	quiet.ID = "msg3"
+	mockUserRepo.On("GetByID", mock.Anything).Return(&entity.User{ID: tests.TestUserID1}, nil)
+	mockMessageRepo.On("GetByConversation", tests.TestUserID2, tests.TestUserID1).Return([]*entity.Message{older, newer, quiet}, nil)
+
+	threads, err := ms.GetDirectThreads(tests.TestUserID2, tests.TestUserID1)
+
+	assert.NoError(t, err)
+	assert.Len(t, threads, 2)
+	assert.Equal(t, "msg2", threads[0].ID)
+	assert.Equal(t, 3, threads[1].ReplyCount)
+}
+
+// testHistory returns direct messages msg0..msg(n-1), one second apart, with their history keys
+func testHistory(n int) []*entity.Message {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	messages := make([]*entity.Message, n)
+	for i := range messages {
+		message := testDirectMessage()
+		message.ID = fmt.Sprintf("msg%d", i)
+		message.SentAt = start.Add(time.Duration(i) * time.Second)
+		message.SetHistoryKey()
+		messages[i] = message
+	}
+	return messages
+}
+
+func TestMessageService_GetDirectMessagePage_Latest(t *testing.T) {
+	ms, mockUserRepo, _, mockMessageRepo := newMessageService()
+
+	history := testHistory(4)
+	scope := history[0].HistoryScope()
+	mockUserRepo.On("GetByID", mock.Anything).Return(&entity.User{ID: tests.TestUserID1}, nil)
+	mockMessageRepo.On("GetHistory", scope+"|", scope+"|\uf8ff", 5, true).Return(history, nil)
+
+	page, err := ms.GetDirectMessagePage(tests.TestUserID1, tests.TestUserID1, tests.TestUserID2, &dto.MessagePageQuery{Limit: 3})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Messages, 3)
+	assert.Equal(t, "msg1", page.Messages[0].ID)
+	assert.Equal(t, "msg1", page.PrevCursor)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestMessageService_GetDirectMessagePage_BeforeCursor(t *testing.T) {
+	ms, mockUserRepo, _, mockMessageRepo := newMessageService()
+
+	history := testHistory(3)
+	scope := history[0].HistoryScope()
+	mockUserRepo.On("GetByID", mock.Anything).Return(&entity.User{ID: tests.TestUserID1}, nil)
+	mockMessageRepo.On("GetByID", "msg2").Return(history[2], nil)
+	mockMessageRepo.On("GetHistory", scope+"|", history[2].HistoryKey, 4, true).Return(history, nil)
+
+	page, err := ms.GetDirectMessagePage(tests.TestUserID2, tests.TestUserID1, tests.TestUserID2, &dto.MessagePageQuery{Before: "msg2", Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Messages, 2)
+	assert.Equal(t, "msg0", page.Messages[0].ID)
+	assert.Empty(t, page.PrevCursor)
+	assert.Equal(t, "msg1", page.NextCursor)
+}
+
+func TestMessageService_GetDirectMessagePage_Around(t *testing.T) {
+	ms, mockUserRepo, _, mockMessageRepo := newMessageService()
+
+	history := testHistory(5)
+	scope := history[0].HistoryScope()
+	key := history[2].HistoryKey
+	mockUserRepo.On("GetByID", mock.Anything).Return(&entity.User{ID: tests.TestUserID1}, nil)
+	mockMessageRepo.On("GetByID", "msg2").Return(history[2], nil)
+	mockMessageRepo.On("GetHistory", scope+"|", key, 3, true).Return(history[:3], nil)
+	mockMessageRepo.On("GetHistory", key, scope+"|\uf8ff", 3, false).Return(history[2:], nil)
+
+	page, err := ms.GetDirectMessagePage(tests.TestUserID1, tests.TestUserID1, tests.TestUserID2, &dto.MessagePageQuery{Around: "msg2", Limit: 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"msg1", "msg2", "msg3"}, []string{page.Messages[0].ID, page.Messages[1].ID, page.Messages[2].ID})
+	assert.Equal(t, "msg1", page.PrevCursor)
+	assert.Equal(t, "msg3", page.NextCursor)
+}
+
+func TestMessageService_GetDirectMessagePage_CursorFromOtherConversation(t *testing.T) {
+	ms, mockUserRepo, _, mockMessageRepo := newMessageService()
+
+	other := entity.NewMessage("msg9", tests.TestUserID1, entity.GetConversationKey(tests.TestUserID1, "user3"), "", "", "hi")
+	mockUserRepo.On("GetByID", mock.Anything).Return(&entity.User{ID: tests.TestUserID1}, nil)
+	mockMessageRepo.On("GetByID", "msg9").Return(other, nil)
+
+	_, err := ms.GetDirectMessagePage(tests.TestUserID1, tests.TestUserID1, tests.TestUserID2, &dto.MessagePageQuery{After: "msg9"})
+
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+}
+
+func TestMessageService_GetDirectMessagePage_OneCursorOnly(t *testing.T) {
+	ms, mockUserRepo, _, _ := newMessageService()
+	mockUserRepo.On("GetByID", mock.Anything).Return(&entity.User{ID: tests.TestUserID1}, nil)
+
+	_, err := ms.GetDirectMessagePage(tests.TestUserID1, tests.TestUserID1, tests.TestUserID2, &dto.MessagePageQuery{Before: "msg1", After: "msg2"})
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+}
+
+func TestMessageService_GetDirectMessagePage_Outsider(t *testing.T) {
+	ms, _, _, _ := newMessageService()
+
+	_, err := ms.GetDirectMessagePage("user3", tests.TestUserID1, tests.TestUserID2, &dto.MessagePageQuery{Limit: 10})
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+}
+
+func TestMessageService_GetMessageByID_OtherOrganization(t *testing.T) {
+	ms, mockUserRepo, mockTeamRepo, mockMessageRepo := newMessageService()
+	teamMessage := entity.NewMessage("msg2", tests.TestUserID1, "", tests.TestTeamID, "", "helo")
+	mockMessageRepo.On("GetByID", testMessageID).Return(testDirectMessage(), nil)
+	mockMessageRepo.On("GetByID", "msg2").Return(teamMessage, nil)
+	// TestUserID2 was moved to another organization after joining the conversation and the team
+	mockUserRepo.On("GetByID", tests.TestUserID1).Return(&entity.User{ID: tests.TestUserID1, OrganizationID: "org1"}, nil)
+	mockUserRepo.On("GetByID", tests.TestUserID2).Return(&entity.User{ID: tests.TestUserID2, OrganizationID: "org2"}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, OrganizationID: "org1", UsersIds: []string{tests.TestUserID1, tests.TestUserID2}}, nil)
+
+	_, err := ms.GetMessageByID(tests.TestUserID2, testMessageID)
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+
+	_, err = ms.GetMessageByID(tests.TestUserID2, "msg2")
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+}
+
+func TestMessageService_GetMessageByID_Outsider(t *testing.T) {
+	ms, _, mockTeamRepo, mockMessageRepo := newMessageService()
+	teamMessage := entity.NewMessage("msg2", tests.TestUserID1, "", tests.TestTeamID, "", "helo")
+	mockMessageRepo.On("GetByID", testMessageID).Return(testDirectMessage(), nil)
+	mockMessageRepo.On("GetByID", "msg2").Return(teamMessage, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID1}}, nil)
+
+	_, err := ms.GetMessageByID("stranger", testMessageID)
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+
+	_, err = ms.GetMessageByID("stranger", "msg2")
+	assert.ErrorIs(t, err, service.ErrForbidden)
+}
+
+func TestMessageService_GetDirectMessages_Outsider(t *testing.T) {
+	ms, mockUserRepo, _, mockMessageRepo := newMessageService()
+
+	_, err := ms.GetDirectMessages("stranger", tests.TestUserID1, tests.TestUserID2)
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockUserRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+	mockMessageRepo.AssertNotCalled(t, "GetByConversation", mock.Anything, mock.Anything)
+}
+
+func TestMessageService_MigrateHistoryKeys_OnlyOnce(t *testing.T) {
+	ms, _, _, mockMessageRepo := newMessageService()
+	migrationRepo := new(tests.MockMigrationRepository)
+	ms.SetMigrationRepository(migrationRepo)
+	migrationRepo.On("IsDone", mock.Anything).Return(false, nil).Once()
+	migrationRepo.On("MarkDone", mock.Anything).Return(nil)
+	mockMessageRepo.On("GetAll").Return([]*entity.Message{}, nil)
+
+	assert.NoError(t, ms.MigrateHistoryKeys())
+	migrationRepo.On("IsDone", mock.Anything).Return(true, nil)
+	assert.NoError(t, ms.MigrateHistoryKeys())
+
+	mockMessageRepo.AssertNumberOfCalls(t, "GetAll", 1)
+	migrationRepo.AssertNumberOfCalls(t, "MarkDone", 1)
+}
@@ -0,0 +1,268 @@
+package service_test
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type messageSocketMocks struct {
+	userRepo          *tests.MockUserRepository
+	teamRepo          *tests.MockTeamRepository
+	messageRepo       *tests.MockMessageRepository
+	friendRequestRepo *tests.MockFriendRequestRepository
+	presenceRepo      *tests.MockPresenceRepository
+	outboxRepo        *tests.MockOutboxRepository
+}
+
+// startMessageSocketServer serves the message WebSocket of a service backed by the mocks, the user being given as ?user=
+func startMessageSocketServer(t *testing.T) (*httptest.Server, *messageSocketMocks) {
+	ms, mockUserRepo, mockTeamRepo, mockMessageRepo := newMessageService()
+	mocks := &messageSocketMocks{
+		userRepo:          mockUserRepo,
+		teamRepo:          mockTeamRepo,
+		messageRepo:       mockMessageRepo,
+		friendRequestRepo: new(tests.MockFriendRequestRepository),
+		presenceRepo:      new(tests.MockPresenceRepository),
+		outboxRepo:        new(tests.MockOutboxRepository),
+	}
+	rs := service.NewReadServiceWithRepo(mockUserRepo, mockTeamRepo, new(tests.MockChannelRepository), mockMessageRepo, new(tests.MockReadMarkerRepository), new(tests.MockConversationRepository))
+	ps := service.NewPresenceServiceWithRepo(mockUserRepo, mockTeamRepo, mocks.friendRequestRepo, mocks.presenceRepo)
+	mss := service.NewMessageSocketServiceWithRepo(mockUserRepo, mockTeamRepo, ms, rs, ps, mocks.outboxRepo)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/connect", func(c *gin.Context) {
+		since, err := strconv.ParseInt(c.DefaultQuery("since", "0"), 10, 64)
+		require.NoError(t, err)
+		conn, err := hub.AcceptConnection(c)
+		require.NoError(t, err)
+		mss.Connect(c.Query("user"), since, conn)
+	})
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server, mocks
+}
+
+// expectUsersWithoutContacts mocks users without friends or teams, whose presence nobody gets, and without events yet
+func expectUsersWithoutContacts(mocks *messageSocketMocks) {
+	expectNoContacts(mocks)
+	expectEmptyOutboxes(mocks)
+}
+
+// expectNoContacts lets users connect and disconnect without presence events
+func expectNoContacts(mocks *messageSocketMocks) {
+	mocks.userRepo.On("GetByID", mock.Anything).Return(&entity.User{ID: tests.TestUserID1}, nil)
+	mocks.friendRequestRepo.On("GetFriendsForUser", mock.Anything).Return([]string{}, nil)
+	mocks.presenceRepo.On("Save", mock.AnythingOfType("*entity.Presence")).Return(nil).Maybe()
+}
+
+func expectEmptyOutboxes(mocks *messageSocketMocks) {
+	mocks.outboxRepo.On("GetLast", mock.Anything).Return(nil, nil)
+	mocks.outboxRepo.On("SaveAll", mock.Anything, mock.Anything).Return(nil).Maybe()
+}
+
+func dialMessages(t *testing.T, server *httptest.Server, userID string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/connect?user="+userID, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	// Commands are only read once the connection is registered, so any answer means it gets events
+	require.NoError(t, conn.WriteJSON(dto.MessageCommand{}))
+	var failure dto.CommandError
+	readNoteMessage(t, conn, hub.CommandError, &failure)
+	return conn
+}
+
+func TestMessageSocketService_SendMessage_AcksWithStoredMessage(t *testing.T) {
+	server, mocks := startMessageSocketServer(t)
+	expectUsersWithoutContacts(mocks)
+	mocks.messageRepo.On("Create", mock.AnythingOfType("*entity.Message")).Return(nil)
+
+	conn := dialMessages(t, server, tests.TestUserID1)
+	require.NoError(t, conn.WriteJSON(dto.MessageCommand{
+		Type:        dto.MessageCommandSend,
+		RequestID:   "c1",
+		ReceiverID:  tests.TestUserID2,
+		TextContent: "hello",
+	}))
+
+	var ack dto.CommandAck
+	readNoteMessage(t, conn, hub.CommandAck, &ack)
+	assert.Equal(t, "c1", ack.RequestID)
+	require.NotNil(t, ack.Message)
+	assert.Equal(t, "hello", ack.Message.TextContent)
+	assert.Equal(t, tests.TestUserID2, ack.Message.ReceiverID)
+	mocks.messageRepo.AssertCalled(t, "Create", mock.MatchedBy(func(message *entity.Message) bool {
+		return message.SenderID == tests.TestUserID1
+	}))
+}
+
+func TestMessageSocketService_EditOthersMessage_ReturnsError(t *testing.T) {
+	server, mocks := startMessageSocketServer(t)
+	expectUsersWithoutContacts(mocks)
+	mocks.messageRepo.On("GetByID", testMessageID).Return(testDirectMessage(), nil)
+
+	conn := dialMessages(t, server, tests.TestUserID2)
+	require.NoError(t, conn.WriteJSON(dto.MessageCommand{
+		Type:        dto.MessageCommandEdit,
+		RequestID:   "c2",
+		MessageID:   testMessageID,
+		TextContent: "hello",
+	}))
+
+	var failure dto.CommandError
+	readNoteMessage(t, conn, hub.CommandError, &failure)
+	assert.Equal(t, "c2", failure.RequestID)
+	assert.Equal(t, dto.CommandErrorForbidden, failure.Code)
+	mocks.messageRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestMessageSocketService_UnknownCommand(t *testing.T) {
+	server, mocks := startMessageSocketServer(t)
+	expectUsersWithoutContacts(mocks)
+
+	conn := dialMessages(t, server, tests.TestUserID1)
+	require.NoError(t, conn.WriteJSON(dto.MessageCommand{Type: "shout", RequestID: "c3"}))
+
+	var failure dto.CommandError
+	readNoteMessage(t, conn, hub.CommandError, &failure)
+	assert.Equal(t, "c3", failure.RequestID)
+	assert.Equal(t, dto.CommandErrorInvalid, failure.Code)
+}
+
+func TestMessageSocketService_TypingReachesOtherMembers(t *testing.T) {
+	server, mocks := startMessageSocketServer(t)
+	expectUsersWithoutContacts(mocks)
+	mocks.teamRepo.On("GetTeamById", tests.TestTeamID).Return(newForumTeam(), nil)
+
+	typist := dialMessages(t, server, tests.TestUserID1)
+	member := dialMessages(t, server, tests.TestUserID2)
+	require.NoError(t, typist.WriteJSON(dto.MessageCommand{Type: dto.MessageCommandTyping, RequestID: "c4", TeamID: tests.TestTeamID}))
+
+	var ack dto.CommandAck
+	readNoteMessage(t, typist, hub.CommandAck, &ack)
+	assert.Equal(t, "c4", ack.RequestID)
+	var typing dto.TypingEvent
+	readNoteMessage(t, member, hub.Typing, &typing)
+	assert.Equal(t, tests.TestUserID1, typing.UserID)
+	assert.Equal(t, tests.TestTeamID, typing.TeamID)
+	assert.True(t, typing.Typing)
+
+	require.NoError(t, typist.WriteJSON(dto.MessageCommand{Type: dto.MessageCommandTyping, TeamID: tests.TestTeamID, Stopped: true}))
+	readNoteMessage(t, member, hub.Typing, &typing)
+	assert.False(t, typing.Typing)
+}
+
+func TestMessageSocketService_PresenceReachesFriends(t *testing.T) {
+	server, mocks := startMessageSocketServer(t)
+	mocks.userRepo.On("GetByID", mock.Anything).Return(&entity.User{}, nil)
+	mocks.friendRequestRepo.On("GetFriendsForUser", tests.TestUserID1).Return([]string{tests.TestUserID2}, nil)
+	mocks.friendRequestRepo.On("GetFriendsForUser", tests.TestUserID2).Return([]string{tests.TestUserID1}, nil)
+	mocks.presenceRepo.On("Save", mock.AnythingOfType("*entity.Presence")).Return(nil)
+	expectEmptyOutboxes(mocks)
+
+	friend := dialMessages(t, server, tests.TestUserID2)
+	user := dialMessages(t, server, tests.TestUserID1)
+	var presence dto.PresenceDTO
+	readNoteMessage(t, friend, hub.Presence, &presence)
+	assert.Equal(t, tests.TestUserID1, presence.UserID)
+	assert.Equal(t, entity.PresenceOnline, presence.Status)
+
+	require.NoError(t, user.WriteJSON(dto.MessageCommand{Type: dto.MessageCommandPresence, Status: entity.PresenceAway}))
+	readNoteMessage(t, friend, hub.Presence, &presence)
+	assert.Equal(t, entity.PresenceAway, presence.Status)
+
+	require.NoError(t, user.Close())
+	readNoteMessage(t, friend, hub.Presence, &presence)
+	assert.Equal(t, entity.PresenceOffline, presence.Status)
+	assert.NotZero(t, presence.LastSeenAt)
+}
+
+func TestMessageSocketService_SendMessage_ReachesEveryDeviceAckOnlyTheSender(t *testing.T) {
+	server, mocks := startMessageSocketServer(t)
+	expectUsersWithoutContacts(mocks)
+	mocks.messageRepo.On("Create", mock.AnythingOfType("*entity.Message")).Return(nil)
+
+	phone := dialMessages(t, server, tests.TestUserID1)
+	laptop := dialMessages(t, server, tests.TestUserID1)
+	require.NoError(t, phone.WriteJSON(dto.MessageCommand{
+		Type:        dto.MessageCommandSend,
+		RequestID:   "c1",
+		ReceiverID:  tests.TestUserID2,
+		TextContent: "hello",
+	}))
+
+	var ack dto.CommandAck
+	readNoteMessage(t, phone, hub.CommandAck, &ack)
+	assert.Equal(t, "c1", ack.RequestID)
+
+	var message dto.MessageDTO
+	readNoteMessage(t, laptop, hub.DirectMessage, &message)
+	assert.Equal(t, "hello", message.TextContent)
+	// The ack would be sent before the answer to this command
+	require.NoError(t, laptop.WriteJSON(dto.MessageCommand{}))
+	for msgType := hub.MessageType(""); msgType != hub.CommandError; {
+		msgType = readSeqMessage(t, laptop).Type
+		assert.NotEqual(t, hub.CommandAck, msgType)
+	}
+}
+
+func TestMessageSocketService_PresenceOnlineUntilLastDeviceDisconnects(t *testing.T) {
+	server, mocks := startMessageSocketServer(t)
+	mocks.userRepo.On("GetByID", mock.Anything).Return(&entity.User{}, nil)
+	mocks.friendRequestRepo.On("GetFriendsForUser", tests.TestUserID1).Return([]string{tests.TestUserID2}, nil)
+	mocks.friendRequestRepo.On("GetFriendsForUser", tests.TestUserID2).Return([]string{tests.TestUserID1}, nil)
+	mocks.presenceRepo.On("Save", mock.AnythingOfType("*entity.Presence")).Return(nil)
+	expectEmptyOutboxes(mocks)
+
+	friend := dialMessages(t, server, tests.TestUserID2)
+	phone := dialMessages(t, server, tests.TestUserID1)
+	var presence dto.PresenceDTO
+	readNoteMessage(t, friend, hub.Presence, &presence)
+	assert.Equal(t, entity.PresenceOnline, presence.Status)
+
+	// A second device is not a change, so the next event is the status set from it
+	laptop := dialMessages(t, server, tests.TestUserID1)
+	require.NoError(t, laptop.WriteJSON(dto.MessageCommand{Type: dto.MessageCommandPresence, Status: entity.PresenceAway}))
+	readNoteMessage(t, friend, hub.Presence, &presence)
+	assert.Equal(t, entity.PresenceAway, presence.Status)
+
+	require.NoError(t, phone.Close())
+	require.NoError(t, laptop.Close())
+	readNoteMessage(t, friend, hub.Presence, &presence)
+	assert.Equal(t, entity.PresenceOffline, presence.Status)
+	mocks.presenceRepo.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestMessageSocketService_SendTeamMessage_NotMember(t *testing.T) {
+	server, mocks := startMessageSocketServer(t)
+	expectUsersWithoutContacts(mocks)
+	mocks.teamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID1}}, nil)
+
+	conn := dialMessages(t, server, tests.TestUserID2)
+	require.NoError(t, conn.WriteJSON(dto.MessageCommand{
+		Type:        dto.MessageCommandSend,
+		RequestID:   "c4",
+		TeamID:      tests.TestTeamID,
+		TextContent: "hello",
+	}))
+
+	var failure dto.CommandError
+	readNoteMessage(t, conn, hub.CommandError, &failure)
+	assert.Equal(t, "c4", failure.RequestID)
+	assert.Equal(t, dto.CommandErrorForbidden, failure.Code)
+	mocks.messageRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
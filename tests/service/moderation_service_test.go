@@ -0,0 +1,154 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newModerationTeam returns a team owned by TestUserID1, with TestUserID2 as an admin and TestUserID as a plain member
+func newModerationTeam() *entity.Team {
+	return &entity.Team{
+		Id:       tests.TestTeamID,
+		OwnerId:  tests.TestUserID1,
+		AdminIds: []string{tests.TestUserID2},
+		UsersIds: []string{tests.TestUserID1, tests.TestUserID2, tests.TestUserID},
+	}
+}
+
+func TestModerationService_Ban_RemovesMemberAndLogs(t *testing.T) {
+	mockModerationRepo := new(tests.MockModerationRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockUserRepo := new(tests.MockUserRepository)
+	ms := service.NewModerationServiceWithRepo(mockModerationRepo, mockTeamRepo, mockUserRepo)
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(newModerationTeam(), nil)
+	mockUserRepo.On("GetByID", tests.TestUserID).Return(&entity.User{ID: tests.TestUserID, TeamsIds: &[]string{tests.TestTeamID}}, nil)
+	mockUserRepo.On("Update", mock.AnythingOfType("*entity.User")).Return(nil)
+	mockTeamRepo.On("Update", mock.AnythingOfType("*entity.Team")).Return(nil)
+	mockModerationRepo.On("SaveBan", mock.AnythingOfType("*entity.TeamRestriction")).Return(nil)
+	mockModerationRepo.On("CreateLogEntry", mock.AnythingOfType("*entity.ModerationLogEntry")).Return(nil)
+
+	before := time.Now().Unix()
+	ban, err := ms.Ban(tests.TestUserID2, tests.TestTeamID, tests.TestUserID, &dto.ModerationRequest{Reason: " spam ", DurationMinutes: 60})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "spam", ban.Reason)
+	assert.GreaterOrEqual(t, ban.ExpiresAt, before+3600)
+	mockTeamRepo.AssertCalled(t, "Update", mock.MatchedBy(func(team *entity.Team) bool {
+		return len(team.UsersIds) == 2
+	}))
+	mockModerationRepo.AssertCalled(t, "CreateLogEntry", mock.MatchedBy(func(entry *entity.ModerationLogEntry) bool {
+		return entry.Action == entity.ModerationBan && entry.TargetID == tests.TestUserID && entry.ModeratorID == tests.TestUserID2
+	}))
+}
+
+func TestModerationService_Kick_AdminCanNotKickAdmin(t *testing.T) {
+	mockModerationRepo := new(tests.MockModerationRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ms := service.NewModerationServiceWithRepo(mockModerationRepo, mockTeamRepo, new(tests.MockUserRepository))
+
+	team := newModerationTeam()
+	team.AdminIds = append(team.AdminIds, tests.TestUserID)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(team, nil)
+
+	_, err := ms.Kick(tests.TestUserID2, tests.TestTeamID, tests.TestUserID, &dto.ModerationRequest{})
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockTeamRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestModerationService_Mute_RequiresDuration(t *testing.T) {
+	ms := service.NewModerationServiceWithRepo(new(tests.MockModerationRepository), new(tests.MockTeamRepository), new(tests.MockUserRepository))
+
+	_, err := ms.Mute(tests.TestUserID1, tests.TestTeamID, tests.TestUserID, &dto.ModerationRequest{Reason: "flooding"})
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+}
+
+func TestModerationService_CheckNotBanned_IgnoresExpiredBan(t *testing.T) {
+	mockModerationRepo := new(tests.MockModerationRepository)
+	ms := service.NewModerationServiceWithRepo(mockModerationRepo, new(tests.MockTeamRepository), new(tests.MockUserRepository))
+
+	expired := entity.NewTeamRestriction(tests.TestTeamID, tests.TestUserID, tests.TestUserID1, "", 100, time.Now().Add(-time.Minute).Unix())
+	mockModerationRepo.On("GetBan", tests.TestTeamID, tests.TestUserID).Return(expired, nil)
+
+	assert.NoError(t, ms.CheckNotBanned(tests.TestTeamID, tests.TestUserID))
+}
+
+func TestTeamService_AddUserToTeam_Banned(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockModerationRepo := new(tests.MockModerationRepository)
+	ts := service.NewTeamServiceWithRepo(mockUserRepo, mockTeamRepo)
+	ts.SetModerationChecker(service.NewModerationServiceWithRepo(mockModerationRepo, mockTeamRepo, mockUserRepo))
+
+	mockUserRepo.On("GetByID", tests.TestUserID).Return(&entity.User{ID: tests.TestUserID}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(newModerationTeam(), nil)
+	mockModerationRepo.On("GetBan", tests.TestTeamID, tests.TestUserID).Return(entity.NewTeamRestriction(tests.TestTeamID, tests.TestUserID, tests.TestUserID1, "spam", 100, 0), nil)
+
+	_, _, err := ts.AddUserToTeam(tests.TestUserID, tests.TestUserID, tests.TestTeamID)
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestTeamService_AddUserToTeam_OnlyAdminsAddOthers(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTeamServiceWithRepo(mockUserRepo, mockTeamRepo)
+
+	mockUserRepo.On("GetByID", "newcomer").Return(&entity.User{ID: "newcomer"}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(newModerationTeam(), nil)
+
+	_, _, err := ts.AddUserToTeam(tests.TestUserID, "newcomer", tests.TestTeamID)
+	assert.ErrorIs(t, err, service.ErrForbidden)
+
+	_, _, err = ts.AddUserToTeam("newcomer", "newcomer", tests.TestTeamID)
+	assert.ErrorIs(t, err, service.ErrForbidden)
+
+	mockTeamRepo.AssertNotCalled(t, "Update", mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestTeamService_DeleteUserFromTeam_MemberCanNotRemoveOthers(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockModerationRepo := new(tests.MockModerationRepository)
+	ts := service.NewTeamServiceWithRepo(mockUserRepo, mockTeamRepo)
+	ts.SetMemberKicker(service.NewModerationServiceWithRepo(mockModerationRepo, mockTeamRepo, mockUserRepo))
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(newModerationTeam(), nil)
+
+	_, _, err := ts.DeleteUserFromTeam(tests.TestUserID, tests.TestUserID2, tests.TestTeamID)
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockTeamRepo.AssertNotCalled(t, "Update", mock.Anything)
+	mockModerationRepo.AssertNotCalled(t, "CreateLogEntry", mock.Anything)
+}
+
+func TestMessageService_CreateTeamMessage_Muted(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockMessageRepo := new(tests.MockMessageRepository)
+	mockModerationRepo := new(tests.MockModerationRepository)
+	ms := service.NewMessageServiceWithRepo(mockUserRepo, mockTeamRepo, mockMessageRepo, new(tests.MockChannelRepository))
+	ms.SetModerationChecker(service.NewModerationServiceWithRepo(mockModerationRepo, mockTeamRepo, mockUserRepo))
+
+	mockUserRepo.On("GetByID", tests.TestUserID).Return(&entity.User{ID: tests.TestUserID}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(newModerationTeam(), nil)
+	mute := entity.NewTeamRestriction(tests.TestTeamID, tests.TestUserID, tests.TestUserID1, "", 100, time.Now().Add(time.Hour).Unix())
+	mockModerationRepo.On("GetMute", tests.TestTeamID, tests.TestUserID).Return(mute, nil)
+
+	_, err := ms.CreateTeamMessage(dto.NewTeamMessageRequest(tests.TestUserID, tests.TestTeamID, "hello"))
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockMessageRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,205 @@
+package service_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/crdt"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testNoteID = "note1"
+
+func newNoteService() (*service.NoteService, *tests.MockNoteRepository, *tests.MockTeamRepository) {
+	noteRepo := new(tests.MockNoteRepository)
+	teamRepo := new(tests.MockTeamRepository)
+	teamRepo.On("GetTeamById", tests.TestTeamID).Return(newForumTeam(), nil)
+	return service.NewNoteServiceWithRepo(noteRepo, teamRepo), noteRepo, teamRepo
+}
+
+// startNoteServer serves the note WebSocket of the service, the user being given as ?user=
+func startNoteServer(t *testing.T, ns *service.NoteService) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/notes/:noteId", func(c *gin.Context) {
+		version, err := strconv.Atoi(c.DefaultQuery("version", "-1"))
+		require.NoError(t, err)
+		conn, err := hub.AcceptConnection(c)
+		require.NoError(t, err)
+		require.NoError(t, ns.Join(c.Query("user"), tests.TestTeamID, c.Param("noteId"), version, conn))
+	})
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// expectLiveNote mocks an empty note which is saved once its editors leave
+func expectLiveNote(noteRepo *tests.MockNoteRepository) {
+	noteRepo.On("GetByID", testNoteID).Return(entity.NewNote(testNoteID, tests.TestTeamID, "Algebra", tests.TestUserID1), nil)
+	noteRepo.On("GetSnapshot", testNoteID).Return(nil, nil)
+	noteRepo.On("SaveSnapshot", mock.AnythingOfType("*entity.NoteSnapshot")).Return(nil).Maybe()
+	noteRepo.On("Update", mock.AnythingOfType("*entity.Note")).Return(nil).Maybe()
+}
+
+func dialNote(t *testing.T, server *httptest.Server, userID, query string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/notes/" + testNoteID + "?user=" + userID + query
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	return conn
+}
+
+// readNoteMessage returns the next message of the given type, skipping the others
+func readNoteMessage(t *testing.T, conn *websocket.Conn, msgType hub.MessageType, payload interface{}) {
+	for {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		var msg struct {
+			Type    hub.MessageType `json:"type"`
+			Payload json.RawMessage `json:"payload"`
+		}
+		require.NoError(t, conn.ReadJSON(&msg))
+		if msg.Type == msgType {
+			require.NoError(t, json.Unmarshal(msg.Payload, payload))
+			return
+		}
+	}
+}
+
+func TestNoteService_GetNote_NonMemberForbidden(t *testing.T) {
+	ns, _, _ := newNoteService()
+
+	_, err := ns.GetNote("stranger", tests.TestTeamID, testNoteID)
+
+	assert.True(t, errors.Is(err, service.ErrForbidden))
+}
+
+func TestNoteService_GetNote_ReadsSnapshot(t *testing.T) {
+	ns, noteRepo, _ := newNoteService()
+	text := crdt.NewText()
+	_, _ = text.Apply(crdt.Operation{Type: crdt.OperationInsert, ID: crdt.ID{Counter: 1, Site: "a"}, Value: "Vectors"})
+
+	noteRepo.On("GetByID", testNoteID).Return(entity.NewNote(testNoteID, tests.TestTeamID, "Algebra", tests.TestUserID1), nil)
+	noteRepo.On("GetSnapshot", testNoteID).Return(&entity.NoteSnapshot{NoteID: testNoteID, Version: 1, Elements: text.Elements()}, nil)
+
+	resp, err := ns.GetNote(tests.TestUserID2, tests.TestTeamID, testNoteID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Vectors", resp.Text)
+	assert.Empty(t, resp.EditorIDs)
+}
+
+func TestNoteService_Join_SynchronisesEditsAndPresence(t *testing.T) {
+	ns, noteRepo, _ := newNoteService()
+	expectLiveNote(noteRepo)
+	server := startNoteServer(t, ns)
+
+	first := dialNote(t, server, tests.TestUserID1, "")
+	defer first.Close()
+	var firstState dto.NoteStatePayload
+	readNoteMessage(t, first, hub.NoteState, &firstState)
+	assert.False(t, firstState.CatchUp)
+	assert.Equal(t, 0, firstState.Version)
+
+	second := dialNote(t, server, tests.TestUserID2, "")
+	defer second.Close()
+	var secondState dto.NoteStatePayload
+	readNoteMessage(t, second, hub.NoteState, &secondState)
+	assert.Len(t, secondState.Presence, 1)
+	var joined dto.NotePresence
+	readNoteMessage(t, first, hub.NotePresence, &joined)
+	assert.Equal(t, tests.TestUserID2, joined.UserID)
+
+	site := firstState.Site
+	require.NoError(t, first.WriteJSON(dto.NoteClientMessage{Type: dto.NoteClientOperations, Ops: []crdt.Operation{
+		{Type: crdt.OperationInsert, ID: crdt.ID{Counter: 1, Site: site}, Value: "Hi"},
+	}}))
+	var ops dto.NoteOperationsPayload
+	readNoteMessage(t, second, hub.NoteOperations, &ops)
+	assert.Equal(t, 0, ops.BaseVersion)
+	assert.Equal(t, 1, ops.Version)
+	assert.Equal(t, tests.TestUserID1, ops.UserID)
+
+	require.NoError(t, second.WriteJSON(dto.NoteClientMessage{Type: dto.NoteClientCursor, Cursor: &dto.NoteCursor{
+		Anchor: crdt.ID{Counter: 2, Site: site},
+		Head:   crdt.ID{Counter: 2, Site: site},
+	}}))
+	var cursor dto.NotePresence
+	readNoteMessage(t, first, hub.NotePresence, &cursor)
+	assert.Equal(t, 2, cursor.Cursor.Anchor.Counter)
+
+	resp, err := ns.GetNote(tests.TestUserID, tests.TestTeamID, testNoteID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hi", resp.Text)
+	assert.Equal(t, 1, resp.Version)
+	assert.Equal(t, []string{tests.TestUserID1, tests.TestUserID2}, resp.EditorIDs)
+
+	// a client that saw version 0 catches up with the operations
+	third := dialNote(t, server, tests.TestUserID, "&version=0")
+	defer third.Close()
+	var catchUp dto.NoteStatePayload
+	readNoteMessage(t, third, hub.NoteState, &catchUp)
+	assert.True(t, catchUp.CatchUp)
+	assert.Len(t, catchUp.Ops, 1)
+	assert.Empty(t, catchUp.Elements)
+}
+
+func TestNoteService_InvalidOperationsReturnAnError(t *testing.T) {
+	ns, noteRepo, _ := newNoteService()
+	expectLiveNote(noteRepo)
+	server := startNoteServer(t, ns)
+
+	conn := dialNote(t, server, tests.TestUserID1, "")
+	defer conn.Close()
+	var state dto.NoteStatePayload
+	readNoteMessage(t, conn, hub.NoteState, &state)
+
+	require.NoError(t, conn.WriteJSON(dto.NoteClientMessage{Type: dto.NoteClientOperations, Ops: []crdt.Operation{
+		{Type: crdt.OperationDelete, ID: crdt.ID{Counter: 7, Site: "nobody"}},
+	}}))
+	var failure map[string]string
+	readNoteMessage(t, conn, hub.NoteError, &failure)
+	assert.Contains(t, failure["error"], "unknown character")
+}
+
+func TestNoteService_LastEditorLeaving_SavesSnapshot(t *testing.T) {
+	ns, noteRepo, _ := newNoteService()
+	noteRepo.On("GetByID", testNoteID).Return(entity.NewNote(testNoteID, tests.TestTeamID, "Algebra", tests.TestUserID1), nil)
+	noteRepo.On("GetSnapshot", testNoteID).Return(nil, nil)
+	saved := make(chan *entity.NoteSnapshot, 1)
+	noteRepo.On("SaveSnapshot", mock.AnythingOfType("*entity.NoteSnapshot")).Run(func(args mock.Arguments) {
+		saved <- args.Get(0).(*entity.NoteSnapshot)
+	}).Return(nil)
+	noteRepo.On("Update", mock.AnythingOfType("*entity.Note")).Return(nil)
+	server := startNoteServer(t, ns)
+
+	conn := dialNote(t, server, tests.TestUserID1, "")
+	var state dto.NoteStatePayload
+	readNoteMessage(t, conn, hub.NoteState, &state)
+	require.NoError(t, conn.WriteJSON(dto.NoteClientMessage{Type: dto.NoteClientOperations, Ops: []crdt.Operation{
+		{Type: crdt.OperationInsert, ID: crdt.ID{Counter: 1, Site: state.Site}, Value: "Matrices"},
+	}}))
+	var ops dto.NoteOperationsPayload
+	readNoteMessage(t, conn, hub.NoteOperations, &ops)
+	require.NoError(t, conn.Close())
+
+	select {
+	case snapshot := <-saved:
+		assert.Equal(t, 1, snapshot.Version)
+		assert.Equal(t, "Matrices", crdt.NewTextFromElements(snapshot.Elements).String())
+	case <-time.After(2 * time.Second):
+		t.Fatal("the note was not saved")
+	}
+}
@@ -0,0 +1,147 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testOrganizationID = "org1"
+
+func newOrganizationService() (*service.OrganizationService, *tests.MockOrganizationRepository, *tests.MockUserRepository) {
+	mockOrganizationRepo := new(tests.MockOrganizationRepository)
+	mockUserRepo := new(tests.MockUserRepository)
+	return service.NewOrganizationServiceWithRepo(mockOrganizationRepo, mockUserRepo), mockOrganizationRepo, mockUserRepo
+}
+
+func testOrganization() *entity.Organization {
+	organization := entity.NewOrganization(testOrganizationID, "UBB", []string{"ubbcluj.ro"}, tests.TestUserID)
+	organization.AdminIds = []string{tests.TestUserID1}
+	return organization
+}
+
+func TestOrganizationService_CreateOrganization_DomainTaken(t *testing.T) {
+	orgs, mockOrganizationRepo, _ := newOrganizationService()
+
+	mockOrganizationRepo.On("GetAll").Return([]*entity.Organization{testOrganization()}, nil)
+
+	_, err := orgs.CreateOrganization(tests.TestUserID, &dto.OrganizationRequest{Name: "Other", EmailDomains: []string{" UBBCluj.ro "}})
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+	mockOrganizationRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestOrganizationService_CreateOrganization_InvalidDomain(t *testing.T) {
+	orgs, _, _ := newOrganizationService()
+
+	_, err := orgs.CreateOrganization(tests.TestUserID, &dto.OrganizationRequest{Name: "UBB", EmailDomains: []string{"@ubbcluj.ro"}})
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+}
+
+func TestOrganizationService_ResolveSignupOrganization_ByEmailDomain(t *testing.T) {
+	orgs, mockOrganizationRepo, _ := newOrganizationService()
+
+	mockOrganizationRepo.On("GetAll").Return([]*entity.Organization{testOrganization()}, nil)
+
+	organizationID, err := orgs.ResolveSignupOrganization("ana@UBBCLUJ.ro", "")
+	assert.NoError(t, err)
+	assert.Equal(t, testOrganizationID, organizationID)
+
+	organizationID, err = orgs.ResolveSignupOrganization("ana@gmail.com", "")
+	assert.NoError(t, err)
+	assert.Empty(t, organizationID)
+}
+
+func TestOrganizationService_ResolveSignupOrganization_RequestedOrganizationRestrictsDomain(t *testing.T) {
+	orgs, mockOrganizationRepo, _ := newOrganizationService()
+
+	mockOrganizationRepo.On("GetByID", testOrganizationID).Return(testOrganization(), nil)
+
+	_, err := orgs.ResolveSignupOrganization("ana@gmail.com", testOrganizationID)
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+}
+
+func TestOrganizationService_SetAdmin_OnlyOrganizationAdmins(t *testing.T) {
+	orgs, mockOrganizationRepo, mockUserRepo := newOrganizationService()
+
+	mockUserRepo.On("GetByID", tests.TestUserID2).Return(&entity.User{ID: tests.TestUserID2, OrganizationID: testOrganizationID}, nil)
+	mockOrganizationRepo.On("GetByID", testOrganizationID).Return(testOrganization(), nil)
+
+	_, err := orgs.SetAdmin(tests.TestUserID2, testOrganizationID, tests.TestUserID2, true)
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockOrganizationRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestOrganizationService_SetAdmin_KeepsLastAdmin(t *testing.T) {
+	orgs, mockOrganizationRepo, mockUserRepo := newOrganizationService()
+
+	mockUserRepo.On("GetByID", tests.TestUserID1).Return(&entity.User{ID: tests.TestUserID1, OrganizationID: testOrganizationID}, nil)
+	mockOrganizationRepo.On("GetByID", testOrganizationID).Return(testOrganization(), nil)
+
+	_, err := orgs.SetAdmin(tests.TestUserID1, testOrganizationID, tests.TestUserID1, false)
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+}
+
+func TestOrganizationService_AddUser_UserInTeams(t *testing.T) {
+	orgs, mockOrganizationRepo, mockUserRepo := newOrganizationService()
+
+	teams := []string{tests.TestTeamID}
+	mockOrganizationRepo.On("GetByID", testOrganizationID).Return(testOrganization(), nil)
+	mockUserRepo.On("GetByID", tests.TestUserID2).Return(&entity.User{ID: tests.TestUserID2, TeamsIds: &teams}, nil)
+
+	_, err := orgs.AddUser(testOrganizationID, tests.TestUserID2)
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestTeamService_GetVisibleTeam_OtherOrganization(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTeamServiceWithRepo(mockUserRepo, mockTeamRepo)
+
+	mockUserRepo.On("GetByID", tests.TestUserID).Return(&entity.User{ID: tests.TestUserID, OrganizationID: testOrganizationID}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, OrganizationID: "org2"}, nil)
+
+	_, err := ts.GetVisibleTeam(tests.TestUserID, tests.TestTeamID)
+
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+}
+
+func TestTeamService_AddUserToTeam_OtherOrganization(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTeamServiceWithRepo(mockUserRepo, mockTeamRepo)
+
+	mockUserRepo.On("GetByID", tests.TestUserID2).Return(&entity.User{ID: tests.TestUserID2, OrganizationID: testOrganizationID}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, OrganizationID: "org2"}, nil)
+
+	_, _, err := ts.AddUserToTeam(tests.TestUserID2, tests.TestUserID2, tests.TestTeamID)
+
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUserService_GetAllUsers_ScopedToOrganization(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	us := service.NewUserServiceWithRepo(mockUserRepo, new(tests.MockTeamRepository))
+
+	mockUserRepo.On("GetByID", tests.TestUserID).Return(&entity.User{ID: tests.TestUserID, OrganizationID: testOrganizationID}, nil)
+	mockUserRepo.On("GetByOrganizationID", testOrganizationID).Return([]*entity.User{{ID: tests.TestUserID, OrganizationID: testOrganizationID}}, nil)
+
+	users, err := us.GetAllUsers(tests.TestUserID)
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	mockUserRepo.AssertNotCalled(t, "GetAll")
+}
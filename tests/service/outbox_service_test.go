@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/hub"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type seqMessage struct {
+	Type    hub.MessageType `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+	Seq     int64           `json:"seq"`
+}
+
+func readSeqMessage(t *testing.T, conn *websocket.Conn) seqMessage {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var msg seqMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestOutboxService_NumbersAndTrimsEvents(t *testing.T) {
+	server, mocks := startMessageSocketServer(t)
+	expectNoContacts(mocks)
+	mocks.outboxRepo.On("GetLast", tests.TestUserID1).Return(&entity.OutboxEntry{Seq: 549}, nil)
+	mocks.outboxRepo.On("GetLast", tests.TestUserID2).Return(nil, nil)
+	saved := make(chan []*entity.OutboxEntry, 1)
+	mocks.outboxRepo.On("SaveAll", tests.TestUserID1, mock.Anything).Return(nil)
+	mocks.outboxRepo.On("SaveAll", tests.TestUserID2, mock.Anything).Run(func(args mock.Arguments) {
+		saved <- args.Get(1).([]*entity.OutboxEntry)
+	}).Return(nil)
+	trimmed := make(chan struct{}, 1)
+	mocks.outboxRepo.On("DeleteUpTo", tests.TestUserID1, int64(50)).Run(func(mock.Arguments) {
+		trimmed <- struct{}{}
+	}).Return(nil)
+	mocks.messageRepo.On("Create", mock.AnythingOfType("*entity.Message")).Return(nil)
+
+	conn := dialMessages(t, server, tests.TestUserID1)
+	require.NoError(t, conn.WriteJSON(dto.MessageCommand{Type: dto.MessageCommandSend, ReceiverID: tests.TestUserID2, TextContent: "hello"}))
+
+	var msg seqMessage
+	for msg.Type != hub.DirectMessage {
+		msg = readSeqMessage(t, conn)
+	}
+	assert.Equal(t, int64(550), msg.Seq)
+
+	// The events are saved in the background
+	select {
+	case entries := <-saved:
+		require.Len(t, entries, 1)
+		assert.Equal(t, int64(1), entries[0].Seq)
+		assert.Equal(t, string(hub.DirectMessage), entries[0].Type)
+	case <-time.After(2 * time.Second):
+		t.Fatal("the event was not saved")
+	}
+	select {
+	case <-trimmed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the outbox was not trimmed")
+	}
+}
+
+func TestOutboxService_ReplaysMissedEventsBeforeLiveOnes(t *testing.T) {
+	server, mocks := startMessageSocketServer(t)
+	expectNoContacts(mocks)
+	mocks.outboxRepo.On("GetLast", tests.TestUserID1).Return(&entity.OutboxEntry{Seq: 12}, nil)
+	mocks.outboxRepo.On("GetAfter", tests.TestUserID1, int64(10)).Return([]*entity.OutboxEntry{
+		entity.NewOutboxEntry(tests.TestUserID1, 11, string(hub.MessageEdited), json.RawMessage(`{"id":"msg1"}`)),
+		entity.NewOutboxEntry(tests.TestUserID1, 12, string(hub.MessageDeleted), json.RawMessage(`{"id":"msg2"}`)),
+	}, nil)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/connect?user=" + tests.TestUserID1 + "&since=10"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	edited := readSeqMessage(t, conn)
+	assert.Equal(t, hub.MessageEdited, edited.Type)
+	assert.Equal(t, int64(11), edited.Seq)
+	assert.JSONEq(t, `{"id":"msg1"}`, string(edited.Payload))
+	assert.Equal(t, int64(12), readSeqMessage(t, conn).Seq)
+
+	connectedMsg := readSeqMessage(t, conn)
+	require.Equal(t, hub.Connected, connectedMsg.Type)
+	var connected dto.ConnectedEvent
+	require.NoError(t, json.Unmarshal(connectedMsg.Payload, &connected))
+	assert.Equal(t, dto.ConnectedEvent{Seq: 12, Replayed: 2}, connected)
+}
+
+func TestOutboxService_ReplayTruncated(t *testing.T) {
+	server, mocks := startMessageSocketServer(t)
+	expectNoContacts(mocks)
+	mocks.outboxRepo.On("GetLast", tests.TestUserID1).Return(&entity.OutboxEntry{Seq: 900}, nil)
+	mocks.outboxRepo.On("GetAfter", tests.TestUserID1, int64(3)).Return([]*entity.OutboxEntry{
+		entity.NewOutboxEntry(tests.TestUserID1, 900, string(hub.MessageEdited), json.RawMessage(`{}`)),
+	}, nil)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/connect?user=" + tests.TestUserID1 + "&since=3"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var connected dto.ConnectedEvent
+	readNoteMessage(t, conn, hub.Connected, &connected)
+	assert.True(t, connected.Truncated)
+	assert.Equal(t, 1, connected.Replayed)
+}
+
+func TestOutboxService_ReplaysEventsNotSavedYet(t *testing.T) {
+	server, mocks := startMessageSocketServer(t)
+	expectNoContacts(mocks)
+	mocks.outboxRepo.On("GetLast", mock.Anything).Return(nil, nil)
+	mocks.outboxRepo.On("GetAfter", tests.TestUserID2, int64(1)).Return([]*entity.OutboxEntry{}, nil)
+	mocks.outboxRepo.On("SaveAll", tests.TestUserID1, mock.Anything).Return(nil)
+	// The first save of TestUserID2's events is slow, so the second event waits for it
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	mocks.outboxRepo.On("SaveAll", tests.TestUserID2, mock.Anything).Run(func(mock.Arguments) {
+		<-release
+	}).Return(nil)
+	mocks.messageRepo.On("Create", mock.AnythingOfType("*entity.Message")).Return(nil)
+
+	sender := dialMessages(t, server, tests.TestUserID1)
+	for _, requestID := range []string{"c1", "c2"} {
+		require.NoError(t, sender.WriteJSON(dto.MessageCommand{Type: dto.MessageCommandSend, RequestID: requestID, ReceiverID: tests.TestUserID2, TextContent: "hello"}))
+		var ack dto.CommandAck
+		readNoteMessage(t, sender, hub.CommandAck, &ack)
+	}
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/connect?user=" + tests.TestUserID2 + "&since=1"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	replayed := readSeqMessage(t, conn)
+	assert.Equal(t, hub.DirectMessage, replayed.Type)
+	assert.Equal(t, int64(2), replayed.Seq)
+	var connected dto.ConnectedEvent
+	readNoteMessage(t, conn, hub.Connected, &connected)
+	assert.Equal(t, dto.ConnectedEvent{Seq: 2, Replayed: 1}, connected)
+}
@@ -0,0 +1,37 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresenceService_GetContactsPresence_FriendsAndTeammatesOnce(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockFriendRequestRepo := new(tests.MockFriendRequestRepository)
+	mockPresenceRepo := new(tests.MockPresenceRepository)
+	ps := service.NewPresenceServiceWithRepo(mockUserRepo, mockTeamRepo, mockFriendRequestRepo, mockPresenceRepo)
+
+	teams := []string{tests.TestTeamID}
+	mockUserRepo.On("GetByID", tests.TestUserID1).Return(&entity.User{ID: tests.TestUserID1, TeamsIds: &teams}, nil)
+	mockFriendRequestRepo.On("GetFriendsForUser", tests.TestUserID1).Return([]string{tests.TestUserID2}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(newForumTeam(), nil)
+	mockPresenceRepo.On("Get", tests.TestUserID).Return(nil, nil)
+	mockPresenceRepo.On("Get", tests.TestUserID2).Return(entity.NewPresence(tests.TestUserID2, 1700000000), nil)
+
+	presences, err := ps.GetContactsPresence(tests.TestUserID1)
+
+	assert.NoError(t, err)
+	assert.Len(t, presences, 2)
+	for _, presence := range presences {
+		assert.NotEqual(t, tests.TestUserID1, presence.UserID)
+		assert.Equal(t, entity.PresenceOffline, presence.Status)
+		if presence.UserID == tests.TestUserID2 {
+			assert.Equal(t, int64(1700000000), presence.LastSeenAt)
+		}
+	}
+}
@@ -144,16 +144,25 @@ func TestQuizService_CreateQuiz_InvalidQuestionFormat_ValidationFail(t *testing.
 	assert.Contains(t, err.Error(), "questions are invalid")
 }
 
-func TestQuizService_GetQuizWithAnswersById_Success(t *testing.T) {
+// newVisibleQuizService returns a quiz service whose test team and user belong to the same organization
+func newVisibleQuizService() (*service.QuizService, *tests.MockQuizRepository, *tests.MockUserRepository) {
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockUserRepo := new(tests.MockUserRepository)
 	mockQuizRepo := new(tests.MockQuizRepository)
-	quizService := service.NewQuizServiceWithRepo(nil, nil, mockQuizRepo)
+	mockTeamRepo.On("GetTeamById", TestTeamID).Return(&entity.Team{Id: TestTeamID, OrganizationID: "org1"}, nil)
+	mockUserRepo.On("GetByID", TestUserID).Return(&entity.User{ID: TestUserID, OrganizationID: "org1"}, nil)
+	return service.NewQuizServiceWithRepo(mockTeamRepo, mockUserRepo, mockQuizRepo), mockQuizRepo, mockUserRepo
+}
+
+func TestQuizService_GetQuizWithAnswersById_Success(t *testing.T) {
+	quizService, mockQuizRepo, _ := newVisibleQuizService()
 
 	expectedQuiz := getValidQuizRequestEntity()
 	expectedQuiz.ID = MockQuizID
 
 	mockQuizRepo.On("GetById", MockQuizID).Return(expectedQuiz, nil).Once()
 
-	resultQuiz, err := quizService.GetQuizWithAnswersById(MockQuizID)
+	resultQuiz, err := quizService.GetQuizWithAnswersById(TestUserID, MockQuizID)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedQuiz, resultQuiz)
 	mockQuizRepo.AssertExpectations(t)
@@ -162,7 +171,7 @@ func TestQuizService_GetQuizWithAnswersById_Success(t *testing.T) {
 func TestQuizService_GetQuizWithAnswersById_EmptyID_ValidationFail(t *testing.T) {
 	mockService := service.NewQuizServiceWithRepo(nil, nil, nil)
 
-	_, err := mockService.GetQuizWithAnswersById("")
+	_, err := mockService.GetQuizWithAnswersById(TestUserID, "")
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, validator.ErrValidation))
@@ -175,7 +184,7 @@ func TestQuizService_GetQuizWithAnswersById_NotFound(t *testing.T) {
 
 	mockQuizRepo.On("GetById", MockQuizID).Return(entity.Quiz{}, errors.New("db error: quiz not found")).Once()
 
-	_, err := quizService.GetQuizWithAnswersById(MockQuizID)
+	_, err := quizService.GetQuizWithAnswersById(TestUserID, MockQuizID)
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, service.ErrResourceNotFound))
@@ -184,8 +193,7 @@ func TestQuizService_GetQuizWithAnswersById_NotFound(t *testing.T) {
 }
 
 func TestQuizService_GetQuizWithoutAnswersById_Success(t *testing.T) {
-	mockQuizRepo := new(tests.MockQuizRepository)
-	quizService := service.NewQuizServiceWithRepo(nil, nil, mockQuizRepo)
+	quizService, mockQuizRepo, _ := newVisibleQuizService()
 
 	quiz := getValidQuizRequestEntity()
 	quiz.ID = MockQuizID
@@ -193,7 +201,7 @@ func TestQuizService_GetQuizWithoutAnswersById_Success(t *testing.T) {
 
 	mockQuizRepo.On("GetById", MockQuizID).Return(quiz, nil).Once()
 
-	result, err := quizService.GetQuizWithoutAnswersById(MockQuizID)
+	result, err := quizService.GetQuizWithoutAnswersById(TestUserID, MockQuizID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, MockQuizID, result.QuizID)
@@ -208,7 +216,7 @@ func TestQuizService_GetQuizWithoutAnswersById_Success(t *testing.T) {
 func TestQuizService_GetQuizWithoutAnswersById_EmptyID_ValidationFail(t *testing.T) {
 	quizService := service.NewQuizServiceWithRepo(nil, nil, nil)
 
-	_, err := quizService.GetQuizWithoutAnswersById("")
+	_, err := quizService.GetQuizWithoutAnswersById(TestUserID, "")
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, validator.ErrValidation))
@@ -221,7 +229,7 @@ func TestQuizService_GetQuizWithoutAnswersById_NotFound(t *testing.T) {
 
 	mockQuizRepo.On("GetById", MockQuizID).Return(entity.Quiz{}, errors.New("db error: quiz not found")).Once()
 
-	_, err := quizService.GetQuizWithoutAnswersById(MockQuizID)
+	_, err := quizService.GetQuizWithoutAnswersById(TestUserID, MockQuizID)
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, service.ErrResourceNotFound))
@@ -229,6 +237,21 @@ func TestQuizService_GetQuizWithoutAnswersById_NotFound(t *testing.T) {
 	mockQuizRepo.AssertExpectations(t)
 }
 
+func TestQuizService_GetQuizWithAnswersById_OtherOrganization(t *testing.T) {
+	quizService, mockQuizRepo, mockUserRepo := newVisibleQuizService()
+
+	quiz := getValidQuizRequestEntity()
+	quiz.ID = MockQuizID
+	mockQuizRepo.On("GetById", MockQuizID).Return(quiz, nil)
+	mockUserRepo.On("GetByID", "outsider").Return(&entity.User{ID: "outsider", OrganizationID: "org2"}, nil)
+
+	_, err := quizService.GetQuizWithAnswersById("outsider", MockQuizID)
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+
+	_, err = quizService.GetQuizWithoutAnswersById("outsider", MockQuizID)
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+}
+
 func TestQuizService_SolveQuiz_Success_AllCorrect(t *testing.T) {
 	mockQuizRepo := new(tests.MockQuizRepository)
 	mockUserRepo := new(tests.MockUserRepository)
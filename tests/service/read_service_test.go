@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testDirectConversationID = entity.DirectConversationID(entity.GetConversationKey(tests.TestUserID1, tests.TestUserID2))
+
+func newReadService() (*service.ReadService, *tests.MockUserRepository, *tests.MockConversationRepository, *tests.MockMessageRepository, *tests.MockReadMarkerRepository) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockConversationRepo := new(tests.MockConversationRepository)
+	mockMessageRepo := new(tests.MockMessageRepository)
+	mockReadMarkerRepo := new(tests.MockReadMarkerRepository)
+	rs := service.NewReadServiceWithRepo(mockUserRepo, new(tests.MockTeamRepository), new(tests.MockChannelRepository), mockMessageRepo, mockReadMarkerRepo, mockConversationRepo)
+	return rs, mockUserRepo, mockConversationRepo, mockMessageRepo, mockReadMarkerRepo
+}
+
+func TestReadService_MarkRead(t *testing.T) {
+	rs, _, mockConversationRepo, mockMessageRepo, mockReadMarkerRepo := newReadService()
+
+	mockMessageRepo.On("GetByID", testMessageID).Return(testDirectMessage(), nil)
+	mockReadMarkerRepo.On("Get", tests.TestUserID2, testDirectConversationID).Return(nil, nil)
+	mockReadMarkerRepo.On("Save", mock.Anything).Return(nil)
+	entry := &entity.ConversationEntry{UserID: tests.TestUserID2, ConversationID: testDirectConversationID, LastMessageID: testMessageID, UnreadCount: 3}
+	mockConversationRepo.On("Get", tests.TestUserID2, testDirectConversationID).Return(entry, nil)
+	mockConversationRepo.On("Update", tests.TestUserID2, testDirectConversationID).Return(entry, nil)
+	mockConversationRepo.On("Save", mock.Anything).Return(nil)
+
+	marker, err := rs.MarkRead(tests.TestUserID2, testMessageID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, testMessageID, marker.MessageID)
+	assert.Equal(t, testDirectConversationID, marker.ConversationID)
+	mockConversationRepo.AssertCalled(t, "Save", mock.MatchedBy(func(entry *entity.ConversationEntry) bool {
+		return entry.UnreadCount == 0
+	}))
+}
+
+func TestReadService_MarkRead_RecountsEarlierMessage(t *testing.T) {
+	rs, _, mockConversationRepo, mockMessageRepo, mockReadMarkerRepo := newReadService()
+
+	read := testDirectMessage()
+	later := entity.NewMessage("msg2", tests.TestUserID1, read.ConversationKey, "", "", "are you there?")
+	later.SentAt = read.SentAt.Add(time.Minute)
+	mockMessageRepo.On("GetByID", testMessageID).Return(read, nil)
+	mockMessageRepo.On("GetByConversation", mock.Anything, mock.Anything).Return([]*entity.Message{read, later}, nil)
+	mockReadMarkerRepo.On("Get", tests.TestUserID2, testDirectConversationID).Return(nil, nil)
+	mockReadMarkerRepo.On("Save", mock.Anything).Return(nil)
+	entry := &entity.ConversationEntry{UserID: tests.TestUserID2, ConversationID: testDirectConversationID, LastMessageID: "msg2", UnreadCount: 2}
+	mockConversationRepo.On("Get", tests.TestUserID2, testDirectConversationID).Return(entry, nil)
+	mockConversationRepo.On("Update", tests.TestUserID2, testDirectConversationID).Return(entry, nil)
+	mockConversationRepo.On("Save", mock.Anything).Return(nil)
+
+	_, err := rs.MarkRead(tests.TestUserID2, testMessageID)
+
+	assert.NoError(t, err)
+	mockConversationRepo.AssertCalled(t, "Save", mock.MatchedBy(func(entry *entity.ConversationEntry) bool {
+		return entry.UnreadCount == 1
+	}))
+}
+
+func TestReadService_MarkRead_NeverMovesBack(t *testing.T) {
+	rs, _, _, mockMessageRepo, mockReadMarkerRepo := newReadService()
+
+	message := testDirectMessage()
+	marker := entity.NewReadMarker(tests.TestUserID2, testDirectConversationID)
+	marker.MessageID = "later"
+	marker.SentAt = message.SentAt.Add(time.Minute)
+	mockMessageRepo.On("GetByID", testMessageID).Return(message, nil)
+	mockReadMarkerRepo.On("Get", tests.TestUserID2, testDirectConversationID).Return(marker, nil)
+
+	read, err := rs.MarkRead(tests.TestUserID2, testMessageID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "later", read.MessageID)
+	mockReadMarkerRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestReadService_MarkRead_Reply(t *testing.T) {
+	rs, _, _, mockMessageRepo, _ := newReadService()
+
+	reply := testDirectMessage()
+	reply.ParentID = "parent"
+	mockMessageRepo.On("GetByID", testMessageID).Return(reply, nil)
+
+	_, err := rs.MarkRead(tests.TestUserID2, testMessageID)
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+}
+
+func TestReadService_GetUnreadCounts_SkipsLeftTeams(t *testing.T) {
+	rs, mockUserRepo, mockConversationRepo, _, mockReadMarkerRepo := newReadService()
+
+	teams := []string{tests.TestTeamID}
+	mockUserRepo.On("GetByID", tests.TestUserID2).Return(&entity.User{ID: tests.TestUserID2, TeamsIds: &teams}, nil)
+	mockConversationRepo.On("GetByUser", tests.TestUserID2).Return([]*entity.ConversationEntry{
+		{ConversationID: testDirectConversationID, Type: entity.ConversationTypeDirect, OtherUserID: tests.TestUserID1, UnreadCount: 1},
+		{ConversationID: entity.ChannelConversationID("c1"), Type: entity.ConversationTypeChannel, TeamID: tests.TestTeamID, ChannelID: "c1", UnreadCount: 2},
+		{ConversationID: entity.ChannelConversationID("c2"), Type: entity.ConversationTypeChannel, TeamID: "left", ChannelID: "c2", UnreadCount: 5},
+	}, nil)
+	mockReadMarkerRepo.On("GetByUser", tests.TestUserID2).Return([]*entity.ReadMarker{{ConversationID: testDirectConversationID, MessageID: testMessageID}}, nil)
+
+	counts, err := rs.GetUnreadCounts(tests.TestUserID2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, counts.TotalUnread)
+	assert.Len(t, counts.Conversations, 2)
+	assert.Equal(t, entity.ChannelConversationID("c1"), counts.Conversations[0].ConversationID)
+	assert.Equal(t, testMessageID, counts.Conversations[1].LastReadMessageID)
+}
+
+func TestReadService_TrackMessage_SenderHasRead(t *testing.T) {
+	rs, _, _, _, mockReadMarkerRepo := newReadService()
+
+	mockReadMarkerRepo.On("Save", mock.Anything).Return(nil)
+
+	rs.TrackMessage(testDirectMessage())
+
+	mockReadMarkerRepo.AssertCalled(t, "Save", mock.MatchedBy(func(marker *entity.ReadMarker) bool {
+		return marker.UserID == tests.TestUserID1 && marker.MessageID == testMessageID
+	}))
+}
@@ -0,0 +1,182 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/mappers"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSearchService() (*service.SearchService, *tests.MockSearchRepository, *tests.MockUserRepository) {
+	mockSearchRepo := new(tests.MockSearchRepository)
+	mockUserRepo := new(tests.MockUserRepository)
+	ss := service.NewSearchServiceWithRepo(mockSearchRepo, mockUserRepo, new(tests.MockTeamRepository), new(tests.MockQuizRepository), nil)
+	return ss, mockSearchRepo, mockUserRepo
+}
+
+func TestSearchService_Search_RanksExactMatchesFirst(t *testing.T) {
+	ss, mockSearchRepo, mockUserRepo := newSearchService()
+
+	teams := []string{"team1"}
+	mockUserRepo.On("GetByID", "user1").Return(&entity.User{ID: "user1", TeamsIds: &teams}, nil)
+
+	exact := mappers.MapTeamToSearchDocument(&entity.Team{Id: "team1", Name: "Algebra", IsPublic: true})
+	prefix := mappers.MapTeamToSearchDocument(&entity.Team{Id: "team2", Name: "Algebraic Geometry", IsPublic: true})
+
+	mockSearchRepo.On("GetPostingsByPrefix", "algebra", 50).Return(map[string]map[string]int{
+		"algebra":   {exact.Key: exact.Terms["algebra"]},
+		"algebraic": {prefix.Key: prefix.Terms["algebraic"]},
+	}, nil)
+	mockSearchRepo.On("GetByKeys", []string{exact.Key, prefix.Key}).Return(searchDocsByKey(exact, prefix), nil)
+
+	resp, err := ss.Search("user1", "ALGEBRA", nil, 1, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resp.TotalCount)
+	assert.Equal(t, "team1", resp.Results[0].ID)
+	assert.Equal(t, "team2", resp.Results[1].ID)
+	assert.Greater(t, resp.Results[0].Score, resp.Results[1].Score)
+	mockSearchRepo.AssertExpectations(t)
+}
+
+func TestSearchService_Search_FoldsDiacriticsAndRequiresEveryWord(t *testing.T) {
+	ss, mockSearchRepo, mockUserRepo := newSearchService()
+
+	mockUserRepo.On("GetByID", "user1").Return(&entity.User{ID: "user1"}, nil)
+
+	both := mappers.MapTeamToSearchDocument(&entity.Team{Id: "team1", Name: "Știința datelor", IsPublic: true})
+
+	mockSearchRepo.On("GetPostingsByPrefix", "stiinta", 50).Return(map[string]map[string]int{
+		"stiinta": {both.Key: 3, "team_team2": 3},
+	}, nil)
+	mockSearchRepo.On("GetPostingsByPrefix", "datelor", 50).Return(map[string]map[string]int{
+		"datelor": {both.Key: 3},
+	}, nil)
+	mockSearchRepo.On("GetByKeys", []string{both.Key}).Return(searchDocsByKey(both), nil)
+
+	resp, err := ss.Search("user1", "ȘTIINȚA Datelor", nil, 1, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.TotalCount)
+	assert.Equal(t, "team1", resp.Results[0].ID)
+	mockSearchRepo.AssertExpectations(t)
+}
+
+func TestSearchService_Search_RespectsMembership(t *testing.T) {
+	ss, mockSearchRepo, mockUserRepo := newSearchService()
+
+	teams := []string{"team1"}
+	mockUserRepo.On("GetByID", "user1").Return(&entity.User{ID: "user1", TeamsIds: &teams}, nil)
+
+	ownTeamMessage := mappers.MapMessageToSearchDocument(&entity.Message{ID: "m1", TeamID: "team1", TextContent: "exam tomorrow"})
+	otherTeamMessage := mappers.MapMessageToSearchDocument(&entity.Message{ID: "m2", TeamID: "team2", TextContent: "exam tomorrow"})
+	ownDirectMessage := mappers.MapMessageToSearchDocument(&entity.Message{ID: "m3", ConversationKey: entity.GetConversationKey("user1", "user2"), TextContent: "exam"})
+	otherDirectMessage := mappers.MapMessageToSearchDocument(&entity.Message{ID: "m4", ConversationKey: entity.GetConversationKey("user2", "user3"), TextContent: "exam"})
+	privateTeam := mappers.MapTeamToSearchDocument(&entity.Team{Id: "team3", Name: "Exam prep", IsPublic: false})
+
+	docs := []*entity.SearchDocument{ownTeamMessage, otherTeamMessage, ownDirectMessage, otherDirectMessage, privateTeam}
+	postings := map[string]int{}
+	for _, doc := range docs {
+		postings[doc.Key] = doc.Terms["exam"]
+	}
+	mockSearchRepo.On("GetPostingsByPrefix", "exam", 50).Return(map[string]map[string]int{"exam": postings}, nil)
+	mockSearchRepo.On("GetByKeys", mock.Anything).Return(searchDocsByKey(docs...), nil)
+
+	resp, err := ss.Search("user1", "exam", nil, 1, 10)
+
+	assert.NoError(t, err)
+	ids := make([]string, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		ids = append(ids, result.ID)
+	}
+	assert.ElementsMatch(t, []string{"m1", "m3"}, ids)
+}
+
+func TestSearchService_Search_FiltersByTypeAndPaginates(t *testing.T) {
+	ss, mockSearchRepo, mockUserRepo := newSearchService()
+
+	mockUserRepo.On("GetByID", "user1").Return(&entity.User{ID: "user1"}, nil)
+
+	users := []*entity.SearchDocument{
+		mappers.MapUserToSearchDocument(&entity.User{ID: "u1", Username: "ana"}),
+		mappers.MapUserToSearchDocument(&entity.User{ID: "u2", Username: "anabela"}),
+		mappers.MapUserToSearchDocument(&entity.User{ID: "u3", Username: "anastasia"}),
+	}
+	mockSearchRepo.On("GetPostingsByPrefix", "ana", 50).Return(map[string]map[string]int{
+		"ana":       {users[0].Key: 3, "team_t1": 3},
+		"anabela":   {users[1].Key: 3},
+		"anastasia": {users[2].Key: 3},
+	}, nil)
+	mockSearchRepo.On("GetByKeys", []string{users[0].Key, users[1].Key, users[2].Key}).Return(searchDocsByKey(users...), nil)
+
+	resp, err := ss.Search("user1", "ana", []string{entity.SearchTypeUser}, 2, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, resp.TotalCount)
+	assert.Equal(t, 2, resp.TotalPages)
+	assert.Len(t, resp.Results, 1)
+	assert.Equal(t, "u3", resp.Results[0].ID)
+	mockSearchRepo.AssertExpectations(t)
+}
+
+func TestSearchService_Search_ShortWordsOnlyMatchThemselves(t *testing.T) {
+	ss, mockSearchRepo, mockUserRepo := newSearchService()
+
+	mockUserRepo.On("GetByID", "user1").Return(&entity.User{ID: "user1"}, nil)
+
+	ai := mappers.MapTeamToSearchDocument(&entity.Team{Id: "team1", Name: "AI", IsPublic: true})
+	mockSearchRepo.On("GetPostings", "ai").Return(map[string]int{ai.Key: 3}, nil)
+	mockSearchRepo.On("GetByKeys", []string{ai.Key}).Return(searchDocsByKey(ai), nil)
+
+	resp, err := ss.Search("user1", "ai", nil, 1, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.TotalCount)
+	mockSearchRepo.AssertNotCalled(t, "GetPostingsByPrefix", mock.Anything, mock.Anything)
+}
+
+func TestSearchService_Search_KeepsTheBestPostingsOfAWord(t *testing.T) {
+	ss, mockSearchRepo, mockUserRepo := newSearchService()
+
+	mockUserRepo.On("GetByID", "user1").Return(&entity.User{ID: "user1"}, nil)
+
+	postings := map[string]int{"user_best": 9}
+	for i := 0; i < 600; i++ {
+		postings[fmt.Sprintf("user_u%03d", i)] = 1
+	}
+	mockSearchRepo.On("GetPostingsByPrefix", "exam", 50).Return(map[string]map[string]int{"exam": postings}, nil)
+	mockSearchRepo.On("GetByKeys", mock.MatchedBy(func(keys []string) bool {
+		return len(keys) == 500 && slices.Contains(keys, "user_best")
+	})).Return(map[string]*entity.SearchDocument{}, nil)
+
+	_, err := ss.Search("user1", "exam", nil, 1, 10)
+
+	assert.NoError(t, err)
+	mockSearchRepo.AssertExpectations(t)
+}
+
+func searchDocsByKey(docs ...*entity.SearchDocument) map[string]*entity.SearchDocument {
+	byKey := make(map[string]*entity.SearchDocument, len(docs))
+	for _, doc := range docs {
+		byKey[doc.Key] = doc
+	}
+	return byKey
+}
+
+func TestSearchService_Search_InvalidQuery(t *testing.T) {
+	ss, _, _ := newSearchService()
+
+	_, err := ss.Search("user1", " ?! ", nil, 1, 10)
+	assert.True(t, errors.Is(err, validator.ErrValidation))
+
+	_, err = ss.Search("user1", "exam", []string{"files"}, 1, 10)
+	assert.True(t, errors.Is(err, validator.ErrValidation))
+}
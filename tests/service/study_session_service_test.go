@@ -0,0 +1,142 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStudySession_OccurrencesBetween_KeepsLocalTimeAcrossDST(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Bucharest")
+	start := time.Date(2025, time.October, 20, 18, 0, 0, 0, loc)
+	session := &entity.StudySession{
+		StartAt:    start.UTC(),
+		EndAt:      start.Add(2 * time.Hour).UTC(),
+		Timezone:   "Europe/Bucharest",
+		Recurrence: &entity.RecurrenceRule{Frequency: entity.RecurrenceWeekly, Count: 3},
+	}
+
+	occurrences := session.OccurrencesBetween(start.AddDate(0, 0, -1), start.AddDate(1, 0, 0))
+
+	assert.Len(t, occurrences, 3)
+	for _, occurrence := range occurrences {
+		assert.Equal(t, 18, occurrence.In(loc).Hour())
+	}
+	// DST ends on the 26th, the UTC time moves by an hour
+	assert.Equal(t, 15, occurrences[0].UTC().Hour())
+	assert.Equal(t, 16, occurrences[1].UTC().Hour())
+}
+
+func TestStudySessionService_CreateSession_ReturnsConflicts(t *testing.T) {
+	mockSessionRepo := new(tests.MockStudySessionRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockUserRepo := new(tests.MockUserRepository)
+	ss := service.NewStudySessionServiceWithRepo(mockSessionRepo, mockTeamRepo, mockUserRepo)
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	teamIDs := []string{tests.TestTeamID, tests.TestTeamID2}
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID}}, nil)
+	mockUserRepo.On("GetByID", tests.TestUserID).Return(&entity.User{ID: tests.TestUserID, TeamsIds: &teamIDs}, nil)
+	mockSessionRepo.On("Create", mock.AnythingOfType("*entity.StudySession")).Return(nil)
+	mockSessionRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.StudySession{}, nil)
+	mockSessionRepo.On("GetByTeamID", tests.TestTeamID2).Return([]*entity.StudySession{
+		{ID: "overlapping", TeamID: tests.TestTeamID2, Title: "Algebra", StartAt: start.Add(time.Hour), EndAt: start.Add(3 * time.Hour), RSVPs: map[string]entity.RSVPStatus{tests.TestUserID: entity.RSVPGoing}},
+		{ID: "declined", TeamID: tests.TestTeamID2, StartAt: start, EndAt: start.Add(time.Hour), RSVPs: map[string]entity.RSVPStatus{tests.TestUserID: entity.RSVPDeclined}},
+		{ID: "later", TeamID: tests.TestTeamID2, StartAt: start.Add(5 * time.Hour), EndAt: start.Add(6 * time.Hour), RSVPs: map[string]entity.RSVPStatus{tests.TestUserID: entity.RSVPGoing}},
+	}, nil)
+
+	resp, err := ss.CreateSession(tests.TestUserID, tests.TestTeamID, &dto.StudySessionRequest{
+		Title:         "Exam prep",
+		StartAt:       start,
+		EndAt:         start.Add(2 * time.Hour),
+		LinkVoiceRoom: true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RSVPGoing, resp.Session.RSVPs[tests.TestUserID])
+	assert.Equal(t, tests.TestTeamID, resp.Session.VoiceRoomID)
+	assert.Equal(t, 15, resp.Session.ReminderMinutes)
+	assert.Len(t, resp.Conflicts, 1)
+	assert.Equal(t, "overlapping", resp.Conflicts[0].SessionID)
+}
+
+func TestStudySessionService_CreateSession_InvalidRecurrence(t *testing.T) {
+	ss := service.NewStudySessionServiceWithRepo(new(tests.MockStudySessionRepository), new(tests.MockTeamRepository), new(tests.MockUserRepository))
+
+	start := time.Date(2025, time.January, 31, 10, 0, 0, 0, time.UTC)
+	_, err := ss.CreateSession(tests.TestUserID, tests.TestTeamID, &dto.StudySessionRequest{
+		Title:      "Monthly review",
+		StartAt:    start,
+		EndAt:      start.Add(time.Hour),
+		Recurrence: &entity.RecurrenceRule{Frequency: entity.RecurrenceMonthly},
+	})
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+}
+
+func TestStudySessionService_UpdateSession_OnlyCreatorOrOwner(t *testing.T) {
+	mockSessionRepo := new(tests.MockStudySessionRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ss := service.NewStudySessionServiceWithRepo(mockSessionRepo, mockTeamRepo, new(tests.MockUserRepository))
+
+	start := time.Now().Add(time.Hour)
+	mockSessionRepo.On("GetByID", "s1").Return(&entity.StudySession{ID: "s1", TeamID: tests.TestTeamID, CreatedBy: tests.TestUserID1}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, OwnerId: tests.TestUserID1, UsersIds: []string{tests.TestUserID1, tests.TestUserID2}}, nil)
+
+	_, err := ss.UpdateSession(tests.TestUserID2, "s1", &dto.StudySessionRequest{Title: "Moved", StartAt: start, EndAt: start.Add(time.Hour)})
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockSessionRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestStudySessionService_RSVP_StoresStatus(t *testing.T) {
+	mockSessionRepo := new(tests.MockStudySessionRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ss := service.NewStudySessionServiceWithRepo(mockSessionRepo, mockTeamRepo, new(tests.MockUserRepository))
+
+	mockSessionRepo.On("GetByID", "s1").Return(&entity.StudySession{ID: "s1", TeamID: tests.TestTeamID, CreatedBy: tests.TestUserID1}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID1, tests.TestUserID2}}, nil)
+	mockSessionRepo.On("Update", mock.MatchedBy(func(s *entity.StudySession) bool {
+		return s.RSVPs[tests.TestUserID2] == entity.RSVPDeclined
+	})).Return(nil)
+
+	resp, err := ss.RSVP(tests.TestUserID2, "s1", &dto.RSVPRequest{Status: entity.RSVPDeclined})
+
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Conflicts)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestStudySessionService_SendDueReminders_OncePerOccurrence(t *testing.T) {
+	mockSessionRepo := new(tests.MockStudySessionRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ss := service.NewStudySessionServiceWithRepo(mockSessionRepo, mockTeamRepo, new(tests.MockUserRepository))
+
+	now := time.Date(2025, time.March, 3, 17, 50, 0, 0, time.UTC)
+	start := time.Date(2025, time.March, 1, 18, 0, 0, 0, time.UTC)
+	session := &entity.StudySession{
+		ID:              "s1",
+		TeamID:          tests.TestTeamID,
+		StartAt:         start,
+		EndAt:           start.Add(time.Hour),
+		ReminderMinutes: 15,
+		Recurrence:      &entity.RecurrenceRule{Frequency: entity.RecurrenceDaily},
+	}
+	mockSessionRepo.On("GetAll").Return([]*entity.StudySession{session}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID}}, nil)
+	mockSessionRepo.On("Update", session).Return(nil).Once()
+
+	assert.NoError(t, ss.SendDueReminders(now))
+	assert.Equal(t, time.Date(2025, time.March, 3, 18, 0, 0, 0, time.UTC).Unix(), session.LastReminderAt)
+
+	// The same occurrence is not reminded twice
+	assert.NoError(t, ss.SendDueReminders(now.Add(time.Minute)))
+	mockSessionRepo.AssertNumberOfCalls(t, "Update", 1)
+}
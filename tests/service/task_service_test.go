@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTaskTeam() *entity.Team {
+	return &entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID1, tests.TestUserID2}}
+}
+
+func TestTaskService_GetBoard_DefaultColumnsWithOrderedTasks(t *testing.T) {
+	mockTaskRepo := new(tests.MockTaskRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTaskServiceWithRepo(mockTaskRepo, mockTeamRepo)
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(newTaskTeam(), nil)
+	mockTaskRepo.On("GetBoard", tests.TestTeamID).Return(nil, nil)
+	mockTaskRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.Task{
+		{ID: "t2", ColumnID: "todo", Position: 1},
+		{ID: "t3", ColumnID: "done", Position: 0},
+		{ID: "t1", ColumnID: "todo", Position: 0},
+	}, nil)
+
+	board, err := ts.GetBoard(tests.TestUserID1, tests.TestTeamID)
+
+	assert.NoError(t, err)
+	assert.Len(t, board.Columns, 3)
+	assert.Equal(t, "To do", board.Columns[0].Name)
+	assert.Equal(t, "t1", board.Columns[0].Tasks[0].ID)
+	assert.Equal(t, "t2", board.Columns[0].Tasks[1].ID)
+	assert.Empty(t, board.Columns[1].Tasks)
+	assert.Equal(t, "t3", board.Columns[2].Tasks[0].ID)
+}
+
+func TestTaskService_CreateTask_AppendsToColumn(t *testing.T) {
+	mockTaskRepo := new(tests.MockTaskRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTaskServiceWithRepo(mockTaskRepo, mockTeamRepo)
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(newTaskTeam(), nil)
+	mockTaskRepo.On("GetBoard", tests.TestTeamID).Return(nil, nil)
+	mockTaskRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.Task{{ID: "t1", ColumnID: "todo", Position: 0}}, nil)
+	mockTaskRepo.On("Create", mock.AnythingOfType("*entity.Task")).Return(nil)
+
+	task, err := ts.CreateTask(tests.TestUserID1, tests.TestTeamID, &dto.TaskRequest{
+		Title:       " Summarise chapter 3 ",
+		AssigneeIDs: []string{tests.TestUserID2, tests.TestUserID2},
+		Labels:      []string{"exam", "exam "},
+		Checklist:   []entity.ChecklistItem{{Text: "Definitions"}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "todo", task.ColumnID)
+	assert.Equal(t, 1, task.Position)
+	assert.Equal(t, "Summarise chapter 3", task.Title)
+	assert.Equal(t, []string{tests.TestUserID2}, task.AssigneeIDs)
+	assert.Equal(t, []string{"exam"}, task.Labels)
+	assert.NotEmpty(t, task.Checklist[0].ID)
+}
+
+func TestTaskService_CreateTask_AssigneeNotInTeam(t *testing.T) {
+	mockTaskRepo := new(tests.MockTaskRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTaskServiceWithRepo(mockTaskRepo, mockTeamRepo)
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(newTaskTeam(), nil)
+	mockTaskRepo.On("GetBoard", tests.TestTeamID).Return(nil, nil)
+	mockTaskRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.Task{}, nil)
+
+	_, err := ts.CreateTask(tests.TestUserID1, tests.TestTeamID, &dto.TaskRequest{Title: "Slides", AssigneeIDs: []string{"stranger"}})
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+	mockTaskRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTaskService_MoveTask_RenumbersColumns(t *testing.T) {
+	mockTaskRepo := new(tests.MockTaskRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTaskServiceWithRepo(mockTaskRepo, mockTeamRepo)
+
+	a := &entity.Task{ID: "a", TeamID: tests.TestTeamID, ColumnID: "todo", Position: 0}
+	b := &entity.Task{ID: "b", TeamID: tests.TestTeamID, ColumnID: "todo", Position: 1}
+	c := &entity.Task{ID: "c", TeamID: tests.TestTeamID, ColumnID: "done", Position: 0}
+	d := &entity.Task{ID: "d", TeamID: tests.TestTeamID, ColumnID: "done", Position: 1}
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(newTaskTeam(), nil)
+	mockTaskRepo.On("GetBoard", tests.TestTeamID).Return(nil, nil)
+	mockTaskRepo.On("GetByID", "a").Return(&entity.Task{ID: "a", TeamID: tests.TestTeamID, ColumnID: "todo", Position: 0}, nil)
+	mockTaskRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.Task{a, b, c, d}, nil)
+	mockTaskRepo.On("Update", mock.AnythingOfType("*entity.Task")).Return(nil)
+
+	task, err := ts.MoveTask(tests.TestUserID1, tests.TestTeamID, "a", &dto.MoveTaskRequest{ColumnID: "done", Position: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "done", task.ColumnID)
+	assert.Equal(t, 1, task.Position)
+	assert.Equal(t, 0, b.Position)
+	assert.Equal(t, 0, c.Position)
+	assert.Equal(t, 2, d.Position)
+}
+
+func TestTaskService_DeleteColumn_NotEmpty(t *testing.T) {
+	mockTaskRepo := new(tests.MockTaskRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTaskServiceWithRepo(mockTaskRepo, mockTeamRepo)
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(newTaskTeam(), nil)
+	mockTaskRepo.On("GetBoard", tests.TestTeamID).Return(nil, nil)
+	mockTaskRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.Task{{ID: "t1", ColumnID: "done"}}, nil)
+
+	_, err := ts.DeleteColumn(tests.TestUserID1, tests.TestTeamID, "done")
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+	mockTaskRepo.AssertNotCalled(t, "SaveBoard", mock.Anything)
+}
+
+func TestTaskService_AddComment_ArchivedTeam(t *testing.T) {
+	mockTaskRepo := new(tests.MockTaskRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTaskServiceWithRepo(mockTaskRepo, mockTeamRepo)
+
+	team := newTaskTeam()
+	team.Archived = true
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(team, nil)
+
+	_, err := ts.AddComment(tests.TestUserID1, tests.TestTeamID, "t1", &dto.TaskCommentRequest{Text: "Done!"})
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+}
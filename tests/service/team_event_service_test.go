@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTeamEventService_Record_StoresEvent(t *testing.T) {
+	mockEventRepo := new(tests.MockTeamEventRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	tes := service.NewTeamEventServiceWithRepo(mockEventRepo, mockTeamRepo)
+
+	mockEventRepo.On("Create", mock.MatchedBy(func(e *entity.TeamEvent) bool {
+		return e.ID != "" && e.CreatedAt > 0 && e.Type == entity.TeamEventFileUploaded
+	})).Return(nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID}}, nil)
+
+	tes.Record(entity.NewTeamEvent(tests.TestTeamID, entity.TeamEventFileUploaded, tests.TestUserID, "f1", "notes.pdf"))
+
+	mockEventRepo.AssertExpectations(t)
+}
+
+func TestTeamEventService_GetTeamActivity_NewestFirstAndPaginated(t *testing.T) {
+	mockEventRepo := new(tests.MockTeamEventRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	tes := service.NewTeamEventServiceWithRepo(mockEventRepo, mockTeamRepo)
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID}}, nil)
+	mockEventRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.TeamEvent{
+		{ID: "e1", CreatedAt: 100},
+		{ID: "e3", CreatedAt: 300},
+		{ID: "e2", CreatedAt: 200},
+	}, nil)
+
+	resp, err := tes.GetTeamActivity(tests.TestUserID, tests.TestTeamID, 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, resp.TotalCount)
+	assert.Equal(t, 2, resp.TotalPages)
+	assert.Equal(t, "e3", resp.Events[0].ID)
+	assert.Equal(t, "e2", resp.Events[1].ID)
+}
+
+func TestTeamEventService_GetTeamActivity_NotMember(t *testing.T) {
+	mockTeamRepo := new(tests.MockTeamRepository)
+	tes := service.NewTeamEventServiceWithRepo(new(tests.MockTeamEventRepository), mockTeamRepo)
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID}}, nil)
+
+	_, err := tes.GetTeamActivity("stranger", tests.TestTeamID, 1, 10)
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+}
+
+func TestFileService_CreateFile_RecordsActivity(t *testing.T) {
+	mockFileRepo := new(tests.MockFileRepository)
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	recorder := new(tests.MockEventRecorder)
+	fs := service.NewFileServiceWithRepo(mockFileRepo, mockUserRepo, mockTeamRepo)
+	fs.SetEventRecorder(recorder)
+
+	teamIDs := []string{tests.TestTeamID}
+	mockUserRepo.On("GetByID", tests.TestUserID).Return(&entity.User{ID: tests.TestUserID, TeamsIds: &teamIDs}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID}, nil)
+	mockFileRepo.On("Create", mock.AnythingOfType("*entity.File")).Return(nil)
+
+	_, err := fs.CreateFile(&dto.FileUploadRequest{
+		Name:        "notes.pdf",
+		Type:        "application/pdf",
+		Extension:   "pdf",
+		Content:     "dGVzdA==",
+		OwnerID:     tests.TestUserID,
+		Size:        4,
+		ContextType: entity.FileContextTeam,
+		ContextID:   tests.TestTeamID,
+	}, tests.TestUserID)
+
+	assert.NoError(t, err)
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, entity.TeamEventFileUploaded, recorder.Events[0].Type)
+	assert.Equal(t, "notes.pdf", recorder.Events[0].SubjectName)
+}
+
+func TestTeamService_DeleteUserFromTeam_RecordsActivity(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	recorder := new(tests.MockEventRecorder)
+	ts := service.NewTeamServiceWithRepo(mockUserRepo, mockTeamRepo)
+	ts.SetEventRecorder(recorder)
+
+	teamIDs := []string{tests.TestTeamID}
+	mockUserRepo.On("GetByID", tests.TestUserID2).Return(&entity.User{ID: tests.TestUserID2, TeamsIds: &teamIDs}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID1, tests.TestUserID2}}, nil)
+	mockUserRepo.On("Update", mock.AnythingOfType("*entity.User")).Return(nil)
+	mockTeamRepo.On("Update", mock.AnythingOfType("*entity.Team")).Return(nil)
+
+	_, _, err := ts.DeleteUserFromTeam(tests.TestUserID2, tests.TestUserID2, tests.TestTeamID)
+
+	assert.NoError(t, err)
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, entity.TeamEventMemberLeft, recorder.Events[0].Type)
+	assert.Equal(t, tests.TestUserID2, recorder.Events[0].ActorID)
+}
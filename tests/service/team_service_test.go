@@ -0,0 +1,190 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTeamService_SetArchived_OwnerOnly(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTeamServiceWithRepo(mockUserRepo, mockTeamRepo)
+
+	team := &entity.Team{Id: tests.TestTeamID, OwnerId: tests.TestUserID1, UsersIds: []string{tests.TestUserID1, tests.TestUserID2}}
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(team, nil)
+	mockTeamRepo.On("Update", mock.AnythingOfType("*entity.Team")).Return(nil)
+
+	_, err := ts.SetArchived(tests.TestUserID2, tests.TestTeamID, true)
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockTeamRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+	archived, err := ts.SetArchived(tests.TestUserID1, tests.TestTeamID, true)
+	assert.NoError(t, err)
+	assert.True(t, archived.Archived)
+	assert.NotZero(t, archived.ArchivedAt)
+
+	restored, err := ts.SetArchived(tests.TestUserID1, tests.TestTeamID, false)
+	assert.NoError(t, err)
+	assert.False(t, restored.Archived)
+	assert.Zero(t, restored.ArchivedAt)
+}
+
+func TestTeamService_SetArchived_LegacyTeamOwnedByFirstMember(t *testing.T) {
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTeamServiceWithRepo(new(tests.MockUserRepository), mockTeamRepo)
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID1, tests.TestUserID2}}, nil)
+	mockTeamRepo.On("Update", mock.AnythingOfType("*entity.Team")).Return(nil)
+
+	team, err := ts.SetArchived(tests.TestUserID1, tests.TestTeamID, true)
+
+	assert.NoError(t, err)
+	assert.True(t, team.Archived)
+}
+
+func TestTeamService_Update_ArchivedTeamIsReadOnly(t *testing.T) {
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTeamServiceWithRepo(new(tests.MockUserRepository), mockTeamRepo)
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, OwnerId: tests.TestUserID1, UsersIds: []string{tests.TestUserID1}, Archived: true}, nil)
+
+	err := ts.Update(tests.TestUserID1, &entity.Team{Id: tests.TestTeamID, Name: "renamed"})
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+	mockTeamRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestTeamService_Update_KeepsMembersOwnerAndArchivedState(t *testing.T) {
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTeamServiceWithRepo(new(tests.MockUserRepository), mockTeamRepo)
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, OwnerId: tests.TestUserID1, UsersIds: []string{tests.TestUserID1}}, nil)
+	mockTeamRepo.On("Update", mock.AnythingOfType("*entity.Team")).Return(nil)
+
+	err := ts.Update(tests.TestUserID1, &entity.Team{Id: tests.TestTeamID, Name: "renamed", OwnerId: tests.TestUserID2, UsersIds: []string{tests.TestUserID1, "banned"}, Archived: true})
+
+	assert.NoError(t, err)
+	mockTeamRepo.AssertCalled(t, "Update", mock.MatchedBy(func(team *entity.Team) bool {
+		return team.Name == "renamed" && team.OwnerId == tests.TestUserID1 && !team.Archived && len(team.UsersIds) == 1
+	}))
+}
+
+func TestTeamService_Update_AdminsOnly(t *testing.T) {
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTeamServiceWithRepo(new(tests.MockUserRepository), mockTeamRepo)
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, OwnerId: tests.TestUserID1, UsersIds: []string{tests.TestUserID1, tests.TestUserID2}}, nil)
+
+	err := ts.Update(tests.TestUserID2, &entity.Team{Id: tests.TestTeamID, Name: "renamed"})
+	assert.ErrorIs(t, err, service.ErrForbidden)
+
+	err = ts.Update("stranger", &entity.Team{Id: tests.TestTeamID, Name: "renamed"})
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockTeamRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestTeamService_Update_KeepsTopicFromBeforeTheTaxonomy(t *testing.T) {
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockTopicRepo := new(tests.MockTopicRepository)
+	ts := service.NewTeamServiceWithRepo(new(tests.MockUserRepository), mockTeamRepo)
+	ts.SetTopicValidator(service.NewTopicServiceWithRepo(mockTopicRepo, new(tests.MockUserRepository), mockTeamRepo))
+
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, OwnerId: tests.TestUserID1, UsersIds: []string{tests.TestUserID1}, TeamTopic: "Astrology"}, nil)
+	mockTeamRepo.On("Update", mock.AnythingOfType("*entity.Team")).Return(nil)
+	mockTopicRepo.On("GetBySlug", model.TopicOfInterest("Alchemy")).Return(nil, errors.New("topic not found"))
+
+	err := ts.Update(tests.TestUserID1, &entity.Team{Id: tests.TestTeamID, Name: "renamed", TeamTopic: "Astrology"})
+	assert.NoError(t, err)
+	mockTopicRepo.AssertNotCalled(t, "GetBySlug", mock.Anything)
+
+	err = ts.Update(tests.TestUserID1, &entity.Team{Id: tests.TestTeamID, Name: "renamed", TeamTopic: "Alchemy"})
+	assert.ErrorIs(t, err, validator.ErrValidation)
+}
+
+func TestTeamService_GetAll_HidesArchivedTeams(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTeamServiceWithRepo(mockUserRepo, mockTeamRepo)
+
+	mockUserRepo.On("GetByID", tests.TestUserID).Return(&entity.User{ID: tests.TestUserID}, nil)
+	mockTeamRepo.On("GetByOrganizationID", "").Return([]*entity.Team{{Id: "active"}, {Id: "frozen", Archived: true}}, nil)
+
+	teams, err := ts.GetAll(tests.TestUserID)
+
+	assert.NoError(t, err)
+	assert.Len(t, teams, 1)
+	assert.Equal(t, "active", teams[0].Id)
+}
+
+func TestTeamService_Delete_OwnerOfTheOrganizationOnly(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTeamServiceWithRepo(mockUserRepo, mockTeamRepo)
+
+	team := &entity.Team{Id: tests.TestTeamID, OwnerId: tests.TestUserID1, OrganizationID: "org1", UsersIds: []string{tests.TestUserID1, tests.TestUserID2}}
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(team, nil)
+	mockUserRepo.On("GetByID", tests.TestUserID2).Return(&entity.User{ID: tests.TestUserID2, OrganizationID: "org1"}, nil)
+	mockUserRepo.On("GetByID", "outsider").Return(&entity.User{ID: "outsider", OrganizationID: "org2"}, nil)
+
+	err := ts.Delete(tests.TestUserID2, tests.TestTeamID)
+	assert.ErrorIs(t, err, service.ErrForbidden)
+
+	err = ts.Delete("outsider", tests.TestTeamID)
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+
+	mockTeamRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestTeamService_AddUserToTeam_ArchivedTeam(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	ts := service.NewTeamServiceWithRepo(mockUserRepo, mockTeamRepo)
+
+	mockUserRepo.On("GetByID", tests.TestUserID2).Return(&entity.User{ID: tests.TestUserID2}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, Archived: true}, nil)
+
+	_, _, err := ts.AddUserToTeam(tests.TestUserID2, tests.TestUserID2, tests.TestTeamID)
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestMessageService_CreateTeamMessage_NotMember(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockMessageRepo := new(tests.MockMessageRepository)
+	ms := service.NewMessageServiceWithRepo(mockUserRepo, mockTeamRepo, mockMessageRepo, new(tests.MockChannelRepository))
+
+	mockUserRepo.On("GetByID", "kicked").Return(&entity.User{ID: "kicked"}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID}}, nil)
+
+	_, err := ms.CreateTeamMessage(dto.NewTeamMessageRequest("kicked", tests.TestTeamID, "hello"))
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	mockMessageRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestMessageService_CreateTeamMessage_ArchivedTeam(t *testing.T) {
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	mockMessageRepo := new(tests.MockMessageRepository)
+	ms := service.NewMessageServiceWithRepo(mockUserRepo, mockTeamRepo, mockMessageRepo, new(tests.MockChannelRepository))
+
+	mockUserRepo.On("GetByID", tests.TestUserID).Return(&entity.User{ID: tests.TestUserID}, nil)
+	mockTeamRepo.On("GetTeamById", tests.TestTeamID).Return(&entity.Team{Id: tests.TestTeamID, UsersIds: []string{tests.TestUserID}, Archived: true}, nil)
+
+	_, err := ms.CreateTeamMessage(dto.NewTeamMessageRequest(tests.TestUserID, tests.TestTeamID, "hello"))
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+	mockMessageRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
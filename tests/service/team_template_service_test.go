@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type teamTemplateMocks struct {
+	templateRepo *tests.MockTeamTemplateRepository
+	teamRepo     *tests.MockTeamRepository
+	userRepo     *tests.MockUserRepository
+	channelRepo  *tests.MockChannelRepository
+	quizRepo     *tests.MockQuizRepository
+	taskRepo     *tests.MockTaskRepository
+}
+
+func newTeamTemplateService() (*service.TeamTemplateService, *teamTemplateMocks) {
+	m := &teamTemplateMocks{
+		templateRepo: new(tests.MockTeamTemplateRepository),
+		teamRepo:     new(tests.MockTeamRepository),
+		userRepo:     new(tests.MockUserRepository),
+		channelRepo:  new(tests.MockChannelRepository),
+		quizRepo:     new(tests.MockQuizRepository),
+		taskRepo:     new(tests.MockTaskRepository),
+	}
+	return service.NewTeamTemplateServiceWithRepo(m.templateRepo, m.teamRepo, m.userRepo, m.channelRepo, m.quizRepo, m.taskRepo), m
+}
+
+// newCourseTeam returns an archived team owned by TestUserID1 with TestUserID2 as a plain member
+func newCourseTeam() *entity.Team {
+	return &entity.Team{
+		Id:          tests.TestTeamID,
+		Name:        "Algebra 2024",
+		Description: "Linear algebra",
+		OwnerId:     tests.TestUserID1,
+		UsersIds:    []string{tests.TestUserID1, tests.TestUserID2},
+		TeamTopic:   model.TopicOfInterest("Math"),
+		Archived:    true,
+	}
+}
+
+func TestTeamTemplateService_CreateTemplate_CopiesSelectedContent(t *testing.T) {
+	ts, m := newTeamTemplateService()
+	dueAt := time.Now()
+
+	m.teamRepo.On("GetTeamById", tests.TestTeamID).Return(newCourseTeam(), nil)
+	m.channelRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.Channel{
+		entity.NewChannel(entity.GetDefaultChannelID(tests.TestTeamID), tests.TestTeamID, entity.DefaultChannelName, "", 1),
+		entity.NewChannel("c1", tests.TestTeamID, "exam-prep", tests.TestUserID1, 1),
+		{ID: "c2", TeamID: tests.TestTeamID, Name: "old", Archived: true},
+	}, nil)
+	m.taskRepo.On("GetBoard", tests.TestTeamID).Return(nil, nil)
+	m.taskRepo.On("GetByTeamID", tests.TestTeamID).Return([]*entity.Task{{
+		ID:          "t1",
+		ColumnID:    "todo",
+		Title:       "Read chapter 1",
+		AssigneeIDs: []string{tests.TestUserID2},
+		DueAt:       &dueAt,
+		Checklist:   []entity.ChecklistItem{{ID: "i1", Text: "Notes", Done: true}},
+	}}, nil)
+	m.templateRepo.On("Create", mock.AnythingOfType("*entity.TeamTemplate")).Return(nil)
+
+	template, err := ts.CreateTemplate(tests.TestUserID1, tests.TestTeamID, &dto.TeamTemplateRequest{
+		Name:    "Algebra",
+		Include: []entity.TemplateContent{entity.TemplateContentTaskBoard, entity.TemplateContentChannels},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, tests.TestUserID1, template.OwnerID)
+	assert.Equal(t, "Linear algebra", template.Description)
+	assert.Equal(t, []entity.TemplateContent{entity.TemplateContentChannels, entity.TemplateContentTaskBoard}, template.Contents)
+	assert.Equal(t, []string{"exam-prep"}, template.Channels)
+	assert.Len(t, template.BoardColumns, 3)
+	assert.Equal(t, []entity.TemplateTask{{ColumnID: "todo", Title: "Read chapter 1", Checklist: []string{"Notes"}}}, template.Tasks)
+	m.quizRepo.AssertNotCalled(t, "GetAll")
+}
+
+func TestTeamTemplateService_CreateTemplate_OnlyAdmins(t *testing.T) {
+	ts, m := newTeamTemplateService()
+	m.teamRepo.On("GetTeamById", tests.TestTeamID).Return(newCourseTeam(), nil)
+
+	_, err := ts.CreateTemplate(tests.TestUserID2, tests.TestTeamID, &dto.TeamTemplateRequest{Name: "Algebra"})
+
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	m.templateRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTeamTemplateService_GetTemplate_OfAnotherUser(t *testing.T) {
+	ts, m := newTeamTemplateService()
+	m.templateRepo.On("GetByID", "tpl1").Return(&entity.TeamTemplate{ID: "tpl1", OwnerID: tests.TestUserID1}, nil)
+
+	_, err := ts.GetTemplate(tests.TestUserID2, "tpl1")
+
+	assert.ErrorIs(t, err, service.ErrResourceNotFound)
+}
+
+func TestTeamTemplateService_CloneTeam_CopiesQuizzesWithoutMembers(t *testing.T) {
+	ts, m := newTeamTemplateService()
+	newTeam := &entity.Team{Id: "team-new", Name: "Algebra 2025", OwnerId: tests.TestUserID1, UsersIds: []string{}}
+
+	m.teamRepo.On("GetTeamById", tests.TestTeamID).Return(newCourseTeam(), nil)
+	m.teamRepo.On("GetTeamById", mock.Anything).Return(newTeam, nil)
+	m.teamRepo.On("Create", mock.AnythingOfType("*entity.Team")).Return(nil)
+	m.teamRepo.On("Update", mock.AnythingOfType("*entity.Team")).Return(nil)
+	m.userRepo.On("GetByID", tests.TestUserID1).Return(&entity.User{ID: tests.TestUserID1}, nil)
+	m.userRepo.On("Update", mock.AnythingOfType("*entity.User")).Return(nil)
+	m.quizRepo.On("GetAll").Return([]entity.Quiz{
+		{ID: "q1", QuizName: "Matrices", TeamID: tests.TestTeamID, UserID: tests.TestUserID2, Questions: []entity.Question{{ID: "old", Question: "2x2?"}}},
+		{ID: "q2", QuizName: "Other team", TeamID: tests.TestTeamID2},
+	}, nil)
+	m.quizRepo.On("Create", mock.AnythingOfType("entity.Quiz")).Return(nil)
+
+	resp, err := ts.CloneTeam(tests.TestUserID1, tests.TestTeamID, &dto.NewTeamFromTemplateRequest{
+		Name:    "Algebra 2025",
+		Include: []entity.TemplateContent{entity.TemplateContentQuizzes},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.Quizzes)
+	assert.Zero(t, resp.Channels)
+	assert.False(t, resp.Team.Archived)
+	m.teamRepo.AssertCalled(t, "Create", mock.MatchedBy(func(team *entity.Team) bool {
+		return team.Name == "Algebra 2025" && team.Description == "Linear algebra" && team.OwnerId == tests.TestUserID1 && len(team.UsersIds) == 0
+	}))
+	m.quizRepo.AssertCalled(t, "Create", mock.MatchedBy(func(quiz entity.Quiz) bool {
+		return quiz.TeamID == "team-new" && quiz.UserID == tests.TestUserID1 && quiz.ID != "q1" && quiz.Questions[0].ID != "old"
+	}))
+	m.channelRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,112 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTopicService() (*service.TopicService, *tests.MockTopicRepository, *tests.MockUserRepository, *tests.MockTeamRepository) {
+	mockTopicRepo := new(tests.MockTopicRepository)
+	mockUserRepo := new(tests.MockUserRepository)
+	mockTeamRepo := new(tests.MockTeamRepository)
+	return service.NewTopicServiceWithRepo(mockTopicRepo, mockUserRepo, mockTeamRepo), mockTopicRepo, mockUserRepo, mockTeamRepo
+}
+
+func testTopics() []*entity.Topic {
+	return []*entity.Topic{
+		entity.NewTopic(model.Science, map[string]string{"en": "Science", "ro": "Științe"}, "", 0, 0),
+		entity.NewTopic(model.ComputerScience, map[string]string{"en": "Computer Science"}, model.Science, 0, 0),
+		entity.NewTopic(model.Programming, map[string]string{"en": "Programming"}, model.ComputerScience, 0, 0),
+	}
+}
+
+func TestTopicService_GetTopics_WithUsage(t *testing.T) {
+	ts, mockTopicRepo, mockUserRepo, mockTeamRepo := newTopicService()
+
+	userTopics := []model.TopicOfInterest{model.Science, model.Programming}
+	mockTopicRepo.On("GetAll").Return(testTopics(), nil)
+	mockUserRepo.On("GetAll").Return([]*entity.User{{ID: "user1", TopicsOfInterest: &userTopics}, {ID: "user2"}}, nil)
+	mockTeamRepo.On("GetAll").Return([]*entity.Team{{Id: "team1", TeamTopic: model.Science}}, nil)
+
+	topics, err := ts.GetTopics("ro", true)
+
+	assert.NoError(t, err)
+	assert.Len(t, topics, 3)
+	assert.Equal(t, model.ComputerScience, topics[0].Slug)
+	assert.Equal(t, "Computer Science", topics[0].DisplayName)
+	assert.Equal(t, model.Science, topics[2].Slug)
+	assert.Equal(t, "Științe", topics[2].DisplayName)
+	assert.Equal(t, []model.TopicOfInterest{model.ComputerScience}, topics[2].Children)
+	assert.Equal(t, 1, *topics[2].UserCount)
+	assert.Equal(t, 1, *topics[2].TeamCount)
+}
+
+func TestTopicService_CreateTopic_RequiresExistingParent(t *testing.T) {
+	ts, mockTopicRepo, _, _ := newTopicService()
+
+	mockTopicRepo.On("GetAll").Return(testTopics(), nil)
+
+	_, err := ts.CreateTopic(&dto.TopicRequest{Slug: "Chemistry", Names: map[string]string{"en": "Chemistry"}, ParentSlug: "Nature"})
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+	mockTopicRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTopicService_CreateTopic_Success(t *testing.T) {
+	ts, mockTopicRepo, _, _ := newTopicService()
+
+	mockTopicRepo.On("GetAll").Return(testTopics(), nil)
+	mockTopicRepo.On("Create", mock.AnythingOfType("*entity.Topic")).Return(nil)
+
+	topic, err := ts.CreateTopic(&dto.TopicRequest{Slug: "Chemistry", Names: map[string]string{"en": "Chemistry"}, ParentSlug: model.Science})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.TopicOfInterest("Chemistry"), topic.Slug)
+	assert.Equal(t, model.Science, topic.ParentSlug)
+	mockTopicRepo.AssertExpectations(t)
+}
+
+func TestTopicService_UpdateTopic_RejectsCycle(t *testing.T) {
+	ts, mockTopicRepo, _, _ := newTopicService()
+
+	mockTopicRepo.On("GetAll").Return(testTopics(), nil)
+
+	_, err := ts.UpdateTopic(model.Science, &dto.UpdateTopicRequest{Names: map[string]string{"en": "Science"}, ParentSlug: model.Programming})
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+	mockTopicRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestTopicService_DeleteTopic_InUse(t *testing.T) {
+	ts, mockTopicRepo, mockUserRepo, mockTeamRepo := newTopicService()
+
+	mockTopicRepo.On("GetAll").Return(testTopics(), nil)
+	mockUserRepo.On("GetAll").Return([]*entity.User{}, nil)
+	mockTeamRepo.On("GetAll").Return([]*entity.Team{{Id: "team1", TeamTopic: model.Programming}}, nil)
+
+	err := ts.DeleteTopic(model.Programming)
+
+	assert.ErrorIs(t, err, service.ErrConflict)
+	mockTopicRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestTopicService_ValidateTopics_UnknownTopic(t *testing.T) {
+	ts, mockTopicRepo, _, _ := newTopicService()
+
+	mockTopicRepo.On("GetBySlug", model.Science).Return(testTopics()[0], nil)
+	mockTopicRepo.On("GetBySlug", model.TopicOfInterest("Astrology")).Return(nil, errors.New("topic not found"))
+
+	err := ts.ValidateTopics([]model.TopicOfInterest{model.Science, "Astrology"})
+
+	assert.ErrorIs(t, err, validator.ErrValidation)
+	assert.Contains(t, err.Error(), "Astrology")
+}
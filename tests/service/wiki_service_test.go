@@ -0,0 +1,199 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/service"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type wikiMocks struct {
+	wikiRepo *tests.MockWikiRepository
+	teamRepo *tests.MockTeamRepository
+	fileRepo *tests.MockFileRepository
+	quizRepo *tests.MockQuizRepository
+}
+
+func newWikiService() (*service.WikiService, *wikiMocks) {
+	m := &wikiMocks{
+		wikiRepo: new(tests.MockWikiRepository),
+		teamRepo: new(tests.MockTeamRepository),
+		fileRepo: new(tests.MockFileRepository),
+		quizRepo: new(tests.MockQuizRepository),
+	}
+	m.teamRepo.On("GetTeamById", tests.TestTeamID).Return(newForumTeam(), nil)
+	return service.NewWikiServiceWithRepo(m.wikiRepo, m.teamRepo, m.fileRepo, m.quizRepo), m
+}
+
+func newWikiPage(id, path, content string, revision int) *entity.WikiPage {
+	page := entity.NewWikiPage(id, tests.TestTeamID, path, "Page "+id, content, tests.TestUserID1)
+	page.Revision = revision
+	return page
+}
+
+func TestWikiService_CreatePage_NormalizesPathAndSavesFirstRevision(t *testing.T) {
+	ws, m := newWikiService()
+
+	m.wikiRepo.On("GetPagesByTeamID", tests.TestTeamID).Return([]*entity.WikiPage{
+		newWikiPage("p1", "courses", "", 1),
+		newWikiPage("p2", "courses/algebra/week-2", "", 1),
+	}, nil)
+	m.wikiRepo.On("CreatePage", mock.AnythingOfType("*entity.WikiPage")).Return(nil)
+	m.wikiRepo.On("SaveRevision", mock.MatchedBy(func(revision *entity.WikiRevision) bool {
+		return revision.Number == 1 && revision.AuthorID == tests.TestUserID2 && revision.Summary == "First notes"
+	})).Return(nil)
+
+	resp, err := ws.CreatePage(tests.TestUserID2, tests.TestTeamID, &dto.WikiPageRequest{
+		Path:    "/Courses/Algebra/ ",
+		Title:   "Algebra",
+		Content: "# Algebra",
+		Summary: "First notes",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "courses/algebra", resp.Path)
+	assert.Equal(t, 1, resp.Revision)
+	assert.Len(t, resp.Children, 1)
+	m.wikiRepo.AssertExpectations(t)
+
+	_, err = ws.CreatePage(tests.TestUserID2, tests.TestTeamID, &dto.WikiPageRequest{Path: "courses", Title: "Courses"})
+	assert.True(t, errors.Is(err, service.ErrConflict))
+
+	_, err = ws.CreatePage(tests.TestUserID2, tests.TestTeamID, &dto.WikiPageRequest{Path: "courses/../x", Title: "X"})
+	assert.Error(t, err)
+}
+
+func TestWikiService_GetPages_TreeOrderAndPrefix(t *testing.T) {
+	ws, m := newWikiService()
+
+	m.wikiRepo.On("GetPagesByTeamID", tests.TestTeamID).Return([]*entity.WikiPage{
+		newWikiPage("p1", "courses-old", "", 1),
+		newWikiPage("p2", "courses/algebra", "", 1),
+		newWikiPage("p3", "courses", "", 1),
+		newWikiPage("p4", "about", "", 1),
+	}, nil)
+
+	pages, err := ws.GetPages(tests.TestUserID, tests.TestTeamID, "")
+	assert.NoError(t, err)
+	var paths []string
+	for _, page := range pages {
+		paths = append(paths, page.Path)
+	}
+	assert.Equal(t, []string{"about", "courses", "courses/algebra", "courses-old"}, paths)
+	assert.Equal(t, "courses", pages[2].ParentPath)
+	assert.Equal(t, 1, pages[2].Depth)
+
+	pages, err = ws.GetPages(tests.TestUserID, tests.TestTeamID, "courses")
+	assert.NoError(t, err)
+	assert.Len(t, pages, 2)
+}
+
+func TestWikiService_GetPage_RendersSanitisedHTMLAndResolvesLinks(t *testing.T) {
+	ws, m := newWikiService()
+	content := "<script>alert(1)</script>\n\n" +
+		"[Notes](file:f1) [Other notes](file:f2) [Quiz](quiz:q1) [Week 1](wiki:courses/week-1) [x](javascript:alert(1))\n\n" +
+		"```go\" onmouseover=\"alert(1)\nfmt.Println()\n```\n"
+
+	m.wikiRepo.On("GetPagesByTeamID", tests.TestTeamID).Return([]*entity.WikiPage{
+		newWikiPage("p1", "courses", content, 1),
+		newWikiPage("p2", "courses/week-1", "", 1),
+	}, nil)
+	m.fileRepo.On("GetByID", "f1").Return(&entity.File{ID: "f1", ContextType: entity.FileContextTeam, ContextID: tests.TestTeamID}, nil)
+	m.fileRepo.On("GetByID", "f2").Return(&entity.File{ID: "f2", ContextType: entity.FileContextTeam, ContextID: tests.TestTeamID2}, nil)
+	m.quizRepo.On("GetById", "q1").Return(entity.Quiz{ID: "q1", TeamID: tests.TestTeamID}, nil)
+
+	resp, err := ws.GetPage(tests.TestUserID, tests.TestTeamID, "/courses")
+
+	assert.NoError(t, err)
+	assert.NotContains(t, resp.HTML, "<script>")
+	assert.NotContains(t, resp.HTML, `href="javascript`)
+	assert.NotContains(t, resp.HTML, `" onmouseover`)
+	assert.Contains(t, resp.HTML, `href="/teams/team123/files/f1"`)
+	assert.Contains(t, resp.HTML, `<span class="broken-link">Other notes</span>`)
+	assert.Contains(t, resp.HTML, `href="/quizzes/q1"`)
+	assert.Contains(t, resp.HTML, `href="/teams/team123/wiki/pages/courses/week-1"`)
+	assert.Len(t, resp.Children, 1)
+}
+
+func TestWikiService_UpdatePage_RejectsStaleRevision(t *testing.T) {
+	ws, m := newWikiService()
+	page := newWikiPage("p1", "courses", "old", 3)
+
+	m.wikiRepo.On("GetPage", "p1").Return(page, nil)
+
+	_, err := ws.UpdatePage(tests.TestUserID2, tests.TestTeamID, "p1", &dto.UpdateWikiPageRequest{
+		Title:        "Courses",
+		Content:      "new",
+		BaseRevision: 2,
+	})
+
+	assert.True(t, errors.Is(err, service.ErrConflict))
+	m.wikiRepo.AssertNotCalled(t, "SaveRevision", mock.Anything)
+}
+
+func TestWikiService_UpdatePage_SavesNewRevision(t *testing.T) {
+	ws, m := newWikiService()
+	page := newWikiPage("p1", "courses", "old", 3)
+
+	m.wikiRepo.On("GetPage", "p1").Return(page, nil)
+	m.wikiRepo.On("GetPagesByTeamID", tests.TestTeamID).Return([]*entity.WikiPage{page}, nil)
+	m.wikiRepo.On("SaveRevision", mock.MatchedBy(func(revision *entity.WikiRevision) bool {
+		return revision.Number == 4 && revision.Content == "new" && revision.AuthorID == tests.TestUserID2
+	})).Return(nil)
+	m.wikiRepo.On("UpdatePage", page).Return(nil)
+
+	resp, err := ws.UpdatePage(tests.TestUserID2, tests.TestTeamID, "p1", &dto.UpdateWikiPageRequest{
+		Title:        "Page p1",
+		Content:      "new",
+		BaseRevision: 3,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, resp.Revision)
+	assert.Equal(t, tests.TestUserID2, resp.UpdatedBy)
+	m.wikiRepo.AssertExpectations(t)
+}
+
+func TestWikiService_GetDiff_DefaultsToLastChange(t *testing.T) {
+	ws, m := newWikiService()
+
+	m.wikiRepo.On("GetPage", "p1").Return(newWikiPage("p1", "courses", "", 3), nil)
+	m.wikiRepo.On("GetRevision", "p1", 2).Return(&entity.WikiRevision{Number: 2, PageID: "p1", Content: "a\nb\nc\n"}, nil)
+	m.wikiRepo.On("GetRevision", "p1", 3).Return(&entity.WikiRevision{Number: 3, PageID: "p1", Content: "a\nB\nc\nd\n"}, nil)
+
+	resp, err := ws.GetDiff(tests.TestUserID, tests.TestTeamID, "p1", 0, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resp.From)
+	assert.Equal(t, 3, resp.To)
+	assert.Equal(t, 2, resp.Added)
+	assert.Equal(t, 1, resp.Removed)
+	assert.Contains(t, resp.Diff, "-b\n+B\n")
+}
+
+func TestWikiService_RevertPage_SavesOldContentAsNewRevision(t *testing.T) {
+	ws, m := newWikiService()
+	page := newWikiPage("p1", "courses", "vandalised", 5)
+
+	m.wikiRepo.On("GetPage", "p1").Return(page, nil)
+	m.wikiRepo.On("GetRevision", "p1", 2).Return(&entity.WikiRevision{Number: 2, PageID: "p1", Title: "Courses", Content: "good"}, nil)
+	m.wikiRepo.On("SaveRevision", mock.MatchedBy(func(revision *entity.WikiRevision) bool {
+		return revision.Number == 6 && revision.RevertedFrom == 2 && revision.Content == "good"
+	})).Return(nil)
+	m.wikiRepo.On("UpdatePage", page).Return(nil)
+	m.wikiRepo.On("GetPagesByTeamID", tests.TestTeamID).Return([]*entity.WikiPage{page}, nil)
+
+	resp, err := ws.RevertPage(tests.TestUserID2, tests.TestTeamID, "p1", 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6, resp.Revision)
+	assert.Equal(t, "good", resp.Content)
+
+	_, err = ws.RevertPage(tests.TestUserID2, tests.TestTeamID, "p1", 6)
+	assert.True(t, errors.Is(err, service.ErrConflict))
+}
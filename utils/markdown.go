@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/russross/blackfriday/v2"
+)
+
+// markdownExtensions leaves out custom heading IDs ({#id}), which blackfriday writes into the HTML unescaped
+const markdownExtensions = blackfriday.CommonExtensions &^ blackfriday.HeadingIDs
+
+const markdownFlags = blackfriday.SkipHTML | blackfriday.Safelink | blackfriday.NofollowLinks | blackfriday.NoreferrerLinks
+
+var codeLanguageRegex = regexp.MustCompile(`^[A-Za-z0-9_+#.-]*`)
+
+// MarkdownLinkResolver returns the URL of a "<kind>:<id>" link, or false when it does not point to anything the reader may see
+type MarkdownLinkResolver func(kind, id string) (string, bool)
+
+// RenderMarkdown renders markdown to HTML that is safe to show in a page: raw HTML is dropped, links only keep http(s),
+// mailto and relative URLs and images only http(s) ones. Links written as "<kind>:<id>" for one of the kinds are
+// pointed to the URL the resolver returns; the ones it can not resolve are rendered as <span class="broken-link">.
+func RenderMarkdown(content string, kinds []string, resolve MarkdownLinkResolver) string {
+	renderer := &safeHTMLRenderer{
+		HTMLRenderer: blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{Flags: markdownFlags}),
+		kinds:        kinds,
+		resolve:      resolve,
+		broken:       make(map[*blackfriday.Node]bool),
+	}
+	output := blackfriday.Run([]byte(content), blackfriday.WithExtensions(markdownExtensions), blackfriday.WithRenderer(renderer))
+	return string(output)
+}
+
+type safeHTMLRenderer struct {
+	*blackfriday.HTMLRenderer
+	kinds   []string
+	resolve MarkdownLinkResolver
+	broken  map[*blackfriday.Node]bool
+}
+
+func (r *safeHTMLRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+	switch node.Type {
+	case blackfriday.Link:
+		if entering {
+			r.resolveLink(node)
+		}
+		if r.broken[node] {
+			if entering {
+				io.WriteString(w, `<span class="broken-link">`)
+			} else {
+				io.WriteString(w, `</span>`)
+			}
+			return blackfriday.GoToNext
+		}
+	case blackfriday.Image:
+		dest := strings.ToLower(string(node.LinkData.Destination))
+		if !strings.HasPrefix(dest, "https://") && !strings.HasPrefix(dest, "http://") {
+			return blackfriday.SkipChildren
+		}
+	case blackfriday.CodeBlock:
+		// the language of fenced code is written into a class attribute unescaped
+		node.CodeBlockData.Info = codeLanguageRegex.Find(bytes.TrimSpace(node.CodeBlockData.Info))
+	}
+	return r.HTMLRenderer.RenderNode(w, node, entering)
+}
+
+func (r *safeHTMLRenderer) resolveLink(node *blackfriday.Node) {
+	kind, id, found := strings.Cut(string(node.LinkData.Destination), ":")
+	if !found || !slices.Contains(r.kinds, kind) {
+		return
+	}
+	url, ok := "", false
+	if id != "" {
+		url, ok = r.resolve(kind, id)
+	}
+	if !ok {
+		r.broken[node] = true
+		return
+	}
+	node.LinkData.Destination = []byte(url)
+}
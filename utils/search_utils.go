@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxTokenLength caps indexed tokens so a single very long word cannot bloat the index
+const maxTokenLength = 64
+
+// FoldText lowercases the text and strips diacritics (e.g. "Știință" becomes "stiinta")
+func FoldText(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range norm.NFD.String(text) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// Tokenize folds the text and splits it into unique alphanumeric tokens, keeping the order of first appearance
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(FoldText(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]struct{}, len(fields))
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if runes := []rune(field); len(runes) > maxTokenLength {
+			field = string(runes[:maxTokenLength])
+		}
+		if _, ok := seen[field]; ok {
+			continue
+		}
+		seen[field] = struct{}{}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
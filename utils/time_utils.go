@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"time"
+	// Embedded so timezones resolve on hosts without a zoneinfo database
+	_ "time/tzdata"
+)
+
+// LoadLocation resolves an IANA timezone name, an empty name meaning UTC
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
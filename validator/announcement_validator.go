@@ -0,0 +1,35 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+)
+
+const (
+	maxAnnouncementTitleLength = 200
+	maxAnnouncementBodyLength  = 10000
+
+	announcementTitleRequiredError = "title is required"
+	announcementTitleTooLongError  = "title must be at most 200 characters"
+	announcementBodyRequiredError  = "body is required"
+	announcementBodyTooLongError   = "body must be at most 10000 characters"
+)
+
+func ValidateAnnouncementRequest(request *dto.AnnouncementRequest) error {
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, announcementTitleRequiredError)
+	}
+	if len([]rune(title)) > maxAnnouncementTitleLength {
+		return fmt.Errorf("%w: %s", ErrValidation, announcementTitleTooLongError)
+	}
+	if strings.TrimSpace(request.Body) == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, announcementBodyRequiredError)
+	}
+	if len([]rune(request.Body)) > maxAnnouncementBodyLength {
+		return fmt.Errorf("%w: %s", ErrValidation, announcementBodyTooLongError)
+	}
+	return nil
+}
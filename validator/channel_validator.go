@@ -0,0 +1,32 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+)
+
+const (
+	channelNameInvalidError  = "channel name must be 1-32 lowercase letters, digits or dashes and start with a letter or digit"
+	channelMessageEmptyError = "text content is required"
+)
+
+var channelNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// ValidateChannelName validates an already normalized channel name
+func ValidateChannelName(name string) error {
+	if !channelNameRegex.MatchString(name) {
+		return fmt.Errorf("%w: %s", ErrValidation, channelNameInvalidError)
+	}
+	return nil
+}
+
+// ValidateChannelMessageRequest validates a message posted to a channel
+func ValidateChannelMessageRequest(request *dto.ChannelMessageRequest) error {
+	if strings.TrimSpace(request.TextContent) == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, channelMessageEmptyError)
+	}
+	return nil
+}
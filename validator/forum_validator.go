@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+)
+
+const (
+	maxForumTitleLength = 200
+	maxForumBodyLength  = 20000
+	maxForumTags        = 5
+	maxForumTagLength   = 30
+
+	forumTitleRequiredError = "title is required"
+	forumTitleTooLongError  = "title must be at most 200 characters"
+	forumBodyRequiredError  = "body is required"
+	forumBodyTooLongError   = "body must be at most 20000 characters"
+	forumTooManyTagsError   = "a question can have at most 5 tags"
+	forumTagError           = "tags must have between 1 and 30 characters"
+	voteValueError          = "value must be 1, -1 or 0"
+	forumSortError          = "sort must be votes, recent or unanswered"
+)
+
+// ForumSorts are the orders a team's questions can be listed in
+var ForumSorts = []string{"recent", "votes", "unanswered"}
+
+func ValidateForumQuestionRequest(request *dto.ForumQuestionRequest) error {
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, forumTitleRequiredError)
+	}
+	if len([]rune(title)) > maxForumTitleLength {
+		return fmt.Errorf("%w: %s", ErrValidation, forumTitleTooLongError)
+	}
+	if err := validateForumBody(request.Body); err != nil {
+		return err
+	}
+	if len(request.Tags) > maxForumTags {
+		return fmt.Errorf("%w: %s", ErrValidation, forumTooManyTagsError)
+	}
+	for _, tag := range request.Tags {
+		if length := len([]rune(strings.TrimSpace(tag))); length == 0 || length > maxForumTagLength {
+			return fmt.Errorf("%w: %s", ErrValidation, forumTagError)
+		}
+	}
+	return nil
+}
+
+func ValidateForumAnswerRequest(request *dto.ForumAnswerRequest) error {
+	return validateForumBody(request.Body)
+}
+
+func ValidateVoteRequest(request *dto.VoteRequest) error {
+	if request.Value < -1 || request.Value > 1 {
+		return fmt.Errorf("%w: %s", ErrValidation, voteValueError)
+	}
+	return nil
+}
+
+func ValidateForumSort(sort string) error {
+	for _, s := range ForumSorts {
+		if s == sort {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrValidation, forumSortError)
+}
+
+func validateForumBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, forumBodyRequiredError)
+	}
+	if len([]rune(body)) > maxForumBodyLength {
+		return fmt.Errorf("%w: %s", ErrValidation, forumBodyTooLongError)
+	}
+	return nil
+}
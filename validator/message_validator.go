@@ -1,7 +1,27 @@
 package validator
 
 import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	maxReactionLength  = 16
+	maxMessagePageSize = 100
+
+	messageTextRequiredError = "text content is required"
+	reactionInvalidError     = "reaction must be an emoji of at most 16 characters, without spaces or . $ # [ ] /"
+	messagePageCursorsError  = "use only one of before, after and around"
+	messagePageLimitError    = "limit must be between 1 and 100"
+	commandTargetError       = "exactly one of receiverId and teamId is required"
+	commandMessageIDError    = "message id is required"
+	commandTypeError         = "unknown command type"
+	commandStatusError       = "status must be online or away"
 )
 
 func ValidateDirectMessageRequest(request *dto.DirectMessageRequest) error {
@@ -31,3 +51,60 @@ func ValidateTeamMessageRequest(request *dto.TeamMessageRequest) error {
 	}
 	return nil
 }
+
+func ValidateEditMessageRequest(request *dto.EditMessageRequest) error {
+	if strings.TrimSpace(request.TextContent) == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, messageTextRequiredError)
+	}
+	return nil
+}
+
+// ValidateReaction checks that the reaction can be stored as a key, so it is kept to a single short emoji or shortcode
+func ValidateReaction(emoji string) error {
+	if emoji == "" || utf8.RuneCountInString(emoji) > maxReactionLength || strings.ContainsAny(emoji, ".$#[]/") {
+		return fmt.Errorf("%w: %s", ErrValidation, reactionInvalidError)
+	}
+	for _, r := range emoji {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return fmt.Errorf("%w: %s", ErrValidation, reactionInvalidError)
+		}
+	}
+	return nil
+}
+
+func ValidateMessagePageQuery(query *dto.MessagePageQuery) error {
+	cursors := 0
+	for _, cursor := range []string{query.Before, query.After, query.Around} {
+		if cursor != "" {
+			cursors++
+		}
+	}
+	if cursors > 1 {
+		return fmt.Errorf("%w: %s", ErrValidation, messagePageCursorsError)
+	}
+	if query.Limit < 0 || query.Limit > maxMessagePageSize {
+		return fmt.Errorf("%w: %s", ErrValidation, messagePageLimitError)
+	}
+	return nil
+}
+
+// ValidateMessageCommand checks that the command has what its type needs; the rest is validated like the REST requests
+func ValidateMessageCommand(command *dto.MessageCommand) error {
+	switch command.Type {
+	case dto.MessageCommandSend, dto.MessageCommandTyping:
+		if (command.ReceiverID == "") == (command.TeamID == "") {
+			return fmt.Errorf("%w: %s", ErrValidation, commandTargetError)
+		}
+	case dto.MessageCommandEdit, dto.MessageCommandDelete, dto.MessageCommandMarkRead:
+		if strings.TrimSpace(command.MessageID) == "" {
+			return fmt.Errorf("%w: %s", ErrValidation, commandMessageIDError)
+		}
+	case dto.MessageCommandPresence:
+		if command.Status != entity.PresenceOnline && command.Status != entity.PresenceAway {
+			return fmt.Errorf("%w: %s", ErrValidation, commandStatusError)
+		}
+	default:
+		return fmt.Errorf("%w: %s %q", ErrValidation, commandTypeError, command.Type)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+)
+
+const (
+	maxModerationReasonLength = 500
+	maxModerationMinutes      = 365 * 24 * 60
+
+	moderationReasonTooLongError  = "reason must be at most 500 characters"
+	moderationDurationError       = "durationMinutes must be between 0 and 525600 (one year)"
+	moderationMuteDurationMissing = "a mute needs a durationMinutes greater than 0"
+)
+
+// ValidateModerationRequest checks the reason and the duration; mutes must always expire
+func ValidateModerationRequest(request *dto.ModerationRequest, durationRequired bool) error {
+	if len([]rune(request.Reason)) > maxModerationReasonLength {
+		return fmt.Errorf("%w: %s", ErrValidation, moderationReasonTooLongError)
+	}
+	if request.DurationMinutes < 0 || request.DurationMinutes > maxModerationMinutes {
+		return fmt.Errorf("%w: %s", ErrValidation, moderationDurationError)
+	}
+	if durationRequired && request.DurationMinutes == 0 {
+		return fmt.Errorf("%w: %s", ErrValidation, moderationMuteDurationMissing)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/crdt"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+)
+
+const (
+	maxNoteTitleLength     = 200
+	maxNoteOperations      = 500
+	maxNoteInsertLength    = 10000
+	noteTitleRequiredError = "title is required"
+	noteTitleTooLongError  = "title must be at most 200 characters"
+	noteOperationsError    = "between 1 and 500 operations can be sent at once"
+	noteOperationTypeError = "operation type must be insert or delete"
+	noteInsertLengthError  = "an insert must have between 1 and 10000 characters"
+)
+
+func ValidateNoteRequest(request *dto.NoteRequest) error {
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, noteTitleRequiredError)
+	}
+	if len([]rune(title)) > maxNoteTitleLength {
+		return fmt.Errorf("%w: %s", ErrValidation, noteTitleTooLongError)
+	}
+	return nil
+}
+
+func ValidateNoteOperations(ops []crdt.Operation) error {
+	if len(ops) == 0 || len(ops) > maxNoteOperations {
+		return fmt.Errorf("%w: %s", ErrValidation, noteOperationsError)
+	}
+	for _, op := range ops {
+		switch op.Type {
+		case crdt.OperationInsert:
+			if length := len([]rune(op.Value)); length == 0 || length > maxNoteInsertLength {
+				return fmt.Errorf("%w: %s", ErrValidation, noteInsertLengthError)
+			}
+		case crdt.OperationDelete:
+		default:
+			return fmt.Errorf("%w: %s", ErrValidation, noteOperationTypeError)
+		}
+	}
+	return nil
+}
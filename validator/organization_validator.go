@@ -0,0 +1,41 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+)
+
+const (
+	maxOrganizationNameLength = 100
+	maxOrganizationDomains    = 20
+
+	organizationNameRequiredError = "name is required"
+	organizationNameTooLongError  = "name must be at most 100 characters"
+	organizationDomainsError      = "at most 20 email domains are allowed"
+	organizationDomainError       = "invalid email domain: %s"
+)
+
+// emailDomainPattern matches domains such as ubbcluj.ro or scs.ubbcluj.ro, without the @
+var emailDomainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`)
+
+func ValidateOrganizationRequest(request *dto.OrganizationRequest) error {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, organizationNameRequiredError)
+	}
+	if len([]rune(name)) > maxOrganizationNameLength {
+		return fmt.Errorf("%w: %s", ErrValidation, organizationNameTooLongError)
+	}
+	if len(request.EmailDomains) > maxOrganizationDomains {
+		return fmt.Errorf("%w: %s", ErrValidation, organizationDomainsError)
+	}
+	for _, domain := range request.EmailDomains {
+		if !emailDomainPattern.MatchString(strings.ToLower(strings.TrimSpace(domain))) {
+			return fmt.Errorf("%w: "+organizationDomainError, ErrValidation, domain)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	searchQueryEmptyError   = "search query must contain at least one word"
+	searchQueryTooLongError = "search query has too many words"
+	searchTypeInvalidError  = "unknown search type"
+
+	// MaxSearchTokens limits how many words a single query may contain
+	MaxSearchTokens = 10
+)
+
+// ValidateSearchQuery validates the tokenized search query
+func ValidateSearchQuery(tokens []string) error {
+	if len(tokens) == 0 {
+		return fmt.Errorf("%w: %s", ErrValidation, searchQueryEmptyError)
+	}
+	if len(tokens) > MaxSearchTokens {
+		return fmt.Errorf("%w: %s", ErrValidation, searchQueryTooLongError)
+	}
+	return nil
+}
+
+// ValidateSearchTypes validates the requested result types
+func ValidateSearchTypes(types []string) error {
+	for _, t := range types {
+		switch t {
+		case entity.SearchTypeTeam, entity.SearchTypeUser, entity.SearchTypeQuiz, entity.SearchTypeMessage:
+		default:
+			return fmt.Errorf("%w: %s %q", ErrValidation, searchTypeInvalidError, t)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/utils"
+)
+
+const (
+	maxSessionTitleLength  = 120
+	maxSessionDuration     = 24 * time.Hour
+	maxSessionInterval     = 52
+	maxSessionCount        = 365
+	maxSessionReminderMins = 24 * 60
+
+	sessionTitleRequiredError    = "title is required"
+	sessionTitleTooLongError     = "title must be at most 120 characters"
+	sessionTimesError            = "endAt must be after startAt"
+	sessionTooLongError          = "a session can last at most 24 hours"
+	sessionTimezoneError         = "timezone must be an IANA timezone name, e.g. Europe/Bucharest"
+	sessionFrequencyError        = "recurrence frequency must be daily, weekly or monthly"
+	sessionIntervalError         = "recurrence interval must be between 1 and 52"
+	sessionCountError            = "recurrence count must be between 0 and 365"
+	sessionUntilError            = "recurrence until must be after startAt"
+	sessionMonthlyDayError       = "monthly sessions must start on one of the first 28 days of the month"
+	sessionReminderError         = "reminderMinutes must be between 0 and 1440"
+	sessionRSVPStatusError       = "status must be going, maybe or declined"
+	sessionAgendaRangeError      = "to must be after from"
+	sessionAgendaRangeTooLongErr = "the agenda can span at most 366 days"
+)
+
+// ValidateStudySessionRequest validates a new or replaced study session
+func ValidateStudySessionRequest(request *dto.StudySessionRequest) error {
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, sessionTitleRequiredError)
+	}
+	if len([]rune(title)) > maxSessionTitleLength {
+		return fmt.Errorf("%w: %s", ErrValidation, sessionTitleTooLongError)
+	}
+	if !request.EndAt.After(request.StartAt) {
+		return fmt.Errorf("%w: %s", ErrValidation, sessionTimesError)
+	}
+	if request.EndAt.Sub(request.StartAt) > maxSessionDuration {
+		return fmt.Errorf("%w: %s", ErrValidation, sessionTooLongError)
+	}
+
+	loc, err := utils.LoadLocation(request.Timezone)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, sessionTimezoneError)
+	}
+
+	if request.ReminderMinutes != nil && (*request.ReminderMinutes < 0 || *request.ReminderMinutes > maxSessionReminderMins) {
+		return fmt.Errorf("%w: %s", ErrValidation, sessionReminderError)
+	}
+
+	if request.Recurrence != nil {
+		return validateRecurrence(request.Recurrence, request.StartAt.In(loc))
+	}
+	return nil
+}
+
+func validateRecurrence(rule *entity.RecurrenceRule, start time.Time) error {
+	switch rule.Frequency {
+	case entity.RecurrenceDaily, entity.RecurrenceWeekly:
+	case entity.RecurrenceMonthly:
+		// AddDate would roll the 31st over into the next month
+		if start.Day() > 28 {
+			return fmt.Errorf("%w: %s", ErrValidation, sessionMonthlyDayError)
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrValidation, sessionFrequencyError)
+	}
+
+	if rule.Interval < 0 || rule.Interval > maxSessionInterval {
+		return fmt.Errorf("%w: %s", ErrValidation, sessionIntervalError)
+	}
+	if rule.Count < 0 || rule.Count > maxSessionCount {
+		return fmt.Errorf("%w: %s", ErrValidation, sessionCountError)
+	}
+	if rule.Until != nil && !rule.Until.After(start) {
+		return fmt.Errorf("%w: %s", ErrValidation, sessionUntilError)
+	}
+	return nil
+}
+
+// ValidateRSVPRequest validates a member's answer to a session
+func ValidateRSVPRequest(request *dto.RSVPRequest) error {
+	switch request.Status {
+	case entity.RSVPGoing, entity.RSVPMaybe, entity.RSVPDeclined:
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrValidation, sessionRSVPStatusError)
+}
+
+// ValidateAgendaRange validates the time range of a user's agenda
+func ValidateAgendaRange(from, to time.Time) error {
+	if !to.After(from) {
+		return fmt.Errorf("%w: %s", ErrValidation, sessionAgendaRangeError)
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		return fmt.Errorf("%w: %s", ErrValidation, sessionAgendaRangeTooLongErr)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+)
+
+const (
+	maxColumnNameLength    = 50
+	maxTaskTitleLength     = 200
+	maxTaskDescription     = 5000
+	maxTaskLabels          = 10
+	maxTaskLabelLength     = 32
+	maxTaskChecklistItems  = 50
+	maxChecklistItemLength = 200
+	maxTaskCommentLength   = 2000
+
+	columnNameRequiredError   = "column name is required"
+	columnNameTooLongError    = "column name must be at most 50 characters"
+	taskTitleRequiredError    = "title is required"
+	taskTitleTooLongError     = "title must be at most 200 characters"
+	taskDescriptionTooLongErr = "description must be at most 5000 characters"
+	taskLabelsError           = "a task can have at most 10 labels of at most 32 characters"
+	taskChecklistError        = "a checklist can have at most 50 items of 1 to 200 characters"
+	taskCommentRequiredError  = "comment text is required"
+	taskCommentTooLongError   = "comment must be at most 2000 characters"
+	taskMoveColumnRequiredErr = "columnId is required"
+	taskMovePositionError     = "position can not be negative"
+	columnPositionNegativeErr = "position can not be negative"
+)
+
+func ValidateColumnName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, columnNameRequiredError)
+	}
+	if len([]rune(name)) > maxColumnNameLength {
+		return fmt.Errorf("%w: %s", ErrValidation, columnNameTooLongError)
+	}
+	return nil
+}
+
+func ValidateUpdateBoardColumnRequest(request *dto.UpdateBoardColumnRequest) error {
+	if request.Name != nil {
+		if err := ValidateColumnName(*request.Name); err != nil {
+			return err
+		}
+	}
+	if request.Position != nil && *request.Position < 0 {
+		return fmt.Errorf("%w: %s", ErrValidation, columnPositionNegativeErr)
+	}
+	return nil
+}
+
+// ValidateTaskRequest checks the content of a task, assignees are checked against the team by the service
+func ValidateTaskRequest(request *dto.TaskRequest) error {
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, taskTitleRequiredError)
+	}
+	if len([]rune(title)) > maxTaskTitleLength {
+		return fmt.Errorf("%w: %s", ErrValidation, taskTitleTooLongError)
+	}
+	if len([]rune(request.Description)) > maxTaskDescription {
+		return fmt.Errorf("%w: %s", ErrValidation, taskDescriptionTooLongErr)
+	}
+
+	if len(request.Labels) > maxTaskLabels {
+		return fmt.Errorf("%w: %s", ErrValidation, taskLabelsError)
+	}
+	for _, label := range request.Labels {
+		label = strings.TrimSpace(label)
+		if label == "" || len([]rune(label)) > maxTaskLabelLength {
+			return fmt.Errorf("%w: %s", ErrValidation, taskLabelsError)
+		}
+	}
+
+	if len(request.Checklist) > maxTaskChecklistItems {
+		return fmt.Errorf("%w: %s", ErrValidation, taskChecklistError)
+	}
+	for _, item := range request.Checklist {
+		text := strings.TrimSpace(item.Text)
+		if text == "" || len([]rune(text)) > maxChecklistItemLength {
+			return fmt.Errorf("%w: %s", ErrValidation, taskChecklistError)
+		}
+	}
+	return nil
+}
+
+func ValidateMoveTaskRequest(request *dto.MoveTaskRequest) error {
+	if request.ColumnID == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, taskMoveColumnRequiredErr)
+	}
+	if request.Position < 0 {
+		return fmt.Errorf("%w: %s", ErrValidation, taskMovePositionError)
+	}
+	return nil
+}
+
+func ValidateTaskCommentRequest(request *dto.TaskCommentRequest) error {
+	text := strings.TrimSpace(request.Text)
+	if text == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, taskCommentRequiredError)
+	}
+	if len([]rune(text)) > maxTaskCommentLength {
+		return fmt.Errorf("%w: %s", ErrValidation, taskCommentTooLongError)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/entity"
+)
+
+const (
+	maxTemplateNameLength = 100
+
+	templateNameRequiredError = "name is required"
+	templateNameTooLongError  = "name must be at most 100 characters"
+	templateContentError      = "include may only contain channels, quizzes and taskBoard"
+)
+
+func ValidateTeamTemplateRequest(request *dto.TeamTemplateRequest) error {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, templateNameRequiredError)
+	}
+	if len([]rune(name)) > maxTemplateNameLength {
+		return fmt.Errorf("%w: %s", ErrValidation, templateNameTooLongError)
+	}
+	return validateTemplateContents(request.Include)
+}
+
+func ValidateNewTeamFromTemplateRequest(request *dto.NewTeamFromTemplateRequest) error {
+	if strings.TrimSpace(request.Name) == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, templateNameRequiredError)
+	}
+	return validateTemplateContents(request.Include)
+}
+
+func validateTemplateContents(contents []entity.TemplateContent) error {
+	for _, content := range contents {
+		if !slices.Contains(entity.TemplateContents, content) {
+			return fmt.Errorf("%w: %s", ErrValidation, templateContentError)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model"
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+)
+
+const (
+	topicSlugInvalidError     = "slug must be 2-64 letters, digits or dashes"
+	topicNamesMissingError    = "a display name in the default language is required"
+	topicLanguageInvalidError = "language codes must be two lowercase letters"
+	topicNameEmptyError       = "display names can not be empty"
+	topicOwnParentError       = "a topic can not be its own parent"
+)
+
+var (
+	topicSlugRegex     = regexp.MustCompile(`^[A-Za-z0-9-]{2,64}$`)
+	topicLanguageRegex = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+// ValidateTopicRequest validates the topic creation request
+func ValidateTopicRequest(request *dto.TopicRequest) error {
+	if !topicSlugRegex.MatchString(string(request.Slug)) {
+		return fmt.Errorf("%w: %s", ErrValidation, topicSlugInvalidError)
+	}
+	if request.ParentSlug == request.Slug {
+		return fmt.Errorf("%w: %s", ErrValidation, topicOwnParentError)
+	}
+	return validateTopicNames(request.Names)
+}
+
+// ValidateUpdateTopicRequest validates the topic update request
+func ValidateUpdateTopicRequest(slug model.TopicOfInterest, request *dto.UpdateTopicRequest) error {
+	if request.ParentSlug == slug {
+		return fmt.Errorf("%w: %s", ErrValidation, topicOwnParentError)
+	}
+	return validateTopicNames(request.Names)
+}
+
+func validateTopicNames(names map[string]string) error {
+	if strings.TrimSpace(names[model.DefaultTopicLanguage]) == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, topicNamesMissingError)
+	}
+	for lang, name := range names {
+		if !topicLanguageRegex.MatchString(lang) {
+			return fmt.Errorf("%w: %s", ErrValidation, topicLanguageInvalidError)
+		}
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("%w: %s", ErrValidation, topicNameEmptyError)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/SerbanEduard/ProiectColectivBackEnd/model/dto"
+)
+
+const (
+	maxWikiPathLength    = 200
+	maxWikiPathDepth     = 10
+	maxWikiTitleLength   = 200
+	maxWikiContentLength = 100000
+	maxWikiSummaryLength = 300
+
+	wikiPathError           = "path must be made of lowercase letters, digits and dashes separated by \"/\", e.g. courses/algebra/week-1"
+	wikiPathTooLongError    = "path must be at most 200 characters and 10 levels deep"
+	wikiTitleRequiredError  = "title is required"
+	wikiTitleTooLongError   = "title must be at most 200 characters"
+	wikiContentTooLongError = "content must be at most 100000 characters"
+	wikiSummaryTooLongError = "summary must be at most 300 characters"
+)
+
+var wikiPathSegmentRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ValidateWikiPath checks a normalised page path
+func ValidateWikiPath(path string) error {
+	segments := strings.Split(path, "/")
+	if len(path) > maxWikiPathLength || len(segments) > maxWikiPathDepth {
+		return fmt.Errorf("%w: %s", ErrValidation, wikiPathTooLongError)
+	}
+	for _, segment := range segments {
+		if !wikiPathSegmentRegex.MatchString(segment) {
+			return fmt.Errorf("%w: %s", ErrValidation, wikiPathError)
+		}
+	}
+	return nil
+}
+
+func ValidateWikiPageRequest(request *dto.WikiPageRequest) error {
+	return validateWikiPage(request.Title, request.Content, request.Summary)
+}
+
+func ValidateUpdateWikiPageRequest(request *dto.UpdateWikiPageRequest) error {
+	return validateWikiPage(request.Title, request.Content, request.Summary)
+}
+
+func validateWikiPage(title, content, summary string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return fmt.Errorf("%w: %s", ErrValidation, wikiTitleRequiredError)
+	}
+	if len([]rune(title)) > maxWikiTitleLength {
+		return fmt.Errorf("%w: %s", ErrValidation, wikiTitleTooLongError)
+	}
+	if len([]rune(content)) > maxWikiContentLength {
+		return fmt.Errorf("%w: %s", ErrValidation, wikiContentTooLongError)
+	}
+	if len([]rune(summary)) > maxWikiSummaryLength {
+		return fmt.Errorf("%w: %s", ErrValidation, wikiSummaryTooLongError)
+	}
+	return nil
+}